@@ -168,7 +168,9 @@ CREATE TABLE public.replication_queue (
     attempt integer DEFAULT 3 NOT NULL,
     lock_id text,
     job jsonb,
-    meta jsonb
+    meta jsonb,
+    priority integer DEFAULT 0 NOT NULL,
+    error text
 );
 
 
@@ -268,7 +270,11 @@ CREATE TABLE public.storage_repositories (
     generation bigint NOT NULL,
     repository_id bigint NOT NULL,
     verified_at timestamp with time zone,
-    verification_leased_until timestamp with time zone
+    verification_leased_until timestamp with time zone,
+    checksum text,
+    checksum_generation bigint,
+    checksum_verified_at timestamp with time zone,
+    checksum_matches boolean
 );
 
 
@@ -341,6 +347,29 @@ CREATE TABLE public.storage_cleanups (
 );
 
 
+--
+-- Name: storage_drain_moves; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.storage_drain_moves (
+    repository_id bigint NOT NULL,
+    virtual_storage text NOT NULL,
+    source_storage text NOT NULL,
+    target_storage text
+);
+
+
+--
+-- Name: storage_drains; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.storage_drains (
+    virtual_storage text NOT NULL,
+    storage text NOT NULL,
+    started_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
 --
 -- Name: valid_primaries; Type: VIEW; Schema: public; Owner: -
 --
@@ -478,6 +507,22 @@ ALTER TABLE ONLY public.storage_cleanups
     ADD CONSTRAINT storage_cleanups_pkey PRIMARY KEY (virtual_storage, storage);
 
 
+--
+-- Name: storage_drain_moves storage_drain_moves_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.storage_drain_moves
+    ADD CONSTRAINT storage_drain_moves_pkey PRIMARY KEY (repository_id, source_storage);
+
+
+--
+-- Name: storage_drains storage_drains_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.storage_drains
+    ADD CONSTRAINT storage_drains_pkey PRIMARY KEY (virtual_storage, storage);
+
+
 --
 -- Name: storage_repositories storage_repositories_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -638,6 +683,22 @@ ALTER TABLE ONLY public.repository_assignments
     ADD CONSTRAINT repository_assignments_virtual_storage_relative_path_fkey FOREIGN KEY (virtual_storage, relative_path) REFERENCES public.repositories(virtual_storage, relative_path) ON UPDATE CASCADE ON DELETE CASCADE;
 
 
+--
+-- Name: storage_drain_moves storage_drain_moves_repository_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.storage_drain_moves
+    ADD CONSTRAINT storage_drain_moves_repository_id_fkey FOREIGN KEY (repository_id) REFERENCES public.repositories(repository_id) ON DELETE CASCADE;
+
+
+--
+-- Name: storage_drain_moves storage_drain_moves_virtual_storage_source_storage_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.storage_drain_moves
+    ADD CONSTRAINT storage_drain_moves_virtual_storage_source_storage_fkey FOREIGN KEY (virtual_storage, source_storage) REFERENCES public.storage_drains(virtual_storage, storage) ON DELETE CASCADE;
+
+
 --
 -- Name: storage_repositories storage_repositories_repository_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
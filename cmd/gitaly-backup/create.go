@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -29,8 +30,13 @@ type createSubcommand struct {
 	parallel        int
 	parallelStorage int
 	layout          string
+	keyFile         string
+	keyring         string
+	allowPlaintext  bool
 	incremental     bool
+	objectPools     bool
 	backupID        string
+	serverSide      bool
 }
 
 func (cmd *createSubcommand) Flags(fs *flag.FlagSet) {
@@ -38,25 +44,63 @@ func (cmd *createSubcommand) Flags(fs *flag.FlagSet) {
 	fs.IntVar(&cmd.parallel, "parallel", runtime.NumCPU(), "maximum number of parallel backups")
 	fs.IntVar(&cmd.parallelStorage, "parallel-storage", 2, "maximum number of parallel backups per storage. Note: actual parallelism when combined with `-parallel` depends on the order the repositories are received.")
 	fs.StringVar(&cmd.layout, "layout", "legacy", "determines how backup files are located. One of legacy, pointer. Note: The feature is not ready for production use.")
+	fs.StringVar(&cmd.keyFile, "encryption-key-file", "", "path of the key file used to encrypt backups. Mutually exclusive with `-encryption-keyring`.")
+	fs.StringVar(&cmd.keyring, "encryption-keyring", "", "path of the keyring directory used to encrypt backups. Mutually exclusive with `-encryption-key-file`.")
+	fs.BoolVar(&cmd.allowPlaintext, "allow-plaintext", false, "allow reading files of existing backups that aren't encrypted, such as the steps incremental backups are based on. Only used with encryption keys.")
 	fs.BoolVar(&cmd.incremental, "incremental", false, "creates an incremental backup if possible.")
+	fs.BoolVar(&cmd.objectPools, "object-pools", false, "backs up object pools once and excludes their objects from the backups of pool members. Requires the pointer layout.")
 	fs.StringVar(&cmd.backupID, "id", time.Now().UTC().Format("20060102150405"), "the backup ID used when creating a full backup.")
+	fs.BoolVar(&cmd.serverSide, "server-side", false, "use server-side backups. Gitaly writes the backups to its own configured backup sink. Note: The feature is not ready for production use.")
 }
 
 func (cmd *createSubcommand) Run(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
-	sink, err := backup.ResolveSink(ctx, cmd.backupPath)
-	if err != nil {
-		return fmt.Errorf("create: resolve sink: %w", err)
-	}
-
-	locator, err := backup.ResolveLocator(cmd.layout, sink)
-	if err != nil {
-		return fmt.Errorf("create: resolve locator: %w", err)
-	}
-
 	pool := client.NewPool(internalclient.UnaryInterceptor(), internalclient.StreamInterceptor())
 	defer pool.Close()
 
-	manager := backup.NewManager(sink, locator, pool, cmd.backupID)
+	var manager backup.Strategy
+	if cmd.serverSide {
+		if cmd.backupPath != "" {
+			return errors.New("create: path cannot be used with server-side backups")
+		}
+		if cmd.objectPools {
+			return errors.New("create: object pools cannot be used with server-side backups")
+		}
+
+		manager = backup.NewServerSideAdapter(pool, cmd.backupID)
+	} else {
+		if cmd.objectPools && cmd.layout != "pointer" {
+			return errors.New("create: object pools require the pointer layout")
+		}
+
+		sink, err := backup.ResolveSink(ctx, cmd.backupPath)
+		if err != nil {
+			return fmt.Errorf("create: resolve sink: %w", err)
+		}
+
+		keys, err := backup.ResolveKeyProvider(cmd.keyFile, cmd.keyring)
+		if err != nil {
+			return fmt.Errorf("create: resolve key provider: %w", err)
+		}
+		if keys != nil {
+			var sinkOpts []backup.EncryptedSinkOption
+			if cmd.allowPlaintext {
+				sinkOpts = append(sinkOpts, backup.WithPlaintext())
+			}
+			sink = backup.NewEncryptedSink(sink, keys, sinkOpts...)
+		}
+
+		locator, err := backup.ResolveLocator(cmd.layout, sink)
+		if err != nil {
+			return fmt.Errorf("create: resolve locator: %w", err)
+		}
+
+		var opts []backup.ManagerOption
+		if cmd.objectPools {
+			opts = append(opts, backup.WithObjectPools())
+		}
+
+		manager = backup.NewManager(sink, locator, pool, cmd.backupID, opts...)
+	}
 
 	var pipeline backup.Pipeline
 	pipeline = backup.NewLoggingPipeline(log.StandardLogger())
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service/setup"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
@@ -71,3 +72,54 @@ func TestCreateSubcommand(t *testing.T) {
 		require.FileExists(t, bundlePath)
 	}
 }
+
+func TestCreateSubcommand_serverSide(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	backupRoot := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(backupRoot)
+	locator, err := backup.ResolveLocator("pointer", sink)
+	require.NoError(t, err)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll,
+		testserver.WithBackupSink(sink),
+		testserver.WithBackupLocator(locator),
+	)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+
+	var stdin bytes.Buffer
+	require.NoError(t, json.NewEncoder(&stdin).Encode(map[string]string{
+		"address":         cfg.SocketPath,
+		"token":           cfg.Auth.Token,
+		"storage_name":    repo.StorageName,
+		"relative_path":   repo.RelativePath,
+		"gl_project_path": repo.GlProjectPath,
+	}))
+
+	cmd := createSubcommand{}
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	cmd.Flags(fs)
+
+	require.NoError(t, fs.Parse([]string{"-server-side", "-id", "abc123"}))
+	require.NoError(t, cmd.Run(ctx, &stdin, io.Discard))
+
+	b, err := locator.Find(ctx, repo, "abc123")
+	require.NoError(t, err)
+	require.Len(t, b.Steps, 1)
+	require.FileExists(t, filepath.Join(backupRoot, b.Steps[0].BundlePath))
+
+	t.Run("path", func(t *testing.T) {
+		cmd := createSubcommand{}
+		fs := flag.NewFlagSet("create", flag.ContinueOnError)
+		cmd.Flags(fs)
+
+		require.NoError(t, fs.Parse([]string{"-server-side", "-path", backupRoot}))
+		require.EqualError(t, cmd.Run(ctx, &bytes.Buffer{}, io.Discard), "create: path cannot be used with server-side backups")
+	})
+}
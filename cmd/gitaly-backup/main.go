@@ -17,6 +17,8 @@ type subcmd interface {
 var subcommands = map[string]subcmd{
 	"create":  &createSubcommand{},
 	"restore": &restoreSubcommand{},
+	"prune":   &pruneSubcommand{},
+	"verify":  &verifySubcommand{},
 }
 
 func main() {
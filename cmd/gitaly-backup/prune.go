@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+)
+
+type pruneSubcommand struct {
+	backupPath     string
+	parallel       int
+	keyFile        string
+	keyring        string
+	allowPlaintext bool
+	keepLast       int
+	keepDaily      int
+	keepWeekly     int
+	keepMonthly    int
+	dryRun         bool
+}
+
+func (cmd *pruneSubcommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&cmd.backupPath, "path", "", "repository backup path")
+	fs.IntVar(&cmd.parallel, "parallel", runtime.NumCPU(), "maximum number of repositories pruned in parallel")
+	fs.StringVar(&cmd.keyFile, "encryption-key-file", "", "path of the key file used to decrypt backups. Mutually exclusive with `-encryption-keyring`.")
+	fs.StringVar(&cmd.keyring, "encryption-keyring", "", "path of the keyring directory used to decrypt backups. Mutually exclusive with `-encryption-key-file`.")
+	fs.BoolVar(&cmd.allowPlaintext, "allow-plaintext", false, "allow reading files that aren't encrypted, such as backups created before encryption was enabled. Only used with encryption keys.")
+	fs.IntVar(&cmd.keepLast, "keep-last", 0, "number of most recent full backups to keep")
+	fs.IntVar(&cmd.keepDaily, "keep-daily", 0, "number of days for which to keep the latest full backup")
+	fs.IntVar(&cmd.keepWeekly, "keep-weekly", 0, "number of weeks for which to keep the latest full backup")
+	fs.IntVar(&cmd.keepMonthly, "keep-monthly", 0, "number of months for which to keep the latest full backup")
+	fs.BoolVar(&cmd.dryRun, "dry-run", false, "only report the files that would be deleted")
+}
+
+func (cmd *pruneSubcommand) Run(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	policy := backup.RetentionPolicy{
+		KeepLast:    cmd.keepLast,
+		KeepDaily:   cmd.keepDaily,
+		KeepWeekly:  cmd.keepWeekly,
+		KeepMonthly: cmd.keepMonthly,
+	}
+	if policy == (backup.RetentionPolicy{}) {
+		return errors.New("prune: no retention policy given")
+	}
+
+	sink, err := backup.ResolveSink(ctx, cmd.backupPath)
+	if err != nil {
+		return fmt.Errorf("prune: resolve sink: %w", err)
+	}
+
+	keys, err := backup.ResolveKeyProvider(cmd.keyFile, cmd.keyring)
+	if err != nil {
+		return fmt.Errorf("prune: resolve key provider: %w", err)
+	}
+	var sinkOpts []backup.EncryptedSinkOption
+	if cmd.allowPlaintext {
+		sinkOpts = append(sinkOpts, backup.WithPlaintext())
+	}
+	sink = backup.NewEncryptedSink(sink, keys, sinkOpts...)
+
+	pruner := backup.NewPruner(sink, policy, cmd.dryRun, stdout)
+
+	var pipeline backup.Pipeline
+	pipeline = backup.NewLoggingPipeline(log.StandardLogger())
+	if cmd.parallel > 0 {
+		// Pruning only accesses the sink, so it isn't limited per storage.
+		pipeline = backup.NewParallelPipeline(pipeline, cmd.parallel, 0)
+	}
+
+	// Repositories are found by walking the sink so that the backups of
+	// repositories which have been deleted in the meantime are pruned, too.
+	repos, err := pruner.Repositories(ctx)
+	if err != nil {
+		return fmt.Errorf("prune: %w", err)
+	}
+	for _, repo := range repos {
+		pipeline.Handle(ctx, backup.NewPruneCommand(pruner, repo))
+	}
+
+	if err := pipeline.Done(); err != nil {
+		return fmt.Errorf("prune: %w", err)
+	}
+	return nil
+}
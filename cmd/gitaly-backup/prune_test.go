@@ -0,0 +1,81 @@
+//go:build !gitaly_test_sha256
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+)
+
+func TestPruneSubcommand(t *testing.T) {
+	ctx := testhelper.Context(t)
+	path := testhelper.TempDir(t)
+
+	writeFile := func(relativePath, content string) {
+		fullPath := filepath.Join(path, relativePath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0o644))
+	}
+
+	// Repositories are found by walking the backups, so the backups of
+	// repositories which don't exist anymore are pruned as well.
+	for _, repoPath := range []string{"repo", filepath.Join("group", "deleted")} {
+		for _, backupID := range []string{"20220101120000", "20220102120000"} {
+			writeFile(filepath.Join(repoPath, backupID, "001.bundle"), "bundle")
+			writeFile(filepath.Join(repoPath, backupID, "001.refs"), "refs")
+			writeFile(filepath.Join(repoPath, backupID, "LATEST"), "001")
+		}
+		writeFile(filepath.Join(repoPath, "LATEST"), "20220102120000")
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := pruneSubcommand{}
+
+		fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+		cmd.Flags(fs)
+		require.NoError(t, fs.Parse(append([]string{"-path", path}, args...)))
+
+		var stdout bytes.Buffer
+		err := cmd.Run(ctx, &bytes.Buffer{}, &stdout)
+		return stdout.String(), err
+	}
+
+	_, err := run()
+	require.EqualError(t, err, "prune: no retention policy given")
+
+	expectedDeletions := []string{
+		filepath.Join("group", "deleted", "20220101120000", "001.bundle"),
+		filepath.Join("group", "deleted", "20220101120000", "001.refs"),
+		filepath.Join("group", "deleted", "20220101120000", "LATEST"),
+		filepath.Join("repo", "20220101120000", "001.bundle"),
+		filepath.Join("repo", "20220101120000", "001.refs"),
+		filepath.Join("repo", "20220101120000", "LATEST"),
+	}
+
+	out, err := run("-keep-last", "1", "-dry-run", "-parallel", "1")
+	require.NoError(t, err)
+	var expectedOut strings.Builder
+	for _, relativePath := range expectedDeletions {
+		expectedOut.WriteString("would delete " + relativePath + "\n")
+		require.FileExists(t, filepath.Join(path, relativePath))
+	}
+	require.Equal(t, expectedOut.String(), out)
+
+	out, err = run("-keep-last", "1", "-parallel", "1")
+	require.NoError(t, err)
+	expectedOut.Reset()
+	for _, relativePath := range expectedDeletions {
+		expectedOut.WriteString("deleted " + relativePath + "\n")
+		require.NoFileExists(t, filepath.Join(path, relativePath))
+	}
+	require.Equal(t, expectedOut.String(), out)
+	require.FileExists(t, filepath.Join(path, "repo", "20220102120000", "001.bundle"))
+	require.FileExists(t, filepath.Join(path, "group", "deleted", "20220102120000", "001.bundle"))
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -31,6 +32,16 @@ type restoreSubcommand struct {
 	parallel        int
 	parallelStorage int
 	layout          string
+	keyFile         string
+	keyring         string
+	allowPlaintext  bool
+	serverSide      bool
+	backupID        string
+	timestamp       string
+	refs            string
+	refNamespace    string
+	overwriteRefs   bool
+	skipValidation  bool
 }
 
 func (cmd *restoreSubcommand) Flags(fs *flag.FlagSet) {
@@ -38,23 +49,81 @@ func (cmd *restoreSubcommand) Flags(fs *flag.FlagSet) {
 	fs.IntVar(&cmd.parallel, "parallel", runtime.NumCPU(), "maximum number of parallel restores")
 	fs.IntVar(&cmd.parallelStorage, "parallel-storage", 2, "maximum number of parallel restores per storage. Note: actual parallelism when combined with `-parallel` depends on the order the repositories are received.")
 	fs.StringVar(&cmd.layout, "layout", "legacy", "determines how backup files are located. One of legacy, pointer. Note: The feature is not ready for production use.")
+	fs.StringVar(&cmd.keyFile, "encryption-key-file", "", "path of the key file used to decrypt backups. Mutually exclusive with `-encryption-keyring`.")
+	fs.StringVar(&cmd.keyring, "encryption-keyring", "", "path of the keyring directory used to decrypt backups. Mutually exclusive with `-encryption-key-file`.")
+	fs.BoolVar(&cmd.allowPlaintext, "allow-plaintext", false, "allow restoring files that aren't encrypted, such as backups created before encryption was enabled. Only used with encryption keys.")
+	fs.BoolVar(&cmd.serverSide, "server-side", false, "use server-side backups. Gitaly reads the backups from its own configured backup sink. Note: The feature is not ready for production use.")
+	fs.StringVar(&cmd.backupID, "id", "", "the backup ID to restore. The latest backup is restored if not given. Only supported by the pointer layout. Mutually exclusive with `-timestamp`.")
+	fs.StringVar(&cmd.timestamp, "timestamp", "", "the point in time to restore repositories to, formatted as RFC 3339. Only supported by the pointer layout. Mutually exclusive with `-id`.")
+	fs.StringVar(&cmd.refs, "refs", "", "comma-separated list of refs to restore into the existing repositories instead of replacing them. Refs ending with a slash select all refs with that prefix.")
+	fs.StringVar(&cmd.refNamespace, "ref-namespace", "", "the namespace refs selected by `-refs` are restored into, for example `refs/restored`. The refs are restored under their original names if not given.")
+	fs.BoolVar(&cmd.overwriteRefs, "overwrite-refs", false, "allow refs selected by `-refs` to overwrite existing refs with the same names. Restoring fails if any of the refs exists otherwise.")
+	fs.BoolVar(&cmd.skipValidation, "skip-validation", false, "don't verify all files of a backup against their checksums before restoring it. All files are only read once, but a corrupt backup fails the restore after the repository has already been replaced.")
 }
 
 func (cmd *restoreSubcommand) Run(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
-	sink, err := backup.ResolveSink(ctx, cmd.backupPath)
-	if err != nil {
-		return fmt.Errorf("restore: resolve sink: %w", err)
+	var timestamp time.Time
+	if cmd.timestamp != "" {
+		if cmd.backupID != "" {
+			return errors.New("restore: id and timestamp are mutually exclusive")
+		}
+
+		var err error
+		timestamp, err = time.Parse(time.RFC3339, cmd.timestamp)
+		if err != nil {
+			return fmt.Errorf("restore: parse timestamp: %w", err)
+		}
 	}
 
-	locator, err := backup.ResolveLocator(cmd.layout, sink)
-	if err != nil {
-		return fmt.Errorf("restore: resolve locator: %w", err)
+	var refs []string
+	if cmd.refs != "" {
+		refs = strings.Split(cmd.refs, ",")
+	} else if cmd.refNamespace != "" {
+		return errors.New("restore: ref-namespace requires refs")
+	} else if cmd.overwriteRefs {
+		return errors.New("restore: overwrite-refs requires refs")
 	}
 
 	pool := client.NewPool(internalclient.UnaryInterceptor(), internalclient.StreamInterceptor())
 	defer pool.Close()
 
-	manager := backup.NewManager(sink, locator, pool, time.Now().UTC().Format("20060102150405"))
+	var manager backup.Strategy
+	if cmd.serverSide {
+		if cmd.backupPath != "" {
+			return errors.New("restore: path cannot be used with server-side backups")
+		}
+
+		manager = backup.NewServerSideAdapter(pool, "")
+	} else {
+		sink, err := backup.ResolveSink(ctx, cmd.backupPath)
+		if err != nil {
+			return fmt.Errorf("restore: resolve sink: %w", err)
+		}
+
+		keys, err := backup.ResolveKeyProvider(cmd.keyFile, cmd.keyring)
+		if err != nil {
+			return fmt.Errorf("restore: resolve key provider: %w", err)
+		}
+		var sinkOpts []backup.EncryptedSinkOption
+		if cmd.allowPlaintext {
+			sinkOpts = append(sinkOpts, backup.WithPlaintext())
+		}
+		// Always decrypt so that encrypted backups are detected even if no keys have been
+		// provided, in which case restoring them fails.
+		sink = backup.NewEncryptedSink(sink, keys, sinkOpts...)
+
+		locator, err := backup.ResolveLocator(cmd.layout, sink)
+		if err != nil {
+			return fmt.Errorf("restore: resolve locator: %w", err)
+		}
+
+		var opts []backup.ManagerOption
+		if cmd.skipValidation {
+			opts = append(opts, backup.WithoutValidation())
+		}
+
+		manager = backup.NewManager(sink, locator, pool, time.Now().UTC().Format("20060102150405"), opts...)
+	}
 
 	var pipeline backup.Pipeline
 	pipeline = backup.NewLoggingPipeline(log.StandardLogger())
@@ -76,7 +145,16 @@ func (cmd *restoreSubcommand) Run(ctx context.Context, stdin io.Reader, stdout i
 			RelativePath:  req.RelativePath,
 			GlProjectPath: req.GlProjectPath,
 		}
-		pipeline.Handle(ctx, backup.NewRestoreCommand(manager, req.ServerInfo, &repo, req.AlwaysCreate))
+		pipeline.Handle(ctx, backup.NewRestoreCommand(manager, backup.RestoreRequest{
+			Server:        req.ServerInfo,
+			Repository:    &repo,
+			BackupID:      cmd.backupID,
+			Timestamp:     timestamp,
+			AlwaysCreate:  req.AlwaysCreate,
+			Refs:          refs,
+			RefNamespace:  cmd.refNamespace,
+			OverwriteRefs: cmd.overwriteRefs,
+		}))
 	}
 
 	if err := pipeline.Done(); err != nil {
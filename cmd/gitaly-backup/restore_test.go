@@ -12,8 +12,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service/setup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
@@ -83,3 +86,189 @@ func TestRestoreSubcommand(t *testing.T) {
 		require.Contains(t, string(output), "The bundle records a complete history")
 	}
 }
+
+func TestRestoreSubcommand_serverSide(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	sink := backup.NewFilesystemSink(testhelper.TempDir(t))
+	locator, err := backup.ResolveLocator("pointer", sink)
+	require.NoError(t, err)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll,
+		testserver.WithBackupSink(sink),
+		testserver.WithBackupLocator(locator),
+	)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	commitID := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	require.NoError(t, backup.NewServerSideAdapter(pool, "abc123").Create(ctx, &backup.CreateRequest{
+		Server:     storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token},
+		Repository: repo,
+	}))
+
+	gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("feature"))
+
+	var stdin bytes.Buffer
+	require.NoError(t, json.NewEncoder(&stdin).Encode(map[string]string{
+		"address":         cfg.SocketPath,
+		"token":           cfg.Auth.Token,
+		"storage_name":    repo.StorageName,
+		"relative_path":   repo.RelativePath,
+		"gl_project_path": repo.GlProjectPath,
+	}))
+
+	cmd := restoreSubcommand{}
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	cmd.Flags(fs)
+
+	require.NoError(t, fs.Parse([]string{"-server-side"}))
+	require.NoError(t, cmd.Run(ctx, &stdin, io.Discard))
+
+	repoPath = filepath.Join(cfg.Storages[0].Path, gittest.GetReplicaPath(t, ctx, cfg, repo))
+	refs := gittest.Exec(t, cfg, "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)")
+	require.Equal(t, commitID.String()+" refs/heads/master\n", string(refs))
+}
+
+func TestRestoreSubcommand_pointInTime(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	commitID := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+
+	path := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(path)
+	locator, err := backup.ResolveLocator("pointer", sink)
+	require.NoError(t, err)
+
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	server := storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token}
+	require.NoError(t, backup.NewManager(sink, locator, pool, "abc123").Create(ctx, &backup.CreateRequest{
+		Server:     server,
+		Repository: repo,
+	}))
+
+	gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("feature"))
+	require.NoError(t, backup.NewManager(sink, locator, pool, "def456").Create(ctx, &backup.CreateRequest{
+		Server:     server,
+		Repository: repo,
+	}))
+
+	var stdin bytes.Buffer
+	require.NoError(t, json.NewEncoder(&stdin).Encode(map[string]string{
+		"address":         cfg.SocketPath,
+		"token":           cfg.Auth.Token,
+		"storage_name":    repo.StorageName,
+		"relative_path":   repo.RelativePath,
+		"gl_project_path": repo.GlProjectPath,
+	}))
+
+	for _, tc := range []struct {
+		desc        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			desc:        "id and timestamp",
+			args:        []string{"-id", "abc123", "-timestamp", "2022-01-02T03:04:05Z"},
+			expectedErr: "restore: id and timestamp are mutually exclusive",
+		},
+		{
+			desc:        "invalid timestamp",
+			args:        []string{"-timestamp", "yesterday"},
+			expectedErr: "restore: parse timestamp: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\"",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			cmd := restoreSubcommand{}
+			fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+			cmd.Flags(fs)
+
+			require.NoError(t, fs.Parse(append([]string{"-path", path, "-layout", "pointer"}, tc.args...)))
+			require.EqualError(t, cmd.Run(ctx, bytes.NewReader(stdin.Bytes()), io.Discard), tc.expectedErr)
+		})
+	}
+
+	cmd := restoreSubcommand{}
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	cmd.Flags(fs)
+
+	require.NoError(t, fs.Parse([]string{"-path", path, "-layout", "pointer", "-id", "abc123"}))
+	require.NoError(t, cmd.Run(ctx, &stdin, io.Discard))
+
+	repoPath = filepath.Join(cfg.Storages[0].Path, gittest.GetReplicaPath(t, ctx, cfg, repo))
+	refs := gittest.Exec(t, cfg, "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)")
+	require.Equal(t, commitID.String()+" refs/heads/master\n", string(refs))
+}
+
+func TestRestoreSubcommand_refs(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+	testcfg.BuildGitalySSH(t, cfg)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	commitID := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+	featureID := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("feature"), gittest.WithParents(commitID))
+
+	path := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(path)
+	locator, err := backup.ResolveLocator("pointer", sink)
+	require.NoError(t, err)
+
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	require.NoError(t, backup.NewManager(sink, locator, pool, "abc123").Create(ctx, &backup.CreateRequest{
+		Server:     storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token},
+		Repository: repo,
+	}))
+
+	gittest.Exec(t, cfg, "-C", repoPath, "update-ref", "-d", "refs/heads/feature")
+
+	var stdin bytes.Buffer
+	require.NoError(t, json.NewEncoder(&stdin).Encode(map[string]string{
+		"address":         cfg.SocketPath,
+		"token":           cfg.Auth.Token,
+		"storage_name":    repo.StorageName,
+		"relative_path":   repo.RelativePath,
+		"gl_project_path": repo.GlProjectPath,
+	}))
+
+	cmd := restoreSubcommand{}
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	cmd.Flags(fs)
+
+	require.NoError(t, fs.Parse([]string{"-path", path, "-layout", "pointer", "-ref-namespace", "refs/restored"}))
+	require.EqualError(t, cmd.Run(ctx, bytes.NewReader(stdin.Bytes()), io.Discard), "restore: ref-namespace requires refs")
+
+	cmd = restoreSubcommand{}
+	fs = flag.NewFlagSet("restore", flag.ContinueOnError)
+	cmd.Flags(fs)
+
+	require.NoError(t, fs.Parse([]string{"-path", path, "-layout", "pointer", "-refs", "refs/heads/feature", "-ref-namespace", "refs/restored"}))
+	require.NoError(t, cmd.Run(ctx, &stdin, io.Discard))
+
+	repoPath = filepath.Join(cfg.Storages[0].Path, gittest.GetReplicaPath(t, ctx, cfg, repo))
+	refs := gittest.Exec(t, cfg, "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)")
+	require.Equal(t, commitID.String()+" refs/heads/master\n"+featureID.String()+" refs/restored/heads/feature\n", string(refs))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	internalclient "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+type verifyRequest struct {
+	storage.ServerInfo
+	StorageName   string `json:"storage_name"`
+	RelativePath  string `json:"relative_path"`
+	GlProjectPath string `json:"gl_project_path"`
+}
+
+type verifySubcommand struct {
+	backupPath      string
+	parallel        int
+	parallelStorage int
+	layout          string
+	backupID        string
+	keyFile         string
+	keyring         string
+	allowPlaintext  bool
+}
+
+func (cmd *verifySubcommand) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&cmd.backupPath, "path", "", "repository backup path")
+	fs.IntVar(&cmd.parallel, "parallel", runtime.NumCPU(), "maximum number of parallel verifications")
+	fs.IntVar(&cmd.parallelStorage, "parallel-storage", 2, "maximum number of parallel verifications per storage. Note: actual parallelism when combined with `-parallel` depends on the order the repositories are received.")
+	fs.StringVar(&cmd.layout, "layout", "legacy", "determines how backup files are located. One of legacy, pointer. Note: The feature is not ready for production use.")
+	fs.StringVar(&cmd.backupID, "id", "", "the backup ID to verify. The latest backup is verified if not given. Only supported by the pointer layout.")
+	fs.StringVar(&cmd.keyFile, "encryption-key-file", "", "path of the key file used to decrypt backups. Mutually exclusive with `-encryption-keyring`.")
+	fs.StringVar(&cmd.keyring, "encryption-keyring", "", "path of the keyring directory used to decrypt backups. Mutually exclusive with `-encryption-key-file`.")
+	fs.BoolVar(&cmd.allowPlaintext, "allow-plaintext", false, "allow verifying files that aren't encrypted, such as backups created before encryption was enabled. Only used with encryption keys.")
+}
+
+func (cmd *verifySubcommand) Run(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	sink, err := backup.ResolveSink(ctx, cmd.backupPath)
+	if err != nil {
+		return fmt.Errorf("verify: resolve sink: %w", err)
+	}
+
+	keys, err := backup.ResolveKeyProvider(cmd.keyFile, cmd.keyring)
+	if err != nil {
+		return fmt.Errorf("verify: resolve key provider: %w", err)
+	}
+	var sinkOpts []backup.EncryptedSinkOption
+	if cmd.allowPlaintext {
+		sinkOpts = append(sinkOpts, backup.WithPlaintext())
+	}
+	sink = backup.NewEncryptedSink(sink, keys, sinkOpts...)
+
+	locator, err := backup.ResolveLocator(cmd.layout, sink)
+	if err != nil {
+		return fmt.Errorf("verify: resolve locator: %w", err)
+	}
+
+	pool := client.NewPool(internalclient.UnaryInterceptor(), internalclient.StreamInterceptor())
+	defer pool.Close()
+
+	manager := backup.NewManager(sink, locator, pool, "")
+	reporter := backup.NewVerifyReporter(stdout)
+
+	var pipeline backup.Pipeline
+	pipeline = backup.NewLoggingPipeline(log.StandardLogger())
+	if cmd.parallel > 0 || cmd.parallelStorage > 0 {
+		pipeline = backup.NewParallelPipeline(pipeline, cmd.parallel, cmd.parallelStorage)
+	}
+
+	decoder := json.NewDecoder(stdin)
+	for {
+		var req verifyRequest
+		if err := decoder.Decode(&req); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("verify: %w", err)
+		}
+
+		repo := gitalypb.Repository{
+			StorageName:   req.StorageName,
+			RelativePath:  req.RelativePath,
+			GlProjectPath: req.GlProjectPath,
+		}
+		pipeline.Handle(ctx, backup.NewVerifyCommand(manager, req.ServerInfo, &repo, cmd.backupID, reporter))
+	}
+
+	if err := pipeline.Done(); err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	return nil
+}
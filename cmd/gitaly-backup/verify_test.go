@@ -0,0 +1,84 @@
+//go:build !gitaly_test_sha256
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service/setup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func TestVerifySubcommand(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+
+	path := testhelper.TempDir(t)
+	backupPath := filepath.Join(path, "backup")
+
+	var stdin bytes.Buffer
+	encoder := json.NewEncoder(&stdin)
+	for _, repo := range []*gitalypb.Repository{repo, {StorageName: repo.StorageName, RelativePath: "missing.git"}} {
+		require.NoError(t, encoder.Encode(map[string]string{
+			"address":         cfg.SocketPath,
+			"token":           cfg.Auth.Token,
+			"storage_name":    repo.StorageName,
+			"relative_path":   repo.RelativePath,
+			"gl_project_path": repo.GlProjectPath,
+		}))
+	}
+
+	createCmd := createSubcommand{}
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	createCmd.Flags(fs)
+	require.NoError(t, fs.Parse([]string{"-path", backupPath, "-layout", "pointer", "-id", "abc123"}))
+	require.NoError(t, createCmd.Run(ctx, bytes.NewReader(stdin.Bytes()), &bytes.Buffer{}))
+
+	verifyCmd := verifySubcommand{}
+	fs = flag.NewFlagSet("verify", flag.ContinueOnError)
+	verifyCmd.Flags(fs)
+	require.NoError(t, fs.Parse([]string{"-path", backupPath, "-layout", "pointer", "-id", "abc123"}))
+
+	var stdout bytes.Buffer
+	err := verifyCmd.Run(ctx, &stdin, &stdout)
+	require.ErrorContains(t, err, "verify: pipeline: 1 failures encountered:\n - missing.git: manager: pointer locator: find:")
+
+	reports := make(map[string]backup.VerifyReport)
+	decoder := json.NewDecoder(&stdout)
+	for decoder.More() {
+		var report backup.VerifyReport
+		require.NoError(t, decoder.Decode(&report))
+		reports[report.RelativePath] = report
+	}
+	require.Len(t, reports, 2)
+
+	require.Equal(t, backup.VerifyReport{
+		StorageName:   repo.StorageName,
+		RelativePath:  repo.RelativePath,
+		GlProjectPath: repo.GlProjectPath,
+		BackupID:      "abc123",
+		Steps:         1,
+		Status:        backup.VerifyStatusOK,
+	}, reports[repo.RelativePath])
+
+	require.Equal(t, backup.VerifyStatusFailed, reports["missing.git"].Status)
+	require.Contains(t, reports["missing.git"].Error, "doesn't exist")
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-enry/go-license-detector/v4/licensedb"
@@ -14,6 +15,7 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15"
 	"gitlab.com/gitlab-org/gitaly/v15/client"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/backchannel"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/bootstrap"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/bootstrap/starter"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/cache"
@@ -101,12 +103,14 @@ func main() {
 	log.Infof("Starting %s", version.GetVersionString())
 	fips.Check()
 
-	cfg, err := configure(flag.Arg(0))
+	configPath := flag.Arg(0)
+
+	cfg, err := configure(configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if err := run(cfg); err != nil {
+	if err := run(configPath, cfg); err != nil {
 		log.WithError(err).Error("Gitaly shutdown")
 		os.Exit(1)
 	}
@@ -140,10 +144,14 @@ func preloadLicenseDatabase() {
 	log.Info("License database preloaded")
 }
 
-func run(cfg config.Cfg) error {
+func run(configPath string, cfg config.Cfg) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// The runtime directory is changed below, so we need to remember the configuration as loaded
+	// to detect changes when reloading it.
+	loadedCfg := cfg
+
 	if cfg.RuntimeDir != "" {
 		if err := config.PruneOldGitalyProcessDirectories(log.StandardLogger(), cfg.RuntimeDir); err != nil {
 			return fmt.Errorf("prune runtime directories: %w", err)
@@ -280,18 +288,12 @@ func run(cfg config.Cfg) error {
 		string(cfg.PackObjectsLimiting.Key),
 		cfg.Prometheus.GRPCLatencyBuckets,
 	)
-	packObjectsConcurrencyLimit := cfg.PackObjectsLimiting.MaxConcurrency
-	if packObjectsConcurrencyLimit == 0 {
-		// TODO: remove this default setting when we remove the feature
-		// flags PackObjectsLimitingRepo and PackObjectsLimitingUser
-		// feature flag issue:  https://gitlab.com/gitlab-org/gitaly/-/issues/4413
-		packObjectsConcurrencyLimit = 200
-	}
+	packObjectsMaxQueueWait := int64(cfg.PackObjectsLimiting.MaxQueueWait.Duration())
 	packObjectsLimiter := limithandler.NewConcurrencyLimiter(
-		packObjectsConcurrencyLimit,
+		packObjectsConcurrencyLimit(cfg),
 		0,
 		func() helper.Ticker {
-			return helper.NewTimerTicker(cfg.PackObjectsLimiting.MaxQueueWait.Duration())
+			return helper.NewTimerTicker(time.Duration(atomic.LoadInt64(&packObjectsMaxQueueWait)))
 		},
 		packObjectsMonitor,
 	)
@@ -322,6 +324,19 @@ func run(cfg config.Cfg) error {
 	concurrencyTracker := hook.NewConcurrencyTracker()
 	prometheus.MustRegister(concurrencyTracker)
 
+	var backupSink backup.Sink
+	var backupLocator backup.Locator
+	if cfg.Backup.GoCloudURL != "" {
+		backupSink, err = backup.ResolveSink(ctx, cfg.Backup.GoCloudURL)
+		if err != nil {
+			return fmt.Errorf("resolve backup sink: %w", err)
+		}
+		backupLocator, err = backup.ResolveLocator(cfg.Backup.Layout, backupSink)
+		if err != nil {
+			return fmt.Errorf("resolve backup locator: %w", err)
+		}
+	}
+
 	for _, c := range []starter.Config{
 		{Name: starter.Unix, Addr: cfg.SocketPath, HandoverOnUpgrade: true},
 		{Name: starter.Unix, Addr: cfg.InternalSocketPath(), HandoverOnUpgrade: false},
@@ -361,6 +376,8 @@ func run(cfg config.Cfg) error {
 			Git2goExecutor:                git2goExecutor,
 			UpdaterWithHooks:              updaterWithHooks,
 			HousekeepingManager:           housekeepingManager,
+			BackupSink:                    backupSink,
+			BackupLocator:                 backupLocator,
 		})
 		b.RegisterStarter(starter.New(c, srv))
 	}
@@ -402,6 +419,25 @@ func run(cfg config.Cfg) error {
 		return fmt.Errorf("unable to start the bootstrap: %v", err)
 	}
 
+	// SIGHUP triggers a graceful upgrade when upgrades are enabled, so the configuration can only
+	// be reloaded in place with SIGUSR1 then.
+	upgradesEnabled, _ := env.GetBool(bootstrap.EnvUpgradesEnabled, false)
+	if upgradesEnabled {
+		log.Warn("graceful upgrades are enabled, SIGHUP triggers an upgrade instead of reloading the configuration in place, send SIGUSR1 to reload it")
+	}
+
+	reloader := &configReloader{
+		load: func() (config.Cfg, error) {
+			return loadConfig(configPath)
+		},
+		cfg:                     loadedCfg,
+		limitHandlers:           []*limithandler.LimiterMiddleware{concurrencyLimitHandler, rateLimitHandler},
+		packObjectsLimiter:      packObjectsLimiter,
+		packObjectsMaxQueueWait: &packObjectsMaxQueueWait,
+		cgroupsManager:          cgroupMgr,
+	}
+	reloader.reloadOnSignals(ctx, reloadSignals(upgradesEnabled)...)
+
 	shutdownWorkers, err := maintenance.StartWorkers(
 		ctx,
 		glog.Default(),
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/cgroups"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/middleware/limithandler"
+)
+
+// configReloader applies changes to the configuration file to a running Gitaly process. Only
+// limits can be changed at runtime, see config.Cfg.ValidateReload.
+type configReloader struct {
+	// load loads and validates the configuration.
+	load func() (config.Cfg, error)
+	// cfg is the configuration as currently applied.
+	cfg config.Cfg
+
+	limitHandlers      []*limithandler.LimiterMiddleware
+	packObjectsLimiter *limithandler.ConcurrencyLimiter
+	// packObjectsMaxQueueWait is the maximum time pack-objects processes wait in the queue.
+	// It must be accessed atomically.
+	packObjectsMaxQueueWait *int64
+	cgroupsManager          cgroups.Manager
+}
+
+// reload loads the configuration and applies it if it only differs from the current configuration
+// in settings that can be changed at runtime. Otherwise, the configuration is rejected as a whole
+// and nothing is changed.
+func (r *configReloader) reload() error {
+	newCfg, err := r.load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	if err := r.cfg.ValidateReload(newCfg); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	if err := r.cgroupsManager.Update(newCfg.Cgroups); err != nil {
+		return fmt.Errorf("update cgroups: %w", err)
+	}
+
+	for _, limitHandler := range r.limitHandlers {
+		limitHandler.Reload(newCfg)
+	}
+
+	atomic.StoreInt64(r.packObjectsMaxQueueWait, int64(newCfg.PackObjectsLimiting.MaxQueueWait.Duration()))
+	r.packObjectsLimiter.SetMaxPerKey(packObjectsConcurrencyLimit(newCfg))
+
+	r.cfg = newCfg
+
+	return nil
+}
+
+// reloadSignals returns the signals that reload the configuration. SIGUSR1 always reloads it.
+// SIGHUP triggers a graceful upgrade when upgrades are enabled, so it only reloads the
+// configuration when they are disabled.
+func reloadSignals(upgradesEnabled bool) []os.Signal {
+	if upgradesEnabled {
+		return []os.Signal{syscall.SIGUSR1}
+	}
+
+	return []os.Signal{syscall.SIGHUP, syscall.SIGUSR1}
+}
+
+// reloadOnSignals reloads the configuration whenever the process receives one of the signals until
+// the context is cancelled.
+func (r *configReloader) reloadOnSignals(ctx context.Context, reloadSignals ...os.Signal) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, reloadSignals...)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-signals:
+				if err := r.reload(); err != nil {
+					log.WithError(err).Error("configuration has not been reloaded")
+					continue
+				}
+
+				log.Info("configuration reloaded")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func packObjectsConcurrencyLimit(cfg config.Cfg) int {
+	if cfg.PackObjectsLimiting.MaxConcurrency == 0 {
+		// TODO: remove this default setting when we remove the feature
+		// flags PackObjectsLimitingRepo and PackObjectsLimitingUser
+		// feature flag issue:  https://gitlab.com/gitlab-org/gitaly/-/issues/4413
+		return 200
+	}
+
+	return cfg.PackObjectsLimiting.MaxConcurrency
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/cgroups"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/duration"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/middleware/limithandler"
+)
+
+func TestConfigReloader(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Cfg{
+		SocketPath: "/tmp/gitaly.socket",
+		Concurrency: []config.Concurrency{
+			{RPC: "/gitaly.RepositoryService/GarbageCollect", MaxPerRepo: 1},
+		},
+		PackObjectsLimiting: config.PackObjectsLimiting{
+			MaxConcurrency: 10,
+			MaxQueueWait:   duration.Duration(time.Second),
+		},
+	}
+
+	newReloader := func(load func() (config.Cfg, error)) *configReloader {
+		packObjectsMaxQueueWait := int64(time.Second)
+
+		return &configReloader{
+			load: load,
+			cfg:  cfg,
+			limitHandlers: []*limithandler.LimiterMiddleware{
+				limithandler.New(cfg, limithandler.LimitConcurrencyByRepo, limithandler.WithConcurrencyLimiters),
+			},
+			packObjectsLimiter:      limithandler.NewConcurrencyLimiter(10, 0, nil, nil),
+			packObjectsMaxQueueWait: &packObjectsMaxQueueWait,
+			cgroupsManager:          &cgroups.NoopManager{},
+		}
+	}
+
+	t.Run("reloadable settings", func(t *testing.T) {
+		t.Parallel()
+
+		newCfg := cfg
+		newCfg.Concurrency = []config.Concurrency{
+			{RPC: "/gitaly.RepositoryService/GarbageCollect", MaxPerRepo: 2},
+		}
+		newCfg.PackObjectsLimiting.MaxQueueWait = duration.Duration(time.Minute)
+
+		reloader := newReloader(func() (config.Cfg, error) {
+			return newCfg, nil
+		})
+
+		require.NoError(t, reloader.reload())
+		require.Equal(t, newCfg, reloader.cfg)
+		require.Equal(t, int64(time.Minute), *reloader.packObjectsMaxQueueWait)
+	})
+
+	t.Run("non-reloadable settings", func(t *testing.T) {
+		t.Parallel()
+
+		newCfg := cfg
+		newCfg.SocketPath = "/tmp/other.socket"
+		newCfg.PackObjectsLimiting.MaxQueueWait = duration.Duration(time.Minute)
+
+		reloader := newReloader(func() (config.Cfg, error) {
+			return newCfg, nil
+		})
+
+		require.EqualError(t, reloader.reload(), "validate config: settings cannot be changed at runtime: socket_path")
+		require.Equal(t, cfg, reloader.cfg)
+		require.Equal(t, int64(time.Second), *reloader.packObjectsMaxQueueWait)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Parallel()
+
+		reloader := newReloader(func() (config.Cfg, error) {
+			return config.Cfg{}, errors.New("invalid config")
+		})
+
+		require.EqualError(t, reloader.reload(), "load config: invalid config")
+		require.Equal(t, cfg, reloader.cfg)
+	})
+}
+
+func TestConfigReloader_reloadOnSignals(t *testing.T) {
+	require.Equal(t, []os.Signal{syscall.SIGHUP, syscall.SIGUSR1}, reloadSignals(false))
+
+	// SIGHUP triggers a graceful upgrade when upgrades are enabled, so only SIGUSR1 reloads the
+	// configuration.
+	upgradesEnabledSignals := reloadSignals(true)
+	require.Equal(t, []os.Signal{syscall.SIGUSR1}, upgradesEnabledSignals)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.Cfg{SocketPath: "/tmp/gitaly.socket"}
+	newCfg := cfg
+	newCfg.Concurrency = []config.Concurrency{
+		{RPC: "/gitaly.RepositoryService/GarbageCollect", MaxPerRepo: 2},
+	}
+
+	loaded := make(chan struct{})
+	packObjectsMaxQueueWait := int64(0)
+	reloader := &configReloader{
+		load: func() (config.Cfg, error) {
+			defer close(loaded)
+			return newCfg, nil
+		},
+		cfg: cfg,
+		limitHandlers: []*limithandler.LimiterMiddleware{
+			limithandler.New(cfg, limithandler.LimitConcurrencyByRepo, limithandler.WithConcurrencyLimiters),
+		},
+		packObjectsLimiter:      limithandler.NewConcurrencyLimiter(10, 0, nil, nil),
+		packObjectsMaxQueueWait: &packObjectsMaxQueueWait,
+		cgroupsManager:          &cgroups.NoopManager{},
+	}
+	reloader.reloadOnSignals(ctx, upgradesEnabledSignals...)
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+
+	select {
+	case <-loaded:
+	case <-time.After(time.Minute):
+		require.FailNow(t, "configuration was not reloaded")
+	}
+}
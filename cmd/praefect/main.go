@@ -86,6 +86,7 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/nodes"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/nodes/tracker"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/protoregistry"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/rebalancer"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/reconciler"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/repocleaner"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/service"
@@ -336,10 +337,10 @@ func run(
 		// before the router is ready with the health status of the nodes.
 		<-hm.Updated()
 
-		elector := nodes.NewPerRepositoryElector(db)
+		elector := nodes.NewPerRepositoryElector(db, conf.FailureDomains())
 
 		primaryGetter = elector
-		assignmentStore = datastore.NewAssignmentStore(db, conf.StorageNames())
+		assignmentStore = datastore.NewAssignmentStore(db, conf.StorageNames(), conf.FailureDomains())
 
 		router = praefect.NewPerRepositoryRouter(
 			nodeSet.Connections(),
@@ -350,6 +351,7 @@ func run(
 			assignmentStore,
 			rs,
 			conf.DefaultReplicationFactors(),
+			conf.FailureDomains(),
 		)
 
 		if conf.BackgroundVerification.VerificationInterval > 0 {
@@ -361,6 +363,7 @@ func run(
 				hm,
 				conf.BackgroundVerification.VerificationInterval.Duration(),
 				conf.BackgroundVerification.DeleteInvalidRecords,
+				conf.BackgroundVerification.VerifyChecksums,
 			)
 			promreg.MustRegister(verifier)
 
@@ -378,6 +381,23 @@ func run(
 		} else {
 			logger.Info("background verifier is disabled")
 		}
+
+		if interval := conf.Rebalancing.SchedulingInterval.Duration(); interval > 0 {
+			rb := rebalancer.NewRebalancer(
+				logger,
+				db,
+				hm,
+				rebalancer.NewNodeStatistics(nodeSet.Connections()),
+				conf.Rebalancing,
+				conf.FailureDomains(),
+			)
+
+			go func() {
+				if err := rb.Run(ctx, helper.NewTimerTicker(interval)); err != nil {
+					logger.WithError(err).Error("rebalancer finished execution")
+				}
+			}()
+		}
 	} else {
 		if conf.Failover.Enabled {
 			logger.WithField("election_strategy", conf.Failover.ElectionStrategy).Warn(
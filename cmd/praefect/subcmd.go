@@ -34,22 +34,27 @@ const (
 
 func subcommands(logger *logrus.Entry) map[string]subcmd {
 	return map[string]subcmd{
-		sqlPingCmdName:                &sqlPingSubcommand{},
-		sqlMigrateCmdName:             newSQLMigrateSubCommand(os.Stdout),
-		dialNodesCmdName:              newDialNodesSubcommand(os.Stdout),
-		sqlMigrateDownCmdName:         &sqlMigrateDownSubcommand{},
-		sqlMigrateStatusCmdName:       &sqlMigrateStatusSubcommand{},
-		datalossCmdName:               newDatalossSubcommand(),
-		acceptDatalossCmdName:         &acceptDatalossSubcommand{},
-		setReplicationFactorCmdName:   newSetReplicatioFactorSubcommand(os.Stdout),
-		removeRepositoryCmdName:       newRemoveRepository(logger, os.Stdout),
-		trackRepositoryCmdName:        newTrackRepository(logger, os.Stdout),
-		trackRepositoriesCmdName:      newTrackRepositories(logger, os.Stdout),
-		listUntrackedRepositoriesName: newListUntrackedRepositories(logger, os.Stdout),
-		checkCmdName:                  newCheckSubcommand(os.Stdout, service.AllChecks()...),
-		metadataCmdName:               newMetadataSubcommand(os.Stdout),
-		verifyCmdName:                 newVerifySubcommand(os.Stdout),
-		listStoragesCmdName:           newListStorages(os.Stdout),
+		sqlPingCmdName:                   &sqlPingSubcommand{},
+		sqlMigrateCmdName:                newSQLMigrateSubCommand(os.Stdout),
+		dialNodesCmdName:                 newDialNodesSubcommand(os.Stdout),
+		sqlMigrateDownCmdName:            &sqlMigrateDownSubcommand{},
+		sqlMigrateStatusCmdName:          &sqlMigrateStatusSubcommand{},
+		datalossCmdName:                  newDatalossSubcommand(),
+		acceptDatalossCmdName:            &acceptDatalossSubcommand{},
+		setReplicationFactorCmdName:      newSetReplicatioFactorSubcommand(os.Stdout),
+		removeRepositoryCmdName:          newRemoveRepository(logger, os.Stdout),
+		trackRepositoryCmdName:           newTrackRepository(logger, os.Stdout),
+		trackRepositoriesCmdName:         newTrackRepositories(logger, os.Stdout),
+		listUntrackedRepositoriesName:    newListUntrackedRepositories(logger, os.Stdout),
+		checkCmdName:                     newCheckSubcommand(os.Stdout, service.AllChecks()...),
+		metadataCmdName:                  newMetadataSubcommand(os.Stdout),
+		verifyCmdName:                    newVerifySubcommand(os.Stdout),
+		listStoragesCmdName:              newListStorages(os.Stdout),
+		drainStorageCmdName:              newDrainStorageSubcommand(os.Stdout),
+		rebalanceCmdName:                 newRebalanceSubcommand(logger, os.Stdout),
+		listReplicationJobsCmdName:       newListReplicationJobsSubcommand(os.Stdout),
+		cancelReplicationJobsCmdName:     newCancelReplicationJobsSubcommand(os.Stdout),
+		prioritizeReplicationJobsCmdName: newPrioritizeReplicationJobsSubcommand(os.Stdout),
 	}
 }
 
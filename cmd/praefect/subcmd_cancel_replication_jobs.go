@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+const (
+	cancelReplicationJobsCmdName = "cancel-replication-jobs"
+	paramJobIDs                  = "ids"
+)
+
+type cancelReplicationJobsSubcommand struct {
+	stdout io.Writer
+	ids    string
+}
+
+func newCancelReplicationJobsSubcommand(stdout io.Writer) *cancelReplicationJobsSubcommand {
+	return &cancelReplicationJobsSubcommand{stdout: stdout}
+}
+
+func (cmd *cancelReplicationJobsSubcommand) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cancelReplicationJobsCmdName, flag.ContinueOnError)
+	fs.StringVar(&cmd.ids, paramJobIDs, "", "comma separated list of the IDs of the jobs to cancel")
+	fs.Usage = func() {
+		printfErr("Description:\n" +
+			"	This command removes replication jobs which are waiting to be processed from the\n" +
+			"	replication queue. Jobs being processed are not cancelled. If a replica remains\n" +
+			"	outdated, the reconciler schedules a new job for it.\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (cmd *cancelReplicationJobsSubcommand) Exec(flags *flag.FlagSet, cfg config.Config) error {
+	if flags.NArg() > 0 {
+		return unexpectedPositionalArgsError{Command: flags.Name()}
+	} else if cmd.ids == "" {
+		return requiredParameterError(paramJobIDs)
+	}
+
+	ids, err := parseJobIDs(cmd.ids)
+	if err != nil {
+		return err
+	}
+
+	nodeAddr, err := getNodeAddress(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	conn, err := subCmdDial(ctx, nodeAddr, cfg.Auth.Token, defaultDialTimeout)
+	if err != nil {
+		return fmt.Errorf("error dialing: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := gitalypb.NewPraefectInfoServiceClient(conn).CancelReplicationJobs(ctx, &gitalypb.CancelReplicationJobsRequest{
+		Ids: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.stdout, "cancelled jobs: %s\n", formatJobIDs(resp.GetCancelledIds()))
+	return nil
+}
+
+// parseJobIDs parses a comma separated list of replication job IDs.
+func parseJobIDs(value string) ([]uint64, error) {
+	var ids []uint64
+	for _, field := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job ID %q: %w", field, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// formatJobIDs formats the replication job IDs as a comma separated list.
+func formatJobIDs(ids []uint64) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+
+	formatted := make([]string, len(ids))
+	for i, id := range ids {
+		formatted[i] = strconv.FormatUint(id, 10)
+	}
+
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,98 @@
+//go:build !gitaly_test_sha256
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/service/info"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCancelReplicationJobsSubcommand(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc        string
+		args        []string
+		jobManager  bool
+		expectedIDs []uint64
+		cancelled   []uint64
+		error       error
+		stdout      string
+	}{
+		{
+			desc:  "unexpected positional arguments",
+			args:  []string{"positonal-arg"},
+			error: unexpectedPositionalArgsError{Command: "cancel-replication-jobs"},
+		},
+		{
+			desc:  "missing ids",
+			args:  []string{},
+			error: requiredParameterError("ids"),
+		},
+		{
+			desc:  "invalid id",
+			args:  []string{"-ids=1,invalid"},
+			error: errors.New(`invalid job ID "invalid": strconv.ParseUint: parsing "invalid": invalid syntax`),
+		},
+		{
+			desc:  "postgres queue not configured",
+			args:  []string{"-ids=1"},
+			error: status.Error(codes.FailedPrecondition, "managing replication jobs requires the Postgres replication queue"),
+		},
+		{
+			desc:        "no jobs cancelled",
+			args:        []string{"-ids=1"},
+			jobManager:  true,
+			expectedIDs: []uint64{1},
+			stdout:      "cancelled jobs: none\n",
+		},
+		{
+			desc:        "jobs cancelled",
+			args:        []string{"-ids=1, 2,3"},
+			jobManager:  true,
+			expectedIDs: []uint64{1, 2, 3},
+			cancelled:   []uint64{1, 3},
+			stdout:      "cancelled jobs: 1, 3\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srv := info.NewServer(config.Config{}, nil, nil, nil, nil, nil)
+			if tc.jobManager {
+				srv = info.NewServer(config.Config{}, nil, nil, nil, nil, mockReplicationJobManager{
+					cancelJobs: func(_ context.Context, ids []uint64) ([]uint64, error) {
+						require.Equal(t, tc.expectedIDs, ids)
+						return tc.cancelled, nil
+					},
+				})
+			}
+
+			ln, clean := listenAndServe(t, []svcRegistrar{registerPraefectInfoServer(srv)})
+			defer clean()
+
+			stdout := &bytes.Buffer{}
+			cmd := newCancelReplicationJobsSubcommand(stdout)
+			fs := cmd.FlagSet()
+			require.NoError(t, fs.Parse(tc.args))
+			err := cmd.Exec(fs, config.Config{
+				SocketPath: ln.Addr().String(),
+			})
+			if tc.error != nil {
+				require.EqualError(t, err, tc.error.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.stdout, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+const (
+	drainStorageCmdName = "drain-storage"
+	paramStorage        = "storage"
+)
+
+type drainStorageSubcommand struct {
+	stdout         io.Writer
+	virtualStorage string
+	storage        string
+	cancel         bool
+	pollInterval   time.Duration
+	timeout        time.Duration
+}
+
+func newDrainStorageSubcommand(stdout io.Writer) *drainStorageSubcommand {
+	return &drainStorageSubcommand{stdout: stdout}
+}
+
+func (cmd *drainStorageSubcommand) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(drainStorageCmdName, flag.ContinueOnError)
+	fs.StringVar(&cmd.virtualStorage, paramVirtualStorage, "", "name of the storage's virtual storage")
+	fs.StringVar(&cmd.storage, paramStorage, "", "storage to drain")
+	fs.BoolVar(&cmd.cancel, "cancel", false, "stop draining the storage")
+	fs.DurationVar(&cmd.pollInterval, "poll-interval", 10*time.Second, "how often to check the progress of the drain")
+	fs.DurationVar(&cmd.timeout, "timeout", 0, "how long to wait for the storage to be drained before giving up. Waits indefinitely if not set.")
+	fs.Usage = func() {
+		printfErr("Description:\n" +
+			"	This command drains a storage for maintenance. The storage is not elected as a primary nor\n" +
+			"	assigned new repositories anymore, and the repositories on it are moved to the other storages\n" +
+			"	of the virtual storage. The command reports the progress until the storage is empty. It fails if\n" +
+			"	none of the remaining repositories can be moved, or if the storage isn't empty after -timeout.\n" +
+			"	Use -cancel to stop draining the storage once the maintenance is done.\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (cmd *drainStorageSubcommand) Exec(flags *flag.FlagSet, cfg config.Config) error {
+	if flags.NArg() > 0 {
+		return unexpectedPositionalArgsError{Command: flags.Name()}
+	} else if cmd.virtualStorage == "" {
+		return requiredParameterError(paramVirtualStorage)
+	} else if cmd.storage == "" {
+		return requiredParameterError(paramStorage)
+	}
+
+	nodeAddr, err := getNodeAddress(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	conn, err := subCmdDial(ctx, nodeAddr, cfg.Auth.Token, defaultDialTimeout)
+	if err != nil {
+		return fmt.Errorf("error dialing: %w", err)
+	}
+	defer conn.Close()
+
+	client := gitalypb.NewPraefectInfoServiceClient(conn)
+
+	if cmd.cancel {
+		if _, err := client.DrainStorage(ctx, &gitalypb.DrainStorageRequest{
+			VirtualStorage: cmd.virtualStorage,
+			Storage:        cmd.storage,
+			Cancel:         true,
+		}); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.stdout, "stopped draining storage %q\n", cmd.storage)
+		return nil
+	}
+
+	var deadline <-chan time.Time
+	if cmd.timeout > 0 {
+		timer := time.NewTimer(cmd.timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		resp, err := client.DrainStorage(ctx, &gitalypb.DrainStorageRequest{
+			VirtualStorage: cmd.virtualStorage,
+			Storage:        cmd.storage,
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.GetRepositories() == 0 {
+			fmt.Fprintf(cmd.stdout, "storage %q drained\n", cmd.storage)
+			return nil
+		}
+
+		fmt.Fprintf(cmd.stdout, "repositories remaining: %d, primaries remaining: %d, replicas being moved: %d\n",
+			resp.GetRepositories(), resp.GetPrimaries(), resp.GetMoves())
+
+		unmovable := resp.GetUnmovableRepositories()
+		for _, relativePath := range unmovable {
+			fmt.Fprintf(cmd.stdout, "repository %q can't be moved: no other storage can take its replica\n", relativePath)
+		}
+
+		// Unmovable repositories are only moved once another storage becomes available, so there's
+		// no point in waiting if none of the remaining repositories can be moved.
+		if int64(len(unmovable)) >= resp.GetRepositories() {
+			return fmt.Errorf("storage %q can't be drained: none of the %d remaining repositories can be moved",
+				cmd.storage, resp.GetRepositories())
+		}
+
+		select {
+		case <-deadline:
+			return fmt.Errorf("storage %q not drained after %s: %d repositories remaining",
+				cmd.storage, cmd.timeout, resp.GetRepositories())
+		case <-time.After(cmd.pollInterval):
+		}
+	}
+}
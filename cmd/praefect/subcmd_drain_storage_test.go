@@ -0,0 +1,128 @@
+//go:build !gitaly_test_sha256
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/service/info"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testdb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDrainStorageSubcommand(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	conf := config.Config{
+		VirtualStorages: []*config.VirtualStorage{
+			{
+				Name: "virtual-storage",
+				Nodes: []*config.Node{
+					{Storage: "gitaly-1"},
+					{Storage: "gitaly-2"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc   string
+		setup  func(t *testing.T, rs *datastore.PostgresRepositoryStore)
+		args   []string
+		error  error
+		stdout string
+	}{
+		{
+			desc:  "unexpected positional arguments",
+			args:  []string{"positonal-arg"},
+			error: unexpectedPositionalArgsError{Command: "drain-storage"},
+		},
+		{
+			desc:  "missing virtual-storage",
+			args:  []string{},
+			error: requiredParameterError("virtual-storage"),
+		},
+		{
+			desc:  "missing storage",
+			args:  []string{"-virtual-storage=virtual-storage"},
+			error: requiredParameterError("storage"),
+		},
+		{
+			desc:  "virtual storage not found",
+			args:  []string{"-virtual-storage=non-existent", "-storage=gitaly-1"},
+			error: status.Error(codes.InvalidArgument, `unknown virtual storage: "non-existent"`),
+		},
+		{
+			desc:  "storage not found",
+			args:  []string{"-virtual-storage=virtual-storage", "-storage=non-existent"},
+			error: status.Error(codes.InvalidArgument, `unknown storage: "non-existent"`),
+		},
+		{
+			desc:   "storage drained",
+			args:   []string{"-virtual-storage=virtual-storage", "-storage=gitaly-1"},
+			stdout: "storage \"gitaly-1\" drained\n",
+		},
+		{
+			desc: "repositories can't be moved",
+			setup: func(t *testing.T, rs *datastore.PostgresRepositoryStore) {
+				ctx := testhelper.Context(t)
+				require.NoError(t, rs.CreateRepository(ctx, 1, "virtual-storage", "relative-path", "replica-path", "gitaly-1", nil, nil, true, true))
+
+				// The only other storage is draining, too.
+				_, err := rs.DrainStorage(ctx, "virtual-storage", "gitaly-2", nil)
+				require.NoError(t, err)
+			},
+			args:  []string{"-virtual-storage=virtual-storage", "-storage=gitaly-1"},
+			error: errors.New(`storage "gitaly-1" can't be drained: none of the 1 remaining repositories can be moved`),
+			stdout: "repositories remaining: 1, primaries remaining: 1, replicas being moved: 1\n" +
+				"repository \"relative-path\" can't be moved: no other storage can take its replica\n",
+		},
+		{
+			desc: "timeout",
+			setup: func(t *testing.T, rs *datastore.PostgresRepositoryStore) {
+				ctx := testhelper.Context(t)
+				require.NoError(t, rs.CreateRepository(ctx, 1, "virtual-storage", "relative-path", "replica-path", "gitaly-1", nil, nil, true, true))
+			},
+			args:   []string{"-virtual-storage=virtual-storage", "-storage=gitaly-1", "-timeout=1ms", "-poll-interval=1h"},
+			error:  errors.New(`storage "gitaly-1" not drained after 1ms: 1 repositories remaining`),
+			stdout: "repositories remaining: 1, primaries remaining: 1, replicas being moved: 1\n",
+		},
+		{
+			desc:   "drain cancelled",
+			args:   []string{"-virtual-storage=virtual-storage", "-storage=gitaly-1", "-cancel"},
+			stdout: "stopped draining storage \"gitaly-1\"\n",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			db.TruncateAll(t)
+
+			rs := datastore.NewPostgresRepositoryStore(db, conf.StorageNames())
+			if tc.setup != nil {
+				tc.setup(t, rs)
+			}
+
+			ln, clean := listenAndServe(t, []svcRegistrar{registerPraefectInfoServer(
+				info.NewServer(conf, rs, nil, nil, nil, nil),
+			)})
+			defer clean()
+
+			stdout := &bytes.Buffer{}
+			cmd := newDrainStorageSubcommand(stdout)
+			fs := cmd.FlagSet()
+			require.NoError(t, fs.Parse(tc.args))
+			err := cmd.Exec(fs, config.Config{
+				SocketPath: ln.Addr().String(),
+			})
+			testhelper.RequireGrpcError(t, tc.error, err)
+			require.Equal(t, tc.stdout, stdout.String())
+		})
+	}
+}
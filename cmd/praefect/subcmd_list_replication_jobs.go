@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const listReplicationJobsCmdName = "list-replication-jobs"
+
+type listReplicationJobsSubcommand struct {
+	stdout         io.Writer
+	virtualStorage string
+	targetStorage  string
+	relativePath   string
+	states         string
+	olderThan      time.Duration
+	newerThan      time.Duration
+	limit          int
+}
+
+func newListReplicationJobsSubcommand(stdout io.Writer) *listReplicationJobsSubcommand {
+	return &listReplicationJobsSubcommand{stdout: stdout}
+}
+
+func (cmd *listReplicationJobsSubcommand) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(listReplicationJobsCmdName, flag.ContinueOnError)
+	fs.StringVar(&cmd.virtualStorage, paramVirtualStorage, "", "list only the jobs of the virtual storage")
+	fs.StringVar(&cmd.targetStorage, "target-storage", "", "list only the jobs replicating to the storage")
+	fs.StringVar(&cmd.relativePath, paramRelativePath, "", "list only the jobs of the repository")
+	fs.StringVar(&cmd.states, "state", "", "comma separated list of the states of the jobs to list: ready, in_progress or failed")
+	fs.DurationVar(&cmd.olderThan, "older-than", 0, "list only the jobs created longer ago than the duration")
+	fs.DurationVar(&cmd.newerThan, "newer-than", 0, "list only the jobs created more recently than the duration")
+	fs.IntVar(&cmd.limit, "limit", 100, "maximum number of jobs to list, 0 lists all jobs")
+	fs.Usage = func() {
+		printfErr("Description:\n" +
+			"	This command lists the jobs in the replication queue, oldest first. The jobs can be\n" +
+			"	filtered by their virtual storage, target storage, repository, state and age. The\n" +
+			"	error of the latest failed attempt is printed for each job.\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (cmd *listReplicationJobsSubcommand) Exec(flags *flag.FlagSet, cfg config.Config) error {
+	if flags.NArg() > 0 {
+		return unexpectedPositionalArgsError{Command: flags.Name()}
+	}
+
+	req := &gitalypb.ListReplicationJobsRequest{
+		VirtualStorage: cmd.virtualStorage,
+		TargetStorage:  cmd.targetStorage,
+		RelativePath:   cmd.relativePath,
+		Limit:          int32(cmd.limit),
+	}
+
+	if cmd.states != "" {
+		for _, state := range strings.Split(cmd.states, ",") {
+			req.States = append(req.States, strings.TrimSpace(state))
+		}
+	}
+
+	now := time.Now()
+	if cmd.olderThan > 0 {
+		req.CreatedBefore = timestamppb.New(now.Add(-cmd.olderThan))
+	}
+
+	if cmd.newerThan > 0 {
+		req.CreatedAfter = timestamppb.New(now.Add(-cmd.newerThan))
+	}
+
+	nodeAddr, err := getNodeAddress(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	conn, err := subCmdDial(ctx, nodeAddr, cfg.Auth.Token, defaultDialTimeout)
+	if err != nil {
+		return fmt.Errorf("error dialing: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := gitalypb.NewPraefectInfoServiceClient(conn).ListReplicationJobs(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.GetJobs()) == 0 {
+		fmt.Fprintln(cmd.stdout, "no replication jobs found")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(cmd.stdout)
+	table.SetHeader([]string{
+		"ID", "STATE", "CHANGE", "VIRTUAL_STORAGE", "REPOSITORY", "SOURCE", "TARGET",
+		"ATTEMPTS_LEFT", "PRIORITY", "CREATED_AT", "ERROR",
+	})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoFormatHeaders(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("\t") // pad with tabs
+	table.SetNoWhiteSpace(true)
+	table.SetAutoWrapText(false)
+
+	for _, job := range resp.GetJobs() {
+		table.Append([]string{
+			strconv.FormatUint(job.GetId(), 10),
+			job.GetState(),
+			job.GetChange(),
+			job.GetVirtualStorage(),
+			job.GetRelativePath(),
+			job.GetSourceStorage(),
+			job.GetTargetStorage(),
+			strconv.Itoa(int(job.GetAttemptsLeft())),
+			strconv.Itoa(int(job.GetPriority())),
+			job.GetCreatedAt().AsTime().Format(time.RFC3339),
+			job.GetError(),
+		})
+	}
+
+	table.Render()
+
+	return nil
+}
@@ -0,0 +1,156 @@
+//go:build !gitaly_test_sha256
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/service/info"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockReplicationJobManager struct {
+	listJobs       func(context.Context, datastore.ReplicationJobFilter) ([]datastore.ReplicationEvent, error)
+	cancelJobs     func(context.Context, []uint64) ([]uint64, error)
+	prioritizeJobs func(context.Context, []uint64, int) ([]uint64, error)
+}
+
+func (m mockReplicationJobManager) ListJobs(ctx context.Context, filter datastore.ReplicationJobFilter) ([]datastore.ReplicationEvent, error) {
+	return m.listJobs(ctx, filter)
+}
+
+func (m mockReplicationJobManager) CancelJobs(ctx context.Context, ids []uint64) ([]uint64, error) {
+	return m.cancelJobs(ctx, ids)
+}
+
+func (m mockReplicationJobManager) PrioritizeJobs(ctx context.Context, ids []uint64, priority int) ([]uint64, error) {
+	return m.prioritizeJobs(ctx, ids, priority)
+}
+
+func TestListReplicationJobsSubcommand(t *testing.T) {
+	t.Parallel()
+
+	conf := config.Config{
+		VirtualStorages: []*config.VirtualStorage{
+			{
+				Name:  "virtual-storage",
+				Nodes: []*config.Node{{Storage: "gitaly-1"}, {Storage: "gitaly-2"}},
+			},
+		},
+	}
+
+	createdAt := time.Date(2022, 6, 20, 10, 15, 32, 0, time.UTC)
+	jobs := []datastore.ReplicationEvent{
+		{
+			ID:        1,
+			State:     datastore.JobStateFailed,
+			Attempt:   2,
+			CreatedAt: createdAt,
+			Job: datastore.ReplicationJob{
+				Change:            datastore.UpdateRepo,
+				VirtualStorage:    "virtual-storage",
+				RelativePath:      "repository-1",
+				SourceNodeStorage: "gitaly-1",
+				TargetNodeStorage: "gitaly-2",
+			},
+			Error: "replication failed",
+		},
+		{
+			ID:        2,
+			State:     datastore.JobStateReady,
+			Attempt:   3,
+			Priority:  10,
+			CreatedAt: createdAt,
+			Job: datastore.ReplicationJob{
+				Change:            datastore.DeleteReplica,
+				VirtualStorage:    "virtual-storage",
+				RelativePath:      "repository-2",
+				TargetNodeStorage: "gitaly-1",
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc           string
+		args           []string
+		jobs           []datastore.ReplicationEvent
+		expectedFilter datastore.ReplicationJobFilter
+		error          error
+		stdout         string
+	}{
+		{
+			desc:  "unexpected positional arguments",
+			args:  []string{"positonal-arg"},
+			error: unexpectedPositionalArgsError{Command: "list-replication-jobs"},
+		},
+		{
+			desc:  "virtual storage not found",
+			args:  []string{"-virtual-storage=non-existent"},
+			error: status.Error(codes.InvalidArgument, `unknown virtual storage: "non-existent"`),
+		},
+		{
+			desc:  "invalid state",
+			args:  []string{"-state=completed"},
+			error: status.Error(codes.InvalidArgument, `invalid state: "completed"`),
+		},
+		{
+			desc:           "no jobs",
+			args:           []string{},
+			expectedFilter: datastore.ReplicationJobFilter{Limit: 100},
+			stdout:         "no replication jobs found\n",
+		},
+		{
+			desc: "filtered jobs",
+			args: []string{
+				"-virtual-storage=virtual-storage",
+				"-target-storage=gitaly-2",
+				"-repository=repository-1",
+				"-state=ready, failed",
+				"-limit=0",
+			},
+			jobs: jobs,
+			expectedFilter: datastore.ReplicationJobFilter{
+				VirtualStorage: "virtual-storage",
+				TargetStorage:  "gitaly-2",
+				RelativePath:   "repository-1",
+				States:         []datastore.JobState{datastore.JobStateReady, datastore.JobStateFailed},
+			},
+			stdout: "ID\tSTATE \tCHANGE        \tVIRTUAL_STORAGE\tREPOSITORY  \tSOURCE  \tTARGET  \tATTEMPTS_LEFT\tPRIORITY\tCREATED_AT          \tERROR              \n" +
+				"1 \tfailed\tupdate        \tvirtual-storage\trepository-1\tgitaly-1\tgitaly-2\t2            \t0       \t2022-06-20T10:15:32Z\treplication failed\t\n" +
+				"2 \tready \tdelete_replica\tvirtual-storage\trepository-2\t        \tgitaly-1\t3            \t10      \t2022-06-20T10:15:32Z\t                  \t\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ln, clean := listenAndServe(t, []svcRegistrar{registerPraefectInfoServer(
+				info.NewServer(conf, nil, nil, nil, nil, mockReplicationJobManager{
+					listJobs: func(_ context.Context, filter datastore.ReplicationJobFilter) ([]datastore.ReplicationEvent, error) {
+						require.Equal(t, tc.expectedFilter, filter)
+						return tc.jobs, nil
+					},
+				}),
+			)})
+			defer clean()
+
+			stdout := &bytes.Buffer{}
+			cmd := newListReplicationJobsSubcommand(stdout)
+			fs := cmd.FlagSet()
+			require.NoError(t, fs.Parse(tc.args))
+			err := cmd.Exec(fs, config.Config{
+				SocketPath: ln.Addr().String(),
+			})
+			testhelper.RequireGrpcError(t, tc.error, err)
+			require.Equal(t, tc.stdout, stdout.String())
+		})
+	}
+}
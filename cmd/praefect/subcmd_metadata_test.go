@@ -42,7 +42,7 @@ func TestMetadataSubcommand(t *testing.T) {
 	require.NoError(t, err)
 
 	ln, clean := listenAndServe(t, []svcRegistrar{
-		registerPraefectInfoServer(info.NewServer(config.Config{}, rs, nil, nil, nil)),
+		registerPraefectInfoServer(info.NewServer(config.Config{}, rs, nil, nil, nil, nil)),
 	})
 	defer clean()
 
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+const prioritizeReplicationJobsCmdName = "prioritize-replication-jobs"
+
+type prioritizeReplicationJobsSubcommand struct {
+	stdout   io.Writer
+	ids      string
+	priority int
+}
+
+func newPrioritizeReplicationJobsSubcommand(stdout io.Writer) *prioritizeReplicationJobsSubcommand {
+	return &prioritizeReplicationJobsSubcommand{stdout: stdout}
+}
+
+func (cmd *prioritizeReplicationJobsSubcommand) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(prioritizeReplicationJobsCmdName, flag.ContinueOnError)
+	fs.StringVar(&cmd.ids, paramJobIDs, "", "comma separated list of the IDs of the jobs to prioritize")
+	fs.IntVar(&cmd.priority, "priority", 1, "priority of the jobs, jobs are created with a priority of 0")
+	fs.Usage = func() {
+		printfErr("Description:\n" +
+			"	This command sets the priority of replication jobs which are waiting to be processed.\n" +
+			"	Jobs with a higher priority are processed before jobs with a lower priority, and jobs\n" +
+			"	with the same priority are processed oldest first.\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (cmd *prioritizeReplicationJobsSubcommand) Exec(flags *flag.FlagSet, cfg config.Config) error {
+	if flags.NArg() > 0 {
+		return unexpectedPositionalArgsError{Command: flags.Name()}
+	} else if cmd.ids == "" {
+		return requiredParameterError(paramJobIDs)
+	}
+
+	ids, err := parseJobIDs(cmd.ids)
+	if err != nil {
+		return err
+	}
+
+	nodeAddr, err := getNodeAddress(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	conn, err := subCmdDial(ctx, nodeAddr, cfg.Auth.Token, defaultDialTimeout)
+	if err != nil {
+		return fmt.Errorf("error dialing: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := gitalypb.NewPraefectInfoServiceClient(conn).PrioritizeReplicationJobs(ctx, &gitalypb.PrioritizeReplicationJobsRequest{
+		Ids:      ids,
+		Priority: int32(cmd.priority),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.stdout, "prioritized jobs: %s\n", formatJobIDs(resp.GetPrioritizedIds()))
+	return nil
+}
@@ -0,0 +1,81 @@
+//go:build !gitaly_test_sha256
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/service/info"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+)
+
+func TestPrioritizeReplicationJobsSubcommand(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc             string
+		args             []string
+		expectedIDs      []uint64
+		expectedPriority int
+		prioritized      []uint64
+		error            error
+		stdout           string
+	}{
+		{
+			desc:  "unexpected positional arguments",
+			args:  []string{"positonal-arg"},
+			error: unexpectedPositionalArgsError{Command: "prioritize-replication-jobs"},
+		},
+		{
+			desc:  "missing ids",
+			args:  []string{},
+			error: requiredParameterError("ids"),
+		},
+		{
+			desc:             "default priority",
+			args:             []string{"-ids=1,2"},
+			expectedIDs:      []uint64{1, 2},
+			expectedPriority: 1,
+			prioritized:      []uint64{2},
+			stdout:           "prioritized jobs: 2\n",
+		},
+		{
+			desc:             "explicit priority",
+			args:             []string{"-ids=1,2", "-priority=-5"},
+			expectedIDs:      []uint64{1, 2},
+			expectedPriority: -5,
+			prioritized:      []uint64{1, 2},
+			stdout:           "prioritized jobs: 1, 2\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ln, clean := listenAndServe(t, []svcRegistrar{registerPraefectInfoServer(
+				info.NewServer(config.Config{}, nil, nil, nil, nil, mockReplicationJobManager{
+					prioritizeJobs: func(_ context.Context, ids []uint64, priority int) ([]uint64, error) {
+						require.Equal(t, tc.expectedIDs, ids)
+						require.Equal(t, tc.expectedPriority, priority)
+						return tc.prioritized, nil
+					},
+				}),
+			)})
+			defer clean()
+
+			stdout := &bytes.Buffer{}
+			cmd := newPrioritizeReplicationJobsSubcommand(stdout)
+			fs := cmd.FlagSet()
+			require.NoError(t, fs.Parse(tc.args))
+			err := cmd.Exec(fs, config.Config{
+				SocketPath: ln.Addr().String(),
+			})
+			testhelper.RequireGrpcError(t, tc.error, err)
+			require.Equal(t, tc.stdout, stdout.String())
+		})
+	}
+}
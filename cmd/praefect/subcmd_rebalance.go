@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore/glsql"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/rebalancer"
+	"gitlab.com/gitlab-org/labkit/correlation"
+	"google.golang.org/grpc/metadata"
+)
+
+const rebalanceCmdName = "rebalance"
+
+type rebalanceSubcommand struct {
+	logger logrus.FieldLogger
+	stdout io.Writer
+	dryRun bool
+}
+
+func newRebalanceSubcommand(logger logrus.FieldLogger, stdout io.Writer) *rebalanceSubcommand {
+	return &rebalanceSubcommand{logger: logger, stdout: stdout}
+}
+
+func (cmd *rebalanceSubcommand) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(rebalanceCmdName, flag.ContinueOnError)
+	fs.BoolVar(&cmd.dryRun, "dry-run", false, "print the planned moves without applying them")
+	fs.Usage = func() {
+		printfErr("Description:\n" +
+			"	This command moves replicas from the fullest storages of each virtual storage to the\n" +
+			"	emptiest ones based on their disk usage. The number of moves and the usage threshold\n" +
+			"	are taken from the rebalancing section of the configuration. The replicas are moved by\n" +
+			"	the reconciler after their assignments have been changed.\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (cmd *rebalanceSubcommand) Exec(flags *flag.FlagSet, cfg config.Config) error {
+	if flags.NArg() > 0 {
+		return unexpectedPositionalArgsError{Command: flags.Name()}
+	}
+
+	ctx := correlation.ContextWithCorrelation(context.Background(), correlation.SafeRandomID())
+	ctx = metadata.AppendToOutgoingContext(ctx, "client_name", rebalanceCmdName)
+
+	nodeSet, err := dialGitalyStorages(ctx, cfg, defaultDialTimeout)
+	if err != nil {
+		return fmt.Errorf("dial nodes: %w", err)
+	}
+	defer nodeSet.Close()
+
+	openDBCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	db, err := glsql.OpenDB(openDBCtx, cfg.DB)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	rb := rebalancer.NewRebalancer(
+		cmd.logger,
+		db,
+		praefect.StaticHealthChecker(cfg.StorageNames()),
+		rebalancer.NewNodeStatistics(nodeSet.Connections()),
+		cfg.Rebalancing,
+		cfg.FailureDomains(),
+	)
+
+	moves, err := rb.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("plan moves: %w", err)
+	}
+
+	verb := "would move"
+	if !cmd.dryRun {
+		verb = "moving"
+		if moves, err = rb.Apply(ctx, moves); err != nil {
+			return fmt.Errorf("apply moves: %w", err)
+		}
+	}
+
+	if len(moves) == 0 {
+		fmt.Fprintln(cmd.stdout, "no replicas to move")
+		return nil
+	}
+
+	for _, move := range moves {
+		fmt.Fprintf(cmd.stdout, "%s %q/%q (%d bytes) from %q to %q\n",
+			verb, move.VirtualStorage, move.RelativePath, move.Size, move.SourceStorage, move.TargetStorage)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build !gitaly_test_sha256
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+)
+
+func TestRebalanceSubcommand(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc  string
+		args  []string
+		error error
+	}{
+		{
+			desc:  "unexpected positional arguments",
+			args:  []string{"positional-arg"},
+			error: unexpectedPositionalArgsError{Command: "rebalance"},
+		},
+		{
+			desc:  "no gitaly nodes",
+			args:  []string{"-dry-run"},
+			error: errNoConnectionToGitalies,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			cmd := newRebalanceSubcommand(testhelper.NewDiscardingLogEntry(t), stdout)
+			fs := cmd.FlagSet()
+			require.NoError(t, fs.Parse(tc.args))
+			require.ErrorIs(t, cmd.Exec(fs, config.Config{}), tc.error)
+			require.Empty(t, stdout.String())
+		})
+	}
+}
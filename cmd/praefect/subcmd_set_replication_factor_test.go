@@ -87,7 +87,7 @@ func TestSetReplicationFactorSubcommand(t *testing.T) {
 
 			store := tc.store
 			if tc.store == nil {
-				store = datastore.NewAssignmentStore(db, map[string][]string{"virtual-storage": {"primary", "secondary"}})
+				store = datastore.NewAssignmentStore(db, map[string][]string{"virtual-storage": {"primary", "secondary"}}, nil)
 			}
 
 			// create a repository record
@@ -96,7 +96,7 @@ func TestSetReplicationFactorSubcommand(t *testing.T) {
 			)
 
 			ln, clean := listenAndServe(t, []svcRegistrar{registerPraefectInfoServer(
-				info.NewServer(config.Config{}, nil, store, nil, nil),
+				info.NewServer(config.Config{}, nil, store, nil, nil, nil),
 			)})
 			defer clean()
 
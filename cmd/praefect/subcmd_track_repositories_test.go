@@ -326,7 +326,7 @@ func TestAddRepositories_Exec(t *testing.T) {
 				require.NoError(t, addRepoCmd.Exec(flag.NewFlagSet("", flag.PanicOnError), conf))
 				assert.Contains(t, stdout.String(), tc.expectedOutput)
 
-				as := datastore.NewAssignmentStore(db, conf.StorageNames())
+				as := datastore.NewAssignmentStore(db, conf.StorageNames(), nil)
 
 				for _, path := range tc.relativePaths {
 					repositoryID, err := repoDS.GetRepositoryID(ctx, virtualStorageName, path)
@@ -191,7 +191,7 @@ func TestAddRepository_Exec(t *testing.T) {
 				}
 
 				require.NoError(t, addRepoCmd.Exec(flag.NewFlagSet("", flag.PanicOnError), conf))
-				as := datastore.NewAssignmentStore(db, conf.StorageNames())
+				as := datastore.NewAssignmentStore(db, conf.StorageNames(), nil)
 
 				repositoryID, err := repoDS.GetRepositoryID(ctx, virtualStorageName, tc.relativePath)
 				require.NoError(t, err)
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"gitlab.com/gitlab-org/gitaly/v15/client"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
@@ -28,6 +30,9 @@ var (
 	ErrSkipped = errors.New("repository skipped")
 	// ErrDoesntExist means that the data was not found.
 	ErrDoesntExist = errors.New("doesn't exist")
+	// ErrRefsExist means that selected refs weren't restored because refs
+	// with the same names already exist in the repository.
+	ErrRefsExist = errors.New("refs already exist")
 )
 
 // errEmptyBundle means that the requested bundle contained nothing
@@ -40,10 +45,18 @@ type Sink interface {
 	// GetReader returns a reader that servers the data stored by relativePath.
 	// If relativePath doesn't exists the ErrDoesntExist will be returned.
 	GetReader(ctx context.Context, relativePath string) (io.ReadCloser, error)
+	// List returns the relative paths of all files stored below prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Delete removes the data stored by relativePath.
+	// If relativePath doesn't exists the ErrDoesntExist will be returned.
+	Delete(ctx context.Context, relativePath string) error
 }
 
 // Backup represents all the information needed to restore a backup for a repository
 type Backup struct {
+	// ID is the identifier of the backup. It is empty for backups which
+	// can't be identified, like legacy backups.
+	ID string
 	// Steps are the ordered list of steps required to restore this backup
 	Steps []Step
 }
@@ -51,17 +64,39 @@ type Backup struct {
 // Step represents an incremental step that makes up a complete backup for a repository
 type Step struct {
 	// BundlePath is the path of the bundle
-	BundlePath string
+	BundlePath string `toml:"bundle_path"`
 	// SkippableOnNotFound defines if the bundle can be skipped when it does
 	// not exist. This allows us to maintain legacy behaviour where we always
 	// check a specific location for a bundle without knowing if it exists.
-	SkippableOnNotFound bool
+	SkippableOnNotFound bool `toml:"-"`
 	// RefPath is the path of the ref file
-	RefPath string
+	RefPath string `toml:"ref_path"`
 	// PreviousRefPath is the path of the previous ref file
-	PreviousRefPath string
+	PreviousRefPath string `toml:"previous_ref_path,omitempty"`
 	// CustomHooksPath is the path of the custom hooks archive
-	CustomHooksPath string
+	CustomHooksPath string `toml:"custom_hooks_path"`
+	// ObjectFormat is the object format of the repository at the time the
+	// step was created. It is empty for backups that have been created
+	// without a manifest.
+	ObjectFormat string `toml:"object_format,omitempty"`
+	// BundleChecksum is the hex-encoded SHA256 checksum of the bundle.
+	BundleChecksum string `toml:"bundle_checksum,omitempty"`
+	// RefChecksum is the hex-encoded SHA256 checksum of the ref file.
+	RefChecksum string `toml:"ref_checksum,omitempty"`
+	// CustomHooksChecksum is the hex-encoded SHA256 checksum of the custom
+	// hooks archive. It is empty if the repository has no custom hooks.
+	CustomHooksChecksum string `toml:"custom_hooks_checksum,omitempty"`
+	// ObjectPoolRelativePath is the relative path of the object pool the
+	// repository was linked to. It is empty if the backup has been created
+	// without object pools or if the repository isn't linked to a pool.
+	ObjectPoolRelativePath string `toml:"object_pool_relative_path,omitempty"`
+	// ObjectPoolRefPath is the path of the ref file of the object pool
+	// backup. Objects reachable from these refs are not part of the bundle.
+	ObjectPoolRefPath string `toml:"object_pool_ref_path,omitempty"`
+	// Timestamp is the time at which the step was created. It is the zero
+	// time for steps that have been created without a manifest or before
+	// timestamps were recorded.
+	Timestamp time.Time `toml:"timestamp"`
 }
 
 // Locator finds sink backup paths for repositories
@@ -77,6 +112,14 @@ type Locator interface {
 
 	// FindLatest returns the latest backup that was written by Commit
 	FindLatest(ctx context.Context, repo *gitalypb.Repository) (*Backup, error)
+
+	// Find returns the backup with the given ID that was written by Commit
+	Find(ctx context.Context, repo *gitalypb.Repository, backupID string) (*Backup, error)
+
+	// FindAt returns the backup as it was at the given time, that is the
+	// latest backup with only the steps that had been written by Commit at
+	// that time.
+	FindAt(ctx context.Context, repo *gitalypb.Repository, timestamp time.Time) (*Backup, error)
 }
 
 // ResolveSink returns a sink implementation based on the provided path.
@@ -118,6 +161,22 @@ func ResolveLocator(layout string, sink Sink) (Locator, error) {
 	}
 }
 
+// ResolveKeyProvider returns the key provider used to encrypt backups based on
+// either a key file or a keyring directory. It returns nil if neither has been
+// given, in which case backups are not encrypted.
+func ResolveKeyProvider(keyFile, keyring string) (KeyProvider, error) {
+	switch {
+	case keyFile != "" && keyring != "":
+		return nil, errors.New("key file and keyring are mutually exclusive")
+	case keyFile != "":
+		return NewKeyFileProvider(keyFile)
+	case keyring != "":
+		return NewKeyringProvider(keyring)
+	default:
+		return nil, nil
+	}
+}
+
 // Manager manages process of the creating/restoring backups.
 type Manager struct {
 	sink    Sink
@@ -128,23 +187,60 @@ type Manager struct {
 	// once. We may use this to make it easier to specify a backup to restore
 	// from, rather than always selecting the latest.
 	backupID string
+
+	// skipValidation disables verifying the artifacts of a backup before
+	// it's restored. See WithoutValidation.
+	skipValidation bool
+
+	// objectPools enables backups which deduplicate objects of object pool
+	// members. See WithObjectPools.
+	objectPools        bool
+	objectPoolsMu      sync.Mutex
+	objectPoolBackups  map[string]*objectPoolOnce
+	objectPoolRestores map[string]*objectPoolRestore
 }
 
+// ManagerOption configures a Manager.
+type ManagerOption func(*Manager)
+
 // NewManager creates and returns initialized *Manager instance.
-func NewManager(sink Sink, locator Locator, pool *client.Pool, backupID string) *Manager {
-	return &Manager{
-		sink:     sink,
-		conns:    pool,
-		locator:  locator,
-		backupID: backupID,
+func NewManager(sink Sink, locator Locator, pool *client.Pool, backupID string, opts ...ManagerOption) *Manager {
+	mgr := &Manager{
+		sink:               sink,
+		conns:              pool,
+		locator:            locator,
+		backupID:           backupID,
+		objectPoolBackups:  make(map[string]*objectPoolOnce),
+		objectPoolRestores: make(map[string]*objectPoolRestore),
+	}
+
+	for _, opt := range opts {
+		opt(mgr)
+	}
+
+	return mgr
+}
+
+// WithoutValidation makes Restore skip verifying the artifacts of a backup
+// against their checksums before the target repository is touched, so that
+// every artifact is only read from the sink once. Bundles are still verified
+// while they're restored, but a corrupt backup then fails the restore after
+// the repository has already been replaced.
+func WithoutValidation() ManagerOption {
+	return func(mgr *Manager) {
+		mgr.skipValidation = true
 	}
 }
 
 // CreateRequest is the request to create a backup
 type CreateRequest struct {
-	Server      storage.ServerInfo
-	Repository  *gitalypb.Repository
-	Incremental bool
+	Server     storage.ServerInfo
+	Repository *gitalypb.Repository
+	// VanityRepository is used to determine the backup path. It defaults to
+	// Repository, but differs from it when the repository is accessed through
+	// a rewritten relative path, like on a Praefect replica.
+	VanityRepository *gitalypb.Repository
+	Incremental      bool
 }
 
 // Create creates a repository backup.
@@ -155,30 +251,25 @@ func (mgr *Manager) Create(ctx context.Context, req *CreateRequest) error {
 		return fmt.Errorf("manager: repository empty: %w", ErrSkipped)
 	}
 
-	var step *Step
-	if req.Incremental {
-		var err error
-		step, err = mgr.locator.BeginIncremental(ctx, req.Repository, mgr.backupID)
-		if err != nil {
+	step, err := mgr.beginStep(ctx, vanityRepository(req.Repository, req.VanityRepository), req.Incremental)
+	if err != nil {
+		return fmt.Errorf("manager: %w", err)
+	}
+
+	if mgr.objectPools {
+		if err := mgr.createObjectPool(ctx, req.Server, req.Repository, req.Incremental, step); err != nil {
 			return fmt.Errorf("manager: %w", err)
 		}
-	} else {
-		step = mgr.locator.BeginFull(ctx, req.Repository, mgr.backupID)
 	}
 
 	refs, err := mgr.listRefs(ctx, req.Server, req.Repository)
 	if err != nil {
 		return fmt.Errorf("manager: %w", err)
 	}
-	if err := mgr.writeRefs(ctx, step.RefPath, refs); err != nil {
+
+	if err := mgr.writeStep(ctx, step, req.Server, req.Repository, refs); err != nil {
 		return fmt.Errorf("manager: %w", err)
 	}
-	if err := mgr.writeBundle(ctx, step, req.Server, req.Repository, refs); err != nil {
-		return fmt.Errorf("manager: write bundle: %w", err)
-	}
-	if err := mgr.writeCustomHooks(ctx, step.CustomHooksPath, req.Server, req.Repository); err != nil {
-		return fmt.Errorf("manager: write custom hooks: %w", err)
-	}
 
 	if err := mgr.locator.Commit(ctx, step); err != nil {
 		return fmt.Errorf("manager: %w", err)
@@ -187,30 +278,129 @@ func (mgr *Manager) Create(ctx context.Context, req *CreateRequest) error {
 	return nil
 }
 
+func (mgr *Manager) beginStep(ctx context.Context, repo *gitalypb.Repository, incremental bool) (*Step, error) {
+	if incremental {
+		return mgr.locator.BeginIncremental(ctx, repo, mgr.backupID)
+	}
+	return mgr.locator.BeginFull(ctx, repo, mgr.backupID), nil
+}
+
+// writeStep writes the refs, bundle and custom hooks of the repository for the
+// given step.
+func (mgr *Manager) writeStep(ctx context.Context, step *Step, server storage.ServerInfo, repo *gitalypb.Repository, refs []*gitalypb.ListRefsResponse_Reference) error {
+	// The repository isn't empty, so there is at least one reference we can derive the
+	// object format from.
+	objectHash, err := git.ObjectHashByHex(refs[0].GetTarget())
+	if err != nil {
+		return fmt.Errorf("detecting object hash: %w", err)
+	}
+	step.ObjectFormat = objectHash.Format
+	// Steps are located with a precision of seconds, like backup IDs.
+	step.Timestamp = time.Now().UTC().Truncate(time.Second)
+
+	step.RefChecksum, err = mgr.writeRefs(ctx, step.RefPath, refs)
+	if err != nil {
+		return err
+	}
+	if err := mgr.writeBundle(ctx, step, server, repo, refs); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	step.CustomHooksChecksum, err = mgr.writeCustomHooks(ctx, step.CustomHooksPath, server, repo)
+	if err != nil {
+		return fmt.Errorf("write custom hooks: %w", err)
+	}
+
+	return nil
+}
+
 // RestoreRequest is the request to restore from a backup
 type RestoreRequest struct {
-	Server       storage.ServerInfo
-	Repository   *gitalypb.Repository
+	Server     storage.ServerInfo
+	Repository *gitalypb.Repository
+	// VanityRepository is used to determine the backup path. It defaults to
+	// Repository, but differs from it when the repository is accessed through
+	// a rewritten relative path, like on a Praefect replica.
+	VanityRepository *gitalypb.Repository
+	// BackupID is the backup to restore. The latest backup is restored if
+	// empty.
+	BackupID string
+	// Timestamp is the point in time to restore the repository to. Only the
+	// steps of the latest backup which have been created at or before
+	// Timestamp are restored. It is mutually exclusive with BackupID.
+	Timestamp    time.Time
 	AlwaysCreate bool
+	// Refs selects refs which are restored into the existing repository
+	// instead of replacing the whole repository. Selectors ending with a
+	// slash select all refs with that prefix.
+	Refs []string
+	// RefNamespace is the namespace selected refs are restored into, for
+	// example `refs/restored`. The refs are restored under their original
+	// names if it is empty.
+	RefNamespace string
+	// OverwriteRefs allows selected refs to overwrite existing refs with the
+	// same names. Restoring selected refs fails with ErrRefsExist otherwise.
+	OverwriteRefs bool
 }
 
-// Restore restores a repository from a backup.
+// Restore restores a repository from a backup. The steps of the backup are
+// checked to form a consistent chain and all of their artifacts are verified
+// against their checksums before the target repository is touched, so that a
+// corrupt backup doesn't leave the repository half-restored. Verifying the
+// artifacts can be disabled with WithoutValidation.
+//
+// Once all steps have been applied, the refs of the repository are reset to
+// the refs recorded by the last step. Bundles of incremental steps don't
+// record deleted refs, nor refs which have been moved back to previously
+// backed up commits, so the repository would otherwise not match the state of
+// the last step.
+//
+// If refs are selected by the request, only these refs are restored into the
+// existing repository. See restoreSelectedRefs.
 func (mgr *Manager) Restore(ctx context.Context, req *RestoreRequest) error {
-	if err := mgr.removeRepository(ctx, req.Server, req.Repository); err != nil {
+	var backup *Backup
+	var err error
+	repo := vanityRepository(req.Repository, req.VanityRepository)
+	switch {
+	case !req.Timestamp.IsZero() && req.BackupID != "":
+		return errors.New("manager: backup ID and timestamp are mutually exclusive")
+	case !req.Timestamp.IsZero():
+		backup, err = mgr.locator.FindAt(ctx, repo, req.Timestamp)
+	default:
+		backup, err = mgr.findBackup(ctx, repo, req.BackupID)
+	}
+	if err != nil {
 		return fmt.Errorf("manager: %w", err)
 	}
 
-	backup, err := mgr.locator.FindLatest(ctx, req.Repository)
+	objectFormat, err := mgr.validateRestore(ctx, backup)
 	if err != nil {
 		return fmt.Errorf("manager: %w", err)
 	}
 
-	if err := mgr.createRepository(ctx, req.Server, req.Repository); err != nil {
+	if len(req.Refs) > 0 {
+		if err := mgr.restoreSelectedRefs(ctx, req, backup, objectFormat); err != nil {
+			return fmt.Errorf("manager: %w", err)
+		}
+		return nil
+	}
+
+	if err := mgr.removeRepository(ctx, req.Server, req.Repository); err != nil {
 		return fmt.Errorf("manager: %w", err)
 	}
 
-	for _, step := range backup.Steps {
-		if err := mgr.restoreBundle(ctx, step.BundlePath, req.Server, req.Repository); err != nil {
+	if err := mgr.createRepository(ctx, req.Server, req.Repository, objectFormat); err != nil {
+		return fmt.Errorf("manager: %w", err)
+	}
+
+	objectPool := backup.objectPool()
+	if objectPool != "" {
+		if err := mgr.restoreObjectPool(ctx, req.Server, req.Repository, objectPool, backup); err != nil {
+			return fmt.Errorf("manager: %w", err)
+		}
+	}
+
+	for i, step := range backup.Steps {
+		if err := mgr.restoreBundle(ctx, step.BundlePath, step.BundleChecksum, req.Server, req.Repository); err != nil {
 			if step.SkippableOnNotFound && errors.Is(err, ErrDoesntExist) {
 				// For compatibility with existing backups we need to make sure the
 				// repository exists even if there's no bundle for project
@@ -228,11 +418,148 @@ func (mgr *Manager) Restore(ctx context.Context, req *RestoreRequest) error {
 
 				return fmt.Errorf("manager: %w: %s", ErrSkipped, err.Error())
 			}
+			return fmt.Errorf("manager: step %d: %w", i+1, err)
+		}
+		if objectPool != "" {
+			// Bundles don't contain refs which point to objects of the
+			// object pool, so the refs are restored from the ref file.
+			if err := mgr.restoreRefs(ctx, step.RefPath, req.Server, req.Repository); err != nil {
+				return fmt.Errorf("manager: %w", err)
+			}
 		}
 		if err := mgr.restoreCustomHooks(ctx, step.CustomHooksPath, req.Server, req.Repository); err != nil {
 			return fmt.Errorf("manager: %w", err)
 		}
 	}
+
+	if len(backup.Steps) > 1 {
+		if err := mgr.resetRefs(ctx, backup.Steps[len(backup.Steps)-1].RefPath, req.Server, req.Repository); err != nil {
+			return fmt.Errorf("manager: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// vanityRepository returns the repository used to determine the backup path.
+func vanityRepository(repo, vanityRepo *gitalypb.Repository) *gitalypb.Repository {
+	if vanityRepo != nil {
+		return vanityRepo
+	}
+	return repo
+}
+
+// validateBackup verifies that the steps of the backup form a consistent chain
+// and that all of their artifacts exist in the sink and match their recorded
+// checksums. It returns the object format recorded for the backup, which is
+// unspecified for backups without a manifest.
+func (mgr *Manager) validateBackup(ctx context.Context, backup *Backup) (gitalypb.ObjectFormat, error) {
+	objectFormat, err := validateChain(backup)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, step := range backup.Steps {
+		if err := mgr.validateStep(ctx, step); err != nil {
+			return 0, fmt.Errorf("validate: step %d: %w", i+1, err)
+		}
+	}
+
+	return objectFormat, nil
+}
+
+// validateRestore validates the backup before it's restored. The artifacts of
+// the backup aren't verified if validation has been disabled with
+// WithoutValidation.
+func (mgr *Manager) validateRestore(ctx context.Context, backup *Backup) (gitalypb.ObjectFormat, error) {
+	if mgr.skipValidation {
+		return validateChain(backup)
+	}
+	return mgr.validateBackup(ctx, backup)
+}
+
+// validateChain verifies that the steps of the backup form a consistent chain
+// without reading any of their artifacts. It returns the object format
+// recorded for the backup, which is unspecified for backups without a
+// manifest.
+func validateChain(backup *Backup) (gitalypb.ObjectFormat, error) {
+	objectFormat := ""
+	objectPool := ""
+
+	for i, step := range backup.Steps {
+		if i > 0 && step.PreviousRefPath != backup.Steps[i-1].RefPath {
+			return 0, fmt.Errorf("validate: step %d: %w: previous ref path %q doesn't match %q",
+				i+1, ErrCorrupted, step.PreviousRefPath, backup.Steps[i-1].RefPath)
+		}
+
+		if step.ObjectFormat != "" {
+			if objectFormat != "" && step.ObjectFormat != objectFormat {
+				return 0, fmt.Errorf("validate: step %d: %w: object format %q doesn't match %q",
+					i+1, ErrCorrupted, step.ObjectFormat, objectFormat)
+			}
+			objectFormat = step.ObjectFormat
+		}
+
+		if step.ObjectPoolRelativePath != "" {
+			if objectPool != "" && step.ObjectPoolRelativePath != objectPool {
+				return 0, fmt.Errorf("validate: step %d: %w: object pool %q doesn't match %q",
+					i+1, ErrCorrupted, step.ObjectPoolRelativePath, objectPool)
+			}
+			objectPool = step.ObjectPoolRelativePath
+		}
+	}
+
+	if objectFormat == "" {
+		return gitalypb.ObjectFormat_OBJECT_FORMAT_UNSPECIFIED, nil
+	}
+
+	objectHash, err := git.ObjectHashByFormat(objectFormat)
+	if err != nil {
+		return 0, fmt.Errorf("validate: %w", err)
+	}
+
+	return objectHash.ProtoFormat, nil
+}
+
+// validateStep verifies the artifacts of a single step. Artifacts without a
+// recorded checksum can't be verified and are only checked for existence if
+// they are required.
+func (mgr *Manager) validateStep(ctx context.Context, step Step) error {
+	for _, artifact := range []struct {
+		name     string
+		path     string
+		checksum string
+		required bool
+	}{
+		{name: "bundle", path: step.BundlePath, checksum: step.BundleChecksum, required: !step.SkippableOnNotFound},
+		{name: "refs", path: step.RefPath, checksum: step.RefChecksum},
+		{name: "custom hooks", path: step.CustomHooksPath, checksum: step.CustomHooksChecksum},
+	} {
+		if artifact.path == "" || (artifact.checksum == "" && !artifact.required) {
+			continue
+		}
+
+		reader, err := mgr.sink.GetReader(ctx, artifact.path)
+		if err != nil {
+			if errors.Is(err, ErrDoesntExist) {
+				return fmt.Errorf("%w: %s %q: %s", ErrCorrupted, artifact.name, artifact.path, err.Error())
+			}
+			return fmt.Errorf("%s %q: %w", artifact.name, artifact.path, err)
+		}
+
+		checksumReader := newChecksumReader(reader)
+		_, err = io.Copy(io.Discard, checksumReader)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("%s %q: %w", artifact.name, artifact.path, err)
+		}
+
+		if artifact.checksum != "" && checksumReader.Checksum() != artifact.checksum {
+			return fmt.Errorf("%w: %s %q: checksum mismatch: expected %s, got %s",
+				ErrCorrupted, artifact.name, artifact.path, artifact.checksum, checksumReader.Checksum())
+		}
+	}
+
 	return nil
 }
 
@@ -266,12 +593,15 @@ func (mgr *Manager) removeRepository(ctx context.Context, server storage.ServerI
 	return nil
 }
 
-func (mgr *Manager) createRepository(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository) error {
+func (mgr *Manager) createRepository(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository, objectFormat gitalypb.ObjectFormat) error {
 	repoClient, err := mgr.newRepoClient(ctx, server)
 	if err != nil {
 		return fmt.Errorf("create repository: %w", err)
 	}
-	if _, err := repoClient.CreateRepository(ctx, &gitalypb.CreateRepositoryRequest{Repository: repo}); err != nil {
+	if _, err := repoClient.CreateRepository(ctx, &gitalypb.CreateRepositoryRequest{
+		Repository:   repo,
+		ObjectFormat: objectFormat,
+	}); err != nil {
 		return fmt.Errorf("create repository: %w", err)
 	}
 	return nil
@@ -314,24 +644,77 @@ func (mgr *Manager) writeBundle(ctx context.Context, step *Step, server storage.
 		return resp.GetData(), err
 	})
 
-	if err := LazyWrite(ctx, mgr.sink, step.BundlePath, bundle); err != nil {
+	checksumBundle := newChecksumReader(bundle)
+	if err := LazyWrite(ctx, mgr.sink, step.BundlePath, checksumBundle); err != nil {
 		if errors.Is(err, errEmptyBundle) {
-			return fmt.Errorf("%T write: %w: no changes to bundle", mgr.sink, ErrSkipped)
+			return mgr.handleEmptyBundle(ctx, step)
 		}
 		return fmt.Errorf("%T write: %w", mgr.sink, err)
 	}
+	step.BundleChecksum = checksumBundle.Checksum()
 	return nil
 }
 
+// handleEmptyBundle decides what to do when there were no objects to bundle.
+// Usually this means that nothing changed and the step is skipped. Object pool
+// members may however have changed refs which only point to objects in the
+// object pool, in which case the step is kept without a bundle.
+func (mgr *Manager) handleEmptyBundle(ctx context.Context, step *Step) error {
+	if step.ObjectPoolRefPath != "" {
+		changed, err := mgr.refsChanged(ctx, step)
+		if err != nil {
+			return err
+		}
+		if changed {
+			step.BundlePath = ""
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%T write: %w: no changes to bundle", mgr.sink, ErrSkipped)
+}
+
+// refsChanged returns whether the refs of step differ from the refs of the
+// previous step.
+func (mgr *Manager) refsChanged(ctx context.Context, step *Step) (bool, error) {
+	if step.PreviousRefPath == "" {
+		return true, nil
+	}
+
+	reader, err := mgr.sink.GetReader(ctx, step.PreviousRefPath)
+	if err != nil {
+		return false, fmt.Errorf("refs changed: %w", err)
+	}
+	defer reader.Close()
+
+	previousRefs := newChecksumReader(reader)
+	if _, err := io.Copy(io.Discard, previousRefs); err != nil {
+		return false, fmt.Errorf("refs changed: %w", err)
+	}
+
+	return previousRefs.Checksum() != step.RefChecksum, nil
+}
+
 // sendKnownRefs sends the negated targets of each ref that had previously been
-// backed up. This ensures that git-bundle stops traversing commits once it
-// finds the commits that were previously backed up.
+// backed up, either by the previous step or by the object pool backup. This
+// ensures that git-bundle stops traversing commits once it finds the commits
+// that were previously backed up.
 func (mgr *Manager) sendKnownRefs(ctx context.Context, step *Step, repo *gitalypb.Repository, c *chunk.Chunker) error {
-	if len(step.PreviousRefPath) == 0 {
-		return nil
+	for _, path := range []string{step.PreviousRefPath, step.ObjectPoolRefPath} {
+		if len(path) == 0 {
+			continue
+		}
+
+		if err := mgr.sendNegatedRefs(ctx, path, repo, c); err != nil {
+			return err
+		}
 	}
 
-	reader, err := mgr.sink.GetReader(ctx, step.PreviousRefPath)
+	return nil
+}
+
+func (mgr *Manager) sendNegatedRefs(ctx context.Context, path string, repo *gitalypb.Repository, c *chunk.Chunker) error {
+	reader, err := mgr.sink.GetReader(ctx, path)
 	if err != nil {
 		return err
 	}
@@ -380,7 +763,21 @@ func (s *createBundleFromRefListSender) Send() error {
 	return s.stream.Send(&s.chunk)
 }
 
-func (mgr *Manager) restoreBundle(ctx context.Context, path string, server storage.ServerInfo, repo *gitalypb.Repository) error {
+// restoreBundle fetches the bundle at path into the repository. If a checksum
+// is given, the bundle is verified while it's streamed and the fetch is
+// aborted if it doesn't match.
+func (mgr *Manager) restoreBundle(ctx context.Context, path, checksum string, server storage.ServerInfo, repo *gitalypb.Repository) error {
+	// Steps of object pool members have no bundle if all of their objects
+	// are part of the object pool.
+	if path == "" {
+		return nil
+	}
+
+	// The fetch is only applied once the stream has been closed, so
+	// cancelling the stream discards a bundle which failed verification.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	reader, err := mgr.sink.GetReader(ctx, path)
 	if err != nil {
 		return fmt.Errorf("restore bundle: %w", err)
@@ -407,32 +804,148 @@ func (mgr *Manager) restoreBundle(ctx context.Context, path string, server stora
 
 		return nil
 	})
-	if _, err := io.Copy(bundle, reader); err != nil {
+	checksumReader := newChecksumReader(reader)
+	if _, err := io.Copy(bundle, checksumReader); err != nil {
 		return fmt.Errorf("restore bundle: %q: %w", path, err)
 	}
+	if checksum != "" && checksumReader.Checksum() != checksum {
+		return fmt.Errorf("restore bundle: %w: %q: checksum mismatch: expected %s, got %s",
+			ErrCorrupted, path, checksum, checksumReader.Checksum())
+	}
 	if _, err = stream.CloseAndRecv(); err != nil {
 		return fmt.Errorf("restore bundle: %q: %w", path, err)
 	}
 	return nil
 }
 
-func (mgr *Manager) writeCustomHooks(ctx context.Context, path string, server storage.ServerInfo, repo *gitalypb.Repository) error {
+// restoreRefs updates the refs of the repository to the refs recorded in the
+// ref file at path. HEAD is pointed to the first branch which has the same
+// target as the recorded HEAD.
+func (mgr *Manager) restoreRefs(ctx context.Context, path string, server storage.ServerInfo, repo *gitalypb.Repository) error {
+	reader, err := mgr.sink.GetReader(ctx, path)
+	if err != nil {
+		return fmt.Errorf("restore refs: %w", err)
+	}
+	defer reader.Close()
+
 	repoClient, err := mgr.newRepoClient(ctx, server)
 	if err != nil {
-		return err
+		return fmt.Errorf("restore refs: %q: %w", path, err)
+	}
+
+	var head, defaultBranch string
+
+	d := git.NewShowRefDecoder(reader)
+	for {
+		var ref git.Reference
+
+		if err := d.Decode(&ref); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("restore refs: %q: %w", path, err)
+		}
+
+		if ref.Name == "HEAD" {
+			head = ref.Target
+			continue
+		}
+
+		if _, err := repoClient.WriteRef(ctx, &gitalypb.WriteRefRequest{
+			Repository: repo,
+			Ref:        []byte(ref.Name),
+			Revision:   []byte(ref.Target),
+		}); err != nil {
+			return fmt.Errorf("restore refs: %q: %w", path, err)
+		}
+
+		if defaultBranch == "" && head != "" && ref.Target == head && strings.HasPrefix(ref.Name.String(), "refs/heads/") {
+			defaultBranch = ref.Name.String()
+		}
+	}
+
+	if defaultBranch == "" {
+		return nil
+	}
+
+	if _, err := repoClient.WriteRef(ctx, &gitalypb.WriteRefRequest{
+		Repository: repo,
+		Ref:        []byte("HEAD"),
+		Revision:   []byte(defaultBranch),
+	}); err != nil {
+		return fmt.Errorf("restore refs: %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// resetRefs makes the refs of the repository match the refs recorded in the
+// ref file at path exactly. Refs which haven't been recorded are deleted
+// before all refs are restored from the ref file. Backups created without a
+// manifest may lack ref files, in which case the refs are left as they are.
+func (mgr *Manager) resetRefs(ctx context.Context, path string, server storage.ServerInfo, repo *gitalypb.Repository) error {
+	reader, err := mgr.sink.GetReader(ctx, path)
+	switch {
+	case errors.Is(err, ErrDoesntExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("reset refs: %w", err)
+	}
+	reader.Close()
+
+	mismatched, unexpected, err := mgr.compareRefs(ctx, path, server, repo)
+	if err != nil {
+		return fmt.Errorf("reset refs: %w", err)
+	}
+
+	if len(unexpected) > 0 {
+		refClient, err := mgr.newRefClient(ctx, server)
+		if err != nil {
+			return fmt.Errorf("reset refs: %w", err)
+		}
+
+		refs := make([][]byte, 0, len(unexpected))
+		for _, ref := range unexpected {
+			refs = append(refs, []byte(ref))
+		}
+
+		if _, err := refClient.DeleteRefs(ctx, &gitalypb.DeleteRefsRequest{
+			Repository: repo,
+			Refs:       refs,
+		}); err != nil {
+			return fmt.Errorf("reset refs: delete refs: %w", err)
+		}
+	}
+
+	// HEAD may have pointed to one of the deleted refs, so it is restored
+	// together with the mismatched refs.
+	if len(mismatched) > 0 || len(unexpected) > 0 {
+		if err := mgr.restoreRefs(ctx, path, server, repo); err != nil {
+			return fmt.Errorf("reset refs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeCustomHooks writes the custom hooks archive and returns its checksum.
+// The checksum is empty if the repository has no custom hooks.
+func (mgr *Manager) writeCustomHooks(ctx context.Context, path string, server storage.ServerInfo, repo *gitalypb.Repository) (string, error) {
+	repoClient, err := mgr.newRepoClient(ctx, server)
+	if err != nil {
+		return "", err
 	}
 	stream, err := repoClient.BackupCustomHooks(ctx, &gitalypb.BackupCustomHooksRequest{Repository: repo})
 	if err != nil {
-		return err
+		return "", err
 	}
-	hooks := streamio.NewReader(func() ([]byte, error) {
+	hooks := newChecksumReader(streamio.NewReader(func() ([]byte, error) {
 		resp, err := stream.Recv()
 		return resp.GetData(), err
-	})
+	}))
 	if err := LazyWrite(ctx, mgr.sink, path, hooks); err != nil {
-		return fmt.Errorf("%T write: %w", mgr.sink, err)
+		return "", fmt.Errorf("%T write: %w", mgr.sink, err)
 	}
-	return nil
+	return hooks.Checksum(), nil
 }
 
 func (mgr *Manager) restoreCustomHooks(ctx context.Context, path string, server storage.ServerInfo, repo *gitalypb.Repository) error {
@@ -506,8 +1019,8 @@ func (mgr *Manager) listRefs(ctx context.Context, server storage.ServerInfo, rep
 }
 
 // writeRefs writes the previously fetched list of refs in the same output
-// format as `git-show-ref(1)`
-func (mgr *Manager) writeRefs(ctx context.Context, path string, refs []*gitalypb.ListRefsResponse_Reference) error {
+// format as `git-show-ref(1)` and returns the checksum of the written data.
+func (mgr *Manager) writeRefs(ctx context.Context, path string, refs []*gitalypb.ListRefsResponse_Reference) (string, error) {
 	r, w := io.Pipe()
 	go func() {
 		var err error
@@ -522,12 +1035,13 @@ func (mgr *Manager) writeRefs(ctx context.Context, path string, refs []*gitalypb
 		}
 	}()
 
-	err := mgr.sink.Write(ctx, path, r)
+	checksumRefs := newChecksumReader(r)
+	err := mgr.sink.Write(ctx, path, checksumRefs)
 	if err != nil {
-		return fmt.Errorf("write refs: %w", err)
+		return "", fmt.Errorf("write refs: %w", err)
 	}
 
-	return nil
+	return checksumRefs.Checksum(), nil
 }
 
 func (mgr *Manager) newRepoClient(ctx context.Context, server storage.ServerInfo) (gitalypb.RepositoryServiceClient, error) {
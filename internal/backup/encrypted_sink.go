@@ -0,0 +1,353 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// encryptionVersion is the version of the encrypted data format.
+	encryptionVersion = 1
+	// encryptionChunkSize is the size of the plaintext chunks that are encrypted
+	// individually. Chunking allows us to stream data while still authenticating every
+	// chunk before it is handed out.
+	encryptionChunkSize = 64 * 1024
+	// encryptionSaltSize is the size of the random salt that is used to derive a unique data
+	// key for every file.
+	encryptionSaltSize = 32
+	// encryptionKeyInfo is the context string used when deriving data keys.
+	encryptionKeyInfo = "gitaly-backup data key"
+)
+
+// encryptionMagic identifies data that has been written by the EncryptedSink.
+var encryptionMagic = []byte("GLBKENC")
+
+// ErrDecryption means that encrypted data could not be decrypted because it has been modified or
+// because it was encrypted with a different key.
+var ErrDecryption = errors.New("decryption failed")
+
+// ErrPlaintext means that data read through an EncryptedSink with keys hasn't been encrypted. As
+// anyone with write access to the sink can replace encrypted files with plaintext ones, such data
+// is only accepted if explicitly allowed with WithPlaintext.
+var ErrPlaintext = errors.New("data is not encrypted")
+
+// EncryptedSink is a sink which transparently encrypts data before it is written to the wrapped
+// sink, and decrypts it when it is read back. Every file is encrypted with a data key derived from
+// the encryption key and a random salt using HKDF-SHA256. The data is split into chunks that are
+// encrypted individually with AES-256-GCM, where the nonce of each chunk encodes its position and
+// whether it is the final chunk. Reordered, truncated or modified data is thus detected.
+//
+// Encrypted files are structured as follows:
+//
+//	"GLBKENC" | version (1 byte) | key ID length (1 byte) | key ID | salt (32 bytes) | chunks...
+//
+// If keys have been provided, data that has not been written by an EncryptedSink is rejected
+// unless WithPlaintext is used, in which case it's read back as is so that existing plaintext
+// backups can still be restored.
+type EncryptedSink struct {
+	sink           Sink
+	keys           KeyProvider
+	allowPlaintext bool
+}
+
+// EncryptedSinkOption sets options on the EncryptedSink.
+type EncryptedSinkOption func(s *EncryptedSink)
+
+// WithPlaintext makes the EncryptedSink read data which hasn't been encrypted as is, even if keys
+// have been provided. This allows restoring backups that were created before encryption had been
+// enabled.
+func WithPlaintext() EncryptedSinkOption {
+	return func(s *EncryptedSink) {
+		s.allowPlaintext = true
+	}
+}
+
+// NewEncryptedSink returns a sink which encrypts all data written to sink with keys provided by
+// keys. If keys is nil, then data is written in plaintext and reading encrypted data fails.
+func NewEncryptedSink(sink Sink, keys KeyProvider, opts ...EncryptedSinkOption) *EncryptedSink {
+	s := &EncryptedSink{
+		sink: sink,
+		keys: keys,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Write encrypts the data from r and writes it to relativePath of the wrapped sink.
+func (s *EncryptedSink) Write(ctx context.Context, relativePath string, r io.Reader) error {
+	if s.keys == nil {
+		return s.sink.Write(ctx, relativePath, r)
+	}
+
+	key, err := s.keys.EncryptionKey(ctx)
+	if err != nil {
+		return fmt.Errorf("encrypted sink: write %q: %w", relativePath, err)
+	}
+
+	if len(key.ID) > 255 {
+		return fmt.Errorf("encrypted sink: write %q: key ID too long", relativePath)
+	}
+
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return fmt.Errorf("encrypted sink: write %q: generate salt: %w", relativePath, err)
+	}
+
+	var header bytes.Buffer
+	header.Write(encryptionMagic)
+	header.WriteByte(encryptionVersion)
+	header.WriteByte(byte(len(key.ID)))
+	header.WriteString(key.ID)
+	header.Write(salt)
+
+	aead, err := newChunkCipher(key.Material, salt)
+	if err != nil {
+		return fmt.Errorf("encrypted sink: write %q: %w", relativePath, err)
+	}
+
+	if err := s.sink.Write(ctx, relativePath, &encryptingReader{
+		source:    bufio.NewReader(r),
+		aead:      aead,
+		header:    header.Bytes(),
+		plaintext: make([]byte, encryptionChunkSize),
+		pending:   header.Bytes(),
+	}); err != nil {
+		return fmt.Errorf("encrypted sink: %w", err)
+	}
+
+	return nil
+}
+
+// GetReader returns a reader that decrypts the data stored by relativePath in the wrapped sink.
+// Data which hasn't been encrypted is returned as is if no keys have been provided or plaintext
+// has been allowed with WithPlaintext, and fails with ErrPlaintext otherwise. If relativePath
+// doesn't exist then the error ErrDoesntExist is returned.
+func (s *EncryptedSink) GetReader(ctx context.Context, relativePath string) (io.ReadCloser, error) {
+	reader, err := s.sink.GetReader(ctx, relativePath)
+	if err != nil {
+		return nil, fmt.Errorf("encrypted sink: %w", err)
+	}
+
+	decrypted, err := s.newDecryptingReader(ctx, reader)
+	if err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("encrypted sink: get reader for %q: %w", relativePath, err)
+	}
+
+	return decrypted, nil
+}
+
+// List returns the relative paths of all files stored below prefix in the wrapped sink. Paths
+// are not encrypted.
+func (s *EncryptedSink) List(ctx context.Context, prefix string) ([]string, error) {
+	return s.sink.List(ctx, prefix)
+}
+
+// Delete removes the data stored by relativePath from the wrapped sink.
+func (s *EncryptedSink) Delete(ctx context.Context, relativePath string) error {
+	return s.sink.Delete(ctx, relativePath)
+}
+
+func (s *EncryptedSink) newDecryptingReader(ctx context.Context, reader io.ReadCloser) (io.ReadCloser, error) {
+	source := bufio.NewReader(reader)
+
+	magic, err := source.Peek(len(encryptionMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	if !bytes.Equal(magic, encryptionMagic) {
+		if s.keys != nil && !s.allowPlaintext {
+			return nil, ErrPlaintext
+		}
+
+		return &readCloser{Reader: source, Closer: reader}, nil
+	}
+
+	var header bytes.Buffer
+	prefix := make([]byte, len(encryptionMagic)+2)
+	if _, err := io.ReadFull(io.TeeReader(source, &header), prefix); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	if version := prefix[len(encryptionMagic)]; version != encryptionVersion {
+		return nil, fmt.Errorf("unsupported encryption version %d", version)
+	}
+
+	keyIDAndSalt := make([]byte, int(prefix[len(encryptionMagic)+1])+encryptionSaltSize)
+	if _, err := io.ReadFull(io.TeeReader(source, &header), keyIDAndSalt); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	keyID := string(keyIDAndSalt[:len(keyIDAndSalt)-encryptionSaltSize])
+	salt := keyIDAndSalt[len(keyIDAndSalt)-encryptionSaltSize:]
+
+	if s.keys == nil {
+		return nil, fmt.Errorf("%w: data is encrypted with key %q, but no keys have been provided", ErrUnknownKey, keyID)
+	}
+
+	key, err := s.keys.DecryptionKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newChunkCipher(key.Material, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptingReader{
+		source:     source,
+		closer:     reader,
+		aead:       aead,
+		header:     header.Bytes(),
+		ciphertext: make([]byte, encryptionChunkSize+aead.Overhead()),
+	}, nil
+}
+
+// newChunkCipher derives the data key from the key material and salt via HKDF-SHA256 and returns
+// the AEAD used to encrypt chunks with it.
+func newChunkCipher(material, salt []byte) (cipher.AEAD, error) {
+	extract := hmac.New(sha256.New, salt)
+	_, _ = extract.Write(material)
+
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	_, _ = expand.Write([]byte(encryptionKeyInfo))
+	_, _ = expand.Write([]byte{1})
+
+	block, err := aes.NewCipher(expand.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	return aead, nil
+}
+
+// chunkNonce returns the nonce for the chunk at the given position. The last byte marks the final
+// chunk so that truncation at a chunk boundary is detected.
+func chunkNonce(counter uint64, final bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+	if final {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// readChunk reads the next chunk from source into buf. It returns whether the chunk is the final
+// chunk of the stream.
+func readChunk(source *bufio.Reader, buf []byte) (int, bool, error) {
+	n, err := io.ReadFull(source, buf)
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return n, true, nil
+	case err != nil:
+		return 0, false, err
+	}
+
+	if _, err := source.Peek(1); err != nil {
+		if errors.Is(err, io.EOF) {
+			return n, true, nil
+		}
+		return 0, false, err
+	}
+
+	return n, false, nil
+}
+
+type encryptingReader struct {
+	source    *bufio.Reader
+	aead      cipher.AEAD
+	header    []byte
+	counter   uint64
+	plaintext []byte
+	buf       []byte
+	pending   []byte
+	done      bool
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, final, err := readChunk(r.source, r.plaintext)
+		if err != nil {
+			return 0, err
+		}
+
+		r.buf = r.aead.Seal(r.buf[:0], chunkNonce(r.counter, final), r.plaintext[:n], r.header)
+		r.pending = r.buf
+		r.counter++
+		r.done = final
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+type decryptingReader struct {
+	source     *bufio.Reader
+	closer     io.Closer
+	aead       cipher.AEAD
+	header     []byte
+	counter    uint64
+	ciphertext []byte
+	buf        []byte
+	pending    []byte
+	done       bool
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, final, err := readChunk(r.source, r.ciphertext)
+		if err != nil {
+			return 0, err
+		}
+
+		r.buf, err = r.aead.Open(r.buf[:0], chunkNonce(r.counter, final), r.ciphertext[:n], r.header)
+		if err != nil {
+			return 0, fmt.Errorf("%w: chunk %d: %v", ErrDecryption, r.counter, err)
+		}
+		r.pending = r.buf
+		r.counter++
+		r.done = final
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.closer.Close()
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,240 @@
+//go:build !gitaly_test_sha256
+
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+)
+
+func writeKeyFile(t *testing.T, path string) {
+	t.Helper()
+
+	material := make([]byte, encryptionKeySize)
+	_, err := io.ReadFull(rand.Reader, material)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(material)+"\n"), 0o600))
+}
+
+func newTestKeyFileProvider(t *testing.T) *KeyFileProvider {
+	t.Helper()
+
+	path := filepath.Join(testhelper.TempDir(t), "backup.key")
+	writeKeyFile(t, path)
+
+	keys, err := NewKeyFileProvider(path)
+	require.NoError(t, err)
+
+	return keys
+}
+
+func TestEncryptedSink(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeyFileProvider(t)
+
+	for _, tc := range []struct {
+		desc string
+		size int
+	}{
+		{desc: "empty", size: 0},
+		{desc: "single byte", size: 1},
+		{desc: "single chunk", size: encryptionChunkSize},
+		{desc: "chunk boundary", size: encryptionChunkSize + 1},
+		{desc: "multiple chunks", size: 3*encryptionChunkSize + 5},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testhelper.Context(t)
+			backupPath := testhelper.TempDir(t)
+			sink := NewEncryptedSink(NewFilesystemSink(backupPath), keys)
+
+			data := make([]byte, tc.size)
+			_, err := io.ReadFull(rand.Reader, data)
+			require.NoError(t, err)
+
+			require.NoError(t, sink.Write(ctx, "data", bytes.NewReader(data)))
+
+			stored := testhelper.MustReadFile(t, filepath.Join(backupPath, "data"))
+			require.True(t, bytes.HasPrefix(stored, encryptionMagic))
+			// Short plaintexts may appear in the ciphertext by chance.
+			if tc.size >= 16 {
+				require.False(t, bytes.Contains(stored, data))
+			}
+
+			reader, err := sink.GetReader(ctx, "data")
+			require.NoError(t, err)
+			defer testhelper.MustClose(t, reader)
+
+			decrypted, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.Equal(t, data, decrypted)
+		})
+	}
+
+	t.Run("plaintext", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		fsSink := NewFilesystemSink(testhelper.TempDir(t))
+		require.NoError(t, fsSink.Write(ctx, "data", bytes.NewReader([]byte("plaintext data"))))
+
+		_, err := NewEncryptedSink(fsSink, keys).GetReader(ctx, "data")
+		require.ErrorIs(t, err, ErrPlaintext)
+	})
+
+	t.Run("plaintext allowed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		fsSink := NewFilesystemSink(testhelper.TempDir(t))
+		require.NoError(t, fsSink.Write(ctx, "data", bytes.NewReader([]byte("plaintext data"))))
+
+		reader, err := NewEncryptedSink(fsSink, keys, WithPlaintext()).GetReader(ctx, "data")
+		require.NoError(t, err)
+		defer testhelper.MustClose(t, reader)
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, "plaintext data", string(data))
+	})
+
+	t.Run("plaintext without keys", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		fsSink := NewFilesystemSink(testhelper.TempDir(t))
+		require.NoError(t, fsSink.Write(ctx, "data", bytes.NewReader([]byte("plaintext data"))))
+
+		reader, err := NewEncryptedSink(fsSink, nil).GetReader(ctx, "data")
+		require.NoError(t, err)
+		defer testhelper.MustClose(t, reader)
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, "plaintext data", string(data))
+	})
+
+	t.Run("swapped for plaintext", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		backupPath := testhelper.TempDir(t)
+		sink := NewEncryptedSink(NewFilesystemSink(backupPath), keys)
+		require.NoError(t, sink.Write(ctx, "data", bytes.NewReader([]byte("original data"))))
+
+		// Whoever can write to the sink can replace encrypted files without knowing the key.
+		require.NoError(t, os.WriteFile(filepath.Join(backupPath, "data"), []byte("forged data"), 0o644))
+
+		_, err := sink.GetReader(ctx, "data")
+		require.ErrorIs(t, err, ErrPlaintext)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		_, err := NewEncryptedSink(NewFilesystemSink(testhelper.TempDir(t)), keys).GetReader(ctx, "data")
+		require.ErrorIs(t, err, ErrDoesntExist)
+	})
+
+	t.Run("without keys", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		fsSink := NewFilesystemSink(testhelper.TempDir(t))
+		require.NoError(t, NewEncryptedSink(fsSink, keys).Write(ctx, "data", bytes.NewReader([]byte("data"))))
+
+		_, err := NewEncryptedSink(fsSink, nil).GetReader(ctx, "data")
+		require.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("wrong key file", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		fsSink := NewFilesystemSink(testhelper.TempDir(t))
+		require.NoError(t, NewEncryptedSink(fsSink, keys).Write(ctx, "data", bytes.NewReader([]byte("data"))))
+
+		_, err := NewEncryptedSink(fsSink, newTestKeyFileProvider(t)).GetReader(ctx, "data")
+		require.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("wrong key material", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		keyringPath := testhelper.TempDir(t)
+		require.NoError(t, os.WriteFile(filepath.Join(keyringPath, keyringPrimaryName), []byte("current\n"), 0o600))
+		writeKeyFile(t, filepath.Join(keyringPath, "current.key"))
+
+		keyring, err := NewKeyringProvider(keyringPath)
+		require.NoError(t, err)
+
+		fsSink := NewFilesystemSink(testhelper.TempDir(t))
+		require.NoError(t, NewEncryptedSink(fsSink, keyring).Write(ctx, "data", bytes.NewReader([]byte("data"))))
+
+		// Replace the key with a different one of the same ID.
+		writeKeyFile(t, filepath.Join(keyringPath, "current.key"))
+
+		reader, err := NewEncryptedSink(fsSink, keyring).GetReader(ctx, "data")
+		require.NoError(t, err)
+		defer testhelper.MustClose(t, reader)
+
+		_, err = io.ReadAll(reader)
+		require.ErrorIs(t, err, ErrDecryption)
+	})
+
+	for _, tc := range []struct {
+		desc   string
+		modify func([]byte) []byte
+	}{
+		{
+			desc: "truncated at chunk boundary",
+			modify: func(data []byte) []byte {
+				return data[:len(data)-(encryptionChunkSize/2+16)]
+			},
+		},
+		{
+			desc: "modified data",
+			modify: func(data []byte) []byte {
+				data[len(data)-1] ^= 1
+				return data
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testhelper.Context(t)
+			backupPath := testhelper.TempDir(t)
+			sink := NewEncryptedSink(NewFilesystemSink(backupPath), keys)
+
+			require.NoError(t, sink.Write(ctx, "data", bytes.NewReader(make([]byte, 2*encryptionChunkSize+encryptionChunkSize/2))))
+
+			path := filepath.Join(backupPath, "data")
+			require.NoError(t, os.WriteFile(path, tc.modify(testhelper.MustReadFile(t, path)), 0o600))
+
+			reader, err := sink.GetReader(ctx, "data")
+			require.NoError(t, err)
+			defer testhelper.MustClose(t, reader)
+
+			_, err = io.ReadAll(reader)
+			require.ErrorIs(t, err, ErrDecryption)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/text"
+)
+
+const (
+	// encryptionKeySize is the size of keys used to encrypt backups. Keys are used with
+	// AES-256, so they need to be 32 bytes long.
+	encryptionKeySize = 32
+	// keyringPrimaryName is the name of the file in a keyring directory that names the key
+	// used to encrypt new backups.
+	keyringPrimaryName = "primary"
+	// keyringKeySuffix is the suffix of key files in a keyring directory.
+	keyringKeySuffix = ".key"
+)
+
+// ErrUnknownKey means that a backup has been encrypted with a key that is not available.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// EncryptionKey is a key used to encrypt backups.
+type EncryptionKey struct {
+	// ID identifies the key. It is stored alongside encrypted data so that the key required
+	// for decryption can be looked up.
+	ID string
+	// Material is the secret key material.
+	Material []byte
+}
+
+// KeyProvider provides the keys used to encrypt and decrypt backups.
+type KeyProvider interface {
+	// EncryptionKey returns the key that new backups are encrypted with.
+	EncryptionKey(ctx context.Context) (EncryptionKey, error)
+	// DecryptionKey returns the key with the given ID. If there is no such key then the error
+	// ErrUnknownKey is returned.
+	DecryptionKey(ctx context.Context, id string) (EncryptionKey, error)
+}
+
+// KeyFileProvider provides a single key read from a key file. The key file contains 32 bytes
+// of base64-encoded key material, which can for example be generated with:
+//
+//	head -c 32 /dev/urandom | base64 >backup.key
+//
+// The key ID is derived from the key material so that decrypting with a different key file is
+// detected before any data is decrypted.
+type KeyFileProvider struct {
+	key EncryptionKey
+}
+
+// NewKeyFileProvider reads the key file at path and returns a provider for its key.
+func NewKeyFileProvider(path string) (*KeyFileProvider, error) {
+	material, err := readKeyFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("key file provider: %w", err)
+	}
+
+	fingerprint := sha256.Sum256(material)
+
+	return &KeyFileProvider{
+		key: EncryptionKey{
+			ID:       hex.EncodeToString(fingerprint[:8]),
+			Material: material,
+		},
+	}, nil
+}
+
+// EncryptionKey returns the key of the key file.
+func (p *KeyFileProvider) EncryptionKey(ctx context.Context) (EncryptionKey, error) {
+	return p.key, nil
+}
+
+// DecryptionKey returns the key of the key file if it has the given ID.
+func (p *KeyFileProvider) DecryptionKey(ctx context.Context, id string) (EncryptionKey, error) {
+	if id != p.key.ID {
+		return EncryptionKey{}, fmt.Errorf("key file provider: %w: %q", ErrUnknownKey, id)
+	}
+	return p.key, nil
+}
+
+// KeyringProvider provides keys from a keyring directory, similar to a key management service.
+// Every key is stored in its own file named `<key ID>.key` in the same format as used by
+// KeyFileProvider. The file `primary` contains the ID of the key new backups are encrypted with.
+// Keys can thus be rotated by adding a new key and pointing `primary` to it, while backups
+// encrypted with previous keys can still be decrypted.
+//
+// Structure:
+//
+//	<keyring>/primary
+//	<keyring>/<key ID>.key
+type KeyringProvider struct {
+	path string
+}
+
+// NewKeyringProvider returns a provider for the keyring stored in the directory at path.
+func NewKeyringProvider(path string) (*KeyringProvider, error) {
+	if _, err := os.Stat(filepath.Join(path, keyringPrimaryName)); err != nil {
+		return nil, fmt.Errorf("keyring provider: %w", err)
+	}
+
+	return &KeyringProvider{path: path}, nil
+}
+
+// EncryptionKey returns the primary key of the keyring.
+func (p *KeyringProvider) EncryptionKey(ctx context.Context) (EncryptionKey, error) {
+	primary, err := os.ReadFile(filepath.Join(p.path, keyringPrimaryName))
+	if err != nil {
+		return EncryptionKey{}, fmt.Errorf("keyring provider: read primary: %w", err)
+	}
+
+	key, err := p.DecryptionKey(ctx, text.ChompBytes(primary))
+	if err != nil {
+		return EncryptionKey{}, err
+	}
+
+	return key, nil
+}
+
+// DecryptionKey returns the key with the given ID from the keyring.
+func (p *KeyringProvider) DecryptionKey(ctx context.Context, id string) (EncryptionKey, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return EncryptionKey{}, fmt.Errorf("keyring provider: invalid key ID %q", id)
+	}
+
+	material, err := readKeyFile(filepath.Join(p.path, id+keyringKeySuffix))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return EncryptionKey{}, fmt.Errorf("keyring provider: %w: %q", ErrUnknownKey, id)
+		}
+		return EncryptionKey{}, fmt.Errorf("keyring provider: %w", err)
+	}
+
+	return EncryptionKey{
+		ID:       id,
+		Material: material,
+	}, nil
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+
+	material, err := base64.StdEncoding.DecodeString(text.ChompBytes(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decode key file %q: %w", path, err)
+	}
+
+	if len(material) != encryptionKeySize {
+		return nil, fmt.Errorf("decode key file %q: expected %d bytes of key material, got %d", path, encryptionKeySize, len(material))
+	}
+
+	return material, nil
+}
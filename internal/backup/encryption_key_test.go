@@ -0,0 +1,153 @@
+//go:build !gitaly_test_sha256
+
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+)
+
+func TestNewKeyFileProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+	dir := testhelper.TempDir(t)
+
+	t.Run("valid key", func(t *testing.T) {
+		path := filepath.Join(dir, "valid.key")
+		writeKeyFile(t, path)
+
+		keys, err := NewKeyFileProvider(path)
+		require.NoError(t, err)
+
+		key, err := keys.EncryptionKey(ctx)
+		require.NoError(t, err)
+		require.Len(t, key.ID, 16)
+		require.Len(t, key.Material, encryptionKeySize)
+
+		decryptionKey, err := keys.DecryptionKey(ctx, key.ID)
+		require.NoError(t, err)
+		require.Equal(t, key, decryptionKey)
+
+		_, err = keys.DecryptionKey(ctx, "unknown")
+		require.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("missing key file", func(t *testing.T) {
+		_, err := NewKeyFileProvider(filepath.Join(dir, "missing.key"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.key")
+		require.NoError(t, os.WriteFile(path, []byte("not base64!"), 0o600))
+
+		_, err := NewKeyFileProvider(path)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode key file")
+	})
+
+	t.Run("short key", func(t *testing.T) {
+		path := filepath.Join(dir, "short.key")
+		require.NoError(t, os.WriteFile(path, []byte("c2hvcnQ=\n"), 0o600))
+
+		_, err := NewKeyFileProvider(path)
+		require.EqualError(t, err, "key file provider: decode key file \""+path+"\": expected 32 bytes of key material, got 5")
+	})
+}
+
+func TestKeyringProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+	dir := testhelper.TempDir(t)
+
+	_, err := NewKeyringProvider(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	writeKeyFile(t, filepath.Join(dir, "2022-01.key"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, keyringPrimaryName), []byte("2022-01\n"), 0o600))
+
+	keyring, err := NewKeyringProvider(dir)
+	require.NoError(t, err)
+
+	oldKey, err := keyring.EncryptionKey(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "2022-01", oldKey.ID)
+
+	// Rotate the primary key. The previous key must still be available for decryption.
+	writeKeyFile(t, filepath.Join(dir, "2022-02.key"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, keyringPrimaryName), []byte("2022-02\n"), 0o600))
+
+	newKey, err := keyring.EncryptionKey(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "2022-02", newKey.ID)
+	require.NotEqual(t, oldKey.Material, newKey.Material)
+
+	decryptionKey, err := keyring.DecryptionKey(ctx, "2022-01")
+	require.NoError(t, err)
+	require.Equal(t, oldKey, decryptionKey)
+
+	_, err = keyring.DecryptionKey(ctx, "2021-12")
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = keyring.DecryptionKey(ctx, "../2022-01")
+	require.EqualError(t, err, `keyring provider: invalid key ID "../2022-01"`)
+}
+
+func TestResolveKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	keyFile := filepath.Join(testhelper.TempDir(t), "key")
+	writeKeyFile(t, keyFile)
+
+	keyring := testhelper.TempDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(keyring, keyringPrimaryName), []byte("primary-key\n"), 0o600))
+
+	for _, tc := range []struct {
+		desc         string
+		keyFile      string
+		keyring      string
+		expectedType KeyProvider
+		expectedErr  string
+	}{
+		{
+			desc: "unencrypted",
+		},
+		{
+			desc:         "key file",
+			keyFile:      keyFile,
+			expectedType: &KeyFileProvider{},
+		},
+		{
+			desc:         "keyring",
+			keyring:      keyring,
+			expectedType: &KeyringProvider{},
+		},
+		{
+			desc:        "key file and keyring",
+			keyFile:     keyFile,
+			keyring:     keyring,
+			expectedErr: "key file and keyring are mutually exclusive",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			keys, err := ResolveKeyProvider(tc.keyFile, tc.keyring)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expectedType == nil {
+				require.Nil(t, keys)
+				return
+			}
+			require.IsType(t, tc.expectedType, keys)
+		})
+	}
+}
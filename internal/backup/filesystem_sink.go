@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // FilesystemSink is a sink for creating and restoring backups from the local filesystem.
@@ -59,3 +60,57 @@ func (fs *FilesystemSink) GetReader(ctx context.Context, relativePath string) (i
 	}
 	return f, nil
 }
+
+// List returns the relative paths of all files stored below prefix. The paths
+// are relative to the root of the sink.
+func (fs *FilesystemSink) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+
+	root := filepath.Join(fs.path, prefix)
+	if err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(fs.path, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, relativePath)
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("filesystem sink: list %q: %w", prefix, err)
+	}
+
+	return paths, nil
+}
+
+// Delete removes the file stored by relativePath. Directories which are left
+// empty are removed as well.
+// If relativePath doesn't exist the ErrDoesntExist is returned.
+func (fs *FilesystemSink) Delete(ctx context.Context, relativePath string) error {
+	path := filepath.Join(fs.path, relativePath)
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = ErrDoesntExist
+		}
+		return fmt.Errorf("filesystem sink: delete %q: %w", relativePath, err)
+	}
+
+	// Removing a directory fails if it is not empty, in which case we can stop.
+	root := filepath.Clean(fs.path)
+	for dir := filepath.Dir(path); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
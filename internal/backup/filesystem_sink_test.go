@@ -102,3 +102,42 @@ func TestFilesystemSink_Write(t *testing.T) {
 		require.EqualError(t, err, fmt.Sprintf(`create directory structure %[1]q: mkdir %[1]s: not a directory`, filepath.Join(dir, "nested")))
 	})
 }
+
+func TestFilesystemSink_List(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	dir := testhelper.TempDir(t)
+	fsSink := NewFilesystemSink(dir)
+
+	for _, relativePath := range []string{"repo/LATEST", "repo/id/001.bundle", "repo/id/001.refs", "other/LATEST"} {
+		require.NoError(t, fsSink.Write(ctx, relativePath, strings.NewReader("test")))
+	}
+
+	paths, err := fsSink.List(ctx, "repo/")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"repo/LATEST", "repo/id/001.bundle", "repo/id/001.refs"}, paths)
+
+	paths, err = fsSink.List(ctx, "not-existing/")
+	require.NoError(t, err)
+	require.Empty(t, paths)
+}
+
+func TestFilesystemSink_Delete(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	dir := testhelper.TempDir(t)
+	fsSink := NewFilesystemSink(dir)
+
+	require.NoError(t, fsSink.Write(ctx, "repo/LATEST", strings.NewReader("test")))
+	require.NoError(t, fsSink.Write(ctx, "repo/id/001.bundle", strings.NewReader("test")))
+
+	require.NoError(t, fsSink.Delete(ctx, "repo/id/001.bundle"))
+	require.NoDirExists(t, filepath.Join(dir, "repo", "id"))
+	require.FileExists(t, filepath.Join(dir, "repo", "LATEST"))
+	require.DirExists(t, dir)
+
+	err := fsSink.Delete(ctx, "repo/id/001.bundle")
+	require.ErrorIs(t, err, ErrDoesntExist)
+}
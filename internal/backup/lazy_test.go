@@ -70,3 +70,11 @@ func (s MockSink) GetReader(ctx context.Context, relativePath string) (io.ReadCl
 	}
 	return io.NopCloser(strings.NewReader("")), nil
 }
+
+func (s MockSink) List(ctx context.Context, prefix string) ([]string, error) {
+	return nil, nil
+}
+
+func (s MockSink) Delete(ctx context.Context, relativePath string) error {
+	return nil
+}
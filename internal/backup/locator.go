@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/text"
 	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
@@ -50,6 +52,16 @@ func (l LegacyLocator) FindLatest(ctx context.Context, repo *gitalypb.Repository
 	}, nil
 }
 
+// Find is not supported for legacy backups as there is only a single backup
+func (l LegacyLocator) Find(ctx context.Context, repo *gitalypb.Repository, backupID string) (*Backup, error) {
+	return nil, errors.New("legacy layout: find: not supported")
+}
+
+// FindAt is not supported for legacy backups as there is only a single backup
+func (l LegacyLocator) FindAt(ctx context.Context, repo *gitalypb.Repository, timestamp time.Time) (*Backup, error) {
+	return nil, errors.New("legacy layout: find at: not supported")
+}
+
 func (l LegacyLocator) newFull(repo *gitalypb.Repository) *Step {
 	backupPath := strings.TrimSuffix(repo.RelativePath, ".git")
 
@@ -63,12 +75,15 @@ func (l LegacyLocator) newFull(repo *gitalypb.Repository) *Step {
 
 // PointerLocator locates backup paths where each full backup is put into a
 // unique timestamp directory and the latest backup taken is pointed to by a
-// file named LATEST.
+// file named LATEST. Every backup has a manifest which records all of its
+// steps. Backups created before manifests were introduced are located by
+// their LATEST files only.
 //
 // Structure:
 //
 //	<repo relative path>/LATEST
 //	<repo relative path>/<backup id>/LATEST
+//	<repo relative path>/<backup id>/manifest.toml
 //	<repo relative path>/<backup id>/<nnn>.bundle
 //	<repo relative path>/<backup id>/<nnn>.refs
 //	<repo relative path>/<backup id>/<nnn>.custom_hooks.tar
@@ -101,18 +116,18 @@ func (l PointerLocator) BeginIncremental(ctx context.Context, repo *gitalypb.Rep
 		}
 		return nil, fmt.Errorf("pointer locator: begin incremental: %w", err)
 	}
-	backup, err := l.find(ctx, repo, backupID)
+	backup, err := l.find(ctx, filepath.Join(repoPath, backupID))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("pointer locator: begin incremental: %w", err)
 	}
 	if len(backup.Steps) < 1 {
 		return nil, fmt.Errorf("pointer locator: begin incremental: no full backup")
 	}
 
 	previous := backup.Steps[len(backup.Steps)-1]
-	backupPath := filepath.Dir(previous.BundlePath)
-	bundleName := filepath.Base(previous.BundlePath)
-	incrementID := bundleName[:len(bundleName)-len(filepath.Ext(bundleName))]
+	backupPath := filepath.Dir(previous.RefPath)
+	refName := filepath.Base(previous.RefPath)
+	incrementID := refName[:len(refName)-len(filepath.Ext(refName))]
 	id, err := strconv.Atoi(incrementID)
 	if err != nil {
 		return nil, fmt.Errorf("pointer locator: begin incremental: determine increment ID: %w", err)
@@ -127,14 +142,30 @@ func (l PointerLocator) BeginIncremental(ctx context.Context, repo *gitalypb.Rep
 	}, nil
 }
 
-// Commit persists the step so that it can be looked up by FindLatest
+// Commit persists the step so that it can be looked up by FindLatest. The step
+// is appended to the backup's manifest before the LATEST files are updated.
 func (l PointerLocator) Commit(ctx context.Context, step *Step) error {
-	backupPath := filepath.Dir(step.BundlePath)
-	bundleName := filepath.Base(step.BundlePath)
+	// The bundle path may be empty when all objects are part of an object
+	// pool, so the paths are derived from the ref path instead.
+	backupPath := filepath.Dir(step.RefPath)
+	refName := filepath.Base(step.RefPath)
 	repoPath := filepath.Dir(backupPath)
 	backupID := filepath.Base(backupPath)
-	incrementID := bundleName[:len(bundleName)-len(filepath.Ext(bundleName))]
+	incrementID := refName[:len(refName)-len(filepath.Ext(refName))]
 
+	m := manifest{Version: manifestVersion}
+	if step.PreviousRefPath != "" {
+		previous, err := l.find(ctx, backupPath)
+		if err != nil {
+			return fmt.Errorf("pointer locator: commit: %w", err)
+		}
+		m.Steps = previous.Steps
+	}
+	m.Steps = append(m.Steps, *step)
+
+	if err := writeManifest(ctx, l.Sink, backupPath, &m); err != nil {
+		return fmt.Errorf("pointer locator: commit: %w", err)
+	}
 	if err := l.writeLatest(ctx, backupPath, incrementID); err != nil {
 		return fmt.Errorf("pointer locator: commit: %w", err)
 	}
@@ -158,18 +189,116 @@ func (l PointerLocator) FindLatest(ctx context.Context, repo *gitalypb.Repositor
 		return nil, fmt.Errorf("pointer locator: find latest: %w", err)
 	}
 
-	backup, err := l.find(ctx, repo, backupID)
+	backup, err := l.find(ctx, filepath.Join(repoPath, backupID))
 	if err != nil {
 		return nil, fmt.Errorf("pointer locator: find latest: %w", err)
 	}
 	return backup, nil
 }
 
-// find returns the repository backup at the given backupID. If the backup does
-// not exist then the error ErrDoesntExist is returned.
-func (l PointerLocator) find(ctx context.Context, repo *gitalypb.Repository, backupID string) (*Backup, error) {
+// Find returns the backup with the given ID. If the backup does not exist then
+// the error ErrDoesntExist is returned.
+func (l PointerLocator) Find(ctx context.Context, repo *gitalypb.Repository, backupID string) (*Backup, error) {
+	repoPath := strings.TrimSuffix(repo.RelativePath, ".git")
+
+	backup, err := l.find(ctx, filepath.Join(repoPath, backupID))
+	if err != nil {
+		return nil, fmt.Errorf("pointer locator: find: %w", err)
+	}
+	return backup, nil
+}
+
+// FindAt returns the latest backup of the repository as it was at timestamp.
+// Only the steps which have been created at or before timestamp are returned.
+// If there is no such backup then the error ErrDoesntExist is returned.
+//
+// Backups are dated by their IDs, so only backups with IDs generated by
+// gitaly-backup can be found. Steps which have been created before timestamps
+// were recorded can't be dated, except for the first step of a backup which
+// is dated by the backup ID. These steps are never returned.
+func (l PointerLocator) FindAt(ctx context.Context, repo *gitalypb.Repository, timestamp time.Time) (*Backup, error) {
 	repoPath := strings.TrimSuffix(repo.RelativePath, ".git")
-	backupPath := filepath.Join(repoPath, backupID)
+
+	backupIDs, err := l.listBackupIDs(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("pointer locator: find at: %w", err)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(backupIDs)))
+
+	for _, backupID := range backupIDs {
+		backupTime, err := time.Parse(backupIDLayout, backupID)
+		if err != nil || backupTime.After(timestamp) {
+			continue
+		}
+
+		backup, err := l.find(ctx, filepath.Join(repoPath, backupID))
+		if err != nil {
+			// Backups which haven't been committed yet have neither a
+			// manifest nor a LATEST file.
+			if errors.Is(err, ErrDoesntExist) {
+				continue
+			}
+			return nil, fmt.Errorf("pointer locator: find at: %w", err)
+		}
+
+		steps := 0
+		for i, step := range backup.Steps {
+			stepTime := step.Timestamp
+			if stepTime.IsZero() && i == 0 {
+				stepTime = backupTime
+			}
+			if stepTime.IsZero() || stepTime.After(timestamp) {
+				break
+			}
+			steps++
+		}
+		if steps == 0 {
+			continue
+		}
+
+		backup.Steps = backup.Steps[:steps]
+		return backup, nil
+	}
+
+	return nil, fmt.Errorf("pointer locator: find at: %s: %w", timestamp.Format(time.RFC3339), ErrDoesntExist)
+}
+
+// listBackupIDs returns the IDs of all backups stored in repoPath, including
+// backups which haven't been committed yet.
+func (l PointerLocator) listBackupIDs(ctx context.Context, repoPath string) ([]string, error) {
+	files, err := l.Sink.List(ctx, repoPath+"/")
+	if err != nil {
+		return nil, fmt.Errorf("list backup IDs: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var backupIDs []string
+	for _, file := range files {
+		// Files stored directly below the repository path, like its LATEST
+		// file, don't belong to any backup.
+		backupID, _, ok := strings.Cut(strings.TrimPrefix(file, repoPath+"/"), "/")
+		if !ok || seen[backupID] {
+			continue
+		}
+		seen[backupID] = true
+		backupIDs = append(backupIDs, backupID)
+	}
+
+	return backupIDs, nil
+}
+
+// find returns the repository backup stored in backupPath. The backup's
+// manifest is used if it exists, otherwise the steps are derived from the
+// backup's LATEST file. If the backup does not exist then the error
+// ErrDoesntExist is returned.
+func (l PointerLocator) find(ctx context.Context, backupPath string) (*Backup, error) {
+	m, err := readManifest(ctx, l.Sink, backupPath)
+	switch {
+	case err == nil:
+		return &Backup{ID: filepath.Base(backupPath), Steps: m.Steps}, nil
+	case !errors.Is(err, ErrDoesntExist):
+		return nil, fmt.Errorf("find: %w", err)
+	}
 
 	latestIncrementID, err := l.findLatestID(ctx, backupPath)
 	if err != nil {
@@ -181,7 +310,7 @@ func (l PointerLocator) find(ctx context.Context, repo *gitalypb.Repository, bac
 		return nil, fmt.Errorf("find: determine increment ID: %w", err)
 	}
 
-	var backup Backup
+	backup := Backup{ID: filepath.Base(backupPath)}
 
 	for i := 1; i <= max; i++ {
 		var previousRefPath string
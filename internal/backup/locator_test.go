@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -183,6 +184,7 @@ func TestPointerLocator(t *testing.T) {
 			require.NoError(t, os.WriteFile(filepath.Join(backupPath, repo.RelativePath, "LATEST"), []byte(backupID), 0o644))
 			require.NoError(t, os.WriteFile(filepath.Join(backupPath, repo.RelativePath, backupID, "LATEST"), []byte("003"), 0o644))
 			expected := &Backup{
+				ID: backupID,
 				Steps: []Step{
 					{
 						BundlePath:      filepath.Join(repo.RelativePath, backupID, "001.bundle"),
@@ -238,6 +240,7 @@ func TestPointerLocator(t *testing.T) {
 			require.NoError(t, os.WriteFile(filepath.Join(backupPath, repo.RelativePath, "LATEST"), []byte(backupID), 0o644))
 			require.NoError(t, os.WriteFile(filepath.Join(backupPath, repo.RelativePath, backupID, "LATEST"), []byte("001"), 0o644))
 			expected := &Backup{
+				ID: backupID,
 				Steps: []Step{
 					{
 						BundlePath:      filepath.Join(repo.RelativePath, backupID, "001.bundle"),
@@ -290,4 +293,104 @@ func TestPointerLocator(t *testing.T) {
 			require.EqualError(t, err, "pointer locator: find latest: find: determine increment ID: strconv.Atoi: parsing \"invalid\": invalid syntax")
 		})
 	})
+	t.Run("FindAt", func(t *testing.T) {
+		t.Parallel()
+
+		backupPath := testhelper.TempDir(t)
+		sink := NewFilesystemSink(backupPath)
+		var l Locator = PointerLocator{Sink: sink}
+		ctx := testhelper.Context(t)
+
+		step := func(backupID string, increment int, timestamp time.Time) Step {
+			var previousRefPath string
+			if increment > 1 {
+				previousRefPath = filepath.Join(repo.RelativePath, backupID, fmt.Sprintf("%03d.refs", increment-1))
+			}
+			return Step{
+				BundlePath:      filepath.Join(repo.RelativePath, backupID, fmt.Sprintf("%03d.bundle", increment)),
+				RefPath:         filepath.Join(repo.RelativePath, backupID, fmt.Sprintf("%03d.refs", increment)),
+				PreviousRefPath: previousRefPath,
+				CustomHooksPath: filepath.Join(repo.RelativePath, backupID, fmt.Sprintf("%03d.custom_hooks.tar", increment)),
+				Timestamp:       timestamp,
+			}
+		}
+
+		// A backup created before manifests were introduced, whose steps
+		// can't be dated except for the first one.
+		require.NoError(t, sink.Write(ctx, filepath.Join(repo.RelativePath, "20220101000000", "LATEST"), strings.NewReader("002")))
+
+		february := []Step{
+			step("20220201000000", 1, time.Date(2022, 2, 1, 0, 0, 5, 0, time.UTC)),
+			step("20220201000000", 2, time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC)),
+		}
+		require.NoError(t, writeManifest(ctx, sink, filepath.Join(repo.RelativePath, "20220201000000"), &manifest{
+			Version: manifestVersion,
+			Steps:   february,
+		}))
+
+		// Backups which can't be dated are ignored, as are backups which
+		// haven't been committed.
+		require.NoError(t, sink.Write(ctx, filepath.Join(repo.RelativePath, "abc123", "LATEST"), strings.NewReader("001")))
+		require.NoError(t, sink.Write(ctx, filepath.Join(repo.RelativePath, "20220301000000", "001.bundle"), strings.NewReader("")))
+		require.NoError(t, sink.Write(ctx, filepath.Join(repo.RelativePath, "LATEST"), strings.NewReader("abc123")))
+
+		for _, tc := range []struct {
+			desc          string
+			timestamp     time.Time
+			expected      *Backup
+			expectedError error
+		}{
+			{
+				desc:          "before all backups",
+				timestamp:     time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+				expectedError: ErrDoesntExist,
+			},
+			{
+				desc:      "undated steps",
+				timestamp: time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC),
+				expected: &Backup{
+					ID:    "20220101000000",
+					Steps: []Step{step("20220101000000", 1, time.Time{})},
+				},
+			},
+			{
+				desc:      "before first step",
+				timestamp: time.Date(2022, 2, 1, 0, 0, 2, 0, time.UTC),
+				expected: &Backup{
+					ID:    "20220101000000",
+					Steps: []Step{step("20220101000000", 1, time.Time{})},
+				},
+			},
+			{
+				desc:      "between steps",
+				timestamp: time.Date(2022, 2, 1, 12, 0, 0, 0, time.UTC),
+				expected: &Backup{
+					ID:    "20220201000000",
+					Steps: february[:1],
+				},
+			},
+			{
+				desc:      "at last step",
+				timestamp: time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC),
+				expected: &Backup{
+					ID:    "20220201000000",
+					Steps: february,
+				},
+			},
+			{
+				desc:      "after uncommitted backup",
+				timestamp: time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC),
+				expected: &Backup{
+					ID:    "20220201000000",
+					Steps: february,
+				},
+			},
+		} {
+			t.Run(tc.desc, func(t *testing.T) {
+				backup, err := l.FindAt(ctx, repo, tc.timestamp)
+				require.ErrorIs(t, err, tc.expectedError)
+				require.Equal(t, tc.expected, backup)
+			})
+		}
+	})
 }
@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+const (
+	// manifestName is the name of the manifest file which is stored alongside the steps of a
+	// backup.
+	manifestName = "manifest.toml"
+	// manifestVersion is the version of the manifest format written by this version of Gitaly.
+	// Manifests with a newer version are rejected, as they may describe backups we don't know
+	// how to restore.
+	manifestVersion = 1
+)
+
+// ErrCorrupted means that the data stored in the sink doesn't match what has been recorded in the
+// backup's manifest.
+var ErrCorrupted = errors.New("backup corrupted")
+
+// manifest is the self-describing record of a single backup. It lists every step required to
+// restore the backup in the order they need to be applied.
+type manifest struct {
+	// Version is the version of the manifest format.
+	Version int `toml:"version"`
+	// Steps are the ordered list of steps that make up this backup.
+	Steps []Step `toml:"steps"`
+}
+
+// readManifest reads the manifest stored in backupPath. If there is no manifest then the error
+// ErrDoesntExist is returned.
+func readManifest(ctx context.Context, sink Sink, backupPath string) (*manifest, error) {
+	r, err := sink.GetReader(ctx, filepath.Join(backupPath, manifestName))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	defer r.Close()
+
+	var m manifest
+	if err := toml.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	if m.Version < 1 || m.Version > manifestVersion {
+		return nil, fmt.Errorf("read manifest: unsupported version %d", m.Version)
+	}
+
+	return &m, nil
+}
+
+// writeManifest writes the manifest into backupPath, replacing any previous manifest.
+func writeManifest(ctx context.Context, sink Sink, backupPath string, m *manifest) error {
+	var buf strings.Builder
+	if err := toml.NewEncoder(&buf).Encode(m); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	if err := sink.Write(ctx, filepath.Join(backupPath, manifestName), strings.NewReader(buf.String())); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// checksumReader computes the checksum of all data read through it.
+type checksumReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	n      int64
+}
+
+func newChecksumReader(r io.Reader) *checksumReader {
+	return &checksumReader{
+		reader: r,
+		hash:   sha256.New(),
+	}
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	_, _ = r.hash.Write(p[:n]) // hash.Hash.Write never returns an error
+	return n, err
+}
+
+// Checksum returns the hex-encoded SHA256 checksum of the data read so far. It returns the empty
+// string if no data has been read, which is the case for artifacts that haven't been written.
+func (r *checksumReader) Checksum() string {
+	if r.n == 0 {
+		return ""
+	}
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
@@ -0,0 +1,354 @@
+//go:build !gitaly_test_sha256
+
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func TestPointerLocator_manifest(t *testing.T) {
+	t.Parallel()
+
+	const backupID = "abc123"
+
+	repo := &gitalypb.Repository{
+		StorageName:  "default",
+		RelativePath: "@hashed/ab/cd/abcd.git",
+	}
+	repoPath := "@hashed/ab/cd/abcd"
+
+	t.Run("steps are recorded", func(t *testing.T) {
+		t.Parallel()
+
+		backupPath := testhelper.TempDir(t)
+		l := PointerLocator{Sink: NewFilesystemSink(backupPath)}
+		ctx := testhelper.Context(t)
+
+		full := l.BeginFull(ctx, repo, backupID)
+		full.ObjectFormat = "sha1"
+		full.BundleChecksum = "bundle1"
+		full.RefChecksum = "refs1"
+		full.Timestamp = time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		require.NoError(t, l.Commit(ctx, full))
+
+		incremental, err := l.BeginIncremental(ctx, repo, "fallback")
+		require.NoError(t, err)
+		incremental.ObjectFormat = "sha1"
+		incremental.BundleChecksum = "bundle2"
+		incremental.RefChecksum = "refs2"
+		incremental.CustomHooksChecksum = "hooks2"
+		incremental.Timestamp = time.Date(2022, 1, 3, 3, 4, 5, 0, time.UTC)
+		require.NoError(t, l.Commit(ctx, incremental))
+
+		require.Equal(t, fmt.Sprintf(`version = 1
+
+[[steps]]
+bundle_path = '%[1]s/001.bundle'
+ref_path = '%[1]s/001.refs'
+custom_hooks_path = '%[1]s/001.custom_hooks.tar'
+object_format = 'sha1'
+bundle_checksum = 'bundle1'
+ref_checksum = 'refs1'
+timestamp = 2022-01-02T03:04:05Z
+
+[[steps]]
+bundle_path = '%[1]s/002.bundle'
+ref_path = '%[1]s/002.refs'
+previous_ref_path = '%[1]s/001.refs'
+custom_hooks_path = '%[1]s/002.custom_hooks.tar'
+object_format = 'sha1'
+bundle_checksum = 'bundle2'
+ref_checksum = 'refs2'
+custom_hooks_checksum = 'hooks2'
+timestamp = 2022-01-03T03:04:05Z
+`, filepath.Join(repoPath, backupID)), string(testhelper.MustReadFile(t, filepath.Join(backupPath, repoPath, backupID, manifestName))))
+
+		backup, err := l.FindLatest(ctx, repo)
+		require.NoError(t, err)
+		require.Equal(t, &Backup{ID: backupID, Steps: []Step{*full, *incremental}}, backup)
+	})
+
+	t.Run("incremental on top of backup without manifest", func(t *testing.T) {
+		t.Parallel()
+
+		backupPath := testhelper.TempDir(t)
+		sink := NewFilesystemSink(backupPath)
+		l := PointerLocator{Sink: sink}
+		ctx := testhelper.Context(t)
+
+		require.NoError(t, sink.Write(ctx, filepath.Join(repoPath, "LATEST"), strings.NewReader(backupID)))
+		require.NoError(t, sink.Write(ctx, filepath.Join(repoPath, backupID, "LATEST"), strings.NewReader("001")))
+
+		incremental, err := l.BeginIncremental(ctx, repo, "fallback")
+		require.NoError(t, err)
+		incremental.BundleChecksum = "bundle2"
+		require.NoError(t, l.Commit(ctx, incremental))
+
+		backup, err := l.FindLatest(ctx, repo)
+		require.NoError(t, err)
+		require.Equal(t, &Backup{
+			ID: backupID,
+			Steps: []Step{
+				{
+					BundlePath:      filepath.Join(repoPath, backupID, "001.bundle"),
+					RefPath:         filepath.Join(repoPath, backupID, "001.refs"),
+					CustomHooksPath: filepath.Join(repoPath, backupID, "001.custom_hooks.tar"),
+				},
+				*incremental,
+			},
+		}, backup)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		backupPath := testhelper.TempDir(t)
+		sink := NewFilesystemSink(backupPath)
+		l := PointerLocator{Sink: sink}
+		ctx := testhelper.Context(t)
+
+		require.NoError(t, sink.Write(ctx, filepath.Join(repoPath, "LATEST"), strings.NewReader(backupID)))
+		require.NoError(t, sink.Write(ctx, filepath.Join(repoPath, backupID, manifestName), strings.NewReader("version = 2\n")))
+
+		_, err := l.FindLatest(ctx, repo)
+		require.EqualError(t, err, "pointer locator: find latest: find: read manifest: unsupported version 2")
+	})
+}
+
+func TestManager_validateBackup(t *testing.T) {
+	t.Parallel()
+
+	checksum := func(data string) string {
+		sum := sha256.Sum256([]byte(data))
+		return hex.EncodeToString(sum[:])
+	}
+
+	for _, tc := range []struct {
+		desc                 string
+		files                map[string]string
+		steps                []Step
+		expectedObjectFormat gitalypb.ObjectFormat
+		expectedErr          string
+	}{
+		{
+			desc: "valid chain",
+			files: map[string]string{
+				"001.bundle":           "bundle1",
+				"001.refs":             "refs1",
+				"002.bundle":           "bundle2",
+				"002.refs":             "refs2",
+				"002.custom_hooks.tar": "hooks2",
+			},
+			steps: []Step{
+				{
+					BundlePath:     "001.bundle",
+					RefPath:        "001.refs",
+					ObjectFormat:   "sha256",
+					BundleChecksum: checksum("bundle1"),
+					RefChecksum:    checksum("refs1"),
+				},
+				{
+					BundlePath:          "002.bundle",
+					RefPath:             "002.refs",
+					PreviousRefPath:     "001.refs",
+					CustomHooksPath:     "002.custom_hooks.tar",
+					ObjectFormat:        "sha256",
+					BundleChecksum:      checksum("bundle2"),
+					RefChecksum:         checksum("refs2"),
+					CustomHooksChecksum: checksum("hooks2"),
+				},
+			},
+			expectedObjectFormat: gitalypb.ObjectFormat_OBJECT_FORMAT_SHA256,
+		},
+		{
+			desc: "without manifest",
+			files: map[string]string{
+				"001.bundle": "bundle1",
+			},
+			steps: []Step{
+				{
+					BundlePath:      "001.bundle",
+					RefPath:         "001.refs",
+					CustomHooksPath: "001.custom_hooks.tar",
+				},
+			},
+			expectedObjectFormat: gitalypb.ObjectFormat_OBJECT_FORMAT_UNSPECIFIED,
+		},
+		{
+			desc: "skippable bundle",
+			steps: []Step{
+				{
+					BundlePath:          "001.bundle",
+					SkippableOnNotFound: true,
+				},
+			},
+			expectedObjectFormat: gitalypb.ObjectFormat_OBJECT_FORMAT_UNSPECIFIED,
+		},
+		{
+			desc: "missing bundle",
+			files: map[string]string{
+				"001.bundle": "bundle1",
+			},
+			steps: []Step{
+				{
+					BundlePath:     "001.bundle",
+					BundleChecksum: checksum("bundle1"),
+				},
+				{
+					BundlePath: "002.bundle",
+				},
+			},
+			expectedErr: `validate: step 2: backup corrupted: bundle "002.bundle": filesystem sink: get reader for "002.bundle": doesn't exist`,
+		},
+		{
+			desc: "checksum mismatch",
+			files: map[string]string{
+				"001.bundle": "bundle1",
+				"001.refs":   "refs1",
+				"002.bundle": "bundle2",
+				"002.refs":   "modified",
+			},
+			steps: []Step{
+				{
+					BundlePath:     "001.bundle",
+					RefPath:        "001.refs",
+					BundleChecksum: checksum("bundle1"),
+					RefChecksum:    checksum("refs1"),
+				},
+				{
+					BundlePath:      "002.bundle",
+					RefPath:         "002.refs",
+					PreviousRefPath: "001.refs",
+					BundleChecksum:  checksum("bundle2"),
+					RefChecksum:     checksum("refs2"),
+				},
+			},
+			expectedErr: fmt.Sprintf(`validate: step 2: backup corrupted: refs "002.refs": checksum mismatch: expected %s, got %s`,
+				checksum("refs2"), checksum("modified")),
+		},
+		{
+			desc: "missing custom hooks",
+			files: map[string]string{
+				"001.bundle": "bundle1",
+			},
+			steps: []Step{
+				{
+					BundlePath:          "001.bundle",
+					CustomHooksPath:     "001.custom_hooks.tar",
+					CustomHooksChecksum: checksum("hooks1"),
+				},
+			},
+			expectedErr: `validate: step 1: backup corrupted: custom hooks "001.custom_hooks.tar": filesystem sink: get reader for "001.custom_hooks.tar": doesn't exist`,
+		},
+		{
+			desc: "broken chain",
+			files: map[string]string{
+				"001.bundle": "bundle1",
+				"002.bundle": "bundle2",
+			},
+			steps: []Step{
+				{
+					BundlePath: "001.bundle",
+					RefPath:    "001.refs",
+				},
+				{
+					BundlePath:      "002.bundle",
+					RefPath:         "002.refs",
+					PreviousRefPath: "000.refs",
+				},
+			},
+			expectedErr: `validate: step 2: backup corrupted: previous ref path "000.refs" doesn't match "001.refs"`,
+		},
+		{
+			desc: "mixed object formats",
+			files: map[string]string{
+				"001.bundle": "bundle1",
+				"002.bundle": "bundle2",
+			},
+			steps: []Step{
+				{
+					BundlePath:   "001.bundle",
+					RefPath:      "001.refs",
+					ObjectFormat: "sha1",
+				},
+				{
+					BundlePath:      "002.bundle",
+					RefPath:         "002.refs",
+					PreviousRefPath: "001.refs",
+					ObjectFormat:    "sha256",
+				},
+			},
+			expectedErr: `validate: step 2: backup corrupted: object format "sha256" doesn't match "sha1"`,
+		},
+		{
+			desc: "mixed object pools",
+			files: map[string]string{
+				"001.bundle": "bundle1",
+				"002.bundle": "bundle2",
+			},
+			steps: []Step{
+				{
+					BundlePath:             "001.bundle",
+					RefPath:                "001.refs",
+					ObjectPoolRelativePath: "@pools/aa/bb/pool1.git",
+				},
+				{
+					BundlePath:             "002.bundle",
+					RefPath:                "002.refs",
+					PreviousRefPath:        "001.refs",
+					ObjectPoolRelativePath: "@pools/cc/dd/pool2.git",
+				},
+			},
+			expectedErr: `validate: step 2: backup corrupted: object pool "@pools/cc/dd/pool2.git" doesn't match "@pools/aa/bb/pool1.git"`,
+		},
+		{
+			desc: "object pool member without bundle",
+			files: map[string]string{
+				"001.refs": "refs1",
+			},
+			steps: []Step{
+				{
+					RefPath:                "001.refs",
+					RefChecksum:            checksum("refs1"),
+					ObjectPoolRelativePath: "@pools/aa/bb/pool1.git",
+				},
+			},
+			expectedObjectFormat: gitalypb.ObjectFormat_OBJECT_FORMAT_UNSPECIFIED,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testhelper.Context(t)
+			backupPath := testhelper.TempDir(t)
+			for name, content := range tc.files {
+				require.NoError(t, os.WriteFile(filepath.Join(backupPath, name), []byte(content), 0o644))
+			}
+
+			mgr := NewManager(NewFilesystemSink(backupPath), LegacyLocator{}, nil, "")
+
+			objectFormat, err := mgr.validateBackup(ctx, &Backup{Steps: tc.steps})
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				require.ErrorIs(t, err, ErrCorrupted)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedObjectFormat, objectFormat)
+		})
+	}
+}
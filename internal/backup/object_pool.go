@@ -0,0 +1,311 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+// WithObjectPools makes the manager aware of object pools. When creating a
+// backup of a repository that is linked to an object pool, the object pool is
+// backed up once and the bundles of its members only contain the objects that
+// aren't part of the object pool. The object pool membership is recorded in the
+// backup so that restoring a member restores its object pool and links the
+// member to it.
+//
+// Pool membership is only recorded by locators which persist steps, so this
+// requires the pointer layout.
+func WithObjectPools() ManagerOption {
+	return func(mgr *Manager) {
+		mgr.objectPools = true
+	}
+}
+
+// objectPoolOnce makes sure an object pool is only backed up or restored once
+// even when multiple of its members are processed concurrently.
+type objectPoolOnce struct {
+	once sync.Once
+	step *Step
+	err  error
+}
+
+// objectPoolRestore serializes the restores of an object pool and makes sure
+// every backup of the object pool is only restored once even when multiple of
+// its members are restored concurrently.
+type objectPoolRestore struct {
+	mu sync.Mutex
+	// restored maps the ref path of the last restored step of a backup to
+	// the result of restoring it.
+	restored map[string]error
+}
+
+// objectPool returns the relative path of the object pool the backup depends
+// on, if any.
+func (b *Backup) objectPool() string {
+	for _, step := range b.Steps {
+		if step.ObjectPoolRelativePath != "" {
+			return step.ObjectPoolRelativePath
+		}
+	}
+	return ""
+}
+
+func objectPoolKey(server storage.ServerInfo, pool *gitalypb.Repository) string {
+	return fmt.Sprintf("%s/%s/%s", server.Address, pool.GetStorageName(), pool.GetRelativePath())
+}
+
+func (mgr *Manager) objectPoolOnce(onces map[string]*objectPoolOnce, server storage.ServerInfo, pool *gitalypb.Repository) *objectPoolOnce {
+	mgr.objectPoolsMu.Lock()
+	defer mgr.objectPoolsMu.Unlock()
+
+	key := objectPoolKey(server, pool)
+	if _, ok := onces[key]; !ok {
+		onces[key] = &objectPoolOnce{}
+	}
+
+	return onces[key]
+}
+
+func (mgr *Manager) objectPoolRestore(server storage.ServerInfo, pool *gitalypb.Repository) *objectPoolRestore {
+	mgr.objectPoolsMu.Lock()
+	defer mgr.objectPoolsMu.Unlock()
+
+	key := objectPoolKey(server, pool)
+	if _, ok := mgr.objectPoolRestores[key]; !ok {
+		mgr.objectPoolRestores[key] = &objectPoolRestore{restored: make(map[string]error)}
+	}
+
+	return mgr.objectPoolRestores[key]
+}
+
+// findObjectPoolBackups returns the backups of the object pool that the steps
+// of backup have been created against. Each backup of the object pool only
+// contains its steps up to the last one referenced by backup, so that the
+// object pool is restored to the state its member was backed up against
+// rather than to its latest backup.
+func (mgr *Manager) findObjectPoolBackups(ctx context.Context, pool *gitalypb.Repository, backup *Backup) ([]*Backup, error) {
+	// Object pools require the pointer layout, where the ref file of a step
+	// is stored in the directory named after the ID of its backup.
+	var backupIDs []string
+	refPaths := make(map[string]string)
+	for _, step := range backup.Steps {
+		if step.ObjectPoolRefPath == "" {
+			continue
+		}
+
+		backupID := filepath.Base(filepath.Dir(step.ObjectPoolRefPath))
+		if _, ok := refPaths[backupID]; !ok {
+			backupIDs = append(backupIDs, backupID)
+		}
+		refPaths[backupID] = step.ObjectPoolRefPath
+	}
+
+	poolBackups := make([]*Backup, 0, len(backupIDs))
+	for _, backupID := range backupIDs {
+		poolBackup, err := mgr.locator.Find(ctx, pool, backupID)
+		if err != nil {
+			return nil, fmt.Errorf("find object pool backup: %w", err)
+		}
+
+		steps := -1
+		for i, step := range poolBackup.Steps {
+			if step.RefPath == refPaths[backupID] {
+				steps = i + 1
+				break
+			}
+		}
+		if steps < 0 {
+			return nil, fmt.Errorf("find object pool backup: %w: step %q not found in backup %q",
+				ErrCorrupted, refPaths[backupID], backupID)
+		}
+
+		poolBackup.Steps = poolBackup.Steps[:steps]
+		poolBackups = append(poolBackups, poolBackup)
+	}
+
+	return poolBackups, nil
+}
+
+// createObjectPool backs up the object pool repo is linked to, unless it has
+// already been backed up, and records it in step so that objects of the
+// object pool are excluded from the bundle of repo.
+func (mgr *Manager) createObjectPool(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository, incremental bool, step *Step) error {
+	pool, err := mgr.getObjectPool(ctx, server, repo)
+	if err != nil {
+		return fmt.Errorf("create object pool: %w", err)
+	}
+	if pool == nil {
+		return nil
+	}
+
+	once := mgr.objectPoolOnce(mgr.objectPoolBackups, server, pool)
+	once.once.Do(func() {
+		once.step, once.err = mgr.writeObjectPool(ctx, server, pool, incremental)
+	})
+	if once.err != nil {
+		return fmt.Errorf("create object pool: %q: %w", pool.GetRelativePath(), once.err)
+	}
+
+	// The object pool doesn't have any refs, so there is nothing to deduplicate.
+	if once.step == nil {
+		return nil
+	}
+
+	step.ObjectPoolRelativePath = pool.GetRelativePath()
+	step.ObjectPoolRefPath = once.step.RefPath
+
+	return nil
+}
+
+// writeObjectPool creates a backup of the object pool and returns its step.
+// If nothing changed since the previous backup of the object pool then the
+// latest existing step is returned instead.
+func (mgr *Manager) writeObjectPool(ctx context.Context, server storage.ServerInfo, pool *gitalypb.Repository, incremental bool) (*Step, error) {
+	refs, err := mgr.listRefs(ctx, server, pool)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	step, err := mgr.beginStep(ctx, pool, incremental)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mgr.writeStep(ctx, step, server, pool, refs); err != nil {
+		if !errors.Is(err, ErrSkipped) {
+			return nil, err
+		}
+
+		backup, err := mgr.locator.FindLatest(ctx, pool)
+		if err != nil {
+			return nil, err
+		}
+
+		return &backup.Steps[len(backup.Steps)-1], nil
+	}
+
+	if err := mgr.locator.Commit(ctx, step); err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
+
+// restoreObjectPool restores the backups of the object pool at relativePath
+// which backup has been created against, unless they have already been
+// restored, and links repo to it. An existing object pool is not removed as it
+// may be shared with other repositories, the backed up objects are fetched
+// into it instead.
+func (mgr *Manager) restoreObjectPool(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository, relativePath string, backup *Backup) error {
+	pool := &gitalypb.Repository{
+		StorageName:  repo.GetStorageName(),
+		RelativePath: relativePath,
+	}
+
+	poolBackups, err := mgr.findObjectPoolBackups(ctx, pool, backup)
+	if err != nil {
+		return fmt.Errorf("restore object pool: %q: %w", relativePath, err)
+	}
+
+	restore := mgr.objectPoolRestore(server, pool)
+	restore.mu.Lock()
+	for _, poolBackup := range poolBackups {
+		refPath := poolBackup.Steps[len(poolBackup.Steps)-1].RefPath
+		if _, ok := restore.restored[refPath]; !ok {
+			restore.restored[refPath] = mgr.readObjectPool(ctx, server, pool, poolBackup)
+		}
+		if err := restore.restored[refPath]; err != nil {
+			restore.mu.Unlock()
+			return fmt.Errorf("restore object pool: %q: %w", relativePath, err)
+		}
+	}
+	restore.mu.Unlock()
+
+	objectPoolClient, err := mgr.newObjectPoolClient(ctx, server)
+	if err != nil {
+		return fmt.Errorf("restore object pool: %w", err)
+	}
+
+	if _, err := objectPoolClient.LinkRepositoryToObjectPool(ctx, &gitalypb.LinkRepositoryToObjectPoolRequest{
+		ObjectPool: &gitalypb.ObjectPool{Repository: pool},
+		Repository: repo,
+	}); err != nil {
+		return fmt.Errorf("restore object pool: link %q: %w", relativePath, err)
+	}
+
+	return nil
+}
+
+// readObjectPool restores the steps of backup into the object pool, which is
+// created if it doesn't exist yet.
+func (mgr *Manager) readObjectPool(ctx context.Context, server storage.ServerInfo, pool *gitalypb.Repository, backup *Backup) error {
+	objectFormat, err := mgr.validateRestore(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	exists, err := mgr.repositoryExists(ctx, server, pool)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := mgr.createRepository(ctx, server, pool, objectFormat); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range backup.Steps {
+		if err := mgr.restoreBundle(ctx, step.BundlePath, step.BundleChecksum, server, pool); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getObjectPool returns the object pool repo is linked to. It returns nil if
+// repo isn't linked to an object pool.
+func (mgr *Manager) getObjectPool(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository) (*gitalypb.Repository, error) {
+	objectPoolClient, err := mgr.newObjectPoolClient(ctx, server)
+	if err != nil {
+		return nil, fmt.Errorf("get object pool: %w", err)
+	}
+
+	resp, err := objectPoolClient.GetObjectPool(ctx, &gitalypb.GetObjectPoolRequest{Repository: repo})
+	if err != nil {
+		return nil, fmt.Errorf("get object pool: %w", err)
+	}
+
+	return resp.GetObjectPool().GetRepository(), nil
+}
+
+func (mgr *Manager) repositoryExists(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository) (bool, error) {
+	repoClient, err := mgr.newRepoClient(ctx, server)
+	if err != nil {
+		return false, fmt.Errorf("repository exists: %w", err)
+	}
+
+	resp, err := repoClient.RepositoryExists(ctx, &gitalypb.RepositoryExistsRequest{Repository: repo})
+	if err != nil {
+		return false, fmt.Errorf("repository exists: %w", err)
+	}
+
+	return resp.GetExists(), nil
+}
+
+func (mgr *Manager) newObjectPoolClient(ctx context.Context, server storage.ServerInfo) (gitalypb.ObjectPoolServiceClient, error) {
+	conn, err := mgr.conns.Dial(ctx, server.Address, server.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return gitalypb.NewObjectPoolServiceClient(conn), nil
+}
@@ -0,0 +1,249 @@
+//go:build !gitaly_test_sha256
+
+package backup_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service/setup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func TestManager_objectPools(t *testing.T) {
+	t.Parallel()
+
+	const backupID = "abc123"
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll, testserver.WithDisablePraefect())
+
+	ctx := testhelper.Context(t)
+
+	cc, err := client.Dial(cfg.SocketPath, nil)
+	require.NoError(t, err)
+	defer testhelper.MustClose(t, cc)
+
+	repoClient := gitalypb.NewRepositoryServiceClient(cc)
+	objectPoolClient := gitalypb.NewObjectPoolServiceClient(cc)
+
+	origin, originPath := gittest.CreateRepository(t, ctx, cfg)
+	root := gittest.WriteCommit(t, cfg, originPath, gittest.WithBranch("master"))
+	gittest.Exec(t, cfg, "-C", originPath, "symbolic-ref", "HEAD", "refs/heads/master")
+
+	objectPool := &gitalypb.ObjectPool{
+		Repository: &gitalypb.Repository{
+			StorageName:  origin.GetStorageName(),
+			RelativePath: gittest.NewObjectPoolName(t),
+		},
+	}
+	_, err = objectPoolClient.CreateObjectPool(ctx, &gitalypb.CreateObjectPoolRequest{
+		ObjectPool: objectPool,
+		Origin:     origin,
+	})
+	require.NoError(t, err)
+
+	fork, forkPath := gittest.CreateRepository(t, ctx, cfg)
+	for _, repo := range []*gitalypb.Repository{origin, fork} {
+		_, err := objectPoolClient.LinkRepositoryToObjectPool(ctx, &gitalypb.LinkRepositoryToObjectPoolRequest{
+			ObjectPool: objectPool,
+			Repository: repo,
+		})
+		require.NoError(t, err)
+	}
+
+	gittest.Exec(t, cfg, "-C", forkPath, "update-ref", "refs/heads/master", root.String())
+	forkCommit := gittest.WriteCommit(t, cfg, forkPath, gittest.WithBranch("feature"), gittest.WithParents(root))
+
+	backupPath := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(backupPath)
+	locator := backup.PointerLocator{Sink: sink}
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	server := storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token}
+
+	mgr := backup.NewManager(sink, locator, pool, backupID, backup.WithObjectPools())
+	for _, repo := range []*gitalypb.Repository{origin, fork} {
+		require.NoError(t, mgr.Create(ctx, &backup.CreateRequest{
+			Server:     server,
+			Repository: repo,
+		}))
+	}
+
+	poolBackup, err := locator.FindLatest(ctx, objectPool.GetRepository())
+	require.NoError(t, err)
+	require.Len(t, poolBackup.Steps, 1)
+	require.FileExists(t, filepath.Join(backupPath, poolBackup.Steps[0].BundlePath))
+
+	originBackup, err := locator.FindLatest(ctx, origin)
+	require.NoError(t, err)
+	require.Len(t, originBackup.Steps, 1)
+	require.Equal(t, objectPool.GetRepository().GetRelativePath(), originBackup.Steps[0].ObjectPoolRelativePath)
+	require.Equal(t, poolBackup.Steps[0].RefPath, originBackup.Steps[0].ObjectPoolRefPath)
+	// All objects of the origin are part of the object pool.
+	require.Empty(t, originBackup.Steps[0].BundlePath)
+
+	forkBackup, err := locator.FindLatest(ctx, fork)
+	require.NoError(t, err)
+	require.Len(t, forkBackup.Steps, 1)
+	require.Equal(t, objectPool.GetRepository().GetRelativePath(), forkBackup.Steps[0].ObjectPoolRelativePath)
+
+	// The fork bundle only contains the commit that isn't part of the object pool.
+	bundle := gittest.Exec(t, cfg, "-C", forkPath, "bundle", "verify", filepath.Join(backupPath, forkBackup.Steps[0].BundlePath))
+	require.Contains(t, string(bundle), root.String())
+	require.Contains(t, string(bundle), forkCommit.String())
+
+	// Incremental backups of members without objects of their own can be created on top of
+	// steps which don't have a bundle.
+	gittest.Exec(t, cfg, "-C", originPath, "update-ref", "refs/heads/other", root.String())
+	require.NoError(t, mgr.Create(ctx, &backup.CreateRequest{
+		Server:      server,
+		Repository:  origin,
+		Incremental: true,
+	}))
+
+	originBackup, err = locator.FindLatest(ctx, origin)
+	require.NoError(t, err)
+	require.Len(t, originBackup.Steps, 2)
+	require.Empty(t, originBackup.Steps[1].BundlePath)
+	require.Equal(t, originBackup.Steps[0].RefPath, originBackup.Steps[1].PreviousRefPath)
+
+	expectedOriginRefs := gittest.Exec(t, cfg, "-C", originPath, "show-ref", "--head")
+	expectedForkRefs := gittest.Exec(t, cfg, "-C", forkPath, "show-ref", "--head")
+
+	for _, repo := range []*gitalypb.Repository{origin, fork, objectPool.GetRepository()} {
+		_, err := repoClient.RemoveRepository(ctx, &gitalypb.RemoveRepositoryRequest{Repository: repo})
+		require.NoError(t, err)
+	}
+
+	mgr = backup.NewManager(sink, locator, pool, backupID)
+	for _, repo := range []*gitalypb.Repository{origin, fork} {
+		require.NoError(t, mgr.Restore(ctx, &backup.RestoreRequest{
+			Server:     server,
+			Repository: repo,
+		}))
+
+		resp, err := objectPoolClient.GetObjectPool(ctx, &gitalypb.GetObjectPoolRequest{Repository: repo})
+		require.NoError(t, err)
+		require.Equal(t, objectPool.GetRepository().GetRelativePath(), resp.GetObjectPool().GetRepository().GetRelativePath())
+	}
+
+	require.Equal(t, string(expectedOriginRefs), string(gittest.Exec(t, cfg, "-C", originPath, "show-ref", "--head")))
+	require.Equal(t, string(expectedForkRefs), string(gittest.Exec(t, cfg, "-C", forkPath, "show-ref", "--head")))
+	require.Equal(t, "refs/heads/master", strings.TrimSpace(string(gittest.Exec(t, cfg, "-C", originPath, "symbolic-ref", "HEAD"))))
+
+	// Backups of members are verified against a scratch copy of the object pool.
+	for _, repo := range []*gitalypb.Repository{origin, fork} {
+		result, err := mgr.Verify(ctx, &backup.VerifyRequest{
+			Server:     server,
+			Repository: repo,
+		})
+		require.NoError(t, err)
+		require.Empty(t, result.MismatchedRefs)
+	}
+}
+
+func TestManager_objectPoolsRestoreReferencedBackup(t *testing.T) {
+	t.Parallel()
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll, testserver.WithDisablePraefect())
+
+	ctx := testhelper.Context(t)
+
+	cc, err := client.Dial(cfg.SocketPath, nil)
+	require.NoError(t, err)
+	defer testhelper.MustClose(t, cc)
+
+	repoClient := gitalypb.NewRepositoryServiceClient(cc)
+	objectPoolClient := gitalypb.NewObjectPoolServiceClient(cc)
+
+	origin, originPath := gittest.CreateRepository(t, ctx, cfg)
+	root := gittest.WriteCommit(t, cfg, originPath, gittest.WithBranch("master"))
+
+	objectPool := &gitalypb.ObjectPool{
+		Repository: &gitalypb.Repository{
+			StorageName:  origin.GetStorageName(),
+			RelativePath: gittest.NewObjectPoolName(t),
+		},
+	}
+	_, err = objectPoolClient.CreateObjectPool(ctx, &gitalypb.CreateObjectPoolRequest{
+		ObjectPool: objectPool,
+		Origin:     origin,
+	})
+	require.NoError(t, err)
+	poolPath := filepath.Join(cfg.Storages[0].Path, objectPool.GetRepository().GetRelativePath())
+
+	fork, forkPath := gittest.CreateRepository(t, ctx, cfg)
+	for _, repo := range []*gitalypb.Repository{origin, fork} {
+		_, err := objectPoolClient.LinkRepositoryToObjectPool(ctx, &gitalypb.LinkRepositoryToObjectPoolRequest{
+			ObjectPool: objectPool,
+			Repository: repo,
+		})
+		require.NoError(t, err)
+	}
+
+	gittest.Exec(t, cfg, "-C", forkPath, "update-ref", "refs/heads/master", root.String())
+	gittest.WriteCommit(t, cfg, forkPath, gittest.WithBranch("feature"), gittest.WithParents(root))
+
+	backupPath := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(backupPath)
+	locator := backup.PointerLocator{Sink: sink}
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	server := storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token}
+
+	require.NoError(t, backup.NewManager(sink, locator, pool, "abc123", backup.WithObjectPools()).Create(ctx, &backup.CreateRequest{
+		Server:     server,
+		Repository: fork,
+	}))
+
+	// Replace the contents of the object pool so that its next backup doesn't contain any of
+	// the objects the backup of the fork depends on.
+	for _, ref := range strings.Fields(string(gittest.Exec(t, cfg, "-C", poolPath, "for-each-ref", "--format=%(refname)"))) {
+		gittest.Exec(t, cfg, "-C", poolPath, "update-ref", "-d", ref)
+	}
+	unrelated := gittest.WriteCommit(t, cfg, poolPath, gittest.WithBranch("unrelated"), gittest.WithMessage("unrelated"))
+
+	require.NoError(t, backup.NewManager(sink, locator, pool, "def456", backup.WithObjectPools()).Create(ctx, &backup.CreateRequest{
+		Server:     server,
+		Repository: origin,
+	}))
+
+	poolBackup, err := locator.FindLatest(ctx, objectPool.GetRepository())
+	require.NoError(t, err)
+	require.Equal(t, "def456", poolBackup.ID)
+
+	expectedForkRefs := gittest.Exec(t, cfg, "-C", forkPath, "show-ref")
+
+	for _, repo := range []*gitalypb.Repository{origin, fork, objectPool.GetRepository()} {
+		_, err := repoClient.RemoveRepository(ctx, &gitalypb.RemoveRepositoryRequest{Repository: repo})
+		require.NoError(t, err)
+	}
+
+	// The fork is restored with the backup of the object pool it has been backed up against
+	// rather than with the latest backup of the object pool.
+	require.NoError(t, backup.NewManager(sink, locator, pool, "unused-backup-id").Restore(ctx, &backup.RestoreRequest{
+		Server:     server,
+		Repository: fork,
+	}))
+
+	require.Equal(t, string(expectedForkRefs), string(gittest.Exec(t, cfg, "-C", forkPath, "show-ref")))
+	gittest.Exec(t, cfg, "-C", poolPath, "cat-file", "-e", root.String())
+	require.Error(t, gittest.NewCommand(t, cfg, "-C", poolPath, "cat-file", "-e", unrelated.String()).Run())
+}
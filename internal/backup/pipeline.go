@@ -12,10 +12,11 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
 )
 
-// Strategy used to create/restore backups
+// Strategy used to create/restore/verify backups
 type Strategy interface {
 	Create(context.Context, *CreateRequest) error
 	Restore(context.Context, *RestoreRequest) error
+	Verify(context.Context, *VerifyRequest) (*VerifyResult, error)
 }
 
 // Command handles a specific backup operation
@@ -71,25 +72,22 @@ func (cmd CreateCommand) Execute(ctx context.Context) error {
 
 // RestoreCommand restores a backup for a repository
 type RestoreCommand struct {
-	strategy     Strategy
-	server       storage.ServerInfo
-	repository   *gitalypb.Repository
-	alwaysCreate bool
+	strategy Strategy
+	request  RestoreRequest
 }
 
-// NewRestoreCommand builds a RestoreCommand
-func NewRestoreCommand(strategy Strategy, server storage.ServerInfo, repo *gitalypb.Repository, alwaysCreate bool) *RestoreCommand {
+// NewRestoreCommand builds a RestoreCommand which restores the backup
+// described by req.
+func NewRestoreCommand(strategy Strategy, req RestoreRequest) *RestoreCommand {
 	return &RestoreCommand{
-		strategy:     strategy,
-		server:       server,
-		repository:   repo,
-		alwaysCreate: alwaysCreate,
+		strategy: strategy,
+		request:  req,
 	}
 }
 
 // Repository is the repository that will be acted on
 func (cmd RestoreCommand) Repository() *gitalypb.Repository {
-	return cmd.repository
+	return cmd.request.Repository
 }
 
 // Name is the name of the command
@@ -99,11 +97,81 @@ func (cmd RestoreCommand) Name() string {
 
 // Execute performs the restore
 func (cmd RestoreCommand) Execute(ctx context.Context) error {
-	return cmd.strategy.Restore(ctx, &RestoreRequest{
-		Server:       cmd.server,
-		Repository:   cmd.repository,
-		AlwaysCreate: cmd.alwaysCreate,
+	req := cmd.request
+	return cmd.strategy.Restore(ctx, &req)
+}
+
+// VerifyCommand verifies a backup of a repository
+type VerifyCommand struct {
+	strategy   Strategy
+	server     storage.ServerInfo
+	repository *gitalypb.Repository
+	backupID   string
+	reporter   *VerifyReporter
+}
+
+// NewVerifyCommand builds a VerifyCommand. The outcome of the verification is
+// written to reporter.
+func NewVerifyCommand(strategy Strategy, server storage.ServerInfo, repo *gitalypb.Repository, backupID string, reporter *VerifyReporter) *VerifyCommand {
+	return &VerifyCommand{
+		strategy:   strategy,
+		server:     server,
+		repository: repo,
+		backupID:   backupID,
+		reporter:   reporter,
+	}
+}
+
+// Repository is the repository that will be acted on
+func (cmd VerifyCommand) Repository() *gitalypb.Repository {
+	return cmd.repository
+}
+
+// Name is the name of the command
+func (cmd VerifyCommand) Name() string {
+	return "verify"
+}
+
+// Execute performs the verification
+func (cmd VerifyCommand) Execute(ctx context.Context) error {
+	result, err := cmd.strategy.Verify(ctx, &VerifyRequest{
+		Server:     cmd.server,
+		Repository: cmd.repository,
+		BackupID:   cmd.backupID,
 	})
+	if reportErr := cmd.reporter.Report(cmd.repository, result, err); reportErr != nil && err == nil {
+		return reportErr
+	}
+	return err
+}
+
+// PruneCommand prunes the backups of a repository
+type PruneCommand struct {
+	pruner     *Pruner
+	repository *gitalypb.Repository
+}
+
+// NewPruneCommand builds a PruneCommand
+func NewPruneCommand(pruner *Pruner, repo *gitalypb.Repository) *PruneCommand {
+	return &PruneCommand{
+		pruner:     pruner,
+		repository: repo,
+	}
+}
+
+// Repository is the repository that will be acted on
+func (cmd PruneCommand) Repository() *gitalypb.Repository {
+	return cmd.repository
+}
+
+// Name is the name of the command
+func (cmd PruneCommand) Name() string {
+	return "prune"
+}
+
+// Execute performs the pruning
+func (cmd PruneCommand) Execute(ctx context.Context) error {
+	return cmd.pruner.Prune(ctx, cmd.repository)
 }
 
 // PipelineError represents a summary of errors by repository
@@ -3,7 +3,9 @@
 package backup
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync/atomic"
 	"testing"
@@ -102,6 +104,7 @@ func TestParallelPipeline(t *testing.T) {
 type MockStrategy struct {
 	CreateFunc  func(context.Context, *CreateRequest) error
 	RestoreFunc func(context.Context, *RestoreRequest) error
+	VerifyFunc  func(context.Context, *VerifyRequest) (*VerifyResult, error)
 }
 
 func (s MockStrategy) Create(ctx context.Context, req *CreateRequest) error {
@@ -118,6 +121,13 @@ func (s MockStrategy) Restore(ctx context.Context, req *RestoreRequest) error {
 	return nil
 }
 
+func (s MockStrategy) Verify(ctx context.Context, req *VerifyRequest) (*VerifyResult, error) {
+	if s.VerifyFunc != nil {
+		return s.VerifyFunc(ctx, req)
+	}
+	return &VerifyResult{}, nil
+}
+
 func testPipeline(t *testing.T, init func() Pipeline) {
 	t.Run("create command", func(t *testing.T) {
 		t.Parallel()
@@ -172,9 +182,9 @@ func testPipeline(t *testing.T, init func() Pipeline) {
 		ctx := testhelper.Context(t)
 
 		commands := []Command{
-			NewRestoreCommand(strategy, storage.ServerInfo{}, &gitalypb.Repository{RelativePath: "a.git", StorageName: "normal"}, false),
-			NewRestoreCommand(strategy, storage.ServerInfo{}, &gitalypb.Repository{RelativePath: "b.git", StorageName: "skip"}, false),
-			NewRestoreCommand(strategy, storage.ServerInfo{}, &gitalypb.Repository{RelativePath: "c.git", StorageName: "error"}, false),
+			NewRestoreCommand(strategy, RestoreRequest{Repository: &gitalypb.Repository{RelativePath: "a.git", StorageName: "normal"}}),
+			NewRestoreCommand(strategy, RestoreRequest{Repository: &gitalypb.Repository{RelativePath: "b.git", StorageName: "skip"}}),
+			NewRestoreCommand(strategy, RestoreRequest{Repository: &gitalypb.Repository{RelativePath: "c.git", StorageName: "error"}}),
 		}
 		for _, cmd := range commands {
 			p.Handle(ctx, cmd)
@@ -182,6 +192,76 @@ func testPipeline(t *testing.T, init func() Pipeline) {
 		err := p.Done()
 		require.EqualError(t, err, "pipeline: 1 failures encountered:\n - c.git: assert.AnError general error for testing\n")
 	})
+
+	t.Run("verify command", func(t *testing.T) {
+		t.Parallel()
+
+		strategy := MockStrategy{
+			VerifyFunc: func(_ context.Context, req *VerifyRequest) (*VerifyResult, error) {
+				require.Equal(t, "abc123", req.BackupID)
+
+				switch req.Repository.StorageName {
+				case "normal":
+					return &VerifyResult{BackupID: req.BackupID, Steps: 2}, nil
+				case "skip":
+					return nil, ErrSkipped
+				case "error":
+					return &VerifyResult{BackupID: req.BackupID, Steps: 1, MismatchedRefs: []string{"refs/heads/main"}}, assert.AnError
+				}
+				require.Failf(t, "unexpected call to Verify", "StorageName = %q", req.Repository.StorageName)
+				return nil, nil
+			},
+		}
+		p := init()
+		ctx := testhelper.Context(t)
+
+		var out bytes.Buffer
+		reporter := NewVerifyReporter(&out)
+
+		commands := []Command{
+			NewVerifyCommand(strategy, storage.ServerInfo{}, &gitalypb.Repository{RelativePath: "a.git", StorageName: "normal"}, "abc123", reporter),
+			NewVerifyCommand(strategy, storage.ServerInfo{}, &gitalypb.Repository{RelativePath: "b.git", StorageName: "skip"}, "abc123", reporter),
+			NewVerifyCommand(strategy, storage.ServerInfo{}, &gitalypb.Repository{RelativePath: "c.git", StorageName: "error"}, "abc123", reporter),
+		}
+		for _, cmd := range commands {
+			p.Handle(ctx, cmd)
+		}
+		err := p.Done()
+		require.EqualError(t, err, "pipeline: 1 failures encountered:\n - c.git: assert.AnError general error for testing\n")
+
+		reports := make(map[string]VerifyReport)
+		decoder := json.NewDecoder(&out)
+		for decoder.More() {
+			var report VerifyReport
+			require.NoError(t, decoder.Decode(&report))
+			reports[report.RelativePath] = report
+		}
+
+		require.Equal(t, map[string]VerifyReport{
+			"a.git": {
+				StorageName:  "normal",
+				RelativePath: "a.git",
+				BackupID:     "abc123",
+				Steps:        2,
+				Status:       VerifyStatusOK,
+			},
+			"b.git": {
+				StorageName:  "skip",
+				RelativePath: "b.git",
+				Status:       VerifyStatusSkipped,
+				Error:        ErrSkipped.Error(),
+			},
+			"c.git": {
+				StorageName:    "error",
+				RelativePath:   "c.git",
+				BackupID:       "abc123",
+				Steps:          1,
+				Status:         VerifyStatusFailed,
+				Error:          assert.AnError.Error(),
+				MismatchedRefs: []string{"refs/heads/main"},
+			},
+		}, reports)
+	})
 }
 
 func TestPipelineError(t *testing.T) {
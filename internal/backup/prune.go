@@ -0,0 +1,293 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/housekeeping"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+// backupIDLayout is the time layout of the backup IDs generated by gitaly-backup.
+const backupIDLayout = "20060102150405"
+
+// RetentionPolicy determines which full backups of a repository are kept when
+// pruning. A full backup is kept together with all of its increments if any of
+// the rules selects it, so incremental chains are never broken. The latest
+// backup is always kept.
+//
+// The time-based rules keep the latest full backup of each of the most recent
+// days, weeks or months that have a backup. They rely on backup IDs being
+// timestamps as generated by gitaly-backup. Backups with other IDs can't be
+// dated and are never pruned.
+type RetentionPolicy struct {
+	// KeepLast is the number of most recent full backups to keep.
+	KeepLast int
+	// KeepDaily is the number of days for which to keep the latest full backup.
+	KeepDaily int
+	// KeepWeekly is the number of ISO weeks for which to keep the latest full backup.
+	KeepWeekly int
+	// KeepMonthly is the number of months for which to keep the latest full backup.
+	KeepMonthly int
+}
+
+// retain returns the set of backup IDs that are kept. backupIDs must only
+// contain IDs of committed backups.
+func (p RetentionPolicy) retain(backupIDs []string, latestID string) map[string]bool {
+	sorted := append([]string(nil), backupIDs...)
+	sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+
+	keep := map[string]bool{latestID: true}
+
+	var dated []string
+	times := make(map[string]time.Time, len(sorted))
+	for _, id := range sorted {
+		t, err := time.Parse(backupIDLayout, id)
+		if err != nil {
+			keep[id] = true
+			continue
+		}
+		dated = append(dated, id)
+		times[id] = t
+	}
+
+	for i := 0; i < p.KeepLast && i < len(sorted); i++ {
+		keep[sorted[i]] = true
+	}
+
+	for _, rule := range []struct {
+		count  int
+		period func(time.Time) string
+	}{
+		{
+			count:  p.KeepDaily,
+			period: func(t time.Time) string { return t.Format("2006-01-02") },
+		},
+		{
+			count: p.KeepWeekly,
+			period: func(t time.Time) string {
+				year, week := t.ISOWeek()
+				return fmt.Sprintf("%d-%02d", year, week)
+			},
+		},
+		{
+			count:  p.KeepMonthly,
+			period: func(t time.Time) string { return t.Format("2006-01") },
+		},
+	} {
+		periods := make(map[string]bool, rule.count)
+		for _, id := range dated {
+			if len(periods) >= rule.count {
+				break
+			}
+
+			period := rule.period(times[id])
+			if !periods[period] {
+				periods[period] = true
+				keep[id] = true
+			}
+		}
+	}
+
+	return keep
+}
+
+// Pruner deletes the backups of repositories which aren't retained by a
+// retention policy, as well as files of retained backups which aren't
+// referenced by any of their steps, for example because creating a step
+// failed halfway. Only backups in the pointer layout are pruned.
+//
+// Incremental steps are only ever added to the latest backup, whose
+// unreferenced files may thus belong to a step that is still being created.
+// These files are never deleted.
+//
+// Backups of object pools are never pruned as they may be referenced by the
+// backups of any of their members.
+type Pruner struct {
+	sink    Sink
+	locator PointerLocator
+	policy  RetentionPolicy
+	dryRun  bool
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewPruner returns a pruner which deletes backups from sink according to
+// policy. Every deleted file is reported to out. If dryRun is set, files are
+// only reported but not deleted.
+func NewPruner(sink Sink, policy RetentionPolicy, dryRun bool, out io.Writer) *Pruner {
+	return &Pruner{
+		sink:    sink,
+		locator: PointerLocator{Sink: sink},
+		policy:  policy,
+		dryRun:  dryRun,
+		out:     out,
+	}
+}
+
+// Prune deletes the backups of repo which aren't retained.
+func (p *Pruner) Prune(ctx context.Context, repo *gitalypb.Repository) error {
+	if housekeeping.IsRailsPoolRepository(repo) {
+		return fmt.Errorf("pruner: object pool: %w", ErrSkipped)
+	}
+
+	repoPath := strings.TrimSuffix(repo.GetRelativePath(), ".git")
+
+	latestID, err := p.locator.findLatestID(ctx, repoPath)
+	if err != nil {
+		if errors.Is(err, ErrDoesntExist) {
+			return fmt.Errorf("pruner: no backups: %w", ErrSkipped)
+		}
+		return fmt.Errorf("pruner: %w", err)
+	}
+
+	files, err := p.sink.List(ctx, repoPath+"/")
+	if err != nil {
+		return fmt.Errorf("pruner: %w", err)
+	}
+
+	backups := make(map[string][]string)
+	for _, file := range files {
+		// Files stored directly below the repository path, like its LATEST
+		// file, don't belong to any backup.
+		backupID, _, ok := strings.Cut(strings.TrimPrefix(file, repoPath+"/"), "/")
+		if !ok {
+			continue
+		}
+		backups[backupID] = append(backups[backupID], file)
+	}
+
+	var committed []string
+	for backupID, files := range backups {
+		if backupID == latestID || isCommitted(filepath.Join(repoPath, backupID), files) {
+			committed = append(committed, backupID)
+		}
+	}
+
+	keep := p.policy.retain(committed, latestID)
+
+	var deletions []string
+	for backupID, files := range backups {
+		switch {
+		case keep[backupID]:
+			if backupID == latestID {
+				continue
+			}
+
+			unreferenced, err := p.unreferencedFiles(ctx, filepath.Join(repoPath, backupID), files)
+			if err != nil {
+				return fmt.Errorf("pruner: %w", err)
+			}
+			deletions = append(deletions, unreferenced...)
+		case backupID > latestID && !isCommitted(filepath.Join(repoPath, backupID), files):
+			// A backup that is newer than the latest backup and hasn't been
+			// committed yet may still be in progress.
+			continue
+		default:
+			deletions = append(deletions, files...)
+		}
+	}
+
+	sort.Strings(deletions)
+	for _, path := range deletions {
+		if !p.dryRun {
+			if err := p.sink.Delete(ctx, path); err != nil {
+				return fmt.Errorf("pruner: %w", err)
+			}
+		}
+		p.report(path)
+	}
+
+	return nil
+}
+
+// Repositories returns the repositories which have backups in the pointer
+// layout by walking the sink. Backups don't record the storage of their
+// repository, so the storage names of the returned repositories are empty.
+func (p *Pruner) Repositories(ctx context.Context) ([]*gitalypb.Repository, error) {
+	files, err := p.sink.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("pruner: repositories: %w", err)
+	}
+
+	latestDirs := make(map[string]bool)
+	for _, file := range files {
+		if filepath.Base(file) == "LATEST" {
+			latestDirs[filepath.Dir(file)] = true
+		}
+	}
+
+	var repos []*gitalypb.Repository
+	for dir := range latestDirs {
+		// The LATEST files of backups are stored below the directory of
+		// their repository, which has a LATEST file itself.
+		if dir == "." || latestDirs[filepath.Dir(dir)] {
+			continue
+		}
+		repos = append(repos, &gitalypb.Repository{RelativePath: dir + ".git"})
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].GetRelativePath() < repos[j].GetRelativePath()
+	})
+
+	return repos, nil
+}
+
+// unreferencedFiles returns all files of the backup stored in backupPath which
+// aren't referenced by any of its steps.
+func (p *Pruner) unreferencedFiles(ctx context.Context, backupPath string, files []string) ([]string, error) {
+	backup, err := p.locator.find(ctx, backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("unreferenced files: %w", err)
+	}
+
+	referenced := map[string]bool{
+		filepath.Join(backupPath, "LATEST"):     true,
+		filepath.Join(backupPath, manifestName): true,
+	}
+	for _, step := range backup.Steps {
+		referenced[step.BundlePath] = true
+		referenced[step.RefPath] = true
+		referenced[step.CustomHooksPath] = true
+	}
+
+	var unreferenced []string
+	for _, file := range files {
+		if !referenced[file] {
+			unreferenced = append(unreferenced, file)
+		}
+	}
+
+	return unreferenced, nil
+}
+
+func (p *Pruner) report(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dryRun {
+		fmt.Fprintf(p.out, "would delete %s\n", path)
+	} else {
+		fmt.Fprintf(p.out, "deleted %s\n", path)
+	}
+}
+
+// isCommitted returns whether the backup stored in backupPath has been
+// committed, that is whether it has a LATEST file or a manifest.
+func isCommitted(backupPath string, files []string) bool {
+	for _, file := range files {
+		if file == filepath.Join(backupPath, "LATEST") || file == filepath.Join(backupPath, manifestName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+//go:build !gitaly_test_sha256
+
+package backup
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func TestRetentionPolicy_retain(t *testing.T) {
+	t.Parallel()
+
+	backupIDs := []string{
+		"20220101120000",
+		"20220102120000",
+		"20220103080000",
+		"20220103120000",
+		"20220110120000",
+		"20220201120000",
+		"20220301120000",
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		policy   RetentionPolicy
+		ids      []string
+		expected []string
+	}{
+		{
+			desc:     "latest is always kept",
+			ids:      backupIDs,
+			expected: []string{"20220301120000"},
+		},
+		{
+			desc:     "keep last",
+			policy:   RetentionPolicy{KeepLast: 3},
+			ids:      backupIDs,
+			expected: []string{"20220110120000", "20220201120000", "20220301120000"},
+		},
+		{
+			desc:     "keep daily",
+			policy:   RetentionPolicy{KeepDaily: 5},
+			ids:      backupIDs,
+			expected: []string{"20220102120000", "20220103120000", "20220110120000", "20220201120000", "20220301120000"},
+		},
+		{
+			desc:     "keep weekly",
+			policy:   RetentionPolicy{KeepWeekly: 3},
+			ids:      backupIDs,
+			expected: []string{"20220110120000", "20220201120000", "20220301120000"},
+		},
+		{
+			desc:     "keep monthly",
+			policy:   RetentionPolicy{KeepMonthly: 12},
+			ids:      backupIDs,
+			expected: []string{"20220110120000", "20220201120000", "20220301120000"},
+		},
+		{
+			desc:     "combined",
+			policy:   RetentionPolicy{KeepLast: 1, KeepMonthly: 2},
+			ids:      backupIDs,
+			expected: []string{"20220201120000", "20220301120000"},
+		},
+		{
+			desc:     "undated IDs are kept",
+			policy:   RetentionPolicy{KeepLast: 1},
+			ids:      []string{"20220101120000", "custom", "20220102120000"},
+			expected: []string{"20220102120000", "custom"},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var kept []string
+			for id := range tc.policy.retain(tc.ids, tc.ids[len(tc.ids)-1]) {
+				kept = append(kept, id)
+			}
+			sort.Strings(kept)
+
+			require.Equal(t, tc.expected, kept)
+		})
+	}
+}
+
+func TestPruner(t *testing.T) {
+	t.Parallel()
+
+	repo := &gitalypb.Repository{
+		StorageName:  "default",
+		RelativePath: "@hashed/ab/cd/abcd.git",
+	}
+	repoPath := "@hashed/ab/cd/abcd"
+
+	setup := func(tb testing.TB) (string, Sink) {
+		tb.Helper()
+
+		ctx := testhelper.Context(tb)
+		backupPath := testhelper.TempDir(tb)
+		sink := NewFilesystemSink(backupPath)
+		locator := PointerLocator{Sink: sink}
+
+		writeStep := func(step *Step) {
+			require.NoError(tb, sink.Write(ctx, step.BundlePath, strings.NewReader("bundle")))
+			require.NoError(tb, sink.Write(ctx, step.RefPath, strings.NewReader("refs")))
+			require.NoError(tb, locator.Commit(ctx, step))
+		}
+
+		for _, backupID := range []string{"20220101120000", "20220102120000", "20220103120000"} {
+			writeStep(locator.BeginFull(ctx, repo, backupID))
+
+			step, err := locator.BeginIncremental(ctx, repo, backupID)
+			require.NoError(tb, err)
+			writeStep(step)
+		}
+
+		// A step of a retained backup which has never been committed.
+		require.NoError(tb, sink.Write(ctx, filepath.Join(repoPath, "20220102120000", "003.bundle"), strings.NewReader("bundle")))
+		// A step of the latest backup which may still be in progress.
+		require.NoError(tb, sink.Write(ctx, filepath.Join(repoPath, "20220103120000", "003.bundle"), strings.NewReader("bundle")))
+		// A backup which has never been committed.
+		require.NoError(tb, sink.Write(ctx, filepath.Join(repoPath, "20220101000000", "001.bundle"), strings.NewReader("bundle")))
+		// A backup which is still in progress.
+		require.NoError(tb, sink.Write(ctx, filepath.Join(repoPath, "20220104120000", "001.bundle"), strings.NewReader("bundle")))
+
+		return backupPath, sink
+	}
+
+	expectedDeletions := []string{
+		filepath.Join(repoPath, "20220101000000", "001.bundle"),
+		filepath.Join(repoPath, "20220101120000", "001.bundle"),
+		filepath.Join(repoPath, "20220101120000", "001.refs"),
+		filepath.Join(repoPath, "20220101120000", "002.bundle"),
+		filepath.Join(repoPath, "20220101120000", "002.refs"),
+		filepath.Join(repoPath, "20220101120000", "LATEST"),
+		filepath.Join(repoPath, "20220101120000", manifestName),
+		filepath.Join(repoPath, "20220102120000", "003.bundle"),
+	}
+
+	t.Run("prune", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		backupPath, sink := setup(t)
+
+		var out bytes.Buffer
+		require.NoError(t, NewPruner(sink, RetentionPolicy{KeepLast: 2}, false, &out).Prune(ctx, repo))
+
+		var expectedOut strings.Builder
+		for _, path := range expectedDeletions {
+			expectedOut.WriteString("deleted " + path + "\n")
+			require.NoFileExists(t, filepath.Join(backupPath, path))
+		}
+		require.Equal(t, expectedOut.String(), out.String())
+		require.NoDirExists(t, filepath.Join(backupPath, repoPath, "20220101120000"))
+		require.FileExists(t, filepath.Join(backupPath, repoPath, "20220103120000", "003.bundle"))
+		require.FileExists(t, filepath.Join(backupPath, repoPath, "20220104120000", "001.bundle"))
+
+		backup, err := PointerLocator{Sink: sink}.FindLatest(ctx, repo)
+		require.NoError(t, err)
+		require.Len(t, backup.Steps, 2)
+
+		// Pruning again doesn't find anything else to delete.
+		out.Reset()
+		require.NoError(t, NewPruner(sink, RetentionPolicy{KeepLast: 2}, false, &out).Prune(ctx, repo))
+		require.Empty(t, out.String())
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		backupPath, sink := setup(t)
+
+		var out bytes.Buffer
+		require.NoError(t, NewPruner(sink, RetentionPolicy{KeepLast: 2}, true, &out).Prune(ctx, repo))
+
+		var expectedOut strings.Builder
+		for _, path := range expectedDeletions {
+			expectedOut.WriteString("would delete " + path + "\n")
+			require.FileExists(t, filepath.Join(backupPath, path))
+		}
+		require.Equal(t, expectedOut.String(), out.String())
+	})
+
+	t.Run("repositories", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		_, sink := setup(t)
+
+		// A repository which is only backed up with the legacy layout.
+		require.NoError(t, sink.Write(ctx, "legacy/repo.bundle", strings.NewReader("bundle")))
+		// A repository whose backup has never been committed.
+		require.NoError(t, sink.Write(ctx, "uncommitted/repo/20220101120000/001.bundle", strings.NewReader("bundle")))
+		// A repository which doesn't exist anymore.
+		locator := PointerLocator{Sink: sink}
+		deleted := &gitalypb.Repository{StorageName: "default", RelativePath: "group/deleted.git"}
+		step := locator.BeginFull(ctx, deleted, "20220101120000")
+		require.NoError(t, sink.Write(ctx, step.BundlePath, strings.NewReader("bundle")))
+		require.NoError(t, locator.Commit(ctx, step))
+
+		repos, err := NewPruner(sink, RetentionPolicy{KeepLast: 1}, false, &bytes.Buffer{}).Repositories(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []*gitalypb.Repository{
+			{RelativePath: repo.GetRelativePath()},
+			{RelativePath: "group/deleted.git"},
+		}, repos)
+	})
+
+	t.Run("no backups", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		sink := NewFilesystemSink(testhelper.TempDir(t))
+
+		err := NewPruner(sink, RetentionPolicy{KeepLast: 1}, false, &bytes.Buffer{}).Prune(ctx, repo)
+		require.ErrorIs(t, err, ErrSkipped)
+	})
+
+	t.Run("object pool", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testhelper.Context(t)
+		_, sink := setup(t)
+
+		err := NewPruner(sink, RetentionPolicy{KeepLast: 1}, false, &bytes.Buffer{}).Prune(ctx, &gitalypb.Repository{
+			StorageName:  "default",
+			RelativePath: gittest.NewObjectPoolName(t),
+		})
+		require.EqualError(t, err, "pruner: object pool: repository skipped")
+	})
+}
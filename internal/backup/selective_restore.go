@@ -0,0 +1,229 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/chunk"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+	"gitlab.com/gitlab-org/gitaly/v15/streamio"
+)
+
+// restoreSelectedRefs restores the refs selected by the request, as recorded
+// by the last step of the backup, into the existing repository. Neither the
+// repository nor any of its other refs are touched. Existing refs are only
+// overwritten if the request asks for it.
+//
+// All steps of the backup are first restored into a scratch repository. The
+// selected refs are then fetched from the scratch repository as they are, so
+// annotated tags are restored with their tag objects. All refs are updated in
+// a single reference transaction, so either all or none of them are restored
+// and the update is voted on and replicated like any other ref update.
+func (mgr *Manager) restoreSelectedRefs(ctx context.Context, req *RestoreRequest, backup *Backup, objectFormat gitalypb.ObjectFormat) (returnedErr error) {
+	if len(backup.Steps) == 0 {
+		return fmt.Errorf("restore selected refs: %w: backup has no steps", ErrCorrupted)
+	}
+
+	if req.RefNamespace != "" && !strings.HasPrefix(req.RefNamespace, "refs/") {
+		return fmt.Errorf("restore selected refs: invalid ref namespace %q", req.RefNamespace)
+	}
+
+	exists, err := mgr.repositoryExists(ctx, req.Server, req.Repository)
+	if err != nil {
+		return fmt.Errorf("restore selected refs: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("restore selected refs: repository %w", ErrDoesntExist)
+	}
+
+	refs, err := mgr.selectRefs(ctx, backup.Steps[len(backup.Steps)-1].RefPath, req.Refs)
+	if err != nil {
+		return fmt.Errorf("restore selected refs: %w", err)
+	}
+
+	for i, ref := range refs {
+		if req.RefNamespace != "" {
+			refs[i].Name = git.ReferenceName(strings.TrimSuffix(req.RefNamespace, "/") + "/" + strings.TrimPrefix(ref.Name.String(), "refs/"))
+		}
+	}
+
+	if !req.OverwriteRefs {
+		if err := mgr.checkRefsDontExist(ctx, req.Server, req.Repository, refs); err != nil {
+			return fmt.Errorf("restore selected refs: %w", err)
+		}
+	}
+
+	scratch, removeScratch, err := mgr.restoreScratch(ctx, req.Server, req.Repository.GetStorageName(), backup, objectFormat)
+	defer func() {
+		if err := removeScratch(); err != nil && returnedErr == nil {
+			returnedErr = fmt.Errorf("restore selected refs: %w", err)
+		}
+	}()
+	if err != nil {
+		return fmt.Errorf("restore selected refs: %w", err)
+	}
+
+	if err := mgr.fetchSelectedRefs(ctx, req.Server, scratch, req.Repository, refs); err != nil {
+		return fmt.Errorf("restore selected refs: %w", err)
+	}
+
+	return nil
+}
+
+// checkRefsDontExist verifies that none of the refs exist in the repository.
+func (mgr *Manager) checkRefsDontExist(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository, refs []git.Reference) error {
+	refClient, err := mgr.newRefClient(ctx, server)
+	if err != nil {
+		return fmt.Errorf("check refs: %w", err)
+	}
+
+	var existing []string
+	for _, ref := range refs {
+		resp, err := refClient.RefExists(ctx, &gitalypb.RefExistsRequest{
+			Repository: repo,
+			Ref:        []byte(ref.Name),
+		})
+		if err != nil {
+			return fmt.Errorf("check refs: %q: %w", ref.Name, err)
+		}
+		if resp.GetValue() {
+			existing = append(existing, ref.Name.String())
+		}
+	}
+
+	if len(existing) > 0 {
+		return fmt.Errorf("check refs: %w: %s", ErrRefsExist, strings.Join(existing, ", "))
+	}
+
+	return nil
+}
+
+// fetchSelectedRefs writes the refs into the scratch repository and fetches
+// them together with all objects they point to into repo. The scratch
+// repository is bundled with only the given refs, and the bundle is fetched
+// with the FetchBundle RPC, which updates all refs atomically.
+func (mgr *Manager) fetchSelectedRefs(ctx context.Context, server storage.ServerInfo, scratch, repo *gitalypb.Repository, refs []git.Reference) error {
+	repoClient, err := mgr.newRepoClient(ctx, server)
+	if err != nil {
+		return fmt.Errorf("fetch selected refs: %w", err)
+	}
+
+	for _, ref := range refs {
+		if _, err := repoClient.WriteRef(ctx, &gitalypb.WriteRefRequest{
+			Repository: scratch,
+			Ref:        []byte(ref.Name),
+			Revision:   []byte(ref.Target),
+		}); err != nil {
+			return fmt.Errorf("fetch selected refs: write %q: %w", ref.Name, err)
+		}
+	}
+
+	bundleStream, err := repoClient.CreateBundleFromRefList(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch selected refs: %w", err)
+	}
+	c := chunk.New(&createBundleFromRefListSender{
+		stream: bundleStream,
+	})
+	for _, ref := range refs {
+		if err := c.Send(&gitalypb.CreateBundleFromRefListRequest{
+			Repository: scratch,
+			Patterns:   [][]byte{[]byte(ref.Name)},
+		}); err != nil {
+			return fmt.Errorf("fetch selected refs: %w", err)
+		}
+	}
+	if err := c.Flush(); err != nil {
+		return fmt.Errorf("fetch selected refs: %w", err)
+	}
+	if err := bundleStream.CloseSend(); err != nil {
+		return fmt.Errorf("fetch selected refs: %w", err)
+	}
+	bundle := streamio.NewReader(func() ([]byte, error) {
+		resp, err := bundleStream.Recv()
+		return resp.GetData(), err
+	})
+
+	fetchStream, err := repoClient.FetchBundle(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch selected refs: %w", err)
+	}
+	request := &gitalypb.FetchBundleRequest{Repository: repo}
+	writer := streamio.NewWriter(func(p []byte) error {
+		request.Data = p
+		if err := fetchStream.Send(request); err != nil {
+			return err
+		}
+
+		// Only set `Repository` on the first `Send` of the stream
+		request = &gitalypb.FetchBundleRequest{}
+
+		return nil
+	})
+	if _, err := io.Copy(writer, bundle); err != nil {
+		return fmt.Errorf("fetch selected refs: %w", err)
+	}
+	if _, err := fetchStream.CloseAndRecv(); err != nil {
+		return fmt.Errorf("fetch selected refs: %w", err)
+	}
+
+	return nil
+}
+
+// selectRefs returns the refs recorded in the ref file at path which are
+// selected by any of the selectors. A selector selects the ref with the same
+// name or, if it ends with a slash, all refs with that prefix. Every selector
+// must select at least one ref, otherwise the error ErrDoesntExist is returned.
+func (mgr *Manager) selectRefs(ctx context.Context, path string, selectors []string) ([]git.Reference, error) {
+	reader, err := mgr.sink.GetReader(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("select refs: %w", err)
+	}
+	defer reader.Close()
+
+	matched := make(map[string]bool, len(selectors))
+	var refs []git.Reference
+
+	d := git.NewShowRefDecoder(reader)
+	for {
+		var ref git.Reference
+
+		if err := d.Decode(&ref); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("select refs: %q: %w", path, err)
+		}
+
+		if ref.Name == "HEAD" {
+			continue
+		}
+
+		selected := false
+		for _, selector := range selectors {
+			if ref.Name.String() == selector || (strings.HasSuffix(selector, "/") && strings.HasPrefix(ref.Name.String(), selector)) {
+				matched[selector] = true
+				selected = true
+			}
+		}
+		if selected {
+			refs = append(refs, ref)
+		}
+	}
+
+	var missing []string
+	for _, selector := range selectors {
+		if !matched[selector] {
+			missing = append(missing, selector)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("select refs: %w: %s", ErrDoesntExist, strings.Join(missing, ", "))
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,193 @@
+//go:build !gitaly_test_sha256
+
+package backup_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service/setup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/text"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func TestManager_Restore_selectedRefs(t *testing.T) {
+	t.Parallel()
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+	testcfg.BuildGitalySSH(t, cfg)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll)
+
+	ctx := testhelper.Context(t)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	commit := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+	feature := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("feature"), gittest.WithParents(commit))
+	other := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("other"), gittest.WithParents(commit), gittest.WithMessage("other"))
+	tag := gittest.WriteTag(t, cfg, repoPath, "v1.0.0", other.Revision(), gittest.WriteTagConfig{Message: "annotated"})
+
+	backupPath := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(backupPath)
+	locator := backup.PointerLocator{Sink: sink}
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	server := storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token}
+
+	require.NoError(t, backup.NewManager(sink, locator, pool, "abc123").Create(ctx, &backup.CreateRequest{
+		Server:     server,
+		Repository: repo,
+	}))
+
+	// The live repository moves on after the backup has been created.
+	gittest.Exec(t, cfg, "-C", repoPath, "update-ref", "-d", "refs/heads/feature")
+	gittest.Exec(t, cfg, "-C", repoPath, "update-ref", "-d", "refs/heads/other")
+	gittest.Exec(t, cfg, "-C", repoPath, "update-ref", "-d", "refs/tags/v1.0.0")
+	gittest.Exec(t, cfg, "-C", repoPath, "gc", "--prune=now")
+	master := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"), gittest.WithParents(commit), gittest.WithMessage("master"))
+
+	listRefs := func(tb testing.TB) map[string]string {
+		tb.Helper()
+
+		refs := make(map[string]string)
+		for _, line := range strings.Split(text.ChompBytes(gittest.Exec(tb, cfg, "-C", repoPath, "for-each-ref", "--format=%(refname) %(objectname)")), "\n") {
+			name, target, _ := strings.Cut(line, " ")
+			refs[name] = target
+		}
+		return refs
+	}
+
+	for _, tc := range []struct {
+		desc          string
+		repo          *gitalypb.Repository
+		refs          []string
+		refNamespace  string
+		overwriteRefs bool
+		expectedRefs  map[string]string
+		expectedErr   error
+		expectedMsg   string
+	}{
+		{
+			desc:         "ref namespace",
+			repo:         repo,
+			refs:         []string{"refs/heads/feature"},
+			refNamespace: "refs/restored",
+			expectedRefs: map[string]string{
+				"refs/heads/master":           master.String(),
+				"refs/restored/heads/feature": feature.String(),
+			},
+		},
+		{
+			desc: "original names",
+			repo: repo,
+			refs: []string{"refs/heads/feature"},
+			expectedRefs: map[string]string{
+				"refs/heads/feature": feature.String(),
+				"refs/heads/master":  master.String(),
+			},
+		},
+		{
+			desc:         "prefix",
+			repo:         repo,
+			refs:         []string{"refs/heads/"},
+			refNamespace: "refs/restored/",
+			expectedRefs: map[string]string{
+				"refs/heads/master":           master.String(),
+				"refs/restored/heads/feature": feature.String(),
+				"refs/restored/heads/master":  commit.String(),
+				"refs/restored/heads/other":   other.String(),
+			},
+		},
+		{
+			desc:         "annotated tag",
+			repo:         repo,
+			refs:         []string{"refs/tags/v1.0.0"},
+			refNamespace: "refs/restored",
+			expectedRefs: map[string]string{
+				"refs/heads/master":         master.String(),
+				"refs/restored/tags/v1.0.0": tag.String(),
+			},
+		},
+		{
+			desc:        "existing ref",
+			repo:        repo,
+			refs:        []string{"refs/heads/feature", "refs/heads/master"},
+			expectedErr: backup.ErrRefsExist,
+			expectedMsg: "refs/heads/master",
+		},
+		{
+			desc:          "overwrite existing ref",
+			repo:          repo,
+			refs:          []string{"refs/heads/feature", "refs/heads/master"},
+			overwriteRefs: true,
+			expectedRefs: map[string]string{
+				"refs/heads/feature": feature.String(),
+				"refs/heads/master":  commit.String(),
+			},
+		},
+		{
+			desc:        "missing ref",
+			repo:        repo,
+			refs:        []string{"refs/heads/feature", "refs/heads/missing"},
+			expectedErr: backup.ErrDoesntExist,
+			expectedMsg: "refs/heads/missing",
+		},
+		{
+			desc:         "invalid ref namespace",
+			repo:         repo,
+			refs:         []string{"refs/heads/feature"},
+			refNamespace: "restored",
+			expectedMsg:  "manager: restore selected refs: invalid ref namespace \"restored\"",
+		},
+		{
+			desc:        "missing repository",
+			repo:        &gitalypb.Repository{StorageName: repo.GetStorageName(), RelativePath: repo.GetRelativePath() + "-missing.git"},
+			refs:        []string{"refs/heads/feature"},
+			expectedErr: backup.ErrDoesntExist,
+			expectedMsg: "repository doesn't exist",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			for _, ref := range []string{"refs/heads/feature", "refs/restored/heads/feature", "refs/restored/heads/master", "refs/restored/heads/other", "refs/restored/tags/v1.0.0"} {
+				gittest.Exec(t, cfg, "-C", repoPath, "update-ref", "-d", ref)
+			}
+			gittest.Exec(t, cfg, "-C", repoPath, "update-ref", "refs/heads/master", master.String())
+
+			err := backup.NewManager(sink, locator, pool, "unused").Restore(ctx, &backup.RestoreRequest{
+				Server:           server,
+				Repository:       tc.repo,
+				VanityRepository: repo,
+				Refs:             tc.refs,
+				RefNamespace:     tc.refNamespace,
+				OverwriteRefs:    tc.overwriteRefs,
+			})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			}
+			if tc.expectedMsg != "" {
+				require.ErrorContains(t, err, tc.expectedMsg)
+				// Refs are restored all at once, so none of them are restored on failure.
+				require.Equal(t, map[string]string{"refs/heads/master": master.String()}, listRefs(t))
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedRefs, listRefs(t))
+		})
+	}
+
+	scratchRepos, err := filepath.Glob(filepath.Join(cfg.Storages[0].Path, "@backup-verify", "*", "*", "*.git"))
+	require.NoError(t, err)
+	require.Empty(t, scratchRepos)
+}
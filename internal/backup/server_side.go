@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// errServerSideVerifyUnsupported is returned when trying to verify a backup
+// through a ServerSideAdapter.
+var errServerSideVerifyUnsupported = errors.New("server-side adapter: verify: not supported")
+
+// errServerSideTimestampUnsupported is returned when trying to restore a backup
+// at a point in time through a ServerSideAdapter.
+var errServerSideTimestampUnsupported = errors.New("server-side adapter: restore: timestamp not supported")
+
+// errServerSideRefsUnsupported is returned when trying to restore selected refs
+// through a ServerSideAdapter.
+var errServerSideRefsUnsupported = errors.New("server-side adapter: restore: refs not supported")
+
+// ServerSideAdapter allows calling the server-side backup RPCs
+// `BackupRepository` and `RestoreRepository` through `Pipeline` callers. The
+// backups are then written and read by Gitaly directly, using the backup sink
+// configured on the server.
+type ServerSideAdapter struct {
+	pool     *client.Pool
+	backupID string
+}
+
+// NewServerSideAdapter creates and returns initialized *ServerSideAdapter instance.
+func NewServerSideAdapter(pool *client.Pool, backupID string) *ServerSideAdapter {
+	return &ServerSideAdapter{
+		pool:     pool,
+		backupID: backupID,
+	}
+}
+
+// Create calls the BackupRepository RPC.
+func (ss ServerSideAdapter) Create(ctx context.Context, req *CreateRequest) error {
+	repoClient, err := ss.newRepoClient(ctx, req.Server)
+	if err != nil {
+		return fmt.Errorf("server-side create: %w", err)
+	}
+
+	_, err = repoClient.BackupRepository(ctx, &gitalypb.BackupRepositoryRequest{
+		Repository:       req.Repository,
+		VanityRepository: vanityRepository(req.Repository, req.VanityRepository),
+		BackupId:         ss.backupID,
+		Incremental:      req.Incremental,
+	})
+	if err != nil {
+		if isSkipped(err, &gitalypb.BackupRepositoryResponse_SkippedError{}) {
+			return fmt.Errorf("server-side create: %w: %s", ErrSkipped, err.Error())
+		}
+
+		return fmt.Errorf("server-side create: %w", err)
+	}
+
+	return nil
+}
+
+// Restore calls the RestoreRepository RPC. The repository is created through
+// the regular endpoint first so that Praefect, if any, knows about it, and is
+// then overwritten by Gitaly with the contents of the backup.
+func (ss ServerSideAdapter) Restore(ctx context.Context, req *RestoreRequest) error {
+	if !req.Timestamp.IsZero() {
+		return errServerSideTimestampUnsupported
+	}
+	if len(req.Refs) > 0 {
+		return errServerSideRefsUnsupported
+	}
+
+	repoClient, err := ss.newRepoClient(ctx, req.Server)
+	if err != nil {
+		return fmt.Errorf("server-side restore: %w", err)
+	}
+
+	if err := ss.removeRepository(ctx, repoClient, req.Repository); err != nil {
+		return fmt.Errorf("server-side restore: %w", err)
+	}
+
+	if _, err := repoClient.CreateRepository(ctx, &gitalypb.CreateRepositoryRequest{
+		Repository: req.Repository,
+	}); err != nil {
+		return fmt.Errorf("server-side restore: create repository: %w", err)
+	}
+
+	_, err = repoClient.RestoreRepository(ctx, &gitalypb.RestoreRepositoryRequest{
+		Repository:       req.Repository,
+		VanityRepository: vanityRepository(req.Repository, req.VanityRepository),
+		BackupId:         req.BackupID,
+		AlwaysCreate:     req.AlwaysCreate,
+	})
+	if err != nil {
+		if isSkipped(err, &gitalypb.RestoreRepositoryResponse_SkippedError{}) {
+			// Gitaly has already removed the repository from its storage, but
+			// the removal has to go through the regular endpoint as well so that
+			// Praefect, if any, forgets about it.
+			if err := ss.removeRepository(ctx, repoClient, req.Repository); err != nil {
+				return fmt.Errorf("server-side restore: remove on skipped: %w", err)
+			}
+
+			return fmt.Errorf("server-side restore: %w: %s", ErrSkipped, err.Error())
+		}
+
+		return fmt.Errorf("server-side restore: %w", err)
+	}
+
+	return nil
+}
+
+// Verify is not supported by the server-side adapter.
+func (ss ServerSideAdapter) Verify(context.Context, *VerifyRequest) (*VerifyResult, error) {
+	return nil, errServerSideVerifyUnsupported
+}
+
+func (ss ServerSideAdapter) removeRepository(ctx context.Context, repoClient gitalypb.RepositoryServiceClient, repo *gitalypb.Repository) error {
+	_, err := repoClient.RemoveRepository(ctx, &gitalypb.RemoveRepositoryRequest{Repository: repo})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		return nil
+	case err != nil:
+		return fmt.Errorf("remove repository: %w", err)
+	}
+	return nil
+}
+
+func (ss ServerSideAdapter) newRepoClient(ctx context.Context, server storage.ServerInfo) (gitalypb.RepositoryServiceClient, error) {
+	conn, err := ss.pool.Dial(ctx, server.Address, server.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return gitalypb.NewRepositoryServiceClient(conn), nil
+}
+
+// isSkipped determines whether the error returned by a server-side backup RPC
+// carries the given skipped error detail.
+func isSkipped(err error, skipped proto.Message) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if detail, ok := detail.(proto.Message); ok && proto.MessageName(detail) == proto.MessageName(skipped) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+//go:build !gitaly_test_sha256
+
+package backup_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service/setup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
+)
+
+func TestServerSideAdapter(t *testing.T) {
+	t.Parallel()
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	backupRoot := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(backupRoot)
+	locator, err := backup.ResolveLocator("pointer", sink)
+	require.NoError(t, err)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll,
+		testserver.WithBackupSink(sink),
+		testserver.WithBackupLocator(locator),
+	)
+
+	ctx := testhelper.Context(t)
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	server := storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token}
+	adapter := backup.NewServerSideAdapter(pool, "abc123")
+
+	t.Run("create and restore", func(t *testing.T) {
+		repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+		commitID := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+
+		require.NoError(t, adapter.Create(ctx, &backup.CreateRequest{
+			Server:     server,
+			Repository: repo,
+		}))
+
+		b, err := locator.Find(ctx, repo, "abc123")
+		require.NoError(t, err)
+		require.Len(t, b.Steps, 1)
+
+		gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("feature"))
+
+		require.NoError(t, adapter.Restore(ctx, &backup.RestoreRequest{
+			Server:     server,
+			Repository: repo,
+		}))
+
+		// Restoring recreates the repository, which changes its replica path
+		// when running with Praefect.
+		repoPath = filepath.Join(cfg.Storages[0].Path, gittest.GetReplicaPath(t, ctx, cfg, repo))
+		refs := gittest.Exec(t, cfg, "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)")
+		require.Equal(t, commitID.String()+" refs/heads/master\n", string(refs))
+	})
+
+	t.Run("create empty repository", func(t *testing.T) {
+		repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+		err := adapter.Create(ctx, &backup.CreateRequest{
+			Server:     server,
+			Repository: repo,
+		})
+		require.ErrorIs(t, err, backup.ErrSkipped)
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+		_, err := adapter.Verify(ctx, &backup.VerifyRequest{
+			Server:     server,
+			Repository: repo,
+		})
+		require.EqualError(t, err, "server-side adapter: verify: not supported")
+	})
+
+	t.Run("restore at timestamp", func(t *testing.T) {
+		repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+		err := adapter.Restore(ctx, &backup.RestoreRequest{
+			Server:     server,
+			Repository: repo,
+			Timestamp:  time.Now(),
+		})
+		require.EqualError(t, err, "server-side adapter: restore: timestamp not supported")
+	})
+
+	t.Run("restore selected refs", func(t *testing.T) {
+		repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+		err := adapter.Restore(ctx, &backup.RestoreRequest{
+			Server:     server,
+			Repository: repo,
+			Refs:       []string{"refs/heads/master"},
+		})
+		require.EqualError(t, err, "server-side adapter: restore: refs not supported")
+	})
+}
@@ -82,3 +82,35 @@ func (s *StorageServiceSink) GetReader(ctx context.Context, relativePath string)
 	}
 	return reader, nil
 }
+
+// List returns the keys of all objects stored below prefix on the configured bucket.
+func (s *StorageServiceSink) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+
+	iter := s.bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("storage service sink: list %q: %w", prefix, err)
+		}
+		if obj.IsDir {
+			continue
+		}
+		paths = append(paths, obj.Key)
+	}
+
+	return paths, nil
+}
+
+// Delete removes the data stored by relativePath from the configured bucket.
+func (s *StorageServiceSink) Delete(ctx context.Context, relativePath string) error {
+	if err := s.bucket.Delete(ctx, relativePath); err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			err = ErrDoesntExist
+		}
+		return fmt.Errorf("storage service sink: delete %q: %w", relativePath, err)
+	}
+	return nil
+}
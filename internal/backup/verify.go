@@ -0,0 +1,387 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/text"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+// scratchRepositoryPrefix is the directory in which repositories that backups
+// are restored into for verification are created.
+const scratchRepositoryPrefix = "@backup-verify"
+
+// VerifyRequest is the request to verify a backup
+type VerifyRequest struct {
+	Server     storage.ServerInfo
+	Repository *gitalypb.Repository
+	// BackupID is the ID of the backup to verify. The latest backup is
+	// verified if it is empty.
+	BackupID string
+}
+
+// VerifyResult describes the outcome of verifying a backup.
+type VerifyResult struct {
+	// BackupID is the ID of the verified backup, if it has one.
+	BackupID string
+	// Steps is the number of steps of the verified backup.
+	Steps int
+	// MismatchedRefs are the refs recorded by the last step of the backup
+	// which either don't exist in the restored repository or point to a
+	// different target.
+	MismatchedRefs []string
+	// UnexpectedRefs are the refs of the restored repository which haven't
+	// been recorded by the last step of the backup. Fetching bundles never
+	// deletes refs, so refs which have been deleted between two steps show
+	// up here. They don't fail the verification.
+	UnexpectedRefs []string
+}
+
+// Verify proves that a backup is restorable without touching the backed up
+// repository. The whole backup is validated, restored into a scratch
+// repository on the server of the repository and checked for connectivity
+// with git-fsck(1). The refs of the scratch repository are then compared to
+// the refs recorded by the last step of the backup. The scratch repository is
+// removed afterwards.
+//
+// Backups which depend on an object pool additionally restore the latest
+// backup of the object pool into a scratch object pool.
+func (mgr *Manager) Verify(ctx context.Context, req *VerifyRequest) (_ *VerifyResult, returnedErr error) {
+	backup, err := mgr.findBackup(ctx, req.Repository, req.BackupID)
+	if err != nil {
+		return nil, fmt.Errorf("manager: %w", err)
+	}
+
+	result := &VerifyResult{
+		BackupID: backup.ID,
+		Steps:    len(backup.Steps),
+	}
+	if len(backup.Steps) == 0 {
+		return result, fmt.Errorf("manager: %w: backup has no steps", ErrCorrupted)
+	}
+
+	objectFormat, err := mgr.validateBackup(ctx, backup)
+	if err != nil {
+		return result, fmt.Errorf("manager: %w", err)
+	}
+
+	scratch, removeScratch, err := mgr.restoreScratch(ctx, req.Server, req.Repository.GetStorageName(), backup, objectFormat)
+	defer func() {
+		if err := removeScratch(); err != nil && returnedErr == nil {
+			returnedErr = fmt.Errorf("manager: %w", err)
+		}
+	}()
+	if err != nil {
+		return result, fmt.Errorf("manager: %w", err)
+	}
+
+	if err := mgr.fsck(ctx, req.Server, scratch); err != nil {
+		return result, fmt.Errorf("manager: %w", err)
+	}
+
+	result.MismatchedRefs, result.UnexpectedRefs, err = mgr.compareRefs(ctx, backup.Steps[len(backup.Steps)-1].RefPath, req.Server, scratch)
+	if err != nil {
+		return result, fmt.Errorf("manager: %w", err)
+	}
+	if len(result.MismatchedRefs) > 0 {
+		return result, fmt.Errorf("manager: compare refs: %w: %d refs don't match", ErrCorrupted, len(result.MismatchedRefs))
+	}
+
+	return result, nil
+}
+
+// restoreScratch restores all steps of backup into a new scratch repository on
+// server. Backups which depend on an object pool additionally restore the
+// latest backup of the object pool into a scratch object pool. The returned
+// function removes all scratch repositories which have been created. It must
+// be called even if an error is returned.
+func (mgr *Manager) restoreScratch(ctx context.Context, server storage.ServerInfo, storageName string, backup *Backup, objectFormat gitalypb.ObjectFormat) (*gitalypb.Repository, func() error, error) {
+	var created []*gitalypb.Repository
+	remove := func() error {
+		var removeErr error
+		// The scratch repository is removed before the object pool it is
+		// linked to.
+		for i := len(created) - 1; i >= 0; i-- {
+			if err := mgr.removeRepository(ctx, server, created[i]); err != nil && removeErr == nil {
+				removeErr = fmt.Errorf("remove scratch repository: %w", err)
+			}
+		}
+		return removeErr
+	}
+
+	objectPool := backup.objectPool()
+	var scratchPool *gitalypb.Repository
+	if objectPool != "" {
+		var err error
+		scratchPool, err = newScratchRepository(storageName, "@pools")
+		if err != nil {
+			return nil, remove, err
+		}
+		created = append(created, scratchPool)
+
+		if err := mgr.restoreScratchObjectPool(ctx, server, scratchPool, objectPool, backup); err != nil {
+			return nil, remove, err
+		}
+	}
+
+	scratch, err := newScratchRepository(storageName, scratchRepositoryPrefix)
+	if err != nil {
+		return nil, remove, err
+	}
+	created = append(created, scratch)
+
+	if err := mgr.createRepository(ctx, server, scratch, objectFormat); err != nil {
+		return nil, remove, err
+	}
+
+	if scratchPool != nil {
+		objectPoolClient, err := mgr.newObjectPoolClient(ctx, server)
+		if err != nil {
+			return nil, remove, err
+		}
+
+		if _, err := objectPoolClient.LinkRepositoryToObjectPool(ctx, &gitalypb.LinkRepositoryToObjectPoolRequest{
+			ObjectPool: &gitalypb.ObjectPool{Repository: scratchPool},
+			Repository: scratch,
+		}); err != nil {
+			return nil, remove, fmt.Errorf("link scratch object pool: %w", err)
+		}
+	}
+
+	for _, step := range backup.Steps {
+		if err := mgr.restoreBundle(ctx, step.BundlePath, step.BundleChecksum, server, scratch); err != nil {
+			if step.SkippableOnNotFound && errors.Is(err, ErrDoesntExist) {
+				return nil, remove, fmt.Errorf("%w: %s", ErrSkipped, err.Error())
+			}
+			return nil, remove, err
+		}
+		if objectPool != "" {
+			if err := mgr.restoreRefs(ctx, step.RefPath, server, scratch); err != nil {
+				return nil, remove, err
+			}
+		}
+		if err := mgr.restoreCustomHooks(ctx, step.CustomHooksPath, server, scratch); err != nil {
+			return nil, remove, err
+		}
+	}
+
+	return scratch, remove, nil
+}
+
+func (mgr *Manager) findBackup(ctx context.Context, repo *gitalypb.Repository, backupID string) (*Backup, error) {
+	if backupID == "" {
+		return mgr.locator.FindLatest(ctx, repo)
+	}
+	return mgr.locator.Find(ctx, repo, backupID)
+}
+
+// restoreScratchObjectPool restores the backups of the object pool at
+// relativePath which backup has been created against into the scratch object
+// pool.
+func (mgr *Manager) restoreScratchObjectPool(ctx context.Context, server storage.ServerInfo, scratchPool *gitalypb.Repository, relativePath string, backup *Backup) error {
+	poolBackups, err := mgr.findObjectPoolBackups(ctx, &gitalypb.Repository{
+		StorageName:  scratchPool.GetStorageName(),
+		RelativePath: relativePath,
+	}, backup)
+	if err != nil {
+		return fmt.Errorf("restore object pool: %q: %w", relativePath, err)
+	}
+
+	for i, poolBackup := range poolBackups {
+		objectFormat, err := mgr.validateBackup(ctx, poolBackup)
+		if err != nil {
+			return fmt.Errorf("restore object pool: %q: %w", relativePath, err)
+		}
+
+		if i == 0 {
+			if err := mgr.createRepository(ctx, server, scratchPool, objectFormat); err != nil {
+				return fmt.Errorf("restore object pool: %q: %w", relativePath, err)
+			}
+		}
+
+		for _, step := range poolBackup.Steps {
+			if err := mgr.restoreBundle(ctx, step.BundlePath, step.BundleChecksum, server, scratchPool); err != nil {
+				return fmt.Errorf("restore object pool: %q: %w", relativePath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (mgr *Manager) fsck(ctx context.Context, server storage.ServerInfo, repo *gitalypb.Repository) error {
+	repoClient, err := mgr.newRepoClient(ctx, server)
+	if err != nil {
+		return fmt.Errorf("fsck: %w", err)
+	}
+
+	resp, err := repoClient.Fsck(ctx, &gitalypb.FsckRequest{Repository: repo})
+	if err != nil {
+		return fmt.Errorf("fsck: %w", err)
+	}
+
+	if len(resp.GetError()) > 0 {
+		return fmt.Errorf("fsck: %w: %s", ErrCorrupted, text.ChompBytes(resp.GetError()))
+	}
+
+	return nil
+}
+
+// compareRefs compares the refs recorded in the ref file at path to the refs
+// of the repository. HEAD is ignored as it isn't restored verbatim.
+func (mgr *Manager) compareRefs(ctx context.Context, path string, server storage.ServerInfo, repo *gitalypb.Repository) (mismatched, unexpected []string, _ error) {
+	reader, err := mgr.sink.GetReader(ctx, path)
+	if err != nil {
+		if errors.Is(err, ErrDoesntExist) {
+			return nil, nil, fmt.Errorf("compare refs: %w: %s", ErrCorrupted, err.Error())
+		}
+		return nil, nil, fmt.Errorf("compare refs: %w", err)
+	}
+	defer reader.Close()
+
+	expected := make(map[string]string)
+
+	d := git.NewShowRefDecoder(reader)
+	for {
+		var ref git.Reference
+
+		if err := d.Decode(&ref); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("compare refs: %q: %w", path, err)
+		}
+
+		if ref.Name == "HEAD" {
+			continue
+		}
+		expected[ref.Name.String()] = ref.Target
+	}
+
+	refs, err := mgr.listRefs(ctx, server, repo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compare refs: %w", err)
+	}
+
+	actual := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		if string(ref.GetName()) == "HEAD" {
+			continue
+		}
+		actual[string(ref.GetName())] = ref.GetTarget()
+	}
+
+	for name, target := range expected {
+		if actual[name] != target {
+			mismatched = append(mismatched, name)
+		}
+	}
+	for name := range actual {
+		if _, ok := expected[name]; !ok {
+			unexpected = append(unexpected, name)
+		}
+	}
+
+	sort.Strings(mismatched)
+	sort.Strings(unexpected)
+
+	return mismatched, unexpected, nil
+}
+
+// newScratchRepository returns a repository with a random relative path in
+// the directory prefix, formatted like the hashed storage paths of Rails so
+// that scratch object pools are recognized as object pools.
+func newScratchRepository(storageName, prefix string) (*gitalypb.Repository, error) {
+	hash, err := text.RandomHex(sha256.Size)
+	if err != nil {
+		return nil, fmt.Errorf("scratch repository: %w", err)
+	}
+
+	return &gitalypb.Repository{
+		StorageName:  storageName,
+		RelativePath: filepath.Join(prefix, hash[0:2], hash[2:4], hash+".git"),
+	}, nil
+}
+
+// VerifyStatus is the outcome of verifying the backup of a repository.
+type VerifyStatus string
+
+const (
+	// VerifyStatusOK means that the backup has been restored successfully.
+	VerifyStatusOK VerifyStatus = "ok"
+	// VerifyStatusSkipped means that there was no backup to verify.
+	VerifyStatusSkipped VerifyStatus = "skipped"
+	// VerifyStatusFailed means that the backup couldn't be restored or that
+	// the restored repository doesn't match the backup.
+	VerifyStatusFailed VerifyStatus = "failed"
+)
+
+// VerifyReport is the machine-readable report of verifying the backup of a
+// single repository.
+type VerifyReport struct {
+	StorageName    string       `json:"storage_name"`
+	RelativePath   string       `json:"relative_path"`
+	GlProjectPath  string       `json:"gl_project_path,omitempty"`
+	BackupID       string       `json:"backup_id,omitempty"`
+	Steps          int          `json:"steps"`
+	Status         VerifyStatus `json:"status"`
+	Error          string       `json:"error,omitempty"`
+	MismatchedRefs []string     `json:"mismatched_refs,omitempty"`
+	UnexpectedRefs []string     `json:"unexpected_refs,omitempty"`
+}
+
+// VerifyReporter writes a VerifyReport for every verified repository as a
+// single line of JSON.
+type VerifyReporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewVerifyReporter returns a reporter which writes reports to w.
+func NewVerifyReporter(w io.Writer) *VerifyReporter {
+	return &VerifyReporter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Report writes the report for repo given the result and error returned by
+// Verify.
+func (r *VerifyReporter) Report(repo *gitalypb.Repository, result *VerifyResult, verifyErr error) error {
+	report := VerifyReport{
+		StorageName:   repo.GetStorageName(),
+		RelativePath:  repo.GetRelativePath(),
+		GlProjectPath: repo.GetGlProjectPath(),
+		Status:        VerifyStatusOK,
+	}
+	if result != nil {
+		report.BackupID = result.BackupID
+		report.Steps = result.Steps
+		report.MismatchedRefs = result.MismatchedRefs
+		report.UnexpectedRefs = result.UnexpectedRefs
+	}
+	if verifyErr != nil {
+		report.Status = VerifyStatusFailed
+		if errors.Is(verifyErr, ErrSkipped) {
+			report.Status = VerifyStatusSkipped
+		}
+		report.Error = verifyErr.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.encoder.Encode(report); err != nil {
+		return fmt.Errorf("verify reporter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+//go:build !gitaly_test_sha256
+
+package backup_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service/setup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
+)
+
+func TestManager_Verify(t *testing.T) {
+	t.Parallel()
+
+	cfg := testcfg.Build(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	cfg.SocketPath = testserver.RunGitalyServer(t, cfg, nil, setup.RegisterAll, testserver.WithDisablePraefect())
+
+	ctx := testhelper.Context(t)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	commit := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+	gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("feature"), gittest.WithParents(commit))
+
+	backupPath := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(backupPath)
+	locator := backup.PointerLocator{Sink: sink}
+	pool := client.NewPool()
+	defer testhelper.MustClose(t, pool)
+
+	server := storage.ServerInfo{Address: cfg.SocketPath, Token: cfg.Auth.Token}
+
+	require.NoError(t, backup.NewManager(sink, locator, pool, "abc123").Create(ctx, &backup.CreateRequest{
+		Server:     server,
+		Repository: repo,
+	}))
+
+	// Incremental backups don't record deleted refs.
+	gittest.Exec(t, cfg, "-C", repoPath, "update-ref", "-d", "refs/heads/feature")
+	gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"), gittest.WithParents(commit), gittest.WithMessage("second"))
+	require.NoError(t, backup.NewManager(sink, locator, pool, "abc123").Create(ctx, &backup.CreateRequest{
+		Server:      server,
+		Repository:  repo,
+		Incremental: true,
+	}))
+
+	require.NoError(t, backup.NewManager(sink, locator, pool, "def456").Create(ctx, &backup.CreateRequest{
+		Server:     server,
+		Repository: repo,
+	}))
+
+	// A backup without manifest, so without checksums, whose refs don't match its bundle.
+	repoBackupPath := filepath.Join(backupPath, repo.GetRelativePath()[:len(repo.GetRelativePath())-len(".git")])
+	require.NoError(t, os.MkdirAll(filepath.Join(repoBackupPath, "ghi789"), 0o755))
+	testhelper.CopyFile(t, filepath.Join(repoBackupPath, "def456", "001.bundle"), filepath.Join(repoBackupPath, "ghi789", "001.bundle"))
+	refs := testhelper.MustReadFile(t, filepath.Join(repoBackupPath, "def456", "001.refs"))
+	refs = append(refs, []byte(fmt.Sprintf("%s refs/heads/missing\n", commit))...)
+	require.NoError(t, os.WriteFile(filepath.Join(repoBackupPath, "ghi789", "001.refs"), refs, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoBackupPath, "ghi789", "LATEST"), []byte("001"), 0o644))
+
+	for _, tc := range []struct {
+		desc           string
+		backupID       string
+		expectedResult *backup.VerifyResult
+		expectedErr    error
+	}{
+		{
+			desc: "latest",
+			expectedResult: &backup.VerifyResult{
+				BackupID: "def456",
+				Steps:    1,
+			},
+		},
+		{
+			desc:     "incremental",
+			backupID: "abc123",
+			expectedResult: &backup.VerifyResult{
+				BackupID:       "abc123",
+				Steps:          2,
+				UnexpectedRefs: []string{"refs/heads/feature"},
+			},
+		},
+		{
+			desc:     "mismatched refs",
+			backupID: "ghi789",
+			expectedResult: &backup.VerifyResult{
+				BackupID:       "ghi789",
+				Steps:          1,
+				MismatchedRefs: []string{"refs/heads/missing"},
+			},
+			expectedErr: backup.ErrCorrupted,
+		},
+		{
+			desc:        "missing backup",
+			backupID:    "missing",
+			expectedErr: backup.ErrDoesntExist,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := backup.NewManager(sink, locator, pool, "unused").Verify(ctx, &backup.VerifyRequest{
+				Server:     server,
+				Repository: repo,
+				BackupID:   tc.backupID,
+			})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedResult, result)
+		})
+	}
+
+	scratchRepos, err := filepath.Glob(filepath.Join(cfg.Storages[0].Path, "@backup-verify", "*", "*", "*.git"))
+	require.NoError(t, err)
+	require.Empty(t, scratchRepos)
+}
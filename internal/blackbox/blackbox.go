@@ -208,12 +208,12 @@ func (b Blackbox) push(probe Probe) error {
 
 	commands := make([]stats.PushCommand, len(probe.Push.Commands))
 	for i, command := range probe.Push.Commands {
-		oldOID, err := git.ObjectHashSHA1.FromHex(command.OldOID)
+		oldOID, err := objectHash.FromHex(command.OldOID)
 		if err != nil {
 			return fmt.Errorf("invalid old object ID for probe %q: %w", probe.Name, err)
 		}
 
-		newOID, err := git.ObjectHashSHA1.FromHex(command.NewOID)
+		newOID, err := objectHash.FromHex(command.NewOID)
 		if err != nil {
 			return fmt.Errorf("invalid new object ID for probe %q: %w", probe.Name, err)
 		}
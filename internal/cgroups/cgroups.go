@@ -1,13 +1,10 @@
 package cgroups
 
 import (
-	"path/filepath"
-
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/command"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/repository"
-	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
 )
 
@@ -19,6 +16,9 @@ type Manager interface {
 	Setup() error
 	// AddCommand adds a Command to a cgroup.
 	AddCommand(*command.Command, repository.GitRepo) (string, error)
+	// Update applies the limits of the given configuration to the cgroups created in Setup.
+	// The configuration must only differ in ways permitted by Config.ValidateReload.
+	Update(cgroups.Config) error
 	// Cleanup cleans up cgroups created in Setup.
 	// It is expected to be called once at Gitaly shutdown from any
 	// instance of the Manager.
@@ -29,32 +29,14 @@ type Manager interface {
 
 // NewManager returns the appropriate Cgroups manager
 func NewManager(cfg cgroups.Config, pid int) Manager {
-	if cfg.Repositories.Count > 0 {
-		return newV1Manager(cfg, pid)
+	if cfg.Repositories.Count > 0 || len(cfg.Buckets) > 0 {
+		return newCgroupManager(cfg, pid)
 	}
 
 	return &NoopManager{}
 }
 
-// PruneOldCgroups prunes old cgroups for both the memory and cpu subsystems
+// PruneOldCgroups prunes cgroups left behind by Gitaly processes which are not running anymore
 func PruneOldCgroups(cfg cgroups.Config, logger log.FieldLogger) {
-	if cfg.HierarchyRoot == "" {
-		return
-	}
-
-	if err := config.PruneOldGitalyProcessDirectories(
-		logger,
-		filepath.Join(cfg.Mountpoint, "memory",
-			cfg.HierarchyRoot),
-	); err != nil {
-		logger.WithError(err).Error("failed to clean up memory cgroups")
-	}
-
-	if err := config.PruneOldGitalyProcessDirectories(
-		logger,
-		filepath.Join(cfg.Mountpoint, "cpu",
-			cfg.HierarchyRoot),
-	); err != nil {
-		logger.WithError(err).Error("failed to clean up cpu cgroups")
-	}
+	pruneOldCgroups(cfg, logger)
 }
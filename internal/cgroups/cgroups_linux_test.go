@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	cgrps "github.com/containerd/cgroups"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
@@ -22,7 +23,10 @@ func TestMain(m *testing.M) {
 func TestNewManager(t *testing.T) {
 	cfg := cgroups.Config{Repositories: cgroups.Repositories{Count: 10}}
 
-	require.IsType(t, &CGroupV1Manager{}, &CGroupV1Manager{cfg: cfg})
+	require.IsType(t, &CGroupV1Manager{}, newCgroupManagerWithMode(cfg, 1, cgrps.Legacy))
+	require.IsType(t, &CGroupV1Manager{}, newCgroupManagerWithMode(cfg, 1, cgrps.Hybrid))
+	require.IsType(t, &CGroupV2Manager{}, newCgroupManagerWithMode(cfg, 1, cgrps.Unified))
+	require.IsType(t, &NoopManager{}, newCgroupManagerWithMode(cfg, 1, cgrps.Unavailable))
 	require.IsType(t, &NoopManager{}, NewManager(cgroups.Config{}, 1))
 }
 
@@ -49,7 +53,7 @@ func TestPruneOldCgroups(t *testing.T) {
 			},
 			setup: func(t *testing.T, cfg cgroups.Config) int {
 				pid := 1
-				cgroupManager := NewManager(cfg, pid)
+				cgroupManager := newCgroupManagerWithMode(cfg, pid, cgrps.Legacy)
 				require.NoError(t, cgroupManager.Setup())
 
 				return pid
@@ -69,7 +73,7 @@ func TestPruneOldCgroups(t *testing.T) {
 			},
 			setup: func(t *testing.T, cfg cgroups.Config) int {
 				pid := 1
-				cgroupManager := NewManager(cfg, pid)
+				cgroupManager := newCgroupManagerWithMode(cfg, pid, cgrps.Legacy)
 				require.NoError(t, cgroupManager.Setup())
 
 				return 1
@@ -92,7 +96,7 @@ func TestPruneOldCgroups(t *testing.T) {
 				require.NoError(t, cmd.Run())
 				pid := cmd.Process.Pid
 
-				cgroupManager := NewManager(cfg, pid)
+				cgroupManager := newCgroupManagerWithMode(cfg, pid, cgrps.Legacy)
 				require.NoError(t, cgroupManager.Setup())
 
 				return pid
@@ -113,7 +117,7 @@ func TestPruneOldCgroups(t *testing.T) {
 			setup: func(t *testing.T, cfg cgroups.Config) int {
 				pid := os.Getpid()
 
-				cgroupManager := NewManager(cfg, pid)
+				cgroupManager := newCgroupManagerWithMode(cfg, pid, cgrps.Legacy)
 				require.NoError(t, cgroupManager.Setup())
 
 				return pid
@@ -141,7 +145,7 @@ func TestPruneOldCgroups(t *testing.T) {
 			pid := tc.setup(t, tc.cfg)
 
 			logger, hook := test.NewNullLogger()
-			PruneOldCgroups(tc.cfg, logger)
+			pruneOldCgroupsWithMode(tc.cfg, logger, cgrps.Legacy)
 
 			// create cgroups directories with a different pid
 			oldGitalyProcessMemoryDir := filepath.Join(
@@ -0,0 +1,25 @@
+package cgroups
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// blockDeviceNumbers returns the major and minor device numbers of the block device node at the
+// given path. Block I/O limits are configured by device numbers in both cgroups v1 and v2.
+func blockDeviceNumbers(path string) (int64, int64, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return 0, 0, fmt.Errorf("stat block device: %w", err)
+	}
+
+	if st.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return 0, 0, fmt.Errorf("not a block device: %q", path)
+	}
+
+	//nolint:unconvert // Rdev has different types depending on the architecture.
+	rdev := uint64(st.Rdev)
+
+	return int64(unix.Major(rdev)), int64(unix.Minor(rdev)), nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package cgroups
+
+import (
+	log "github.com/sirupsen/logrus"
+	cgroupscfg "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
+)
+
+// For systems other than Linux, we return a noop manager if cgroups was enabled.
+func newCgroupManager(cfg cgroupscfg.Config, pid int) Manager {
+	return &NoopManager{}
+}
+
+// For systems other than Linux, there are no cgroups that could be pruned.
+func pruneOldCgroups(cfg cgroupscfg.Config, logger log.FieldLogger) {}
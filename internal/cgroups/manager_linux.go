@@ -0,0 +1,51 @@
+package cgroups
+
+import (
+	"github.com/containerd/cgroups"
+	log "github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/command"
+	cgroupscfg "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
+)
+
+// newCgroupManager returns a manager for the cgroup hierarchy the host is running with.
+func newCgroupManager(cfg cgroupscfg.Config, pid int) Manager {
+	return newCgroupManagerWithMode(cfg, pid, cgroups.Mode())
+}
+
+func newCgroupManagerWithMode(cfg cgroupscfg.Config, pid int, mode cgroups.CGMode) Manager {
+	switch mode {
+	case cgroups.Legacy, cgroups.Hybrid:
+		return newV1Manager(cfg, pid)
+	case cgroups.Unified:
+		return newV2Manager(cfg, pid)
+	default:
+		log.WithField("cgroup_mode", mode).Warn("cgroups are not available, disabling cgroups support")
+		return &NoopManager{}
+	}
+}
+
+func pruneOldCgroups(cfg cgroupscfg.Config, logger log.FieldLogger) {
+	pruneOldCgroupsWithMode(cfg, logger, cgroups.Mode())
+}
+
+func pruneOldCgroupsWithMode(cfg cgroupscfg.Config, logger log.FieldLogger, mode cgroups.CGMode) {
+	switch mode {
+	case cgroups.Legacy, cgroups.Hybrid:
+		pruneOldCgroupsV1(cfg, logger)
+	case cgroups.Unified:
+		pruneOldCgroupsV2(cfg, logger)
+	}
+}
+
+// matchBucket returns the first configured bucket that the command matches.
+func matchBucket(buckets []cgroupscfg.Bucket, cmd *command.Command) (cgroupscfg.Bucket, bool) {
+	service, method := cmd.RPC()
+
+	for _, bucket := range buckets {
+		if bucket.Matches(service, method, cmd.Name(), cmd.Subcommand()) {
+			return bucket, true
+		}
+	}
+
+	return cgroupscfg.Bucket{}, false
+}
@@ -0,0 +1,57 @@
+package cgroups
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newPidsGaugeVec() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gitaly_cgroup_pids_total",
+			Help: "Current number of processes and process limit of cgroup",
+		},
+		[]string{"path", "type"},
+	)
+}
+
+func newIOBytesGaugeVec() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gitaly_cgroup_io_bytes_total",
+			Help: "Number of bytes read from and written to block devices by cgroup",
+		},
+		[]string{"path", "op"},
+	)
+}
+
+func newIOOperationsGaugeVec() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gitaly_cgroup_io_operations_total",
+			Help: "Number of read and write operations on block devices by cgroup",
+		},
+		[]string{"path", "op"},
+	)
+}
+
+// collectPids reports the current number of processes and the process limit. A limit of 0
+// means that the cgroup is not limited.
+func collectPids(ch chan<- prometheus.Metric, pids *prometheus.GaugeVec, path string, current, limit uint64) {
+	currentMetric := pids.WithLabelValues(path, "current")
+	currentMetric.Set(float64(current))
+	ch <- currentMetric
+
+	limitMetric := pids.WithLabelValues(path, "limit")
+	limitMetric.Set(float64(limit))
+	ch <- limitMetric
+}
+
+func collectIO(ch chan<- prometheus.Metric, gauge *prometheus.GaugeVec, path string, read, write uint64) {
+	readMetric := gauge.WithLabelValues(path, "read")
+	readMetric.Set(float64(read))
+	ch <- readMetric
+
+	writeMetric := gauge.WithLabelValues(path, "write")
+	writeMetric.Set(float64(write))
+	ch <- writeMetric
+}
@@ -25,10 +25,12 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/containerd/cgroups"
 	"github.com/stretchr/testify/require"
+	cgroupscfg "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
 )
 
@@ -39,10 +41,17 @@ type mockCgroup struct {
 
 func newMock(t *testing.T) *mockCgroup {
 	t.Helper()
+	return newMockWithConfig(t, cgroupscfg.Config{})
+}
+
+// newMockWithConfig creates a new mock whose subsystems match the ones that would be enabled
+// for the given configuration.
+func newMockWithConfig(t *testing.T, cfg cgroupscfg.Config) *mockCgroup {
+	t.Helper()
 
 	root := testhelper.TempDir(t)
 
-	subsystems, err := defaultSubsystems(root)
+	subsystems, err := defaultSubsystems(root, cfg)
 	require.NoError(t, err)
 
 	for _, s := range subsystems {
@@ -96,6 +105,12 @@ func (m *mockCgroup) setupMockCgroupFiles(
 		case "cpu":
 			contentByFilename["cpu.stat"] = ""
 			contentByFilename["cpu.shares"] = "0"
+		case "pids":
+			contentByFilename["pids.current"] = "1"
+			contentByFilename["pids.max"] = strconv.FormatInt(manager.cfg.Repositories.PIDsLimit, 10)
+		case "blkio":
+			contentByFilename["blkio.throttle.io_service_bytes"] = "8:0 Read 1024\n8:0 Write 2048\n8:0 Total 3072\nTotal 3072"
+			contentByFilename["blkio.throttle.io_serviced"] = "8:0 Read 1\n8:0 Write 2\n8:0 Total 3\nTotal 3"
 		default:
 			require.FailNow(t, "cannot set up subsystem", "unknown subsystem %q", s.Name())
 		}
@@ -105,8 +120,8 @@ func (m *mockCgroup) setupMockCgroupFiles(
 			require.NoError(t, os.WriteFile(controlFilePath, []byte(content), 0o644))
 		}
 
-		for shard := uint(0); shard < manager.cfg.Repositories.Count; shard++ {
-			shardPath := filepath.Join(cgroupPath, fmt.Sprintf("repos-%d", shard))
+		for _, path := range manager.cgroupPaths() {
+			shardPath := filepath.Join(m.root, string(s.Name()), path)
 			require.NoError(t, os.MkdirAll(shardPath, 0o755))
 
 			for filename, content := range contentByFilename {
@@ -116,3 +131,72 @@ func (m *mockCgroup) setupMockCgroupFiles(
 		}
 	}
 }
+
+type mockCgroupV2 struct {
+	root string
+}
+
+func newMockV2(t *testing.T) *mockCgroupV2 {
+	t.Helper()
+
+	return &mockCgroupV2{
+		root: testhelper.TempDir(t),
+	}
+}
+
+// setupMockCgroupFiles creates the control files which the kernel would create for us when
+// creating cgroups in the unified hierarchy.
+func (m *mockCgroupV2) setupMockCgroupFiles(
+	t *testing.T,
+	manager *CGroupV2Manager,
+	memMaxEvents int,
+) {
+	controllers := []string{"cpu", "memory"}
+	if manager.cfg.PIDsEnabled() {
+		controllers = append(controllers, "pids")
+	}
+	if manager.cfg.IOEnabled() {
+		controllers = append(controllers, "io")
+	}
+
+	contentByFilename := map[string]string{
+		"cgroup.procs":           "",
+		"cgroup.subtree_control": "",
+		"cgroup.controllers":     strings.Join(controllers, " "),
+		"cpu.stat":               "usage_usec 300\nuser_usec 200\nsystem_usec 100",
+		"cpu.weight":             "0",
+		"memory.stat":            "",
+		"memory.current":         "0",
+		"memory.max":             "0",
+		"memory.events":          fmt.Sprintf("low 0\nhigh 0\nmax %d\noom 0\noom_kill 0", memMaxEvents),
+	}
+
+	if manager.cfg.PIDsEnabled() {
+		contentByFilename["pids.current"] = "1"
+		contentByFilename["pids.max"] = strconv.FormatInt(manager.cfg.Repositories.PIDsLimit, 10)
+	}
+
+	if manager.cfg.IOEnabled() {
+		contentByFilename["io.max"] = ""
+		contentByFilename["io.stat"] = "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0"
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(m.root, "cgroup.subtree_control"), nil, 0o644))
+
+	cgroupPath := filepath.Join(m.root, manager.currentProcessCgroup())
+	require.NoError(t, os.MkdirAll(cgroupPath, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(cgroupPath), "cgroup.subtree_control"), nil, 0o644))
+
+	for filename, content := range contentByFilename {
+		require.NoError(t, os.WriteFile(filepath.Join(cgroupPath, filename), []byte(content), 0o644))
+	}
+
+	for _, path := range manager.cgroupPaths() {
+		shardPath := filepath.Join(m.root, path)
+		require.NoError(t, os.MkdirAll(shardPath, 0o755))
+
+		for filename, content := range contentByFilename {
+			require.NoError(t, os.WriteFile(filepath.Join(shardPath, filename), []byte(content), 0o644))
+		}
+	}
+}
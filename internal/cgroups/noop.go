@@ -4,6 +4,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/command"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/repository"
+	cgroupscfg "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
 )
 
 // NoopManager is a cgroups manager that does nothing
@@ -19,6 +20,11 @@ func (cg *NoopManager) AddCommand(cmd *command.Command, repo repository.GitRepo)
 	return "", nil
 }
 
+// Update does nothing
+func (cg *NoopManager) Update(cgroupscfg.Config) error {
+	return nil
+}
+
 //nolint:revive // This is unintentionally missing documentation.
 func (cg *NoopManager) Cleanup() error {
 	return nil
@@ -5,10 +5,12 @@ import (
 	"hash/crc32"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/containerd/cgroups"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/command"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/repository"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
@@ -18,10 +20,18 @@ import (
 
 // CGroupV1Manager is the manager for cgroups v1
 type CGroupV1Manager struct {
-	cfg                                  cgroupscfg.Config
+	// cfgLock guards cfg, which is replaced by Update while commands are being added to
+	// the cgroups and metrics are being collected.
+	cfgLock sync.RWMutex
+	cfg     cgroupscfg.Config
+	// updateLock serializes calls to Update so that the stored configuration matches the
+	// limits applied last.
+	updateLock                           sync.Mutex
 	hierarchy                            func() ([]cgroups.Subsystem, error)
+	deviceNumbers                        func(string) (int64, int64, error)
 	memoryReclaimAttemptsTotal, cpuUsage *prometheus.GaugeVec
-	procs                                *prometheus.GaugeVec
+	procs, pids                          *prometheus.GaugeVec
+	ioBytes, ioOperations                *prometheus.GaugeVec
 	pid                                  int
 }
 
@@ -30,8 +40,9 @@ func newV1Manager(cfg cgroupscfg.Config, pid int) *CGroupV1Manager {
 		cfg: cfg,
 		pid: pid,
 		hierarchy: func() ([]cgroups.Subsystem, error) {
-			return defaultSubsystems(cfg.Mountpoint)
+			return defaultSubsystems(cfg.Mountpoint, cfg)
 		},
+		deviceNumbers: blockDeviceNumbers,
 		memoryReclaimAttemptsTotal: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "gitaly_cgroup_memory_reclaim_attempts_total",
@@ -53,59 +64,163 @@ func newV1Manager(cfg cgroupscfg.Config, pid int) *CGroupV1Manager {
 			},
 			[]string{"path", "subsystem"},
 		),
+		pids:         newPidsGaugeVec(),
+		ioBytes:      newIOBytesGaugeVec(),
+		ioOperations: newIOOperationsGaugeVec(),
 	}
 }
 
 //nolint:revive // This is unintentionally missing documentation.
 func (cg *CGroupV1Manager) Setup() error {
-	var parentResources specs.LinuxResources
-
-	if cg.cfg.CPUShares > 0 {
-		parentResources.CPU = &specs.LinuxCPU{Shares: &cg.cfg.CPUShares}
-	}
-
-	if cg.cfg.MemoryBytes > 0 {
-		parentResources.Memory = &specs.LinuxMemory{Limit: &cg.cfg.MemoryBytes}
+	cfg := cg.config()
+	parentResources, err := cg.resources(cfg.MemoryBytes, cfg.CPUShares, cfg.PIDsLimit, cfg.IO)
+	if err != nil {
+		return fmt.Errorf("parent cgroup resources: %w", err)
 	}
 
 	if _, err := cgroups.New(
 		cg.hierarchy,
 		cgroups.StaticPath(cg.currentProcessCgroup()),
-		&parentResources,
+		parentResources,
 	); err != nil {
 		return fmt.Errorf("failed creating parent cgroup: %w", err)
 	}
 
-	var reposResources specs.LinuxResources
-
-	if cg.cfg.Repositories.CPUShares > 0 {
-		reposResources.CPU = &specs.LinuxCPU{Shares: &cg.cfg.Repositories.CPUShares}
-	}
-
-	if cg.cfg.Repositories.MemoryBytes > 0 {
-		reposResources.Memory = &specs.LinuxMemory{Limit: &cg.cfg.Repositories.MemoryBytes}
+	reposResources, err := cg.resources(
+		cfg.Repositories.MemoryBytes,
+		cfg.Repositories.CPUShares,
+		cfg.Repositories.PIDsLimit,
+		cfg.Repositories.IO,
+	)
+	if err != nil {
+		return fmt.Errorf("repository cgroup resources: %w", err)
 	}
 
-	for i := 0; i < int(cg.cfg.Repositories.Count); i++ {
+	for i := 0; i < int(cfg.Repositories.Count); i++ {
 		if _, err := cgroups.New(
 			cg.hierarchy,
 			cgroups.StaticPath(cg.repoPath(i)),
-			&reposResources,
+			reposResources,
 		); err != nil {
 			return fmt.Errorf("failed creating repository cgroup: %w", err)
 		}
 	}
 
+	for _, bucket := range cfg.Buckets {
+		bucketResources, err := cg.resources(bucket.MemoryBytes, bucket.CPUShares, bucket.PIDsLimit, bucket.IO)
+		if err != nil {
+			return fmt.Errorf("bucket %q cgroup resources: %w", bucket.Name, err)
+		}
+
+		if _, err := cgroups.New(
+			cg.hierarchy,
+			cgroups.StaticPath(cg.bucketPath(bucket.Name)),
+			bucketResources,
+		); err != nil {
+			return fmt.Errorf("failed creating bucket cgroup: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// AddCommand adds the given command to one of the CGroup's buckets. The bucket used for the command
-// is determined by hashing the repository storage and path. No error is returned if the command has already
-// exited.
+func (cg *CGroupV1Manager) resources(
+	memoryBytes int64,
+	cpuShares uint64,
+	pidsLimit int64,
+	ioLimits []cgroupscfg.IOLimit,
+) (*specs.LinuxResources, error) {
+	var resources specs.LinuxResources
+
+	if cpuShares > 0 {
+		resources.CPU = &specs.LinuxCPU{Shares: &cpuShares}
+	}
+
+	if memoryBytes > 0 {
+		resources.Memory = &specs.LinuxMemory{Limit: &memoryBytes}
+	}
+
+	if pidsLimit > 0 {
+		resources.Pids = &specs.LinuxPids{Limit: pidsLimit}
+	}
+
+	if len(ioLimits) > 0 {
+		var blockIO specs.LinuxBlockIO
+
+		for _, limit := range ioLimits {
+			major, minor, err := cg.deviceNumbers(limit.Device)
+			if err != nil {
+				return nil, fmt.Errorf("resolving io device: %w", err)
+			}
+
+			throttleDevice := func(rate uint64) specs.LinuxThrottleDevice {
+				var device specs.LinuxThrottleDevice
+				device.Major = major
+				device.Minor = minor
+				device.Rate = rate
+				return device
+			}
+
+			if limit.ReadBPS > 0 {
+				blockIO.ThrottleReadBpsDevice = append(blockIO.ThrottleReadBpsDevice, throttleDevice(limit.ReadBPS))
+			}
+			if limit.WriteBPS > 0 {
+				blockIO.ThrottleWriteBpsDevice = append(blockIO.ThrottleWriteBpsDevice, throttleDevice(limit.WriteBPS))
+			}
+			if limit.ReadIOPS > 0 {
+				blockIO.ThrottleReadIOPSDevice = append(blockIO.ThrottleReadIOPSDevice, throttleDevice(limit.ReadIOPS))
+			}
+			if limit.WriteIOPS > 0 {
+				blockIO.ThrottleWriteIOPSDevice = append(blockIO.ThrottleWriteIOPSDevice, throttleDevice(limit.WriteIOPS))
+			}
+		}
+
+		resources.BlockIO = &blockIO
+	}
+
+	return &resources, nil
+}
+
+// Update applies the limits of the given configuration to the parent, repository and bucket
+// cgroups created by Setup. As the cgroups already exist, only their limits are rewritten.
+func (cg *CGroupV1Manager) Update(cfg cgroupscfg.Config) error {
+	cg.updateLock.Lock()
+	defer cg.updateLock.Unlock()
+
+	cg.cfgLock.Lock()
+	cg.cfg = cfg
+	cg.cfgLock.Unlock()
+
+	return cg.Setup()
+}
+
+// config returns the configuration currently in use.
+func (cg *CGroupV1Manager) config() cgroupscfg.Config {
+	cg.cfgLock.RLock()
+	defer cg.cfgLock.RUnlock()
+	return cg.cfg
+}
+
+// AddCommand adds the given command to one of the CGroup's buckets. If the command matches any of
+// the configured buckets, it is added to the first matching bucket. Otherwise, the bucket used for the
+// command is determined by hashing the repository storage and path. No error is returned if the
+// command has already exited.
 func (cg *CGroupV1Manager) AddCommand(
 	cmd *command.Command,
 	repo repository.GitRepo,
 ) (string, error) {
+	cfg := cg.config()
+	if bucket, ok := matchBucket(cfg.Buckets, cmd); ok {
+		cgroupPath := cg.bucketPath(bucket.Name)
+		return cgroupPath, cg.addToCgroup(cmd.Pid(), cgroupPath)
+	}
+
+	// Without repository cgroups, commands which don't match any bucket stay in Gitaly's own
+	// cgroup.
+	if cfg.Repositories.Count == 0 {
+		return "", nil
+	}
+
 	var key string
 	if repo == nil {
 		key = strings.Join(cmd.Args(), "/")
@@ -117,7 +232,7 @@ func (cg *CGroupV1Manager) AddCommand(
 		[]byte(key),
 	)
 
-	groupID := uint(checksum) % cg.cfg.Repositories.Count
+	groupID := uint(checksum) % cfg.Repositories.Count
 	cgroupPath := cg.repoPath(int(groupID))
 
 	return cgroupPath, cg.addToCgroup(cmd.Pid(), cgroupPath)
@@ -143,12 +258,11 @@ func (cg *CGroupV1Manager) addToCgroup(pid int, cgroupPath string) error {
 
 // Collect collects metrics from the cgroups controller
 func (cg *CGroupV1Manager) Collect(ch chan<- prometheus.Metric) {
-	if !cg.cfg.MetricsEnabled {
+	if !cg.config().MetricsEnabled {
 		return
 	}
 
-	for i := 0; i < int(cg.cfg.Repositories.Count); i++ {
-		repoPath := cg.repoPath(i)
+	for _, repoPath := range cg.cgroupPaths() {
 		logger := log.Default().WithField("cgroup_path", repoPath)
 		control, err := cgroups.Load(
 			cg.hierarchy,
@@ -173,6 +287,33 @@ func (cg *CGroupV1Manager) Collect(ch chan<- prometheus.Metric) {
 			cpuKernelMetric := cg.cpuUsage.WithLabelValues(repoPath, "kernel")
 			cpuKernelMetric.Set(float64(metrics.CPU.Usage.Kernel))
 			ch <- cpuKernelMetric
+
+			if metrics.Pids != nil {
+				collectPids(ch, cg.pids, repoPath, metrics.Pids.Current, metrics.Pids.Limit)
+			}
+
+			if metrics.Blkio != nil {
+				var readBytes, writeBytes, readOperations, writeOperations uint64
+				for _, entry := range metrics.Blkio.IoServiceBytesRecursive {
+					switch strings.ToLower(entry.Op) {
+					case "read":
+						readBytes += entry.Value
+					case "write":
+						writeBytes += entry.Value
+					}
+				}
+				for _, entry := range metrics.Blkio.IoServicedRecursive {
+					switch strings.ToLower(entry.Op) {
+					case "read":
+						readOperations += entry.Value
+					case "write":
+						writeOperations += entry.Value
+					}
+				}
+
+				collectIO(ch, cg.ioBytes, repoPath, readBytes, writeBytes)
+				collectIO(ch, cg.ioOperations, repoPath, readOperations, writeOperations)
+			}
 		}
 
 		if subsystems, err := cg.hierarchy(); err != nil {
@@ -220,15 +361,68 @@ func (cg *CGroupV1Manager) repoPath(groupID int) string {
 	return filepath.Join(cg.currentProcessCgroup(), fmt.Sprintf("repos-%d", groupID))
 }
 
+func (cg *CGroupV1Manager) bucketPath(name string) string {
+	return filepath.Join(cg.currentProcessCgroup(), name)
+}
+
+// cgroupPaths returns the paths of all repository and bucket cgroups.
+func (cg *CGroupV1Manager) cgroupPaths() []string {
+	cfg := cg.config()
+	paths := make([]string, 0, int(cfg.Repositories.Count)+len(cfg.Buckets))
+	for i := 0; i < int(cfg.Repositories.Count); i++ {
+		paths = append(paths, cg.repoPath(i))
+	}
+	for _, bucket := range cfg.Buckets {
+		paths = append(paths, cg.bucketPath(bucket.Name))
+	}
+
+	return paths
+}
+
 func (cg *CGroupV1Manager) currentProcessCgroup() string {
-	return config.GetGitalyProcessTempDir(cg.cfg.HierarchyRoot, cg.pid)
+	return config.GetGitalyProcessTempDir(cg.config().HierarchyRoot, cg.pid)
 }
 
-func defaultSubsystems(root string) ([]cgroups.Subsystem, error) {
+func defaultSubsystems(root string, cfg cgroupscfg.Config) ([]cgroups.Subsystem, error) {
 	subsystems := []cgroups.Subsystem{
 		cgroups.NewMemory(root, cgroups.OptionalSwap()),
 		cgroups.NewCpu(root),
 	}
 
+	// The pids and blkio subsystems are only added to the hierarchy when they are configured.
+	// Otherwise, we'd require administrators to create the hierarchy root in these subsystems
+	// even though they do not use them.
+	if cfg.PIDsEnabled() {
+		subsystems = append(subsystems, cgroups.NewPids(root))
+	}
+
+	if cfg.IOEnabled() {
+		subsystems = append(subsystems, cgroups.NewBlkio(root))
+	}
+
 	return subsystems, nil
 }
+
+func pruneOldCgroupsV1(cfg cgroupscfg.Config, logger logrus.FieldLogger) {
+	if cfg.HierarchyRoot == "" {
+		return
+	}
+
+	subsystems := []string{"memory", "cpu"}
+	if cfg.PIDsEnabled() {
+		subsystems = append(subsystems, "pids")
+	}
+	if cfg.IOEnabled() {
+		subsystems = append(subsystems, "blkio")
+	}
+
+	for _, subsystem := range subsystems {
+		if err := config.PruneOldGitalyProcessDirectories(
+			logger,
+			filepath.Join(cfg.Mountpoint, subsystem,
+				cfg.HierarchyRoot),
+		); err != nil {
+			logger.WithError(err).Errorf("failed to clean up %s cgroups", subsystem)
+		}
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	grpcmwtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
@@ -63,6 +65,112 @@ func TestSetup(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	mock := newMock(t)
+
+	pid := 1
+	v1Manager := &CGroupV1Manager{
+		cfg:       defaultCgroupsConfig(),
+		hierarchy: mock.hierarchy,
+		pid:       pid,
+	}
+	require.NoError(t, v1Manager.Setup())
+
+	cfg := defaultCgroupsConfig()
+	cfg.Repositories.MemoryBytes = 2048000
+	cfg.Repositories.CPUShares = 512
+	require.NoError(t, v1Manager.Update(cfg))
+
+	for i := 0; i < 3; i++ {
+		memoryPath := filepath.Join(
+			mock.root, "memory", "gitaly", fmt.Sprintf("gitaly-%d", pid), fmt.Sprintf("repos-%d", i), "memory.limit_in_bytes",
+		)
+		require.Equal(t, "2048000", string(readCgroupFile(t, memoryPath)))
+
+		cpuPath := filepath.Join(
+			mock.root, "cpu", "gitaly", fmt.Sprintf("gitaly-%d", pid), fmt.Sprintf("repos-%d", i), "cpu.shares",
+		)
+		require.Equal(t, "512", string(readCgroupFile(t, cpuPath)))
+	}
+}
+
+func TestUpdate_concurrentAddCommand(t *testing.T) {
+	mock := newMock(t)
+
+	repo := &gitalypb.Repository{
+		StorageName:  "default",
+		RelativePath: "path/to/repo.git",
+	}
+
+	v1Manager := &CGroupV1Manager{
+		cfg:       defaultCgroupsConfig(),
+		hierarchy: mock.hierarchy,
+		pid:       1,
+	}
+	require.NoError(t, v1Manager.Setup())
+
+	cmd, err := command.New(testhelper.Context(t), []string{"ls", "-hal", "."})
+	require.NoError(t, err)
+	require.NoError(t, cmd.Wait())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 10; i++ {
+			cfg := defaultCgroupsConfig()
+			cfg.Repositories.MemoryBytes = int64(1024 * (i + 1))
+			assert.NoError(t, v1Manager.Update(cfg))
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		_, err := v1Manager.AddCommand(cmd, repo)
+		require.NoError(t, err)
+	}
+
+	wg.Wait()
+}
+
+func TestSetup_pidsAndIO(t *testing.T) {
+	cfg := defaultCgroupsConfig()
+	cfg.PIDsLimit = 1000
+	cfg.Repositories.PIDsLimit = 100
+	cfg.Repositories.IO = []cgroups.IOLimit{
+		{Device: "/dev/sda", ReadBPS: 1024, WriteIOPS: 10},
+	}
+
+	mock := newMockWithConfig(t, cfg)
+
+	pid := 1
+	v1Manager := &CGroupV1Manager{
+		cfg:       cfg,
+		hierarchy: mock.hierarchy,
+		deviceNumbers: func(path string) (int64, int64, error) {
+			require.Equal(t, "/dev/sda", path)
+			return 8, 0, nil
+		},
+		pid: pid,
+	}
+	require.NoError(t, v1Manager.Setup())
+
+	parentPidsPath := filepath.Join(mock.root, "pids", "gitaly", fmt.Sprintf("gitaly-%d", pid), "pids.max")
+	require.Equal(t, "1000", string(readCgroupFile(t, parentPidsPath)))
+
+	for i := 0; i < 3; i++ {
+		pidsPath := filepath.Join(
+			mock.root, "pids", "gitaly", fmt.Sprintf("gitaly-%d", pid), fmt.Sprintf("repos-%d", i), "pids.max",
+		)
+		require.Equal(t, "100", string(readCgroupFile(t, pidsPath)))
+
+		blkioPath := filepath.Join(mock.root, "blkio", "gitaly", fmt.Sprintf("gitaly-%d", pid), fmt.Sprintf("repos-%d", i))
+		require.Equal(t, "8:0 1024", string(readCgroupFile(t, filepath.Join(blkioPath, "blkio.throttle.read_bps_device"))))
+		require.Equal(t, "8:0 10", string(readCgroupFile(t, filepath.Join(blkioPath, "blkio.throttle.write_iops_device"))))
+		require.NoFileExists(t, filepath.Join(blkioPath, "blkio.throttle.write_bps_device"))
+	}
+}
+
 func TestAddCommand(t *testing.T) {
 	mock := newMock(t)
 
@@ -137,6 +245,87 @@ func TestAddCommand(t *testing.T) {
 	})
 }
 
+func TestAddCommand_buckets(t *testing.T) {
+	mock := newMock(t)
+
+	config := defaultCgroupsConfig()
+	config.Buckets = []cgroups.Bucket{
+		{
+			Name:           "upload-pack-objects",
+			RPCs:           []string{"PostUploadPackWithSidechannel"},
+			GitSubcommands: []string{"pack-objects"},
+			MemoryBytes:    2048,
+			CPUShares:      128,
+		},
+	}
+
+	pid := 1
+	v1Manager := &CGroupV1Manager{
+		cfg:       config,
+		hierarchy: mock.hierarchy,
+		pid:       pid,
+	}
+	require.NoError(t, v1Manager.Setup())
+
+	bucketPath := filepath.Join("gitaly", fmt.Sprintf("gitaly-%d", pid), "upload-pack-objects")
+	require.Equal(t, "2048", string(readCgroupFile(t, filepath.Join(mock.root, "memory", bucketPath, "memory.limit_in_bytes"))))
+	require.Equal(t, "128", string(readCgroupFile(t, filepath.Join(mock.root, "cpu", bucketPath, "cpu.shares"))))
+
+	tags := grpcmwtags.NewTags()
+	tags.Set("grpc.request.fullMethod", "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel")
+	ctx := grpcmwtags.SetInContext(testhelper.Context(t), tags)
+
+	t.Run("matching command", func(t *testing.T) {
+		cmd, err := command.New(ctx, []string{"ls", "-hal", "."}, command.WithCommandName("git", "pack-objects"))
+		require.NoError(t, err)
+		require.NoError(t, cmd.Wait())
+
+		cgroupPath, err := v1Manager.AddCommand(cmd, nil)
+		require.NoError(t, err)
+		require.Equal(t, bucketPath, cgroupPath)
+
+		for _, s := range mock.subsystems {
+			content := readCgroupFile(t, filepath.Join(mock.root, string(s.Name()), bucketPath, "cgroup.procs"))
+
+			cmdPid, err := strconv.Atoi(string(content))
+			require.NoError(t, err)
+			require.Equal(t, cmd.Pid(), cmdPid)
+		}
+	})
+
+	t.Run("mismatching command", func(t *testing.T) {
+		cmd, err := command.New(ctx, []string{"ls", "-hal", "."}, command.WithCommandName("git", "upload-pack"))
+		require.NoError(t, err)
+		require.NoError(t, cmd.Wait())
+
+		cgroupPath, err := v1Manager.AddCommand(cmd, nil)
+		require.NoError(t, err)
+
+		checksum := crc32.ChecksumIEEE([]byte(strings.Join(cmd.Args(), "/")))
+		groupID := uint(checksum) % config.Repositories.Count
+		require.Equal(t, filepath.Join("gitaly", fmt.Sprintf("gitaly-%d", pid), fmt.Sprintf("repos-%d", groupID)), cgroupPath)
+	})
+
+	t.Run("mismatching command without repository cgroups", func(t *testing.T) {
+		config := config
+		config.Repositories = cgroups.Repositories{}
+
+		v1Manager := &CGroupV1Manager{
+			cfg:       config,
+			hierarchy: mock.hierarchy,
+			pid:       pid,
+		}
+
+		cmd, err := command.New(ctx, []string{"ls", "-hal", "."}, command.WithCommandName("git", "upload-pack"))
+		require.NoError(t, err)
+		require.NoError(t, cmd.Wait())
+
+		cgroupPath, err := v1Manager.AddCommand(cmd, nil)
+		require.NoError(t, err)
+		require.Empty(t, cgroupPath)
+	})
+}
+
 func TestCleanup(t *testing.T) {
 	mock := newMock(t)
 
@@ -238,6 +427,53 @@ gitaly_cgroup_procs_total{path="%s",subsystem="memory"} 1
 	}
 }
 
+func TestMetrics_pidsAndIO(t *testing.T) {
+	t.Parallel()
+
+	config := defaultCgroupsConfig()
+	config.Repositories.Count = 1
+	config.Repositories.PIDsLimit = 100
+	config.Repositories.IO = []cgroups.IOLimit{
+		{Device: "/dev/sda", ReadBPS: 1024},
+	}
+	config.MetricsEnabled = true
+
+	mock := newMockWithConfig(t, config)
+
+	v1Manager := newV1Manager(config, 1)
+	v1Manager.hierarchy = mock.hierarchy
+	v1Manager.deviceNumbers = func(string) (int64, int64, error) {
+		return 8, 0, nil
+	}
+
+	mock.setupMockCgroupFiles(t, v1Manager, 0)
+	require.NoError(t, v1Manager.Setup())
+
+	repoCgroupPath := filepath.Join(v1Manager.currentProcessCgroup(), "repos-0")
+
+	expected := bytes.NewBufferString(fmt.Sprintf(`# HELP gitaly_cgroup_io_bytes_total Number of bytes read from and written to block devices by cgroup
+# TYPE gitaly_cgroup_io_bytes_total gauge
+gitaly_cgroup_io_bytes_total{op="read",path="%[1]s"} 1024
+gitaly_cgroup_io_bytes_total{op="write",path="%[1]s"} 2048
+# HELP gitaly_cgroup_io_operations_total Number of read and write operations on block devices by cgroup
+# TYPE gitaly_cgroup_io_operations_total gauge
+gitaly_cgroup_io_operations_total{op="read",path="%[1]s"} 1
+gitaly_cgroup_io_operations_total{op="write",path="%[1]s"} 2
+# HELP gitaly_cgroup_pids_total Current number of processes and process limit of cgroup
+# TYPE gitaly_cgroup_pids_total gauge
+gitaly_cgroup_pids_total{path="%[1]s",type="current"} 1
+gitaly_cgroup_pids_total{path="%[1]s",type="limit"} 100
+`, repoCgroupPath))
+
+	require.NoError(t, testutil.CollectAndCompare(
+		v1Manager,
+		expected,
+		"gitaly_cgroup_io_bytes_total",
+		"gitaly_cgroup_io_operations_total",
+		"gitaly_cgroup_pids_total",
+	))
+}
+
 func readCgroupFile(t *testing.T, path string) []byte {
 	t.Helper()
 
@@ -0,0 +1,420 @@
+package cgroups
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	cgroupsv2 "github.com/containerd/cgroups/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/command"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/repository"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+	cgroupscfg "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/log"
+)
+
+// CGroupV2Manager is the manager for cgroups v2, also known as the unified hierarchy. In
+// contrast to cgroups v1 there is only a single hierarchy mounted at Mountpoint in which all
+// controllers are available.
+type CGroupV2Manager struct {
+	// cfgLock guards cfg, which is replaced by Update while commands are being added to
+	// the cgroups and metrics are being collected.
+	cfgLock sync.RWMutex
+	cfg     cgroupscfg.Config
+	// updateLock serializes calls to Update so that the stored configuration matches the
+	// limits applied last.
+	updateLock                           sync.Mutex
+	deviceNumbers                        func(string) (int64, int64, error)
+	memoryReclaimAttemptsTotal, cpuUsage *prometheus.GaugeVec
+	procs, pids                          *prometheus.GaugeVec
+	ioBytes, ioOperations                *prometheus.GaugeVec
+	pid                                  int
+}
+
+func newV2Manager(cfg cgroupscfg.Config, pid int) *CGroupV2Manager {
+	return &CGroupV2Manager{
+		cfg:           cfg,
+		pid:           pid,
+		deviceNumbers: blockDeviceNumbers,
+		memoryReclaimAttemptsTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gitaly_cgroup_memory_reclaim_attempts_total",
+				Help: "Number of memory usage hits limits",
+			},
+			[]string{"path"},
+		),
+		cpuUsage: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gitaly_cgroup_cpu_usage_total",
+				Help: "CPU Usage of Cgroup",
+			},
+			[]string{"path", "type"},
+		),
+		procs: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gitaly_cgroup_procs_total",
+				Help: "Total number of procs",
+			},
+			[]string{"path", "subsystem"},
+		),
+		pids:         newPidsGaugeVec(),
+		ioBytes:      newIOBytesGaugeVec(),
+		ioOperations: newIOOperationsGaugeVec(),
+	}
+}
+
+// Setup creates the parent cgroup and the repository cgroups and assigns the configured limits.
+func (cg *CGroupV2Manager) Setup() error {
+	cfg := cg.config()
+	parentResources, err := cg.resources(cfg.MemoryBytes, cfg.CPUShares, cfg.PIDsLimit, cfg.IO)
+	if err != nil {
+		return fmt.Errorf("parent cgroup resources: %w", err)
+	}
+
+	if _, err := cgroupsv2.NewManager(
+		cfg.Mountpoint,
+		"/"+cg.currentProcessCgroup(),
+		parentResources,
+	); err != nil {
+		return fmt.Errorf("failed creating parent cgroup: %w", err)
+	}
+
+	reposResources, err := cg.resources(
+		cfg.Repositories.MemoryBytes,
+		cfg.Repositories.CPUShares,
+		cfg.Repositories.PIDsLimit,
+		cfg.Repositories.IO,
+	)
+	if err != nil {
+		return fmt.Errorf("repository cgroup resources: %w", err)
+	}
+
+	for i := 0; i < int(cfg.Repositories.Count); i++ {
+		if _, err := cgroupsv2.NewManager(
+			cfg.Mountpoint,
+			"/"+cg.repoPath(i),
+			reposResources,
+		); err != nil {
+			return fmt.Errorf("failed creating repository cgroup: %w", err)
+		}
+	}
+
+	for _, bucket := range cfg.Buckets {
+		bucketResources, err := cg.resources(bucket.MemoryBytes, bucket.CPUShares, bucket.PIDsLimit, bucket.IO)
+		if err != nil {
+			return fmt.Errorf("bucket %q cgroup resources: %w", bucket.Name, err)
+		}
+
+		if _, err := cgroupsv2.NewManager(
+			cfg.Mountpoint,
+			"/"+cg.bucketPath(bucket.Name),
+			bucketResources,
+		); err != nil {
+			return fmt.Errorf("failed creating bucket cgroup: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (cg *CGroupV2Manager) resources(
+	memoryBytes int64,
+	cpuShares uint64,
+	pidsLimit int64,
+	ioLimits []cgroupscfg.IOLimit,
+) (*cgroupsv2.Resources, error) {
+	cfg := cg.config()
+	var resources cgroupsv2.Resources
+
+	if cpuShares > 0 {
+		weight := cpuSharesToWeight(cpuShares)
+		resources.CPU = &cgroupsv2.CPU{Weight: &weight}
+	}
+
+	if memoryBytes > 0 {
+		resources.Memory = &cgroupsv2.Memory{Max: &memoryBytes}
+	}
+
+	// Controllers need to be enabled in all cgroups of the hierarchy for their statistics to be
+	// available, even if only the parent or only the repository cgroups are limited. A
+	// negative PID limit and empty I/O limits enable the respective controller without
+	// enforcing any limits.
+	if cfg.PIDsEnabled() {
+		resources.Pids = &cgroupsv2.Pids{Max: -1}
+		if pidsLimit > 0 {
+			resources.Pids.Max = pidsLimit
+		}
+	}
+
+	if cfg.IOEnabled() {
+		resources.IO = &cgroupsv2.IO{}
+
+		for _, limit := range ioLimits {
+			major, minor, err := cg.deviceNumbers(limit.Device)
+			if err != nil {
+				return nil, fmt.Errorf("resolving io device: %w", err)
+			}
+
+			for _, entry := range []struct {
+				ioType cgroupsv2.IOType
+				rate   uint64
+			}{
+				{ioType: cgroupsv2.ReadBPS, rate: limit.ReadBPS},
+				{ioType: cgroupsv2.WriteBPS, rate: limit.WriteBPS},
+				{ioType: cgroupsv2.ReadIOPS, rate: limit.ReadIOPS},
+				{ioType: cgroupsv2.WriteIOPS, rate: limit.WriteIOPS},
+			} {
+				if entry.rate == 0 {
+					continue
+				}
+
+				resources.IO.Max = append(resources.IO.Max, cgroupsv2.Entry{
+					Type:  entry.ioType,
+					Major: major,
+					Minor: minor,
+					Rate:  entry.rate,
+				})
+			}
+		}
+	}
+
+	return &resources, nil
+}
+
+// Update applies the limits of the given configuration to the parent, repository and bucket
+// cgroups created by Setup. As the cgroups already exist, only their limits are rewritten.
+func (cg *CGroupV2Manager) Update(cfg cgroupscfg.Config) error {
+	cg.updateLock.Lock()
+	defer cg.updateLock.Unlock()
+
+	cg.cfgLock.Lock()
+	cg.cfg = cfg
+	cg.cfgLock.Unlock()
+
+	return cg.Setup()
+}
+
+// config returns the configuration currently in use.
+func (cg *CGroupV2Manager) config() cgroupscfg.Config {
+	cg.cfgLock.RLock()
+	defer cg.cfgLock.RUnlock()
+	return cg.cfg
+}
+
+// AddCommand adds the given command to one of the CGroup's buckets. If the command matches any of
+// the configured buckets, it is added to the first matching bucket. Otherwise, the bucket used for the
+// command is determined by hashing the repository storage and path. No error is returned if the
+// command has already exited.
+func (cg *CGroupV2Manager) AddCommand(
+	cmd *command.Command,
+	repo repository.GitRepo,
+) (string, error) {
+	cfg := cg.config()
+	if bucket, ok := matchBucket(cfg.Buckets, cmd); ok {
+		cgroupPath := cg.bucketPath(bucket.Name)
+		return cgroupPath, cg.addToCgroup(cmd.Pid(), cgroupPath)
+	}
+
+	// Without repository cgroups, commands which don't match any bucket stay in Gitaly's own
+	// cgroup.
+	if cfg.Repositories.Count == 0 {
+		return "", nil
+	}
+
+	var key string
+	if repo == nil {
+		key = strings.Join(cmd.Args(), "/")
+	} else {
+		key = repo.GetStorageName() + "/" + repo.GetRelativePath()
+	}
+
+	checksum := crc32.ChecksumIEEE(
+		[]byte(key),
+	)
+
+	groupID := uint(checksum) % cfg.Repositories.Count
+	cgroupPath := cg.repoPath(int(groupID))
+
+	return cgroupPath, cg.addToCgroup(cmd.Pid(), cgroupPath)
+}
+
+func (cg *CGroupV2Manager) addToCgroup(pid int, cgroupPath string) error {
+	control, err := cgroupsv2.LoadManager(cg.config().Mountpoint, "/"+cgroupPath)
+	if err != nil {
+		return fmt.Errorf("failed loading %s cgroup: %w", cgroupPath, err)
+	}
+
+	if err := control.AddProc(uint64(pid)); err != nil {
+		// Command could finish so quickly before we can add it to a cgroup, so
+		// we don't consider it an error.
+		if strings.Contains(err.Error(), "no such process") {
+			return nil
+		}
+		return fmt.Errorf("failed adding process to cgroup: %w", err)
+	}
+
+	return nil
+}
+
+// Collect collects metrics from the cgroups controller
+func (cg *CGroupV2Manager) Collect(ch chan<- prometheus.Metric) {
+	cfg := cg.config()
+	if !cfg.MetricsEnabled {
+		return
+	}
+
+	for _, repoPath := range cg.cgroupPaths() {
+		logger := log.Default().WithField("cgroup_path", repoPath)
+		control, err := cgroupsv2.LoadManager(cfg.Mountpoint, "/"+repoPath)
+		if err != nil {
+			logger.WithError(err).Warn("unable to load cgroup controller")
+			return
+		}
+
+		if metrics, err := control.Stat(); err != nil {
+			logger.WithError(err).Warn("unable to get cgroup stats")
+		} else {
+			var reclaimAttempts uint64
+			if metrics.MemoryEvents != nil {
+				reclaimAttempts = metrics.MemoryEvents.Max
+			}
+
+			memoryMetric := cg.memoryReclaimAttemptsTotal.WithLabelValues(repoPath)
+			memoryMetric.Set(float64(reclaimAttempts))
+			ch <- memoryMetric
+
+			// The unified hierarchy reports CPU usage in microseconds while the v1 hierarchy
+			// reports it in nanoseconds. Convert to nanoseconds so the metric has the same unit
+			// regardless of the cgroup version in use.
+			cpuUserMetric := cg.cpuUsage.WithLabelValues(repoPath, "user")
+			cpuUserMetric.Set(float64(metrics.CPU.UserUsec * 1000))
+			ch <- cpuUserMetric
+
+			cpuKernelMetric := cg.cpuUsage.WithLabelValues(repoPath, "kernel")
+			cpuKernelMetric.Set(float64(metrics.CPU.SystemUsec * 1000))
+			ch <- cpuKernelMetric
+
+			if cfg.PIDsEnabled() && metrics.Pids != nil {
+				limit := metrics.Pids.Limit
+				if limit == math.MaxUint64 {
+					limit = 0
+				}
+
+				collectPids(ch, cg.pids, repoPath, metrics.Pids.Current, limit)
+			}
+
+			if cfg.IOEnabled() && metrics.Io != nil {
+				var readBytes, writeBytes, readOperations, writeOperations uint64
+				for _, entry := range metrics.Io.Usage {
+					readBytes += entry.Rbytes
+					writeBytes += entry.Wbytes
+					readOperations += entry.Rios
+					writeOperations += entry.Wios
+				}
+
+				collectIO(ch, cg.ioBytes, repoPath, readBytes, writeBytes)
+				collectIO(ch, cg.ioOperations, repoPath, readOperations, writeOperations)
+			}
+		}
+
+		if controllers, err := control.Controllers(); err != nil {
+			logger.WithError(err).Warn("unable to get cgroup controllers")
+		} else {
+			// In contrast to cgroups v1, all controllers share the same set of processes in
+			// the unified hierarchy. We still report them per controller so that metrics
+			// are comparable across both versions.
+			processes, err := control.Procs(true)
+			if err != nil {
+				logger.WithError(err).Warn("unable to get process list")
+				continue
+			}
+
+			for _, controller := range controllers {
+				procsMetric := cg.procs.WithLabelValues(repoPath, controller)
+				procsMetric.Set(float64(len(processes)))
+				ch <- procsMetric
+			}
+		}
+	}
+}
+
+// Describe describes the cgroup metrics that Collect provides
+func (cg *CGroupV2Manager) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(cg, ch)
+}
+
+// Cleanup deletes the parent cgroup and all of the repository cgroups created by Setup.
+func (cg *CGroupV2Manager) Cleanup() error {
+	processCgroupPath := cg.currentProcessCgroup()
+
+	control, err := cgroupsv2.LoadManager(cg.config().Mountpoint, "/"+processCgroupPath)
+	if err != nil {
+		return fmt.Errorf("failed loading cgroup %s: %w", processCgroupPath, err)
+	}
+
+	if err := control.Delete(); err != nil {
+		return fmt.Errorf("failed cleaning up cgroup %s: %w", processCgroupPath, err)
+	}
+
+	return nil
+}
+
+func (cg *CGroupV2Manager) repoPath(groupID int) string {
+	return filepath.Join(cg.currentProcessCgroup(), fmt.Sprintf("repos-%d", groupID))
+}
+
+func (cg *CGroupV2Manager) bucketPath(name string) string {
+	return filepath.Join(cg.currentProcessCgroup(), name)
+}
+
+// cgroupPaths returns the paths of all repository and bucket cgroups.
+func (cg *CGroupV2Manager) cgroupPaths() []string {
+	cfg := cg.config()
+	paths := make([]string, 0, int(cfg.Repositories.Count)+len(cfg.Buckets))
+	for i := 0; i < int(cfg.Repositories.Count); i++ {
+		paths = append(paths, cg.repoPath(i))
+	}
+	for _, bucket := range cfg.Buckets {
+		paths = append(paths, cg.bucketPath(bucket.Name))
+	}
+
+	return paths
+}
+
+func (cg *CGroupV2Manager) currentProcessCgroup() string {
+	return config.GetGitalyProcessTempDir(cg.config().HierarchyRoot, cg.pid)
+}
+
+// cpuSharesToWeight converts CPU shares as used by cgroups v1, which are in the range
+// [2, 262144], into a CPU weight as used by cgroups v2, which is in the range [1, 10000].
+// This is the same conversion as performed by runc.
+func cpuSharesToWeight(shares uint64) uint64 {
+	if shares == 0 {
+		return 0
+	}
+	if shares < 2 {
+		shares = 2
+	} else if shares > 262144 {
+		shares = 262144
+	}
+
+	return 1 + ((shares-2)*9999)/262142
+}
+
+func pruneOldCgroupsV2(cfg cgroupscfg.Config, logger logrus.FieldLogger) {
+	if cfg.HierarchyRoot == "" {
+		return
+	}
+
+	if err := config.PruneOldGitalyProcessDirectories(
+		logger,
+		filepath.Join(cfg.Mountpoint, cfg.HierarchyRoot),
+	); err != nil {
+		logger.WithError(err).Error("failed to clean up cgroups")
+	}
+}
@@ -0,0 +1,453 @@
+//go:build !gitaly_test_sha256
+
+package cgroups
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	grpcmwtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/command"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func defaultCgroupsV2Config(mountpoint string) cgroups.Config {
+	cfg := defaultCgroupsConfig()
+	cfg.Mountpoint = mountpoint
+	cfg.MemoryBytes = 2048000
+	cfg.CPUShares = 1024
+	return cfg
+}
+
+func TestSetupV2(t *testing.T) {
+	mock := newMockV2(t)
+
+	pid := 1
+	v2Manager := newV2Manager(defaultCgroupsV2Config(mock.root), pid)
+	mock.setupMockCgroupFiles(t, v2Manager, 0)
+
+	require.NoError(t, v2Manager.Setup())
+
+	parentPath := filepath.Join(mock.root, "gitaly", fmt.Sprintf("gitaly-%d", pid))
+	require.Equal(t, "2048000", string(readCgroupFile(t, filepath.Join(parentPath, "memory.max"))))
+	require.Equal(t, "39", string(readCgroupFile(t, filepath.Join(parentPath, "cpu.weight"))))
+
+	for i := 0; i < 3; i++ {
+		repoPath := filepath.Join(parentPath, fmt.Sprintf("repos-%d", i))
+		require.Equal(t, "1024000", string(readCgroupFile(t, filepath.Join(repoPath, "memory.max"))))
+		require.Equal(t, "10", string(readCgroupFile(t, filepath.Join(repoPath, "cpu.weight"))))
+	}
+
+	subtreeControl := readCgroupFile(t, filepath.Join(mock.root, "gitaly", "cgroup.subtree_control"))
+	require.Equal(t, "+cpu +cpuset +memory", string(subtreeControl))
+}
+
+func TestUpdateV2(t *testing.T) {
+	mock := newMockV2(t)
+
+	pid := 1
+	v2Manager := newV2Manager(defaultCgroupsV2Config(mock.root), pid)
+	mock.setupMockCgroupFiles(t, v2Manager, 0)
+
+	require.NoError(t, v2Manager.Setup())
+
+	cfg := defaultCgroupsV2Config(mock.root)
+	cfg.MemoryBytes = 4096000
+	cfg.Repositories.MemoryBytes = 2048000
+	require.NoError(t, v2Manager.Update(cfg))
+
+	parentPath := filepath.Join(mock.root, "gitaly", fmt.Sprintf("gitaly-%d", pid))
+	require.Equal(t, "4096000", string(readCgroupFile(t, filepath.Join(parentPath, "memory.max"))))
+
+	for i := 0; i < 3; i++ {
+		repoPath := filepath.Join(parentPath, fmt.Sprintf("repos-%d", i))
+		require.Equal(t, "2048000", string(readCgroupFile(t, filepath.Join(repoPath, "memory.max"))))
+	}
+}
+
+func TestUpdateV2_concurrentAddCommand(t *testing.T) {
+	mock := newMockV2(t)
+
+	repo := &gitalypb.Repository{
+		StorageName:  "default",
+		RelativePath: "path/to/repo.git",
+	}
+
+	v2Manager := newV2Manager(defaultCgroupsV2Config(mock.root), 1)
+	mock.setupMockCgroupFiles(t, v2Manager, 0)
+	require.NoError(t, v2Manager.Setup())
+
+	cmd, err := command.New(testhelper.Context(t), []string{"ls", "-hal", "."})
+	require.NoError(t, err)
+	require.NoError(t, cmd.Wait())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 10; i++ {
+			cfg := defaultCgroupsV2Config(mock.root)
+			cfg.Repositories.MemoryBytes = int64(1024 * (i + 1))
+			assert.NoError(t, v2Manager.Update(cfg))
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		_, err := v2Manager.AddCommand(cmd, repo)
+		require.NoError(t, err)
+	}
+
+	wg.Wait()
+}
+
+func TestSetupV2_pidsAndIO(t *testing.T) {
+	mock := newMockV2(t)
+
+	cfg := defaultCgroupsV2Config(mock.root)
+	cfg.PIDsLimit = 1000
+	cfg.Repositories.IO = []cgroups.IOLimit{
+		{Device: "/dev/sda", ReadBPS: 1024},
+	}
+
+	pid := 1
+	v2Manager := newV2Manager(cfg, pid)
+	v2Manager.deviceNumbers = func(path string) (int64, int64, error) {
+		require.Equal(t, "/dev/sda", path)
+		return 8, 0, nil
+	}
+	mock.setupMockCgroupFiles(t, v2Manager, 0)
+
+	require.NoError(t, v2Manager.Setup())
+
+	parentPath := filepath.Join(mock.root, "gitaly", fmt.Sprintf("gitaly-%d", pid))
+	require.Equal(t, "1000", string(readCgroupFile(t, filepath.Join(parentPath, "pids.max"))))
+
+	for i := 0; i < 3; i++ {
+		repoPath := filepath.Join(parentPath, fmt.Sprintf("repos-%d", i))
+		require.Equal(t, "max", string(readCgroupFile(t, filepath.Join(repoPath, "pids.max"))))
+		require.Equal(t, "8:0 rbps=1024", string(readCgroupFile(t, filepath.Join(repoPath, "io.max"))))
+	}
+
+	subtreeControl := readCgroupFile(t, filepath.Join(parentPath, "cgroup.subtree_control"))
+	require.Equal(t, "+cpu +cpuset +memory +pids +io", string(subtreeControl))
+}
+
+func TestAddCommandV2(t *testing.T) {
+	mock := newMockV2(t)
+
+	repo := &gitalypb.Repository{
+		StorageName:  "default",
+		RelativePath: "path/to/repo.git",
+	}
+
+	config := defaultCgroupsV2Config(mock.root)
+	config.Repositories.Count = 10
+	config.Repositories.MemoryBytes = 1024
+	config.Repositories.CPUShares = 16
+
+	pid := 1
+	v2Manager1 := newV2Manager(config, pid)
+	mock.setupMockCgroupFiles(t, v2Manager1, 0)
+	require.NoError(t, v2Manager1.Setup())
+	ctx := testhelper.Context(t)
+
+	cmd2, err := command.New(ctx, []string{"ls", "-hal", "."})
+	require.NoError(t, err)
+	require.NoError(t, cmd2.Wait())
+
+	v2Manager2 := newV2Manager(config, pid)
+
+	for _, tc := range []struct {
+		desc string
+		repo *gitalypb.Repository
+		key  string
+	}{
+		{
+			desc: "without a repository",
+			key:  strings.Join(cmd2.Args(), "/"),
+		},
+		{
+			desc: "with a repository",
+			repo: repo,
+			key:  "default/path/to/repo.git",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var err error
+			if tc.repo == nil {
+				_, err = v2Manager2.AddCommand(cmd2, nil)
+			} else {
+				_, err = v2Manager2.AddCommand(cmd2, tc.repo)
+			}
+			require.NoError(t, err)
+
+			groupID := uint(crc32.ChecksumIEEE([]byte(tc.key))) % config.Repositories.Count
+
+			path := filepath.Join(mock.root, "gitaly",
+				fmt.Sprintf("gitaly-%d", pid), fmt.Sprintf("repos-%d", groupID), "cgroup.procs")
+			content := readCgroupFile(t, path)
+
+			cmdPid, err := strconv.Atoi(string(content))
+			require.NoError(t, err)
+
+			require.Equal(t, cmd2.Pid(), cmdPid)
+		})
+	}
+}
+
+func TestAddCommandV2_buckets(t *testing.T) {
+	mock := newMockV2(t)
+
+	config := defaultCgroupsV2Config(mock.root)
+	config.Buckets = []cgroups.Bucket{
+		{
+			Name:           "upload-pack-objects",
+			RPCs:           []string{"/gitaly.SmartHTTPService/PostUploadPackWithSidechannel"},
+			GitSubcommands: []string{"pack-objects"},
+			MemoryBytes:    2048,
+			CPUShares:      256,
+		},
+	}
+
+	pid := 1
+	v2Manager := newV2Manager(config, pid)
+	mock.setupMockCgroupFiles(t, v2Manager, 0)
+	require.NoError(t, v2Manager.Setup())
+
+	bucketPath := filepath.Join("gitaly", fmt.Sprintf("gitaly-%d", pid), "upload-pack-objects")
+	require.Equal(t, "2048", string(readCgroupFile(t, filepath.Join(mock.root, bucketPath, "memory.max"))))
+	require.Equal(t, "10", string(readCgroupFile(t, filepath.Join(mock.root, bucketPath, "cpu.weight"))))
+
+	tags := grpcmwtags.NewTags()
+	tags.Set("grpc.request.fullMethod", "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel")
+	ctx := grpcmwtags.SetInContext(testhelper.Context(t), tags)
+
+	t.Run("matching command", func(t *testing.T) {
+		cmd, err := command.New(ctx, []string{"ls", "-hal", "."}, command.WithCommandName("git", "pack-objects"))
+		require.NoError(t, err)
+		require.NoError(t, cmd.Wait())
+
+		cgroupPath, err := v2Manager.AddCommand(cmd, nil)
+		require.NoError(t, err)
+		require.Equal(t, bucketPath, cgroupPath)
+
+		cmdPid, err := strconv.Atoi(string(readCgroupFile(t, filepath.Join(mock.root, bucketPath, "cgroup.procs"))))
+		require.NoError(t, err)
+		require.Equal(t, cmd.Pid(), cmdPid)
+	})
+
+	t.Run("mismatching command", func(t *testing.T) {
+		cmd, err := command.New(testhelper.Context(t), []string{"ls", "-hal", "."}, command.WithCommandName("git", "pack-objects"))
+		require.NoError(t, err)
+		require.NoError(t, cmd.Wait())
+
+		cgroupPath, err := v2Manager.AddCommand(cmd, nil)
+		require.NoError(t, err)
+
+		groupID := uint(crc32.ChecksumIEEE([]byte(strings.Join(cmd.Args(), "/")))) % config.Repositories.Count
+		require.Equal(t, filepath.Join("gitaly", fmt.Sprintf("gitaly-%d", pid), fmt.Sprintf("repos-%d", groupID)), cgroupPath)
+	})
+}
+
+func TestCleanupV2(t *testing.T) {
+	mock := newMockV2(t)
+
+	pid := 1
+	v2Manager := newV2Manager(defaultCgroupsV2Config(mock.root), pid)
+	mock.setupMockCgroupFiles(t, v2Manager, 0)
+
+	require.NoError(t, v2Manager.Setup())
+	require.NoError(t, v2Manager.Cleanup())
+
+	require.NoDirExists(t, filepath.Join(mock.root, "gitaly", fmt.Sprintf("gitaly-%d", pid)))
+}
+
+func TestMetricsV2(t *testing.T) {
+	t.Parallel()
+
+	mock := newMockV2(t)
+	repo := &gitalypb.Repository{
+		StorageName:  "default",
+		RelativePath: "path/to/repo.git",
+	}
+
+	config := defaultCgroupsV2Config(mock.root)
+	config.Repositories.Count = 1
+	config.Repositories.MemoryBytes = 1048576
+	config.Repositories.CPUShares = 16
+
+	v2Manager := newV2Manager(config, 1)
+	mock.setupMockCgroupFiles(t, v2Manager, 2)
+
+	require.NoError(t, v2Manager.Setup())
+
+	ctx := testhelper.Context(t)
+
+	cmd, err := command.New(ctx, []string{"ls", "-hal", "."}, command.WithCgroup(v2Manager, repo))
+	require.NoError(t, err)
+	require.NoError(t, cmd.Wait())
+
+	repoCgroupPath := filepath.Join(v2Manager.currentProcessCgroup(), "repos-0")
+
+	expected := bytes.NewBufferString(fmt.Sprintf(`# HELP gitaly_cgroup_cpu_usage_total CPU Usage of Cgroup
+# TYPE gitaly_cgroup_cpu_usage_total gauge
+gitaly_cgroup_cpu_usage_total{path="%s",type="kernel"} 100000
+gitaly_cgroup_cpu_usage_total{path="%s",type="user"} 200000
+# HELP gitaly_cgroup_memory_reclaim_attempts_total Number of memory usage hits limits
+# TYPE gitaly_cgroup_memory_reclaim_attempts_total gauge
+gitaly_cgroup_memory_reclaim_attempts_total{path="%s"} 2
+# HELP gitaly_cgroup_procs_total Total number of procs
+# TYPE gitaly_cgroup_procs_total gauge
+gitaly_cgroup_procs_total{path="%s",subsystem="cpu"} 1
+gitaly_cgroup_procs_total{path="%s",subsystem="memory"} 1
+`, repoCgroupPath, repoCgroupPath, repoCgroupPath, repoCgroupPath, repoCgroupPath))
+
+	for _, metricsEnabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("metrics enabled: %v", metricsEnabled), func(t *testing.T) {
+			v2Manager.cfg.MetricsEnabled = metricsEnabled
+
+			if metricsEnabled {
+				assert.NoError(t, testutil.CollectAndCompare(v2Manager, expected))
+			} else {
+				assert.NoError(t, testutil.CollectAndCompare(v2Manager, bytes.NewBufferString("")))
+			}
+		})
+	}
+}
+
+func TestMetricsV2_pidsAndIO(t *testing.T) {
+	t.Parallel()
+
+	mock := newMockV2(t)
+
+	config := defaultCgroupsV2Config(mock.root)
+	config.Repositories.Count = 1
+	config.Repositories.PIDsLimit = 100
+	config.Repositories.IO = []cgroups.IOLimit{
+		{Device: "/dev/sda", ReadBPS: 1024},
+	}
+	config.MetricsEnabled = true
+
+	v2Manager := newV2Manager(config, 1)
+	v2Manager.deviceNumbers = func(string) (int64, int64, error) {
+		return 8, 0, nil
+	}
+	mock.setupMockCgroupFiles(t, v2Manager, 0)
+
+	require.NoError(t, v2Manager.Setup())
+
+	repoCgroupPath := filepath.Join(v2Manager.currentProcessCgroup(), "repos-0")
+
+	expected := bytes.NewBufferString(fmt.Sprintf(`# HELP gitaly_cgroup_io_bytes_total Number of bytes read from and written to block devices by cgroup
+# TYPE gitaly_cgroup_io_bytes_total gauge
+gitaly_cgroup_io_bytes_total{op="read",path="%[1]s"} 1024
+gitaly_cgroup_io_bytes_total{op="write",path="%[1]s"} 2048
+# HELP gitaly_cgroup_io_operations_total Number of read and write operations on block devices by cgroup
+# TYPE gitaly_cgroup_io_operations_total gauge
+gitaly_cgroup_io_operations_total{op="read",path="%[1]s"} 1
+gitaly_cgroup_io_operations_total{op="write",path="%[1]s"} 2
+# HELP gitaly_cgroup_pids_total Current number of processes and process limit of cgroup
+# TYPE gitaly_cgroup_pids_total gauge
+gitaly_cgroup_pids_total{path="%[1]s",type="current"} 1
+gitaly_cgroup_pids_total{path="%[1]s",type="limit"} 100
+`, repoCgroupPath))
+
+	require.NoError(t, testutil.CollectAndCompare(
+		v2Manager,
+		expected,
+		"gitaly_cgroup_io_bytes_total",
+		"gitaly_cgroup_io_operations_total",
+		"gitaly_cgroup_pids_total",
+	))
+}
+
+func TestPruneOldCgroupsV2(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc           string
+		hierarchyRoot  string
+		pid            func(*testing.T) int
+		expectedPruned bool
+	}{
+		{
+			desc:          "pid of finished process",
+			hierarchyRoot: "gitaly",
+			pid: func(t *testing.T) int {
+				cmd, err := command.New(testhelper.Context(t), []string{"ls"})
+				require.NoError(t, err)
+				require.NoError(t, cmd.Wait())
+				return cmd.Pid()
+			},
+			expectedPruned: true,
+		},
+		{
+			desc:          "pid of running process",
+			hierarchyRoot: "gitaly",
+			pid: func(*testing.T) int {
+				return os.Getpid()
+			},
+			expectedPruned: false,
+		},
+		{
+			desc:          "no hierarchy root",
+			hierarchyRoot: "",
+			pid: func(t *testing.T) int {
+				return 1
+			},
+			expectedPruned: false,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := cgroups.Config{
+				Mountpoint:    testhelper.TempDir(t),
+				HierarchyRoot: tc.hierarchyRoot,
+			}
+
+			processDir := filepath.Join(cfg.Mountpoint, cfg.HierarchyRoot, fmt.Sprintf("gitaly-%d", tc.pid(t)))
+			require.NoError(t, os.MkdirAll(processDir, 0o755))
+
+			logger, _ := test.NewNullLogger()
+			pruneOldCgroupsV2(cfg, logger)
+
+			if tc.expectedPruned {
+				require.NoDirExists(t, processDir)
+			} else {
+				require.DirExists(t, processDir)
+			}
+		})
+	}
+}
+
+func TestCPUSharesToWeight(t *testing.T) {
+	t.Parallel()
+
+	for shares, weight := range map[uint64]uint64{
+		0:      0,
+		1:      1,
+		2:      1,
+		1024:   39,
+		262144: 10000,
+		300000: 10000,
+	} {
+		require.Equal(t, weight, cpuSharesToWeight(shares), "shares: %d", shares)
+	}
+}
@@ -501,6 +501,26 @@ func (c *Command) Pid() int {
 	return c.cmd.Process.Pid
 }
 
+// Name is an accessor for the command name. This is the name set via WithCommandName or the base
+// name of the executable if no name was set.
+func (c *Command) Name() string {
+	if c.metricsCmd != "" {
+		return c.metricsCmd
+	}
+	return path.Base(c.cmd.Args[0])
+}
+
+// Subcommand is an accessor for the subcommand name set via WithCommandName.
+func (c *Command) Subcommand() string {
+	return c.metricsSubCmd
+}
+
+// RPC returns the service and method name of the RPC that has spawned the command. Both are empty
+// if the command has not been spawned in the context of an RPC.
+func (c *Command) RPC() (service string, method string) {
+	return methodFromContext(c.context)
+}
+
 var getSpawnTokenAcquiringSeconds = func(t time.Time) float64 {
 	return time.Since(t).Seconds()
 }
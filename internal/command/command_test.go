@@ -512,3 +512,39 @@ func TestCommand_withFinalizer(t *testing.T) {
 		}
 	})
 }
+
+func TestCommand_accessors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without RPC and name", func(t *testing.T) {
+		cmd, err := New(testhelper.Context(t), []string{"/bin/echo", "hello"})
+		require.NoError(t, err)
+		require.NoError(t, cmd.Wait())
+
+		require.Equal(t, "echo", cmd.Name())
+		require.Empty(t, cmd.Subcommand())
+
+		service, method := cmd.RPC()
+		require.Empty(t, service)
+		require.Empty(t, method)
+	})
+
+	t.Run("with RPC and name", func(t *testing.T) {
+		ctx := testhelper.Context(t)
+
+		tags := grpcmwtags.NewTags()
+		tags.Set("grpc.request.fullMethod", "/test.Service/TestRPC")
+		ctx = grpcmwtags.SetInContext(ctx, tags)
+
+		cmd, err := New(ctx, []string{"/bin/echo", "hello"}, WithCommandName("git", "pack-objects"))
+		require.NoError(t, err)
+		require.NoError(t, cmd.Wait())
+
+		require.Equal(t, "git", cmd.Name())
+		require.Equal(t, "pack-objects", cmd.Subcommand())
+
+		service, method := cmd.RPC()
+		require.Equal(t, "test.Service", service)
+		require.Equal(t, "TestRPC", method)
+	})
+}
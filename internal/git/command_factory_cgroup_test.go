@@ -11,6 +11,7 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/repository"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/metadata/featureflag"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
@@ -29,6 +30,10 @@ func (m *mockCgroupsManager) AddCommand(c *command.Command, repo repository.GitR
 	return "", nil
 }
 
+func (m *mockCgroupsManager) Update(cgroups.Config) error {
+	return nil
+}
+
 func (m *mockCgroupsManager) Cleanup() error {
 	return nil
 }
@@ -81,6 +81,19 @@ func ObjectHashByProto(format gitalypb.ObjectFormat) (ObjectHash, error) {
 	}
 }
 
+// ObjectHashByHex looks up the ObjectHash by the hex representation of a full object ID. This is
+// only useful in contexts where the repository isn't available, e.g. when parsing object IDs that
+// were passed to hooks. Returns ErrInvalidObjectID if the object ID isn't valid for any object hash.
+func ObjectHashByHex(hex string) (ObjectHash, error) {
+	for _, hash := range []ObjectHash{ObjectHashSHA1, ObjectHashSHA256} {
+		if hash.ValidateHex(hex) == nil {
+			return hash, nil
+		}
+	}
+
+	return ObjectHash{}, fmt.Errorf("%w: %q", ErrInvalidObjectID, hex)
+}
+
 // DetectObjectHash detects the object-hash used by the given repository.
 func DetectObjectHash(ctx context.Context, repoExecutor RepositoryExecutor) (ObjectHash, error) {
 	var stdout, stderr bytes.Buffer
@@ -93,6 +93,47 @@ func TestObjectHashByProto(t *testing.T) {
 	}
 }
 
+func TestObjectHashByHex(t *testing.T) {
+	for _, tc := range []struct {
+		desc               string
+		hex                string
+		expectedErr        error
+		expectedObjectHash git.ObjectHash
+	}{
+		{
+			desc:               "SHA1 object ID",
+			hex:                "356e7793f9654d51dfb27312a1464062bceb9fa3",
+			expectedObjectHash: git.ObjectHashSHA1,
+		},
+		{
+			desc:               "SHA256 object ID",
+			hex:                "aec070645fe53ee3b3763059376134f058cc337247c978add178b6ccdfb0019f",
+			expectedObjectHash: git.ObjectHashSHA256,
+		},
+		{
+			desc:        "abbreviated object ID",
+			hex:         "356e7793f9",
+			expectedErr: fmt.Errorf("%w: %q", git.ErrInvalidObjectID, "356e7793f9"),
+		},
+		{
+			desc:        "invalid object ID",
+			hex:         "356e7793f9654d51dfb27312a1464062bceb9fax",
+			expectedErr: fmt.Errorf("%w: %q", git.ErrInvalidObjectID, "356e7793f9654d51dfb27312a1464062bceb9fax"),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			objectHash, err := git.ObjectHashByHex(tc.hex)
+			require.Equal(t, tc.expectedErr, err)
+
+			// Function pointers cannot be compared, so we need to unset them.
+			objectHash.Hash = nil
+			tc.expectedObjectHash.Hash = nil
+
+			require.Equal(t, tc.expectedObjectHash, objectHash)
+		})
+	}
+}
+
 func TestDetectObjectHash(t *testing.T) {
 	cfg := testcfg.Build(t)
 	ctx := testhelper.Context(t)
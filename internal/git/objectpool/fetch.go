@@ -125,6 +125,11 @@ func (o *ObjectPool) pruneReferences(ctx context.Context, origin *localrepo.Repo
 		return fmt.Errorf("computing origin repo's path: %w", err)
 	}
 
+	objectHash, err := o.Repo.ObjectHash(ctx)
+	if err != nil {
+		return fmt.Errorf("detecting object hash: %w", err)
+	}
+
 	// Ideally, we'd just use `git remote prune` directly. But unfortunately, this command does
 	// not support atomic updates, but will instead use a separate reference transaction for
 	// updating the packed-refs file and for updating each of the loose references. This can be
@@ -189,7 +194,7 @@ func (o *ObjectPool) pruneReferences(ctx context.Context, origin *localrepo.Repo
 			// of every reference here.
 			deletedRef := "refs/remotes/" + string(bytes.TrimPrefix(line, []byte(" * [would prune] ")))
 
-			if _, err := io.Copy(voteHash, strings.NewReader(fmt.Sprintf("%[1]s %[1]s %s\n", git.ObjectHashSHA1.ZeroOID, deletedRef))); err != nil {
+			if _, err := io.Copy(voteHash, strings.NewReader(fmt.Sprintf("%[1]s %[1]s %s\n", objectHash.ZeroOID, deletedRef))); err != nil {
 				return fmt.Errorf("hashing reference deletion: %w", err)
 			}
 
@@ -249,6 +254,11 @@ const danglingObjectNamespace = "refs/dangling/"
 // an object is still used anywhere, so the only safe thing to do is to
 // assume that every object _is_ used.
 func (o *ObjectPool) rescueDanglingObjects(ctx context.Context) (returnedErr error) {
+	objectHash, err := o.Repo.ObjectHash(ctx)
+	if err != nil {
+		return fmt.Errorf("detecting object hash: %w", err)
+	}
+
 	fsck, err := o.Repo.Exec(ctx, git.SubCmd{
 		Name:  "fsck",
 		Flags: []git.Option{git.Flag{Name: "--connectivity-only"}, git.Flag{Name: "--dangling"}},
@@ -282,7 +292,7 @@ func (o *ObjectPool) rescueDanglingObjects(ctx context.Context) (returnedErr err
 			continue
 		}
 
-		danglingObjectID, err := git.ObjectHashSHA1.FromHex(split[2])
+		danglingObjectID, err := objectHash.FromHex(split[2])
 		if err != nil {
 			return fmt.Errorf("parsing object ID %q: %w", split[2], err)
 		}
@@ -52,7 +52,14 @@ func (rr *Repo) ResolveRevision(ctx context.Context, revision git.Revision) (git
 		return "", git.ErrReferenceNotFound
 	}
 
-	oid, err := git.ObjectHashSHA1.FromHex(oidHex)
+	// We don't know the object hash of the remote repository, so we need to derive it from
+	// the object ID.
+	objectHash, err := git.ObjectHashByHex(oidHex)
+	if err != nil {
+		return "", err
+	}
+
+	oid, err := objectHash.FromHex(oidHex)
 	if err != nil {
 		return "", err
 	}
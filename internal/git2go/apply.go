@@ -123,10 +123,11 @@ func (b *Executor) Apply(ctx context.Context, repo repository.GitRepo, params Ap
 		return "", result.Err
 	}
 
-	commitID, err := git.ObjectHashSHA1.FromHex(result.CommitID)
-	if err != nil {
+	// The object hash is derived from the commit ID itself given that we don't have the
+	// repository at hand.
+	if _, err := git.ObjectHashByHex(result.CommitID); err != nil {
 		return "", fmt.Errorf("could not parse commit ID: %w", err)
 	}
 
-	return commitID, nil
+	return git.ObjectID(result.CommitID), nil
 }
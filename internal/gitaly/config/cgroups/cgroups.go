@@ -1,21 +1,37 @@
 package cgroups
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
 // Config is a struct for cgroups config
 type Config struct {
 	// Mountpoint is where the cgroup filesystem is mounted, usually under /sys/fs/cgroup/
 	Mountpoint string `toml:"mountpoint"`
 	// HierarchyRoot is the parent cgroup under which Gitaly creates <Count> of cgroups.
 	// A system administrator is expected to create such cgroup/directory under <Mountpoint>/memory
-	// and/or <Mountpoint>/cpu depending on which resource is enabled. HierarchyRoot is expected to
-	// be owned by the user and group Gitaly runs as.
+	// and/or <Mountpoint>/cpu depending on which resource is enabled when using cgroups v1, or
+	// directly under <Mountpoint> when using cgroups v2. HierarchyRoot is expected to be owned by
+	// the user and group Gitaly runs as.
 	HierarchyRoot string       `toml:"hierarchy_root"`
 	Repositories  Repositories `toml:"repositories"`
+	// Buckets configures dedicated cgroups for specific classes of commands. Commands which
+	// match a bucket are added to that bucket's cgroup instead of one of the repository
+	// cgroups.
+	Buckets []Bucket `toml:"buckets"`
 	// MemoryBytes is the memory limit for the parent cgroup. 0 implies no memory limit.
 	MemoryBytes int64 `toml:"memory_bytes"`
 	// CPUShares are the shares of CPU the parent cgroup is allowed to utilize. A value of 1024
 	// is full utilization of the CPU. 0 implies no CPU limit.
-	CPUShares      uint64 `toml:"cpu_shares"`
-	MetricsEnabled bool   `toml:"metrics_enabled"`
+	CPUShares uint64 `toml:"cpu_shares"`
+	// PIDsLimit is the maximum number of processes the parent cgroup may contain. 0 implies no
+	// limit.
+	PIDsLimit int64 `toml:"pids_limit"`
+	// IO configures block I/O limits of the parent cgroup per block device.
+	IO             []IOLimit `toml:"io"`
+	MetricsEnabled bool      `toml:"metrics_enabled"`
 
 	// Deprecated: No longer supported after 15.0
 	Count  uint   `toml:"count"`
@@ -49,6 +65,124 @@ type Repositories struct {
 	// CPUShares are the shares of CPU that each cgroup is allowed to utilize. A value of 1024
 	// is full utilization of the CPU. 0 implies no CPU limit.
 	CPUShares uint64 `toml:"cpu_shares"`
+	// PIDsLimit is the maximum number of processes each cgroup may contain. 0 implies no limit.
+	PIDsLimit int64 `toml:"pids_limit"`
+	// IO configures block I/O limits of each cgroup per block device.
+	IO []IOLimit `toml:"io"`
+}
+
+// Bucket configures a named cgroup that commands are added to when they match any of the bucket's
+// criteria. Buckets are evaluated in order and the first matching bucket wins.
+type Bucket struct {
+	// Name is the name of the bucket. It is used as name of the cgroup created for the bucket
+	// and must thus be a valid path component.
+	Name string `toml:"name"`
+	// RPCs matches commands spawned by any of the given RPCs. Entries may either be the full
+	// method name like "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel" or only the
+	// method name like "PostUploadPackWithSidechannel".
+	RPCs []string `toml:"rpcs"`
+	// Commands matches commands by their name, e.g. "git".
+	Commands []string `toml:"commands"`
+	// GitSubcommands matches Git commands by their subcommand, e.g. "pack-objects".
+	GitSubcommands []string `toml:"git_subcommands"`
+	// MemoryBytes is the memory limit for the bucket's cgroup. 0 implies no memory limit.
+	MemoryBytes int64 `toml:"memory_bytes"`
+	// CPUShares are the shares of CPU that the bucket's cgroup is allowed to utilize. A value of
+	// 1024 is full utilization of the CPU. 0 implies no CPU limit.
+	CPUShares uint64 `toml:"cpu_shares"`
+	// PIDsLimit is the maximum number of processes the bucket's cgroup may contain. 0 implies no
+	// limit.
+	PIDsLimit int64 `toml:"pids_limit"`
+	// IO configures block I/O limits of the bucket's cgroup per block device.
+	IO []IOLimit `toml:"io"`
+}
+
+// Matches determines whether a command spawned by the given RPC with the given command and
+// subcommand name falls into the bucket. All criteria which are set must match for the command to
+// match the bucket, where each criterion matches if any of its entries match.
+func (b Bucket) Matches(service, method, command, subcommand string) bool {
+	if len(b.RPCs) == 0 && len(b.Commands) == 0 && len(b.GitSubcommands) == 0 {
+		return false
+	}
+
+	if len(b.RPCs) > 0 {
+		if method == "" || !containsAny(b.RPCs, "/"+service+"/"+method, method) {
+			return false
+		}
+	}
+
+	if len(b.Commands) > 0 && !containsAny(b.Commands, command) {
+		return false
+	}
+
+	if len(b.GitSubcommands) > 0 && !containsAny(b.GitSubcommands, subcommand) {
+		return false
+	}
+
+	return true
+}
+
+func containsAny(haystack []string, needles ...string) bool {
+	for _, needle := range needles {
+		if needle == "" {
+			continue
+		}
+
+		for _, value := range haystack {
+			if value == needle {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IOLimit configures the block I/O limits for a single block device. Limits which are set to 0
+// are not enforced.
+type IOLimit struct {
+	// Device is the path to the block device node the limits apply to, e.g. /dev/sda.
+	Device string `toml:"device"`
+	// ReadBPS is the maximum number of bytes per second that can be read from the device.
+	ReadBPS uint64 `toml:"read_bps"`
+	// WriteBPS is the maximum number of bytes per second that can be written to the device.
+	WriteBPS uint64 `toml:"write_bps"`
+	// ReadIOPS is the maximum number of read operations per second on the device.
+	ReadIOPS uint64 `toml:"read_iops"`
+	// WriteIOPS is the maximum number of write operations per second on the device.
+	WriteIOPS uint64 `toml:"write_iops"`
+}
+
+// PIDsEnabled returns whether a PID limit is configured for either the parent cgroup, the
+// repository cgroups or any of the buckets.
+func (c Config) PIDsEnabled() bool {
+	if c.PIDsLimit > 0 || c.Repositories.PIDsLimit > 0 {
+		return true
+	}
+
+	for _, bucket := range c.Buckets {
+		if bucket.PIDsLimit > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IOEnabled returns whether block I/O limits are configured for either the parent cgroup, the
+// repository cgroups or any of the buckets.
+func (c Config) IOEnabled() bool {
+	if len(c.IO) > 0 || len(c.Repositories.IO) > 0 {
+		return true
+	}
+
+	for _, bucket := range c.Buckets {
+		if len(bucket.IO) > 0 {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Memory is a struct storing cgroups memory config
@@ -66,3 +200,76 @@ type CPU struct {
 	// Shares is the number of CPU shares (relative weight (ratio) vs. other cgroups with CPU shares).
 	Shares uint64 `toml:"shares"`
 }
+
+// ValidateReload checks whether the configuration can be changed to newCfg while Gitaly is
+// running. Only memory, CPU and PID limits of existing cgroups can be changed at runtime. Changes
+// to the hierarchy itself, to the bucket matchers and to block I/O limits require a restart.
+// Limits can be changed, but not removed, as removing a limit would require resetting it in the
+// existing cgroups.
+func (c Config) ValidateReload(newCfg Config) error {
+	switch {
+	case c.Mountpoint != newCfg.Mountpoint:
+		return errors.New("mountpoint cannot be changed at runtime")
+	case c.HierarchyRoot != newCfg.HierarchyRoot:
+		return errors.New("hierarchy_root cannot be changed at runtime")
+	case c.MetricsEnabled != newCfg.MetricsEnabled:
+		return errors.New("metrics_enabled cannot be changed at runtime")
+	case c.Repositories.Count != newCfg.Repositories.Count:
+		return errors.New("repositories.count cannot be changed at runtime")
+	case c.PIDsEnabled() != newCfg.PIDsEnabled():
+		return errors.New("pid limits cannot be enabled or disabled at runtime")
+	case !reflect.DeepEqual(c.IO, newCfg.IO) || !reflect.DeepEqual(c.Repositories.IO, newCfg.Repositories.IO):
+		return errors.New("io limits cannot be changed at runtime")
+	case len(c.Buckets) != len(newCfg.Buckets):
+		return errors.New("buckets cannot be added or removed at runtime")
+	}
+
+	if err := validateLimitsReload("", c.MemoryBytes, newCfg.MemoryBytes, c.CPUShares, newCfg.CPUShares, c.PIDsLimit, newCfg.PIDsLimit); err != nil {
+		return err
+	}
+
+	if err := validateLimitsReload("repositories.", c.Repositories.MemoryBytes, newCfg.Repositories.MemoryBytes,
+		c.Repositories.CPUShares, newCfg.Repositories.CPUShares,
+		c.Repositories.PIDsLimit, newCfg.Repositories.PIDsLimit,
+	); err != nil {
+		return err
+	}
+
+	for i, bucket := range c.Buckets {
+		newBucket := newCfg.Buckets[i]
+
+		if bucket.Name != newBucket.Name ||
+			!reflect.DeepEqual(bucket.RPCs, newBucket.RPCs) ||
+			!reflect.DeepEqual(bucket.Commands, newBucket.Commands) ||
+			!reflect.DeepEqual(bucket.GitSubcommands, newBucket.GitSubcommands) {
+			return fmt.Errorf("bucket %q: name and matchers cannot be changed at runtime", bucket.Name)
+		}
+
+		if !reflect.DeepEqual(bucket.IO, newBucket.IO) {
+			return fmt.Errorf("bucket %q: io limits cannot be changed at runtime", bucket.Name)
+		}
+
+		if err := validateLimitsReload(fmt.Sprintf("buckets.%s.", bucket.Name),
+			bucket.MemoryBytes, newBucket.MemoryBytes,
+			bucket.CPUShares, newBucket.CPUShares,
+			bucket.PIDsLimit, newBucket.PIDsLimit,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateLimitsReload(prefix string, oldMemory, newMemory int64, oldCPU, newCPU uint64, oldPIDs, newPIDs int64) error {
+	switch {
+	case oldMemory > 0 && newMemory == 0:
+		return fmt.Errorf("%smemory_bytes cannot be removed at runtime", prefix)
+	case oldCPU > 0 && newCPU == 0:
+		return fmt.Errorf("%scpu_shares cannot be removed at runtime", prefix)
+	case oldPIDs > 0 && newPIDs == 0:
+		return fmt.Errorf("%spids_limit cannot be removed at runtime", prefix)
+	}
+
+	return nil
+}
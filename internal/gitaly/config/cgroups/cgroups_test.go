@@ -150,3 +150,223 @@ func TestFallbackToOldVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestBucket_Matches(t *testing.T) {
+	const (
+		service = "gitaly.SmartHTTPService"
+		method  = "PostUploadPackWithSidechannel"
+	)
+
+	testCases := []struct {
+		desc       string
+		bucket     Bucket
+		service    string
+		method     string
+		command    string
+		subcommand string
+		expected   bool
+	}{
+		{
+			desc:       "no criteria",
+			bucket:     Bucket{Name: "empty"},
+			service:    service,
+			method:     method,
+			command:    "git",
+			subcommand: "pack-objects",
+			expected:   false,
+		},
+		{
+			desc:       "full method name",
+			bucket:     Bucket{RPCs: []string{"/gitaly.SmartHTTPService/PostUploadPackWithSidechannel"}},
+			service:    service,
+			method:     method,
+			command:    "git",
+			subcommand: "upload-pack",
+			expected:   true,
+		},
+		{
+			desc:       "method name only",
+			bucket:     Bucket{RPCs: []string{"PostUploadPackWithSidechannel"}},
+			service:    service,
+			method:     method,
+			command:    "git",
+			subcommand: "upload-pack",
+			expected:   true,
+		},
+		{
+			desc:       "mismatching RPC",
+			bucket:     Bucket{RPCs: []string{"SSHUploadPackWithSidechannel"}},
+			service:    service,
+			method:     method,
+			command:    "git",
+			subcommand: "upload-pack",
+			expected:   false,
+		},
+		{
+			desc:       "RPC without context",
+			bucket:     Bucket{RPCs: []string{"SSHUploadPackWithSidechannel"}},
+			command:    "git",
+			subcommand: "upload-pack",
+			expected:   false,
+		},
+		{
+			desc:       "command",
+			bucket:     Bucket{Commands: []string{"gitaly-lfs-smudge"}},
+			command:    "gitaly-lfs-smudge",
+			subcommand: "",
+			expected:   true,
+		},
+		{
+			desc:       "any subcommand",
+			bucket:     Bucket{GitSubcommands: []string{"repack", "gc"}},
+			command:    "git",
+			subcommand: "gc",
+			expected:   true,
+		},
+		{
+			desc: "all criteria match",
+			bucket: Bucket{
+				RPCs:           []string{method, "PackObjectsHookWithSidechannel"},
+				GitSubcommands: []string{"pack-objects"},
+			},
+			service:    service,
+			method:     method,
+			command:    "git",
+			subcommand: "pack-objects",
+			expected:   true,
+		},
+		{
+			desc: "one criterion mismatches",
+			bucket: Bucket{
+				RPCs:           []string{method},
+				GitSubcommands: []string{"pack-objects"},
+			},
+			service:    service,
+			method:     method,
+			command:    "git",
+			subcommand: "upload-pack",
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.bucket.Matches(tc.service, tc.method, tc.command, tc.subcommand))
+		})
+	}
+}
+
+func TestConfig_ValidateReload(t *testing.T) {
+	t.Parallel()
+
+	baseCfg := func() Config {
+		return Config{
+			Mountpoint:    "/sys/fs/cgroup",
+			HierarchyRoot: "gitaly",
+			MemoryBytes:   1024,
+			CPUShares:     512,
+			Repositories: Repositories{
+				Count:       10,
+				MemoryBytes: 256,
+				PIDsLimit:   100,
+			},
+			Buckets: []Bucket{
+				{Name: "pack-objects", GitSubcommands: []string{"pack-objects"}, MemoryBytes: 512},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		desc        string
+		modify      func(cfg *Config)
+		expectedErr string
+	}{
+		{
+			desc:   "unchanged",
+			modify: func(cfg *Config) {},
+		},
+		{
+			desc: "changed limits",
+			modify: func(cfg *Config) {
+				cfg.MemoryBytes = 2048
+				cfg.CPUShares = 1024
+				cfg.Repositories.MemoryBytes = 128
+				cfg.Repositories.PIDsLimit = 50
+				cfg.Buckets[0].MemoryBytes = 1024
+				cfg.Buckets[0].CPUShares = 256
+			},
+		},
+		{
+			desc: "changed mountpoint",
+			modify: func(cfg *Config) {
+				cfg.Mountpoint = "/cgroup"
+			},
+			expectedErr: "mountpoint cannot be changed at runtime",
+		},
+		{
+			desc: "changed repository count",
+			modify: func(cfg *Config) {
+				cfg.Repositories.Count = 20
+			},
+			expectedErr: "repositories.count cannot be changed at runtime",
+		},
+		{
+			desc: "disabled pid limits",
+			modify: func(cfg *Config) {
+				cfg.Repositories.PIDsLimit = 0
+			},
+			expectedErr: "pid limits cannot be enabled or disabled at runtime",
+		},
+		{
+			desc: "added io limits",
+			modify: func(cfg *Config) {
+				cfg.IO = []IOLimit{{Device: "/dev/sda", ReadBPS: 1}}
+			},
+			expectedErr: "io limits cannot be changed at runtime",
+		},
+		{
+			desc: "removed memory limit",
+			modify: func(cfg *Config) {
+				cfg.Repositories.MemoryBytes = 0
+			},
+			expectedErr: "repositories.memory_bytes cannot be removed at runtime",
+		},
+		{
+			desc: "removed bucket",
+			modify: func(cfg *Config) {
+				cfg.Buckets = nil
+			},
+			expectedErr: "buckets cannot be added or removed at runtime",
+		},
+		{
+			desc: "changed bucket matcher",
+			modify: func(cfg *Config) {
+				cfg.Buckets[0].GitSubcommands = []string{"upload-pack"}
+			},
+			expectedErr: `bucket "pack-objects": name and matchers cannot be changed at runtime`,
+		},
+		{
+			desc: "removed bucket limit",
+			modify: func(cfg *Config) {
+				cfg.Buckets[0].MemoryBytes = 0
+			},
+			expectedErr: "buckets.pack-objects.memory_bytes cannot be removed at runtime",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			newCfg := baseCfg()
+			tc.modify(&newCfg)
+
+			err := baseCfg().ValidateReload(newCfg)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
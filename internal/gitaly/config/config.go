@@ -61,12 +61,15 @@ type Cfg struct {
 	GitlabShell            GitlabShell         `toml:"gitlab-shell"`
 	Hooks                  Hooks               `toml:"hooks"`
 	Concurrency            []Concurrency       `toml:"concurrency"`
+	AdaptiveLimiting       AdaptiveLimiting    `toml:"adaptive_limiting"`
+	RequestPriority        RequestPriority     `toml:"request_priority"`
 	RateLimiting           []RateLimiting      `toml:"rate_limiting"`
 	GracefulRestartTimeout duration.Duration   `toml:"graceful_restart_timeout"`
 	DailyMaintenance       DailyJob            `toml:"daily_maintenance"`
 	Cgroups                cgroups.Config      `toml:"cgroups"`
 	PackObjectsCache       StreamCacheConfig   `toml:"pack_objects_cache"`
 	PackObjectsLimiting    PackObjectsLimiting `toml:"pack_objects_limiting"`
+	Backup                 BackupConfig        `toml:"backup"`
 }
 
 // TLS configuration
@@ -150,6 +153,66 @@ type Concurrency struct {
 	// MaxQueueWait is the maximum time a request can remain in the concurrency queue
 	// waiting to be picked up by Gitaly
 	MaxQueueWait duration.Duration `toml:"max_queue_wait"`
+	// Adaptive enables adaptive limiting. MaxPerRepo is then only used as the initial limit,
+	// which is adjusted between MinLimit and MaxLimit based on the observed latency and
+	// resource pressure. See AdaptiveLimiting for how the limit is calibrated.
+	Adaptive bool `toml:"adaptive"`
+	// MinLimit is the lower bound of the adaptive limit. Defaults to 1.
+	MinLimit int `toml:"min_limit"`
+	// MaxLimit is the upper bound of the adaptive limit. It must not be lower than
+	// MaxPerRepo.
+	MaxLimit int `toml:"max_limit"`
+}
+
+// AdaptiveLimiting configures how adaptive concurrency limits are calibrated. Limits follow an
+// additive-increase/multiplicative-decrease scheme: they are increased by one per calibration
+// interval in which requests had to be queued, and multiplied by BackoffFactor whenever latency
+// or resource pressure indicate that Gitaly is overloaded.
+type AdaptiveLimiting struct {
+	// CalibrationInterval is the interval in which adaptive limits are recalculated.
+	// Defaults to 5 seconds.
+	CalibrationInterval duration.Duration `toml:"calibration_interval"`
+	// BackoffFactor is the factor by which limits are multiplied when backing off. Must be in
+	// the range (0, 1). Defaults to 0.75.
+	BackoffFactor float64 `toml:"backoff_factor"`
+	// LatencyTolerance is the ratio between the average latency of a calibration interval and
+	// the baseline latency above which limits are backed off. Must be greater than 1. Defaults
+	// to 2.
+	LatencyTolerance float64 `toml:"latency_tolerance"`
+	// CPUPressureThreshold is the percentage of time tasks were stalled waiting for CPU above
+	// which limits are backed off. Defaults to 60.
+	CPUPressureThreshold float64 `toml:"cpu_pressure_threshold"`
+	// MemoryPressureThreshold is the percentage of time tasks were stalled waiting for memory
+	// above which limits are backed off. Defaults to 20.
+	MemoryPressureThreshold float64 `toml:"memory_pressure_threshold"`
+}
+
+// RequestPriority configures how requests which are queued by concurrency limits are admitted.
+// Without any priority classes, queued requests are admitted in FIFO order. Otherwise, each
+// request is assigned to a priority class and queued requests are admitted by the weight of their
+// class: a class with weight 4 gets four requests admitted for every request of a class with
+// weight 1.
+type RequestPriority struct {
+	// Classes are the priority classes requests are assigned to. Requests authenticated with
+	// Gitaly's token are assigned to the class named by their "request_priority" gRPC metadata.
+	// Otherwise, they are assigned to the first class that matches the name of the calling
+	// client, or to the "default" class. The "default" class has a weight of 1 unless it is
+	// configured explicitly.
+	Classes []PriorityClass `toml:"classes"`
+	// MaxStarvation is the maximum time a queued request may have to wait for requests of
+	// other classes that have been queued after it. Requests that have been queued for longer
+	// are admitted in FIFO order regardless of their priority. Defaults to 1 second.
+	MaxStarvation duration.Duration `toml:"max_starvation"`
+}
+
+// PriorityClass is a class of requests that are admitted with the same priority.
+type PriorityClass struct {
+	// Name is the name of the class.
+	Name string `toml:"name"`
+	// Weight is the relative weight of the class.
+	Weight int `toml:"weight"`
+	// ClientNames are the names of the clients whose requests are assigned to this class.
+	ClientNames []string `toml:"client_names"`
 }
 
 // RateLimiting allows endpoints to be limited to a maximum request rate per
@@ -166,6 +229,54 @@ type RateLimiting struct {
 	Interval duration.Duration `toml:"interval"`
 	// Burst sets the capacity of the token bucket (see above).
 	Burst int `toml:"burst"`
+	// Key is the key by which requests are rate limited. Supported keys are:
+	// repository, user, remote_ip, project. Defaults to repository.
+	Key RateLimitingKey `toml:"key,omitempty"`
+	// DryRun causes requests that exceed the rate limit to be logged and counted
+	// instead of being rejected.
+	DryRun bool `toml:"dry_run"`
+}
+
+// RateLimitingKey is the key for rate limiting requests
+type RateLimitingKey string
+
+const (
+	// RateLimitingKeyRepository will rate limit requests by repository
+	RateLimitingKeyRepository = RateLimitingKey("repository")
+	// RateLimitingKeyUser will rate limit requests by the GitLab user ID
+	RateLimitingKeyUser = RateLimitingKey("user")
+	// RateLimitingKeyRemoteIP will rate limit requests by the remote IP of the
+	// client that talks to GitLab
+	RateLimitingKeyRemoteIP = RateLimitingKey("remote_ip")
+	// RateLimitingKeyProject will rate limit requests by GitLab project path
+	RateLimitingKeyProject = RateLimitingKey("project")
+)
+
+// ParseRateLimitingKey checks if the key is a valid RateLimitingKey
+func ParseRateLimitingKey(k string) (RateLimitingKey, error) {
+	switch RateLimitingKey(k) {
+	case RateLimitingKeyRepository:
+		return RateLimitingKeyRepository, nil
+	case RateLimitingKeyUser:
+		return RateLimitingKeyUser, nil
+	case RateLimitingKeyRemoteIP:
+		return RateLimitingKeyRemoteIP, nil
+	case RateLimitingKeyProject:
+		return RateLimitingKeyProject, nil
+	default:
+		return "", fmt.Errorf("unsupported rate limiting key: %s", k)
+	}
+}
+
+// UnmarshalText unmarshals a key into a RateLimitingKey
+func (k *RateLimitingKey) UnmarshalText(text []byte) error {
+	v, err := ParseRateLimitingKey(string(text))
+	if err != nil {
+		return err
+	}
+	*k = v
+
+	return nil
 }
 
 // PackObjectsLimitingKey is the key for limiting pack objects concurrency
@@ -223,6 +334,35 @@ type StreamCacheConfig struct {
 	Enabled bool              `toml:"enabled"` // Default: false
 	Dir     string            `toml:"dir"`     // Default: <FIRST STORAGE PATH>/+gitaly/PackObjectsCache
 	MaxAge  duration.Duration `toml:"max_age"` // Default: 5m
+	// MaxBytes is the total size of the cache entries above which the least
+	// recently used entries are evicted. Zero means no limit.
+	MaxBytes int64 `toml:"max_bytes"`
+	// MaxBytesPerRepository is the size of the cache entries of a single
+	// repository above which its least recently used entries are evicted.
+	// Zero means no limit.
+	MaxBytesPerRepository int64 `toml:"max_bytes_per_repository"`
+	// MinFreeBytes is the free space on the disk holding Dir below which the
+	// least recently used entries are evicted. Zero means no limit.
+	MinFreeBytes int64 `toml:"min_free_bytes"`
+	// WarmOnPush enables pre-generating the pack for a clone of the default
+	// branch when a push updates the default branch or creates a tag.
+	WarmOnPush bool `toml:"warm_on_push"`
+	// SharedDir is a directory, possibly shared between Gitaly nodes, that is
+	// used as a second cache tier. The second tier is disabled if it is empty.
+	SharedDir string `toml:"shared_dir"`
+	// SharedMaxBytes is the size the second cache tier is kept within by
+	// evicting its least recently used entries. Required if SharedDir is set.
+	SharedMaxBytes int64 `toml:"shared_max_bytes"`
+}
+
+// BackupConfig configures server-side backups.
+type BackupConfig struct {
+	// GoCloudURL is the blob storage GoCloud URL that will be used to store
+	// server-side backups. Server-side backups are disabled if it is empty.
+	GoCloudURL string `toml:"go_cloud_url"`
+	// Layout determines how backup files are located. One of legacy,
+	// pointer. Defaults to pointer.
+	Layout string `toml:"layout"`
 }
 
 // Load initializes the Config variable from file and the environment.
@@ -260,7 +400,10 @@ func (cfg *Cfg) Validate() error {
 		cfg.validateRuntimeDir,
 		cfg.validateMaintenance,
 		cfg.validateCgroups,
+		cfg.validateConcurrency,
+		cfg.validateRequestPriority,
 		cfg.configurePackObjectsCache,
+		cfg.configureBackup,
 	} {
 		if err := run(); err != nil {
 			return err
@@ -556,6 +699,132 @@ func (cfg *Cfg) validateCgroups() error {
 		return errors.New("cgroups.repositories: cpu shares cannot exceed parent")
 	}
 
+	if cg.PIDsLimit < 0 || cg.Repositories.PIDsLimit < 0 {
+		return errors.New("cgroups: pids limit cannot be negative")
+	}
+
+	if cg.PIDsLimit > 0 && (cg.Repositories.PIDsLimit > cg.PIDsLimit) {
+		return errors.New("cgroups.repositories: pids limit cannot exceed parent")
+	}
+
+	bucketNames := make(map[string]bool, len(cg.Buckets))
+	ioLimits := [][]cgroups.IOLimit{cg.IO, cg.Repositories.IO}
+	for _, bucket := range cg.Buckets {
+		if bucket.Name == "" {
+			return errors.New("cgroups.buckets: bucket requires a name")
+		}
+
+		if bucket.Name != filepath.Base(bucket.Name) || bucket.Name == "." || bucket.Name == ".." ||
+			strings.HasPrefix(bucket.Name, "repos-") {
+			return fmt.Errorf("cgroups.buckets: invalid bucket name %q", bucket.Name)
+		}
+
+		if bucketNames[bucket.Name] {
+			return fmt.Errorf("cgroups.buckets: duplicate bucket name %q", bucket.Name)
+		}
+		bucketNames[bucket.Name] = true
+
+		if len(bucket.RPCs) == 0 && len(bucket.Commands) == 0 && len(bucket.GitSubcommands) == 0 {
+			return fmt.Errorf("cgroups.buckets: bucket %q does not match any commands", bucket.Name)
+		}
+
+		if cg.MemoryBytes > 0 && bucket.MemoryBytes > cg.MemoryBytes {
+			return fmt.Errorf("cgroups.buckets: memory limit of bucket %q cannot exceed parent", bucket.Name)
+		}
+
+		if cg.CPUShares > 0 && bucket.CPUShares > cg.CPUShares {
+			return fmt.Errorf("cgroups.buckets: cpu shares of bucket %q cannot exceed parent", bucket.Name)
+		}
+
+		if bucket.PIDsLimit < 0 {
+			return errors.New("cgroups: pids limit cannot be negative")
+		}
+
+		if cg.PIDsLimit > 0 && bucket.PIDsLimit > cg.PIDsLimit {
+			return fmt.Errorf("cgroups.buckets: pids limit of bucket %q cannot exceed parent", bucket.Name)
+		}
+
+		ioLimits = append(ioLimits, bucket.IO)
+	}
+
+	for _, limits := range ioLimits {
+		for _, limit := range limits {
+			if limit.Device == "" {
+				return errors.New("cgroups: io limit requires a device")
+			}
+
+			if !filepath.IsAbs(limit.Device) {
+				return fmt.Errorf("cgroups: io limit device must be an absolute path: %q", limit.Device)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Cfg) validateConcurrency() error {
+	for _, limit := range cfg.Concurrency {
+		if !limit.Adaptive {
+			continue
+		}
+
+		if limit.MaxPerRepo <= 0 {
+			return fmt.Errorf("concurrency %q: adaptive limiting requires max_per_repo", limit.RPC)
+		}
+
+		if limit.MinLimit < 0 || limit.MinLimit > limit.MaxPerRepo {
+			return fmt.Errorf("concurrency %q: min_limit must be between 0 and max_per_repo", limit.RPC)
+		}
+
+		if limit.MaxLimit < limit.MaxPerRepo {
+			return fmt.Errorf("concurrency %q: max_limit cannot be lower than max_per_repo", limit.RPC)
+		}
+	}
+
+	adaptive := cfg.AdaptiveLimiting
+
+	if adaptive.CalibrationInterval < 0 {
+		return errors.New("adaptive_limiting: calibration_interval cannot be negative")
+	}
+
+	if adaptive.BackoffFactor < 0 || adaptive.BackoffFactor >= 1 {
+		return errors.New("adaptive_limiting: backoff_factor must be in the range (0, 1)")
+	}
+
+	if adaptive.LatencyTolerance != 0 && adaptive.LatencyTolerance <= 1 {
+		return errors.New("adaptive_limiting: latency_tolerance must be greater than 1")
+	}
+
+	for _, threshold := range []float64{adaptive.CPUPressureThreshold, adaptive.MemoryPressureThreshold} {
+		if threshold < 0 || threshold > 100 {
+			return errors.New("adaptive_limiting: pressure thresholds must be in the range [0, 100]")
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Cfg) validateRequestPriority() error {
+	if cfg.RequestPriority.MaxStarvation < 0 {
+		return errors.New("request_priority: max_starvation cannot be negative")
+	}
+
+	names := make(map[string]bool, len(cfg.RequestPriority.Classes))
+	for _, class := range cfg.RequestPriority.Classes {
+		if class.Name == "" {
+			return errors.New("request_priority: class name cannot be empty")
+		}
+
+		if names[class.Name] {
+			return fmt.Errorf("request_priority: duplicate class %q", class.Name)
+		}
+		names[class.Name] = true
+
+		if class.Weight <= 0 {
+			return fmt.Errorf("request_priority: class %q must have a positive weight", class.Name)
+		}
+	}
+
 	return nil
 }
 
@@ -563,6 +832,10 @@ var (
 	errPackObjectsCacheNegativeMaxAge = errors.New("pack_objects_cache.max_age cannot be negative")
 	errPackObjectsCacheNoStorages     = errors.New("pack_objects_cache: cannot pick default cache directory: no storages")
 	errPackObjectsCacheRelativePath   = errors.New("pack_objects_cache: storage directory must be absolute path")
+	errPackObjectsCacheSharedRelative = errors.New("pack_objects_cache: shared directory must be absolute path")
+	errPackObjectsCacheSharedSameDir  = errors.New("pack_objects_cache: shared directory must differ from storage directory")
+	errPackObjectsCacheSharedMaxBytes = errors.New("pack_objects_cache.shared_max_bytes must be positive")
+	errPackObjectsCacheNegativeLimit  = errors.New("pack_objects_cache: max_bytes, max_bytes_per_repository and min_free_bytes cannot be negative")
 )
 
 func (cfg *Cfg) configurePackObjectsCache() error {
@@ -579,6 +852,10 @@ func (cfg *Cfg) configurePackObjectsCache() error {
 		poc.MaxAge = duration.Duration(5 * time.Minute)
 	}
 
+	if poc.MaxBytes < 0 || poc.MaxBytesPerRepository < 0 || poc.MinFreeBytes < 0 {
+		return errPackObjectsCacheNegativeLimit
+	}
+
 	if poc.Dir == "" {
 		if len(cfg.Storages) == 0 {
 			return errPackObjectsCacheNoStorages
@@ -591,6 +868,38 @@ func (cfg *Cfg) configurePackObjectsCache() error {
 		return errPackObjectsCacheRelativePath
 	}
 
+	if poc.SharedDir == "" {
+		return nil
+	}
+
+	if !filepath.IsAbs(poc.SharedDir) {
+		return errPackObjectsCacheSharedRelative
+	}
+
+	if filepath.Clean(poc.SharedDir) == filepath.Clean(poc.Dir) {
+		return errPackObjectsCacheSharedSameDir
+	}
+
+	if poc.SharedMaxBytes <= 0 {
+		return errPackObjectsCacheSharedMaxBytes
+	}
+
+	return nil
+}
+
+func (cfg *Cfg) configureBackup() error {
+	if cfg.Backup.GoCloudURL == "" {
+		return nil
+	}
+
+	switch cfg.Backup.Layout {
+	case "":
+		cfg.Backup.Layout = "pointer"
+	case "legacy", "pointer":
+	default:
+		return fmt.Errorf("backup.layout: unknown layout: %q", cfg.Backup.Layout)
+	}
+
 	return nil
 }
 
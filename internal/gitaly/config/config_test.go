@@ -1095,6 +1095,198 @@ func TestValidateCgroups(t *testing.T) {
 					},
 				},
 			},
+			{
+				name: "pids and io limits",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				pids_limit = 1000
+				[[cgroups.io]]
+				device = "/dev/sda"
+				read_bps = 104857600
+				write_iops = 1000
+				[cgroups.repositories]
+				count = 10
+				pids_limit = 100
+				[[cgroups.repositories.io]]
+				device = "/dev/sda"
+				write_bps = 10485760
+				read_iops = 100
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					PIDsLimit:     1000,
+					IO: []cgroups.IOLimit{
+						{Device: "/dev/sda", ReadBPS: 104857600, WriteIOPS: 1000},
+					},
+					Repositories: cgroups.Repositories{
+						Count:     10,
+						PIDsLimit: 100,
+						IO: []cgroups.IOLimit{
+							{Device: "/dev/sda", WriteBPS: 10485760, ReadIOPS: 100},
+						},
+					},
+				},
+			},
+			{
+				name: "repositories pids limit exceeds parent",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				pids_limit = 100
+				[cgroups.repositories]
+				count = 10
+				pids_limit = 1000
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					PIDsLimit:     100,
+					Repositories: cgroups.Repositories{
+						Count:     10,
+						PIDsLimit: 1000,
+					},
+				},
+				validateErr: errors.New("cgroups.repositories: pids limit cannot exceed parent"),
+			},
+			{
+				name: "io limit without device",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				[cgroups.repositories]
+				count = 10
+				[[cgroups.repositories.io]]
+				read_bps = 1024
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					Repositories: cgroups.Repositories{
+						Count: 10,
+						IO: []cgroups.IOLimit{
+							{ReadBPS: 1024},
+						},
+					},
+				},
+				validateErr: errors.New("cgroups: io limit requires a device"),
+			},
+			{
+				name: "buckets",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				memory_bytes = 2048
+				cpu_shares = 1024
+				[cgroups.repositories]
+				count = 10
+				[[cgroups.buckets]]
+				name = "upload-pack-objects"
+				rpcs = ["PostUploadPackWithSidechannel", "SSHUploadPackWithSidechannel"]
+				git_subcommands = ["pack-objects"]
+				memory_bytes = 1024
+				cpu_shares = 512
+				pids_limit = 100
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					MemoryBytes:   2048,
+					CPUShares:     1024,
+					Repositories: cgroups.Repositories{
+						Count: 10,
+					},
+					Buckets: []cgroups.Bucket{
+						{
+							Name:           "upload-pack-objects",
+							RPCs:           []string{"PostUploadPackWithSidechannel", "SSHUploadPackWithSidechannel"},
+							GitSubcommands: []string{"pack-objects"},
+							MemoryBytes:    1024,
+							CPUShares:      512,
+							PIDsLimit:      100,
+						},
+					},
+				},
+			},
+			{
+				name: "bucket without criteria",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				[[cgroups.buckets]]
+				name = "empty"
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					Buckets: []cgroups.Bucket{
+						{Name: "empty"},
+					},
+				},
+				validateErr: errors.New(`cgroups.buckets: bucket "empty" does not match any commands`),
+			},
+			{
+				name: "bucket with invalid name",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				[[cgroups.buckets]]
+				name = "repos-1"
+				commands = ["git"]
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					Buckets: []cgroups.Bucket{
+						{Name: "repos-1", Commands: []string{"git"}},
+					},
+				},
+				validateErr: errors.New(`cgroups.buckets: invalid bucket name "repos-1"`),
+			},
+			{
+				name: "duplicate bucket names",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				[[cgroups.buckets]]
+				name = "git"
+				commands = ["git"]
+				[[cgroups.buckets]]
+				name = "git"
+				git_subcommands = ["repack"]
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					Buckets: []cgroups.Bucket{
+						{Name: "git", Commands: []string{"git"}},
+						{Name: "git", GitSubcommands: []string{"repack"}},
+					},
+				},
+				validateErr: errors.New(`cgroups.buckets: duplicate bucket name "git"`),
+			},
+			{
+				name: "bucket memory exceeds parent",
+				rawCfg: `[cgroups]
+				mountpoint = "/sys/fs/cgroup"
+				hierarchy_root = "gitaly"
+				memory_bytes = 1024
+				[[cgroups.buckets]]
+				name = "repack"
+				git_subcommands = ["repack"]
+				memory_bytes = 2048
+				`,
+				expect: cgroups.Config{
+					Mountpoint:    "/sys/fs/cgroup",
+					HierarchyRoot: "gitaly",
+					MemoryBytes:   1024,
+					Buckets: []cgroups.Bucket{
+						{Name: "repack", GitSubcommands: []string{"repack"}, MemoryBytes: 2048},
+					},
+				},
+				validateErr: errors.New(`cgroups.buckets: memory limit of bucket "repack" cannot exceed parent`),
+			},
 		}
 		for _, tt := range testCases {
 			t.Run(tt.name, func(t *testing.T) {
@@ -1134,8 +1326,34 @@ enabled = true
 enabled = true
 dir = "/bazqux"
 max_age = "10m"
+warm_on_push = true
 `,
-			out: StreamCacheConfig{Enabled: true, MaxAge: duration.Duration(10 * time.Minute), Dir: "/bazqux"},
+			out: StreamCacheConfig{Enabled: true, MaxAge: duration.Duration(10 * time.Minute), Dir: "/bazqux", WarmOnPush: true},
+		},
+		{
+			desc: "enabled with size limits",
+			in: storageConfig + `[pack_objects_cache]
+enabled = true
+max_bytes = 1073741824
+max_bytes_per_repository = 268435456
+min_free_bytes = 536870912
+`,
+			out: StreamCacheConfig{
+				Enabled:               true,
+				MaxAge:                duration.Duration(5 * time.Minute),
+				Dir:                   "/foobar/+gitaly/PackObjectsCache",
+				MaxBytes:              1073741824,
+				MaxBytesPerRepository: 268435456,
+				MinFreeBytes:          536870912,
+			},
+		},
+		{
+			desc: "enabled with negative size limit",
+			in: storageConfig + `[pack_objects_cache]
+enabled = true
+max_bytes_per_repository = -1
+`,
+			err: errPackObjectsCacheNegativeLimit,
 		},
 		{
 			desc: "enabled with 0 storages",
@@ -1160,6 +1378,48 @@ dir = "foobar"
 `,
 			err: errPackObjectsCacheRelativePath,
 		},
+		{
+			desc: "enabled with shared directory",
+			in: storageConfig + `[pack_objects_cache]
+enabled = true
+shared_dir = "/shared"
+shared_max_bytes = 1048576
+`,
+			out: StreamCacheConfig{
+				Enabled:        true,
+				MaxAge:         duration.Duration(5 * time.Minute),
+				Dir:            "/foobar/+gitaly/PackObjectsCache",
+				SharedDir:      "/shared",
+				SharedMaxBytes: 1048576,
+			},
+		},
+		{
+			desc: "enabled with relative shared directory",
+			in: storageConfig + `[pack_objects_cache]
+enabled = true
+shared_dir = "shared"
+shared_max_bytes = 1048576
+`,
+			err: errPackObjectsCacheSharedRelative,
+		},
+		{
+			desc: "enabled with shared directory equal to storage directory",
+			in: storageConfig + `[pack_objects_cache]
+enabled = true
+dir = "/bazqux"
+shared_dir = "/bazqux/"
+shared_max_bytes = 1048576
+`,
+			err: errPackObjectsCacheSharedSameDir,
+		},
+		{
+			desc: "enabled with shared directory without max bytes",
+			in: storageConfig + `[pack_objects_cache]
+enabled = true
+shared_dir = "/shared"
+`,
+			err: errPackObjectsCacheSharedMaxBytes,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1179,6 +1439,60 @@ dir = "foobar"
 	}
 }
 
+func TestConfigureBackup(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc      string
+		in        string
+		out       BackupConfig
+		expErrMsg string
+	}{
+		{desc: "empty"},
+		{
+			desc: "default layout",
+			in: `[backup]
+go_cloud_url = "s3://bucket"
+`,
+			out: BackupConfig{GoCloudURL: "s3://bucket", Layout: "pointer"},
+		},
+		{
+			desc: "legacy layout",
+			in: `[backup]
+go_cloud_url = "s3://bucket"
+layout = "legacy"
+`,
+			out: BackupConfig{GoCloudURL: "s3://bucket", Layout: "legacy"},
+		},
+		{
+			desc: "unknown layout",
+			in: `[backup]
+go_cloud_url = "s3://bucket"
+layout = "unknown"
+`,
+			expErrMsg: `backup.layout: unknown layout: "unknown"`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := Load(strings.NewReader(tc.in))
+			require.NoError(t, err)
+
+			err = cfg.configureBackup()
+			if tc.expErrMsg != "" {
+				require.EqualError(t, err, tc.expErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.out, cfg.Backup)
+		})
+	}
+}
+
 func TestValidateToken(t *testing.T) {
 	require.NoError(t, (&Cfg{Auth: auth.Config{}}).validateToken())
 	require.NoError(t, (&Cfg{Auth: auth.Config{Token: ""}}).validateToken())
@@ -1361,3 +1675,242 @@ func TestPackObjectsLimiting(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const rpc = "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel"
+
+	testCases := []struct {
+		desc      string
+		cfg       Cfg
+		expErrMsg string
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "static limit",
+			cfg: Cfg{Concurrency: []Concurrency{
+				{RPC: rpc, MaxPerRepo: 10},
+			}},
+		},
+		{
+			desc: "adaptive limit",
+			cfg: Cfg{
+				Concurrency: []Concurrency{
+					{RPC: rpc, MaxPerRepo: 10, Adaptive: true, MinLimit: 2, MaxLimit: 20},
+				},
+				AdaptiveLimiting: AdaptiveLimiting{
+					CalibrationInterval:     duration.Duration(time.Second),
+					BackoffFactor:           0.5,
+					LatencyTolerance:        1.5,
+					CPUPressureThreshold:    50,
+					MemoryPressureThreshold: 10,
+				},
+			},
+		},
+		{
+			desc: "adaptive limit without initial limit",
+			cfg: Cfg{Concurrency: []Concurrency{
+				{RPC: rpc, Adaptive: true, MaxLimit: 20},
+			}},
+			expErrMsg: `concurrency "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel": adaptive limiting requires max_per_repo`,
+		},
+		{
+			desc: "adaptive limit with min limit exceeding initial limit",
+			cfg: Cfg{Concurrency: []Concurrency{
+				{RPC: rpc, MaxPerRepo: 10, Adaptive: true, MinLimit: 11, MaxLimit: 20},
+			}},
+			expErrMsg: `concurrency "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel": min_limit must be between 0 and max_per_repo`,
+		},
+		{
+			desc: "adaptive limit with max limit lower than initial limit",
+			cfg: Cfg{Concurrency: []Concurrency{
+				{RPC: rpc, MaxPerRepo: 10, Adaptive: true},
+			}},
+			expErrMsg: `concurrency "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel": max_limit cannot be lower than max_per_repo`,
+		},
+		{
+			desc:      "invalid backoff factor",
+			cfg:       Cfg{AdaptiveLimiting: AdaptiveLimiting{BackoffFactor: 1}},
+			expErrMsg: "adaptive_limiting: backoff_factor must be in the range (0, 1)",
+		},
+		{
+			desc:      "invalid latency tolerance",
+			cfg:       Cfg{AdaptiveLimiting: AdaptiveLimiting{LatencyTolerance: 0.5}},
+			expErrMsg: "adaptive_limiting: latency_tolerance must be greater than 1",
+		},
+		{
+			desc:      "invalid pressure threshold",
+			cfg:       Cfg{AdaptiveLimiting: AdaptiveLimiting{MemoryPressureThreshold: 101}},
+			expErrMsg: "adaptive_limiting: pressure thresholds must be in the range [0, 100]",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.cfg.validateConcurrency()
+			if tc.expErrMsg != "" {
+				require.EqualError(t, err, tc.expErrMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRateLimiting(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc              string
+		rawCfg            string
+		expectedErrString string
+		expectedCfg       []RateLimiting
+	}{
+		{
+			desc: "without key",
+			rawCfg: `[[rate_limiting]]
+			rpc = "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel"
+			interval = "1m"
+			burst = 5
+			`,
+			expectedCfg: []RateLimiting{
+				{
+					RPC:      "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel",
+					Interval: duration.Duration(time.Minute),
+					Burst:    5,
+				},
+			},
+		},
+		{
+			desc: "with keys and dry run",
+			rawCfg: `[[rate_limiting]]
+			rpc = "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel"
+			interval = "1m"
+			burst = 5
+			key = "user"
+
+			[[rate_limiting]]
+			rpc = "/gitaly.SSHService/SSHUploadPackWithSidechannel"
+			interval = "1s"
+			burst = 1
+			key = "remote_ip"
+			dry_run = true
+			`,
+			expectedCfg: []RateLimiting{
+				{
+					RPC:      "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel",
+					Interval: duration.Duration(time.Minute),
+					Burst:    5,
+					Key:      RateLimitingKeyUser,
+				},
+				{
+					RPC:      "/gitaly.SSHService/SSHUploadPackWithSidechannel",
+					Interval: duration.Duration(time.Second),
+					Burst:    1,
+					Key:      RateLimitingKeyRemoteIP,
+					DryRun:   true,
+				},
+			},
+		},
+		{
+			desc: "invalid key",
+			rawCfg: `[[rate_limiting]]
+			rpc = "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel"
+			key = "namespace"
+			`,
+			expectedErrString: "unsupported rate limiting key: namespace",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := Load(strings.NewReader(tc.rawCfg))
+			if tc.expectedErrString != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrString)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCfg, cfg.RateLimiting)
+		})
+	}
+}
+
+func TestValidateRequestPriority(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc      string
+		priority  RequestPriority
+		expErrMsg string
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "valid classes",
+			priority: RequestPriority{
+				Classes: []PriorityClass{
+					{Name: "interactive", Weight: 4, ClientNames: []string{"gitlab-web"}},
+					{Name: "default", Weight: 2},
+					{Name: "ci", Weight: 1},
+				},
+				MaxStarvation: duration.Duration(5 * time.Second),
+			},
+		},
+		{
+			desc:      "negative max starvation",
+			priority:  RequestPriority{MaxStarvation: duration.Duration(-1)},
+			expErrMsg: "request_priority: max_starvation cannot be negative",
+		},
+		{
+			desc: "class without name",
+			priority: RequestPriority{Classes: []PriorityClass{
+				{Weight: 1},
+			}},
+			expErrMsg: "request_priority: class name cannot be empty",
+		},
+		{
+			desc: "duplicate class",
+			priority: RequestPriority{Classes: []PriorityClass{
+				{Name: "ci", Weight: 1},
+				{Name: "ci", Weight: 2},
+			}},
+			expErrMsg: `request_priority: duplicate class "ci"`,
+		},
+		{
+			desc: "class without weight",
+			priority: RequestPriority{Classes: []PriorityClass{
+				{Name: "ci"},
+			}},
+			expErrMsg: `request_priority: class "ci" must have a positive weight`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Cfg{RequestPriority: tc.priority}
+
+			err := cfg.validateRequestPriority()
+			if tc.expErrMsg != "" {
+				require.EqualError(t, err, tc.expErrMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
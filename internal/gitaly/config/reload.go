@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
+)
+
+// ValidateReload checks whether Gitaly can switch from this configuration to newCfg without being
+// restarted. Limits for concurrency, request priorities, rate limiting, pack-objects limiting and cgroups can be
+// reloaded, all other settings require a restart. An error listing the settings which cannot be
+// applied at runtime is returned otherwise.
+func (cfg Cfg) ValidateReload(newCfg Cfg) error {
+	if cfg.PackObjectsLimiting.Key != newCfg.PackObjectsLimiting.Key {
+		return errors.New("pack_objects_limiting.key cannot be changed at runtime")
+	}
+
+	if err := cfg.Cgroups.ValidateReload(newCfg.Cgroups); err != nil {
+		return fmt.Errorf("cgroups: %w", err)
+	}
+
+	// Reset all settings which can be reloaded so that we can compare the remaining ones.
+	for _, c := range []*Cfg{&cfg, &newCfg} {
+		c.Concurrency = nil
+		c.AdaptiveLimiting = AdaptiveLimiting{}
+		c.RequestPriority = RequestPriority{}
+		c.RateLimiting = nil
+		c.PackObjectsLimiting = PackObjectsLimiting{}
+		c.Cgroups = cgroups.Config{}
+	}
+
+	oldValue, newValue := reflect.ValueOf(cfg), reflect.ValueOf(newCfg)
+
+	var changed []string
+	for i := 0; i < oldValue.NumField(); i++ {
+		if reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			continue
+		}
+
+		field := oldValue.Type().Field(i)
+		name := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		changed = append(changed, name)
+	}
+
+	if len(changed) > 0 {
+		return fmt.Errorf("settings cannot be changed at runtime: %s", strings.Join(changed, ", "))
+	}
+
+	return nil
+}
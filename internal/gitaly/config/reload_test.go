@@ -0,0 +1,100 @@
+//go:build !gitaly_test_sha256
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/cgroups"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/duration"
+)
+
+func TestCfg_ValidateReload(t *testing.T) {
+	t.Parallel()
+
+	baseCfg := func() Cfg {
+		return Cfg{
+			SocketPath: "/tmp/gitaly.socket",
+			Storages:   []Storage{{Name: "default", Path: "/repositories"}},
+			Concurrency: []Concurrency{
+				{RPC: "/gitaly.RepositoryService/GarbageCollect", MaxPerRepo: 1},
+			},
+			PackObjectsLimiting: PackObjectsLimiting{
+				Key:            PackObjectsLimitingKeyRepository,
+				MaxConcurrency: 10,
+			},
+			Cgroups: cgroups.Config{
+				Mountpoint:   "/sys/fs/cgroup",
+				Repositories: cgroups.Repositories{Count: 10, MemoryBytes: 1024},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		desc        string
+		modify      func(cfg *Cfg)
+		expectedErr string
+	}{
+		{
+			desc:   "unchanged",
+			modify: func(cfg *Cfg) {},
+		},
+		{
+			desc: "changed limits",
+			modify: func(cfg *Cfg) {
+				cfg.Concurrency[0].MaxPerRepo = 2
+				cfg.Concurrency = append(cfg.Concurrency, Concurrency{
+					RPC: "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel", MaxPerRepo: 10,
+				})
+				cfg.AdaptiveLimiting.BackoffFactor = 0.5
+				cfg.RateLimiting = []RateLimiting{
+					{RPC: "/gitaly.SmartHTTPService/PostUploadPackWithSidechannel", Interval: duration.Duration(time.Minute), Burst: 5},
+				}
+				cfg.PackObjectsLimiting.MaxConcurrency = 20
+				cfg.PackObjectsLimiting.MaxQueueWait = duration.Duration(time.Minute)
+				cfg.Cgroups.Repositories.MemoryBytes = 2048
+			},
+		},
+		{
+			desc: "changed pack objects limiting key",
+			modify: func(cfg *Cfg) {
+				cfg.PackObjectsLimiting.Key = PackObjectsLimitingKeyUser
+			},
+			expectedErr: "pack_objects_limiting.key cannot be changed at runtime",
+		},
+		{
+			desc: "changed cgroup hierarchy",
+			modify: func(cfg *Cfg) {
+				cfg.Cgroups.Repositories.Count = 5
+			},
+			expectedErr: "cgroups: repositories.count cannot be changed at runtime",
+		},
+		{
+			desc: "changed other settings",
+			modify: func(cfg *Cfg) {
+				cfg.SocketPath = "/tmp/other.socket"
+				cfg.Storages = append(cfg.Storages, Storage{Name: "other", Path: "/other"})
+				cfg.Concurrency[0].MaxPerRepo = 2
+			},
+			expectedErr: "settings cannot be changed at runtime: socket_path, storage",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			newCfg := baseCfg()
+			tc.modify(&newCfg)
+
+			err := baseCfg().ValidateReload(newCfg)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -41,6 +41,7 @@ func (d *Diff) Reset() {
 
 // Parser holds necessary state for parsing a diff stream
 type Parser struct {
+	objectHash        git.ObjectHash
 	limits            Limits
 	patchReader       *bufio.Reader
 	rawLines          [][]byte
@@ -95,15 +96,16 @@ const (
 )
 
 var (
-	rawLineRegexp    = regexp.MustCompile(`(?m)^:(\d+) (\d+) ([[:xdigit:]]{40}) ([[:xdigit:]]{40}) ([ADTUXMRC]\d*)\t(.*?)(?:\t(.*?))?$`)
+	rawLineRegexp    = regexp.MustCompile(`(?m)^:(\d+) (\d+) ([[:xdigit:]]{40}|[[:xdigit:]]{64}) ([[:xdigit:]]{40}|[[:xdigit:]]{64}) ([ADTUXMRC]\d*)\t(.*?)(?:\t(.*?))?$`)
 	diffHeaderRegexp = regexp.MustCompile(`(?m)^diff --git "?a/(.*?)"? "?b/(.*?)"?$`)
 )
 
-// NewDiffParser returns a new Parser
-func NewDiffParser(src io.Reader, limits Limits) *Parser {
+// NewDiffParser returns a new Parser. The object hash must be the one of the repository the diff
+// has been generated in.
+func NewDiffParser(objectHash git.ObjectHash, src io.Reader, limits Limits) *Parser {
 	limits.enforceUpperBound()
 
-	parser := &Parser{}
+	parser := &Parser{objectHash: objectHash}
 	reader := bufio.NewReader(src)
 
 	parser.cacheRawLines(reader)
@@ -324,17 +326,19 @@ func (parser *Parser) handleTypeChangeDiff() {
 	// GitLab wants to display the type change in the current diff as a removal followed by an addition.
 	// To make this happen we add a new raw line, which will become the addition on the next iteration of the parser.
 	// We change the current diff in-place so that it becomes a deletion.
+	zeroOID := parser.objectHash.ZeroOID
+
 	newRawLine := fmt.Sprintf(
 		":%o %o %s %s A\t%s\n",
 		0,
 		parser.currentDiff.NewMode,
-		git.ObjectHashSHA1.ZeroOID,
+		zeroOID,
 		parser.currentDiff.ToID,
 		parser.currentDiff.FromPath,
 	)
 
 	parser.currentDiff.NewMode = 0
-	parser.currentDiff.ToID = git.ObjectHashSHA1.ZeroOID.String()
+	parser.currentDiff.ToID = zeroOID.String()
 
 	parser.rawLines = append([][]byte{[]byte(newRawLine)}, parser.rawLines...)
 }
@@ -1,5 +1,3 @@
-//go:build !gitaly_test_sha256
-
 package diff
 
 import (
@@ -43,7 +41,7 @@ index 0000000000000000000000000000000000000000..3be11c69355948412925fa5e073d76d5
 		MaxPatchBytes: 100000,
 		CollapseDiffs: true,
 	}
-	diffs := getDiffs(t, rawDiff, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, rawDiff, limits)
 
 	expectedDiffs := []*Diff{
 		{
@@ -98,7 +96,7 @@ index 3be11c69355948412925fa5e073d76d58ff3afd2..20c4507e1acc7c7ddfd1fc995cfaf4c8
 		CollapseDiffs: false,
 	}
 
-	diffs := getDiffs(t, rawDiff, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, rawDiff, limits)
 	expectedDiffs := []*Diff{
 		{
 			OldMode:   0o100644,
@@ -152,7 +150,7 @@ index 3be11c69355948412925fa5e073d76d58ff3afd2..20c4507e1acc7c7ddfd1fc995cfaf4c8
 		CollapseDiffs: false,
 	}
 
-	diffs := getDiffs(t, rawDiff, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, rawDiff, limits)
 	expectedDiffs := []*Diff{
 		{
 			OldMode:   0o100644,
@@ -210,7 +208,7 @@ index 000000000..3a62d28e3
 		MaxPatchBytes: 100000,
 		CollapseDiffs: false,
 	}
-	diffs := getDiffs(t, rawDiff, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, rawDiff, limits)
 
 	expectedDiffs := []*Diff{
 		{
@@ -262,7 +260,7 @@ index 0000000000000000000000000000000000000000..3be11c69355948412925fa5e073d76d5
 		CollapseDiffs: false,
 	}
 
-	diffs := getDiffs(t, rawDiff, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, rawDiff, limits)
 
 	expectedDiffs := []*Diff{
 		{
@@ -325,7 +323,7 @@ index 0000000000000000000000000000000000000000..3be11c69355948412925fa5e073d76d5
 		CollapseDiffs: false,
 	}
 
-	diffs := getDiffs(t, rawDiff, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, rawDiff, limits)
 
 	expectedDiffs := []*Diff{
 		{
@@ -388,7 +386,7 @@ index 0000000000000000000000000000000000000000..b6507e5b5ce18077e3ec8aaa2291404e
 		MaxPatchBytes: 100000,
 		CollapseDiffs: true,
 	}
-	diffs := getDiffs(t, rawDiff, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, rawDiff, limits)
 
 	expectedDiffs := []*Diff{
 		{
@@ -446,7 +444,7 @@ index 0000000000000000000000000000000000000000..c3ae147b03a2d1fd89b25198b3fc5302
 	limits := Limits{
 		MaxPatchBytes: 1000 * 1000,
 	}
-	diffs := getDiffs(t, header+patch, limits)
+	diffs := getDiffs(t, git.ObjectHashSHA1, header+patch, limits)
 
 	expectedDiffs := []*Diff{
 		{
@@ -466,6 +464,77 @@ index 0000000000000000000000000000000000000000..c3ae147b03a2d1fd89b25198b3fc5302
 	require.Equal(t, expectedDiffs, diffs)
 }
 
+func TestDiffParserWithTypeChange(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		objectHash git.ObjectHash
+		oldID      string
+		newID      string
+	}{
+		{
+			desc:       "SHA1",
+			objectHash: git.ObjectHashSHA1,
+			oldID:      "d670460b4b4aece5915caf5c68d12f560a9fe3e4",
+			newID:      "eb1e3ae3ec8f2af4f1a4a9d5dc8f5e4c3a5d2f3a",
+		},
+		{
+			desc:       "SHA256",
+			objectHash: git.ObjectHashSHA256,
+			oldID:      "2bd3a5a7ce1a1b9ba6dfdcbc96c5a6e1ffb2e44ff9b0d6b7e3c1ba4e3f0b49fd",
+			newID:      "5b4e1a3ac2b57fc4bd8a7c3e92a8c7d8c2f97b8d2ad7c3a1c6b8b53e94a1d0c7",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			zeroOID := tc.objectHash.ZeroOID.String()
+
+			rawDiff := fmt.Sprintf(`:100644 120000 %[1]s %[2]s T	file
+
+diff --git a/file b/file
+deleted file mode 100644
+index %[1]s..%[3]s
+--- a/file
++++ /dev/null
+@@ -1 +0,0 @@
+-content
+diff --git a/file b/file
+new file mode 120000
+index %[3]s..%[2]s
+--- /dev/null
++++ b/file
+@@ -0,0 +1 @@
++target
+`, tc.oldID, tc.newID, zeroOID)
+
+			diffs := getDiffs(t, tc.objectHash, rawDiff, Limits{})
+
+			require.Equal(t, []*Diff{
+				{
+					OldMode:   0o100644,
+					NewMode:   0,
+					FromID:    tc.oldID,
+					ToID:      zeroOID,
+					FromPath:  []byte("file"),
+					ToPath:    []byte("file"),
+					Status:    'T',
+					Patch:     []byte("@@ -1 +0,0 @@\n-content\n"),
+					lineCount: 1,
+				},
+				{
+					OldMode:   0,
+					NewMode:   0o120000,
+					FromID:    zeroOID,
+					ToID:      tc.newID,
+					FromPath:  []byte("file"),
+					ToPath:    []byte("file"),
+					Status:    'A',
+					Patch:     []byte("@@ -0,0 +1 @@\n+target\n"),
+					lineCount: 1,
+				},
+			}, diffs)
+		})
+	}
+}
+
 func TestDiffLimitsBeingEnforcedByUpperBound(t *testing.T) {
 	limits := Limits{
 		SafeMaxLines:  999999999,
@@ -476,7 +545,7 @@ func TestDiffLimitsBeingEnforcedByUpperBound(t *testing.T) {
 		MaxLines:      0,
 		MaxPatchBytes: 0,
 	}
-	diffParser := NewDiffParser(strings.NewReader(""), limits)
+	diffParser := NewDiffParser(git.ObjectHashSHA1, strings.NewReader(""), limits)
 
 	require.Equal(t, diffParser.limits.SafeMaxBytes, safeMaxBytesUpperBound)
 	require.Equal(t, diffParser.limits.SafeMaxFiles, safeMaxFilesUpperBound)
@@ -487,10 +556,10 @@ func TestDiffLimitsBeingEnforcedByUpperBound(t *testing.T) {
 	require.Equal(t, diffParser.limits.MaxPatchBytes, 0)
 }
 
-func getDiffs(tb testing.TB, rawDiff string, limits Limits) []*Diff {
+func getDiffs(tb testing.TB, objectHash git.ObjectHash, rawDiff string, limits Limits) []*Diff {
 	tb.Helper()
 
-	diffParser := NewDiffParser(strings.NewReader(rawDiff), limits)
+	diffParser := NewDiffParser(objectHash, strings.NewReader(rawDiff), limits)
 
 	diffs := []*Diff{}
 	for diffParser.Parse() {
@@ -532,7 +601,7 @@ index 0000000000000000000000000000000000000000..c3ae147b03a2d1fd89b25198b3fc5302
 
 	b.Run("parse", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			parser := NewDiffParser(bytes.NewReader(diffData.Bytes()), Limits{})
+			parser := NewDiffParser(git.ObjectHashSHA1, bytes.NewReader(diffData.Bytes()), Limits{})
 			n := 0
 			for parser.Parse() {
 				n++
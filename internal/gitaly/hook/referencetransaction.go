@@ -12,9 +12,12 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/transaction/voting"
 )
 
-// forceDeletionPrefix is the prefix of a queued reference transaction which deletes a
-// reference without checking its current value.
-var forceDeletionPrefix = fmt.Sprintf("%[1]s %[1]s ", git.ObjectHashSHA1.ZeroOID.String())
+// forceDeletionPrefixes are the prefixes of a queued reference transaction which deletes a
+// reference without checking its current value, one for each object hash.
+var forceDeletionPrefixes = [][]byte{
+	[]byte(fmt.Sprintf("%[1]s %[1]s ", git.ObjectHashSHA1.ZeroOID.String())),
+	[]byte(fmt.Sprintf("%[1]s %[1]s ", git.ObjectHashSHA256.ZeroOID.String())),
+}
 
 //nolint:revive // This is unintentionally missing documentation.
 func (m *GitLabHookManager) ReferenceTransactionHook(ctx context.Context, state ReferenceTransactionState, env []string, stdin io.Reader) error {
@@ -82,12 +85,20 @@ func isForceDeletionsOnly(changes io.Reader) bool {
 	for scanner.Scan() {
 		line := scanner.Bytes()
 
-		if bytes.HasPrefix(line, []byte(forceDeletionPrefix)) {
-			continue
+		if !hasForceDeletionPrefix(line) {
+			return false
 		}
-
-		return false
 	}
 
 	return true
 }
+
+func hasForceDeletionPrefix(line []byte) bool {
+	for _, prefix := range forceDeletionPrefixes {
+		if bytes.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -164,6 +164,10 @@ func TestIsForceDeletionsOnly(t *testing.T) {
 	forceUpdate := fmt.Sprintf("%s %s refs/heads/force-update", zeroOID, anyOID)
 	deletion := fmt.Sprintf("%s %s refs/heads/delete", anyOID, zeroOID)
 
+	sha256ZeroOID := git.ObjectHashSHA256.ZeroOID.String()
+	sha256ForceDeletion := fmt.Sprintf("%s %s refs/heads/force-delete", sha256ZeroOID, sha256ZeroOID)
+	sha256ForceUpdate := fmt.Sprintf("%s %s refs/heads/force-update", sha256ZeroOID, strings.Repeat("1", 64))
+
 	for _, tc := range []struct {
 		desc     string
 		changes  string
@@ -199,6 +203,16 @@ func TestIsForceDeletionsOnly(t *testing.T) {
 			changes:  strings.Join([]string{forceDeletion, forceUpdate}, "\n"),
 			expected: false,
 		},
+		{
+			desc:     "SHA256 force deletions",
+			changes:  strings.Join([]string{sha256ForceDeletion, sha256ForceDeletion}, "\n"),
+			expected: true,
+		},
+		{
+			desc:     "SHA256 force update",
+			changes:  sha256ForceUpdate + "\n",
+			expected: false,
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			actual := isForceDeletionsOnly(strings.NewReader(tc.changes))
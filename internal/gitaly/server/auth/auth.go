@@ -32,6 +32,15 @@ func UnaryServerInterceptor(conf gitalycfgauth.Config) grpc.UnaryServerIntercept
 	return grpcmwauth.UnaryServerInterceptor(checkFunc(conf))
 }
 
+type authenticatedKey struct{}
+
+// IsAuthenticated returns whether the request has been authenticated with a valid token. Requests
+// are never authenticated if authentication has been disabled.
+func IsAuthenticated(ctx context.Context) bool {
+	authenticated, _ := ctx.Value(authenticatedKey{}).(bool)
+	return authenticated
+}
+
 func checkFunc(conf gitalycfgauth.Config) func(ctx context.Context) (context.Context, error) {
 	return func(ctx context.Context) (context.Context, error) {
 		if len(conf.Token) == 0 {
@@ -43,6 +52,7 @@ func checkFunc(conf gitalycfgauth.Config) func(ctx context.Context) (context.Con
 		switch status.Code(err) {
 		case codes.OK:
 			countStatus(okLabel(conf.Transitioning), conf.Transitioning).Inc()
+			ctx = context.WithValue(ctx, authenticatedKey{}, true)
 		case codes.Unauthenticated:
 			countStatus("unauthenticated", conf.Transitioning).Inc()
 		case codes.PermissionDenied:
@@ -50,6 +50,8 @@ func setupWithoutRepo(tb testing.TB, ctx context.Context) (config.Cfg, gitalypb.
 			deps.GetConnsPool(),
 			deps.GetGit2goExecutor(),
 			deps.GetHousekeepingManager(),
+			deps.GetBackupSink(),
+			deps.GetBackupLocator(),
 		))
 	})
 	cfg.SocketPath = addr
@@ -56,6 +56,8 @@ func runCleanupServiceServer(t *testing.T, cfg config.Cfg) string {
 			deps.GetConnsPool(),
 			deps.GetGit2goExecutor(),
 			deps.GetHousekeepingManager(),
+			deps.GetBackupSink(),
+			deps.GetBackupLocator(),
 		))
 	})
 }
@@ -22,6 +22,21 @@ func (s *server) GetCommitSignatures(request *gitalypb.GetCommitSignaturesReques
 		return helper.ErrInvalidArgumentf("GetCommitSignatures: %w", err)
 	}
 
+	ctx := stream.Context()
+	repo := s.localrepo(request.GetRepository())
+
+	objectHash, err := repo.ObjectHash(ctx)
+	if err != nil {
+		return helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	// Do not support shorthand or invalid commit SHAs
+	for _, commitID := range request.CommitIds {
+		if err := objectHash.ValidateHex(commitID); err != nil {
+			return helper.ErrInvalidArgumentf("GetCommitSignatures: %w", err)
+		}
+	}
+
 	return s.getCommitSignatures(request, stream)
 }
 
@@ -135,12 +150,5 @@ func validateGetCommitSignaturesRequest(request *gitalypb.GetCommitSignaturesReq
 		return errors.New("empty CommitIds")
 	}
 
-	// Do not support shorthand or invalid commit SHAs
-	for _, commitID := range request.CommitIds {
-		if err := git.ObjectHashSHA1.ValidateHex(commitID); err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
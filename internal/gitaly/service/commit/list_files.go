@@ -51,7 +51,12 @@ func (s *server) ListFiles(in *gitalypb.ListFilesRequest, stream gitalypb.Commit
 		return stream.Send(&gitalypb.ListFilesResponse{})
 	}
 
-	if err := s.listFiles(repo, revision, stream); err != nil {
+	objectHash, err := repo.ObjectHash(stream.Context())
+	if err != nil {
+		return helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	if err := s.listFiles(repo, objectHash, revision, stream); err != nil {
 		return helper.ErrInternal(err)
 	}
 
@@ -68,7 +73,7 @@ func validateListFilesRequest(in *gitalypb.ListFilesRequest) error {
 	return nil
 }
 
-func (s *server) listFiles(repo git.RepositoryExecutor, revision string, stream gitalypb.CommitService_ListFilesServer) error {
+func (s *server) listFiles(repo git.RepositoryExecutor, objectHash git.ObjectHash, revision string, stream gitalypb.CommitService_ListFilesServer) error {
 	cmd, err := repo.Exec(stream.Context(), git.SubCmd{
 		Name: "ls-tree",
 		Flags: []git.Option{
@@ -85,7 +90,7 @@ func (s *server) listFiles(repo git.RepositoryExecutor, revision string, stream
 
 	sender := chunk.New(&listFilesSender{stream: stream})
 
-	for parser := lstree.NewParser(cmd, git.ObjectHashSHA1); ; {
+	for parser := lstree.NewParser(cmd, objectHash); ; {
 		entry, err := parser.NextEntry()
 		if err == io.EOF {
 			break
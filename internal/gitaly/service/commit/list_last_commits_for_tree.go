@@ -114,6 +114,11 @@ func (s *server) newLSTreeParser(in *gitalypb.ListLastCommitsForTreeRequest, str
 		path = "."
 	}
 
+	objectHash, err := s.localrepo(in.GetRepository()).ObjectHash(stream.Context())
+	if err != nil {
+		return nil, nil, fmt.Errorf("detecting object hash: %w", err)
+	}
+
 	opts := git.ConvertGlobalOptions(in.GetGlobalOptions())
 	cmd, err := s.gitCmdFactory.New(stream.Context(), in.GetRepository(), git.SubCmd{
 		Name:  "ls-tree",
@@ -124,7 +129,7 @@ func (s *server) newLSTreeParser(in *gitalypb.ListLastCommitsForTreeRequest, str
 		return nil, nil, err
 	}
 
-	return cmd, lstree.NewParser(cmd, git.ObjectHashSHA1), nil
+	return cmd, lstree.NewParser(cmd, objectHash), nil
 }
 
 func sendCommitsForTree(batch []*gitalypb.ListLastCommitsForTreeResponse_CommitForTree, stream gitalypb.CommitService_ListLastCommitsForTreeServer) error {
@@ -50,7 +50,12 @@ func (s *server) commitStats(ctx context.Context, in *gitalypb.CommitStatsReques
 	var args []string
 
 	if len(commit.GetParentIds()) == 0 {
-		args = append(args, git.ObjectHashSHA1.EmptyTreeOID.String(), commit.Id)
+		objectHash, err := repo.ObjectHash(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("detecting object hash: %w", err)
+		}
+
+		args = append(args, objectHash.EmptyTreeOID.String(), commit.Id)
 	} else {
 		args = append(args, commit.Id+"^", commit.Id)
 	}
@@ -169,8 +169,13 @@ func (s *server) resolveConflicts(header *gitalypb.ResolveConflictsRequestHeader
 		authorDate = header.Timestamp.AsTime()
 	}
 
-	if git.ObjectHashSHA1.ValidateHex(header.GetOurCommitOid()) != nil ||
-		git.ObjectHashSHA1.ValidateHex(header.GetTheirCommitOid()) != nil {
+	objectHash, err := quarantineRepo.ObjectHash(ctx)
+	if err != nil {
+		return fmt.Errorf("detecting object hash: %w", err)
+	}
+
+	if objectHash.ValidateHex(header.GetOurCommitOid()) != nil ||
+		objectHash.ValidateHex(header.GetTheirCommitOid()) != nil {
 		return errors.New("Rugged::InvalidError: unable to parse OID - contains invalid characters")
 	}
 
@@ -193,7 +198,7 @@ func (s *server) resolveConflicts(header *gitalypb.ResolveConflictsRequestHeader
 		return err
 	}
 
-	commitOID, err := git.ObjectHashSHA1.FromHex(result.CommitID)
+	commitOID, err := objectHash.FromHex(result.CommitID)
 	if err != nil {
 		return err
 	}
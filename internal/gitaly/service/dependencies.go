@@ -3,6 +3,7 @@ package service
 import (
 	"gitlab.com/gitlab-org/gitaly/v15/client"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/backchannel"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/cache"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/catfile"
@@ -39,6 +40,8 @@ type Dependencies struct {
 	Git2goExecutor                *git2go.Executor
 	UpdaterWithHooks              *updateref.UpdaterWithHooks
 	HousekeepingManager           housekeeping.Manager
+	BackupSink                    backup.Sink
+	BackupLocator                 backup.Locator
 }
 
 // GetCfg returns service configuration.
@@ -130,3 +133,13 @@ func (dc *Dependencies) GetHousekeepingManager() housekeeping.Manager {
 func (dc *Dependencies) GetPackObjectsLimiter() limithandler.Limiter {
 	return dc.PackObjectsLimiter
 }
+
+// GetBackupSink returns the backup.Sink
+func (dc *Dependencies) GetBackupSink() backup.Sink {
+	return dc.BackupSink
+}
+
+// GetBackupLocator returns the backup.Locator
+func (dc *Dependencies) GetBackupLocator() backup.Locator {
+	return dc.BackupLocator
+}
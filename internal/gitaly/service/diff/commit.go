@@ -42,7 +42,7 @@ func (s *server) CommitDiff(in *gitalypb.CommitDiffRequest, stream gitalypb.Diff
 		Flags: []git.Option{
 			git.Flag{Name: "--patch"},
 			git.Flag{Name: "--raw"},
-			git.Flag{Name: "--abbrev=40"},
+			git.Flag{Name: "--no-abbrev"},
 			git.Flag{Name: "--full-index"},
 			git.Flag{Name: "--find-renames=30%"},
 		},
@@ -140,7 +140,7 @@ func (s *server) CommitDelta(in *gitalypb.CommitDeltaRequest, stream gitalypb.Di
 		Name: "diff",
 		Flags: []git.Option{
 			git.Flag{Name: "--raw"},
-			git.Flag{Name: "--abbrev=40"},
+			git.Flag{Name: "--no-abbrev"},
 			git.Flag{Name: "--full-index"},
 			git.Flag{Name: "--find-renames"},
 		},
@@ -213,6 +213,11 @@ func validateRequest(in requestWithLeftRightCommitIds) error {
 }
 
 func (s *server) eachDiff(ctx context.Context, rpc string, repo *gitalypb.Repository, subCmd git.Cmd, limits diff.Limits, callback func(*diff.Diff) error) error {
+	objectHash, err := s.localrepo(repo).ObjectHash(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "%s: detecting object hash: %v", rpc, err)
+	}
+
 	diffConfig := git.ConfigPair{Key: "diff.noprefix", Value: "false"}
 
 	cmd, err := s.gitCmdFactory.New(ctx, repo, subCmd, git.WithConfig(diffConfig))
@@ -223,7 +228,7 @@ func (s *server) eachDiff(ctx context.Context, rpc string, repo *gitalypb.Reposi
 		return status.Errorf(codes.Internal, "%s: cmd: %v", rpc, err)
 	}
 
-	diffParser := diff.NewDiffParser(cmd, limits)
+	diffParser := diff.NewDiffParser(objectHash, cmd, limits)
 
 	for diffParser.Parse() {
 		if err := callback(diffParser.Diff()); err != nil {
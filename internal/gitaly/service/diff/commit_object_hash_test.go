@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func TestCommitDiff_typeChangeObjectHash(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+	cfg, client := setupDiffServiceWithoutRepo(t)
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+
+	regularBlob := gittest.WriteBlob(t, cfg, repoPath, []byte("content\n"))
+	symlinkBlob := gittest.WriteBlob(t, cfg, repoPath, []byte("target"))
+
+	leftCommit := gittest.WriteCommit(t, cfg, repoPath, gittest.WithTreeEntries(
+		gittest.TreeEntry{Path: "file", Mode: "100644", OID: regularBlob},
+	))
+	rightCommit := gittest.WriteCommit(t, cfg, repoPath, gittest.WithParents(leftCommit), gittest.WithTreeEntries(
+		gittest.TreeEntry{Path: "file", Mode: "120000", OID: symlinkBlob},
+	))
+
+	stream, err := client.CommitDiff(ctx, &gitalypb.CommitDiffRequest{
+		Repository:    repo,
+		LeftCommitId:  leftCommit.String(),
+		RightCommitId: rightCommit.String(),
+	})
+	require.NoError(t, err)
+
+	var diffs []*gitalypb.CommitDiffResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		diffs = append(diffs, resp)
+	}
+
+	// The type change is split into a deletion and an addition, which both use the zero OID of
+	// the repository's object hash.
+	zeroOID := gittest.DefaultObjectHash.ZeroOID.String()
+	testhelper.ProtoEqual(t, []*gitalypb.CommitDiffResponse{
+		{
+			FromPath:     []byte("file"),
+			ToPath:       []byte("file"),
+			FromId:       regularBlob.String(),
+			ToId:         zeroOID,
+			OldMode:      0o100644,
+			RawPatchData: []byte("@@ -1 +0,0 @@\n-content\n"),
+			EndOfPatch:   true,
+		},
+		{
+			FromPath:     []byte("file"),
+			ToPath:       []byte("file"),
+			FromId:       zeroOID,
+			ToId:         symlinkBlob.String(),
+			NewMode:      0o120000,
+			RawPatchData: []byte("@@ -0,0 +1 @@\n+target\n\\ No newline at end of file\n"),
+			EndOfPatch:   true,
+		},
+	}, diffs)
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash"
@@ -26,7 +27,6 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/metadata/featureflag"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/stream"
 	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
 var (
@@ -44,8 +44,26 @@ var (
 	})
 )
 
+// packObjectsCacheKey is hashed together with stdin to compute the cache key
+// of a git-pack-objects invocation.
+type packObjectsCacheKey struct {
+	RelativePath string   `json:"relative_path"`
+	Args         []string `json:"args"`
+}
+
 func (s *server) packObjectsHook(ctx context.Context, req *gitalypb.PackObjectsHookWithSidechannelRequest, args *packObjectsArgs, stdinReader io.Reader, output io.Writer) error {
-	data, err := protojson.Marshal(req)
+	// The cache key only covers what determines the output of
+	// git-pack-objects: the relative path of the repository, the arguments
+	// and stdin. The user details are left out so that clones of different
+	// users share cache entries, which is also what makes it possible to
+	// warm the cache after a push. The storage name is left out so that the
+	// replicas of a repository, which share their relative path but live on
+	// storages of different names, find each other's entries in the shared
+	// cache tier.
+	data, err := json.Marshal(packObjectsCacheKey{
+		RelativePath: req.GetRepository().GetRelativePath(),
+		Args:         req.GetArgs(),
+	})
 	if err != nil {
 		return err
 	}
@@ -74,12 +92,13 @@ func (s *server) packObjectsHook(ctx context.Context, req *gitalypb.PackObjectsH
 
 	key := hex.EncodeToString(h.Sum(nil))
 
-	r, created, err := s.packObjectsCache.FindOrCreate(key, func(w io.Writer) error {
+	repo := req.GetRepository().GetStorageName() + ":" + req.GetRepository().GetRelativePath()
+	r, created, err := s.packObjectsCache.FindOrCreate(key, repo, func(w io.Writer) error {
 		if featureflag.PackObjectsLimitingRepo.IsEnabled(ctx) {
 			return s.runPackObjectsLimited(
 				ctx,
 				w,
-				req.GetRepository().GetStorageName()+":"+req.GetRepository().GetRelativePath(),
+				repo,
 				req,
 				args,
 				stdin,
@@ -5,12 +5,16 @@ package hook
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
@@ -293,6 +297,117 @@ func testServerPackObjectsHookUsesCache(t *testing.T, ctx context.Context, runti
 	}
 }
 
+func TestServer_PackObjectsHook_sharedTierAcrossStorages(t *testing.T) {
+	t.Parallel()
+
+	testhelper.NewFeatureSets(
+		featureflag.PackObjectsLimitingUser,
+		featureflag.PackObjectsLimitingRepo,
+	).Run(t, testServerPackObjectsHookSharedTierAcrossStorages)
+}
+
+func testServerPackObjectsHookSharedTierAcrossStorages(t *testing.T, ctx context.Context) {
+	sharedDir := testhelper.TempDir(t)
+
+	// The replicas of a repository live on storages with different names, but share
+	// their relative path.
+	newCfg := func(storageName string) config.Cfg {
+		cfg := testcfg.Build(t, testcfg.WithStorages(storageName))
+		cfg.PackObjectsCache.Enabled = true
+		cfg.PackObjectsCache.Dir = testhelper.TempDir(t)
+		cfg.PackObjectsCache.SharedDir = sharedDir
+		cfg.PackObjectsCache.SharedMaxBytes = 1 << 30
+		return cfg
+	}
+
+	const relativePath = "@cluster/repositories/replica.git"
+
+	cfgA := newCfg("gitaly-1")
+	cfgA.SocketPath = runHooksServer(t, cfgA, nil)
+	repoA, repoPathA := gittest.CreateRepository(t, ctx, cfgA, gittest.CreateRepositoryConfig{
+		RelativePath: relativePath,
+	})
+	commitID := gittest.WriteCommit(t, cfgA, repoPathA, gittest.WithBranch("main"))
+
+	// The second node must serve the pack from the shared tier without running
+	// git-pack-objects.
+	cfgB := newCfg("gitaly-2")
+	cfgB.SocketPath = runHooksServer(t, cfgB, []serverOption{withRunPackObjectsFn(func(
+		context.Context,
+		git.CommandFactory,
+		io.Writer,
+		*gitalypb.PackObjectsHookWithSidechannelRequest,
+		*packObjectsArgs,
+		io.Reader,
+		string,
+		*hookPkg.ConcurrencyTracker,
+	) error {
+		return errors.New("pack-objects should not run on a shared tier hit")
+	})})
+	repoB, _ := gittest.CreateRepository(t, ctx, cfgB, gittest.CreateRepositoryConfig{
+		RelativePath: relativePath,
+	})
+
+	doRequest := func(cfg config.Cfg, repo *gitalypb.Repository) []byte {
+		var stdout []byte
+		ctx, wt, err := hookPkg.SetupSidechannel(
+			ctx,
+			git.HooksPayload{
+				RuntimeDir: testhelper.TempDir(t),
+			},
+			func(c *net.UnixConn) error {
+				if _, err := io.WriteString(c, commitID.String()+"\n--not\n\n"); err != nil {
+					return err
+				}
+				if err := c.CloseWrite(); err != nil {
+					return err
+				}
+
+				return pktline.EachSidebandPacket(c, func(band byte, data []byte) error {
+					if band == 1 {
+						stdout = append(stdout, data...)
+					}
+					return nil
+				})
+			},
+		)
+		require.NoError(t, err)
+		defer testhelper.MustClose(t, wt)
+
+		client, conn := newHooksClient(t, cfg.SocketPath)
+		defer conn.Close()
+
+		_, err = client.PackObjectsHookWithSidechannel(ctx, &gitalypb.PackObjectsHookWithSidechannelRequest{
+			Repository: repo,
+			Args:       []string{"pack-objects", "--revs", "--thin", "--stdout", "--delta-base-offset"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, wt.Wait())
+
+		return stdout
+	}
+
+	packA := doRequest(cfgA, repoA)
+	require.NotEmpty(t, packA)
+
+	// Complete entries are copied into the shared tier in the background.
+	require.Eventually(t, func() bool {
+		var stored bool
+		require.NoError(t, filepath.Walk(sharedDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && !strings.HasPrefix(info.Name(), "tmp-") {
+				stored = true
+			}
+			return nil
+		}))
+		return stored
+	}, time.Minute, time.Millisecond)
+
+	require.Equal(t, packA, doRequest(cfgB, repoB))
+}
+
 func TestServer_PackObjectsHookWithSidechannel(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,222 @@
+package hook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/pktline"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+var packObjectsCacheWarms = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gitaly_pack_objects_cache_warms_total",
+	Help: "Number of times the PackObjectsHook cache was warmed after a push",
+})
+
+// warmPackObjectsCache pre-generates the pack for a clone of the default
+// branch of repo if changes, the stdin of the post-receive hook, update the
+// default branch or create a tag. The pack is generated in the background
+// so that the push doesn't have to wait for it.
+func (s *server) warmPackObjectsCache(ctx context.Context, repo *gitalypb.Repository, changes []byte) {
+	logger := ctxlogrus.Extract(ctx)
+
+	defaultBranch, err := s.revParse(ctx, repo, "HEAD", "--symbolic-full-name")
+	if err != nil {
+		logger.WithError(err).Warn("warm pack-objects cache: resolve default branch")
+		return
+	}
+
+	if !shouldWarmPackObjectsCache(changes, defaultBranch) {
+		return
+	}
+
+	s.packObjectsCacheWarmer.run(repo.GetStorageName()+":"+repo.GetRelativePath(), func() {
+		// The pack must outlive the post-receive hook.
+		ctx, cancel := context.WithCancel(helper.SuppressCancellation(ctx))
+		defer cancel()
+
+		if err := s.warmPack(ctx, repo); err != nil {
+			logger.WithError(err).Warn("warm pack-objects cache")
+		}
+	})
+}
+
+// packObjectsCacheWarmer makes sure that at most one pack is warmed per
+// repository at a time. If the pack of a repository is warmed again while
+// it's being warmed, it's warmed once more afterwards, because the default
+// branch may have changed in the meantime.
+type packObjectsCacheWarmer struct {
+	mu sync.Mutex
+	// rewarm contains the repositories being warmed, and whether they have
+	// to be warmed again afterwards.
+	rewarm map[string]bool
+}
+
+// run calls warm in a separate goroutine unless repo is being warmed
+// already.
+func (w *packObjectsCacheWarmer) run(repo string, warm func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.rewarm[repo]; ok {
+		w.rewarm[repo] = true
+		return
+	}
+
+	if w.rewarm == nil {
+		w.rewarm = make(map[string]bool)
+	}
+	w.rewarm[repo] = false
+
+	go func() {
+		for {
+			warm()
+
+			w.mu.Lock()
+			rewarm := w.rewarm[repo]
+			if rewarm {
+				w.rewarm[repo] = false
+			} else {
+				delete(w.rewarm, repo)
+			}
+			w.mu.Unlock()
+
+			if !rewarm {
+				return
+			}
+		}
+	}()
+}
+
+// warmPack replays the fetch request that git-clone(1) sends with
+// --single-branch when its output isn't a terminal, as is the case for most
+// CI jobs. The request is served by git-upload-pack with the pack-objects
+// hook configured like for a clone over HTTP, so the arguments and the stdin
+// of git-pack-objects, and thus the cache key, are derived by Git itself.
+func (s *server) warmPack(ctx context.Context, repo *gitalypb.Repository) error {
+	out, err := s.revParse(ctx, repo, "HEAD^{commit}", "--absolute-git-dir", "--show-object-format")
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(out, "\n")
+	if len(lines) != 3 {
+		return fmt.Errorf("unexpected rev-parse output: %q", out)
+	}
+	repoPath, objectFormat, oid := lines[0], lines[1], lines[2]
+
+	var request bytes.Buffer
+	for _, line := range []string{"command=fetch", "object-format=" + objectFormat} {
+		if _, err := pktline.WriteString(&request, line+"\n"); err != nil {
+			return err
+		}
+	}
+	if err := pktline.WriteDelim(&request); err != nil {
+		return err
+	}
+	for _, line := range []string{"thin-pack", "no-progress", "include-tag", "ofs-delta", "want " + oid, "done"} {
+		if _, err := pktline.WriteString(&request, line+"\n"); err != nil {
+			return err
+		}
+	}
+	if err := pktline.WriteFlush(&request); err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd, err := s.gitCmdFactory.New(ctx, repo,
+		git.SubCmd{
+			Name:  "upload-pack",
+			Flags: []git.Option{git.Flag{Name: "--stateless-rpc"}},
+			Args:  []string{repoPath},
+		},
+		git.WithStdin(&request),
+		git.WithStderr(&stderr),
+		git.WithEnv("GIT_PROTOCOL="+git.ProtocolV2),
+		git.WithPackObjectsHookEnv(repo, "http"),
+	)
+	if err != nil {
+		return err
+	}
+
+	// git-pack-objects stops creating the pack if nobody reads it, so we
+	// have to read the response until the end even though we don't need it.
+	if _, err := io.Copy(io.Discard, cmd); err != nil {
+		return err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("upload-pack: stderr: %q err: %w", stderr.String(), err)
+	}
+
+	packObjectsCacheWarms.Inc()
+
+	return nil
+}
+
+func (s *server) revParse(ctx context.Context, repo *gitalypb.Repository, rev string, flags ...string) (string, error) {
+	var options []git.Option
+	for _, flag := range flags {
+		options = append(options, git.Flag{Name: flag})
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd, err := s.gitCmdFactory.New(ctx, repo,
+		git.SubCmd{
+			Name:  "rev-parse",
+			Flags: options,
+			Args:  []string{rev},
+		},
+		git.WithStdout(&stdout),
+		git.WithStderr(&stderr),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return "", fmt.Errorf("rev-parse: stderr: %q err: %w", stderr.String(), err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// shouldWarmPackObjectsCache determines whether changes update the default
+// branch or create a tag.
+func shouldWarmPackObjectsCache(changes []byte, defaultBranch string) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(changes))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		oldOID, newOID, ref := fields[0], fields[1], fields[2]
+
+		if isZeroOID(newOID) {
+			continue
+		}
+
+		if ref == defaultBranch || (strings.HasPrefix(ref, "refs/tags/") && isZeroOID(oldOID)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isZeroOID determines whether oid is the all-zeroes object ID, whatever the
+// object hash of the repository.
+func isZeroOID(oid string) bool {
+	return strings.Trim(oid, "0") == ""
+}
@@ -0,0 +1,235 @@
+//go:build !gitaly_test_sha256
+
+package hook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	hookPkg "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/hook"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/text"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/metadata/featureflag"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/streamcache"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+	"gitlab.com/gitlab-org/gitaly/v15/streamio"
+)
+
+func TestShouldWarmPackObjectsCache(t *testing.T) {
+	t.Parallel()
+
+	const (
+		zeroOID = "0000000000000000000000000000000000000000"
+		oldOID  = "1e292f8fedd741b75372e19097c76d327140c312"
+		newOID  = "3dd08961455abf80ef9115f4afdc1c6f968b503c"
+	)
+
+	for _, tc := range []struct {
+		desc     string
+		changes  string
+		expected bool
+	}{
+		{
+			desc:     "default branch updated",
+			changes:  fmt.Sprintf("%s %s refs/heads/main\n", oldOID, newOID),
+			expected: true,
+		},
+		{
+			desc:     "default branch created",
+			changes:  fmt.Sprintf("%s %s refs/heads/main\n", zeroOID, newOID),
+			expected: true,
+		},
+		{
+			desc:     "default branch deleted",
+			changes:  fmt.Sprintf("%s %s refs/heads/main\n", oldOID, zeroOID),
+			expected: false,
+		},
+		{
+			desc:     "tag created",
+			changes:  fmt.Sprintf("%s %s refs/tags/v1.0.0\n", zeroOID, newOID),
+			expected: true,
+		},
+		{
+			desc:     "tag updated",
+			changes:  fmt.Sprintf("%s %s refs/tags/v1.0.0\n", oldOID, newOID),
+			expected: false,
+		},
+		{
+			desc:     "other branch updated",
+			changes:  fmt.Sprintf("%s %s refs/heads/feature\n", oldOID, newOID),
+			expected: false,
+		},
+		{
+			desc: "multiple changes",
+			changes: fmt.Sprintf("%s %s refs/heads/feature\n%s %s refs/tags/v1.0.0\n",
+				oldOID, newOID, zeroOID, newOID),
+			expected: true,
+		},
+		{
+			desc:     "malformed changes",
+			changes:  "refs/heads/main\n",
+			expected: false,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, shouldWarmPackObjectsCache([]byte(tc.changes), "refs/heads/main"))
+		})
+	}
+}
+
+func TestPackObjectsCacheWarmer(t *testing.T) {
+	t.Parallel()
+
+	var warmer packObjectsCacheWarmer
+
+	started := make(chan string)
+	release := make(chan struct{})
+	warm := func(repo string) func() {
+		return func() {
+			started <- repo
+			<-release
+		}
+	}
+
+	warmer.run("repo-1", warm("repo-1"))
+	require.Equal(t, "repo-1", <-started)
+
+	// Warming another repository doesn't wait for the first one.
+	warmer.run("repo-2", warm("repo-2"))
+	require.Equal(t, "repo-2", <-started)
+
+	// Warming a repository which is being warmed is deferred until the
+	// current warm is done, and multiple requests are coalesced.
+	for i := 0; i < 10; i++ {
+		warmer.run("repo-1", warm("repo-1"))
+	}
+
+	select {
+	case repo := <-started:
+		require.FailNow(t, "unexpected concurrent warm", repo)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	release <- struct{}{}
+	release <- struct{}{}
+	require.Equal(t, "repo-1", <-started)
+	release <- struct{}{}
+
+	require.Eventually(t, func() bool {
+		warmer.mu.Lock()
+		defer warmer.mu.Unlock()
+		return len(warmer.rewarm) == 0
+	}, time.Minute, time.Millisecond)
+}
+
+func TestServer_PostReceiveHook_warmPackObjectsCache(t *testing.T) {
+	t.Parallel()
+
+	testhelper.NewFeatureSets(
+		featureflag.PackObjectsLimitingUser,
+		featureflag.PackObjectsLimitingRepo,
+	).Run(t, testServerPostReceiveHookWarmPackObjectsCache)
+}
+
+func testServerPostReceiveHookWarmPackObjectsCache(t *testing.T, ctx context.Context) {
+	cfg := cfgWithCache(t)
+	testcfg.BuildGitalyHooks(t, cfg)
+
+	tlc := &streamcache.TestLoggingCache{}
+	cfg.SocketPath = runHooksServer(t, cfg, []serverOption{func(s *server) {
+		tlc.Cache = s.packObjectsCache
+		s.packObjectsCache = tlc
+		s.warmPackObjectsCacheOnPush = true
+		s.manager = hookPkg.NewMockManager(t, nil, func(t *testing.T, ctx context.Context, repo *gitalypb.Repository, pushOptions, env []string, stdin io.Reader, stdout, stderr io.Writer) error {
+			_, err := io.Copy(io.Discard, stdin)
+			return err
+		}, nil, nil)
+	}})
+
+	repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+	commitID := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("main"))
+	gittest.Exec(t, cfg, "-C", repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	client, conn := newHooksClient(t, cfg.SocketPath)
+	defer conn.Close()
+
+	postReceive := func(changes string) {
+		stream, err := client.PostReceiveHook(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&gitalypb.PostReceiveHookRequest{Repository: repo}))
+
+		writer := streamio.NewWriter(func(p []byte) error {
+			return stream.Send(&gitalypb.PostReceiveHookRequest{Stdin: p})
+		})
+		_, err = io.WriteString(writer, changes)
+		require.NoError(t, err)
+		require.NoError(t, stream.CloseSend())
+
+		var status int32
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			status = resp.GetExitStatus().GetValue()
+		}
+		require.Zero(t, status)
+	}
+
+	// Pushing an unrelated branch doesn't warm the cache.
+	postReceive(fmt.Sprintf("%s %s refs/heads/feature\n", git.ObjectHashSHA1.ZeroOID, commitID))
+	require.Empty(t, tlc.Entries())
+
+	tagID := gittest.WriteTag(t, cfg, repoPath, "v1.0.0", commitID.Revision(), gittest.WriteTagConfig{Message: "v1.0.0"})
+	postReceive(fmt.Sprintf("%s %s refs/tags/v1.0.0\n", git.ObjectHashSHA1.ZeroOID, tagID))
+	require.Eventually(t, func() bool {
+		return len(tlc.Entries()) == 1
+	}, time.Minute, time.Millisecond)
+
+	warmed := tlc.Entries()[0]
+	require.True(t, warmed.Created)
+	require.NoError(t, warmed.Err)
+
+	// A clone of the default branch by any user hits the warmed entry.
+	payload, err := git.NewHooksPayload(
+		cfg,
+		repo,
+		nil,
+		&git.UserDetails{UserID: "user-123", Username: "user", Protocol: "http"},
+		git.PackObjectsHook,
+		featureflag.FromContext(ctx),
+	).Env()
+	require.NoError(t, err)
+
+	execEnv := gittest.NewCommandFactory(t, cfg).GetExecutionEnvironment(ctx)
+	uploadPack := testhelper.WriteExecutable(t, filepath.Join(testhelper.TempDir(t), "upload-pack"), []byte(fmt.Sprintf(
+		"#!/bin/sh\nexec '%s' -c uploadpack.packObjectsHook='%s' upload-pack \"$@\"\n",
+		execEnv.BinaryPath, cfg.BinaryPath("gitaly-hooks"),
+	)))
+
+	clonePath := filepath.Join(testhelper.TempDir(t), "clone.git")
+	gittest.ExecOpts(t, cfg, gittest.ExecConfig{Env: []string{payload}},
+		"-c", "protocol.version=2", "clone", "--bare", "--single-branch", "--upload-pack", uploadPack, "file://"+repoPath, clonePath,
+	)
+
+	entries := tlc.Entries()
+	require.Len(t, entries, 2)
+	require.Equal(t, warmed.Key, entries[1].Key)
+	require.False(t, entries[1].Created, "clone should hit the warmed cache entry")
+
+	gittest.Exec(t, cfg, "-C", clonePath, "fsck")
+	require.Equal(t, tagID.String(), text.ChompBytes(gittest.Exec(t, cfg, "-C", clonePath, "rev-parse", "refs/tags/v1.0.0")))
+}
@@ -1,8 +1,10 @@
 package hook
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"sync"
 
@@ -33,11 +35,16 @@ func (s *server) PostReceiveHook(stream gitalypb.HookService_PostReceiveHookServ
 		return helper.ErrInvalidArgument(err)
 	}
 
-	stdin := streamio.NewReader(func() ([]byte, error) {
+	var stdin io.Reader = streamio.NewReader(func() ([]byte, error) {
 		req, err := stream.Recv()
 		return req.GetStdin(), err
 	})
 
+	var changes bytes.Buffer
+	if s.warmPackObjectsCacheOnPush {
+		stdin = io.TeeReader(stdin, &changes)
+	}
+
 	var m sync.Mutex
 	stdout := streamio.NewSyncWriter(&m, func(p []byte) error {
 		return stream.Send(&gitalypb.PostReceiveHookResponse{Stdout: p})
@@ -63,6 +70,10 @@ func (s *server) PostReceiveHook(stream gitalypb.HookService_PostReceiveHookServ
 		return postReceiveHookResponse(stream, 1, fmt.Sprintf("%s", err))
 	}
 
+	if s.warmPackObjectsCacheOnPush {
+		s.warmPackObjectsCache(stream.Context(), firstRequest.Repository, changes.Bytes())
+	}
+
 	return postReceiveHookResponse(stream, 0, "")
 }
 
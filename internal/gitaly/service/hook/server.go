@@ -28,6 +28,23 @@ type server struct {
 		string,
 		*gitalyhook.ConcurrencyTracker,
 	) error
+
+	// warmPackObjectsCacheOnPush enables pre-generating the pack for a clone
+	// of the default branch in the post-receive hook.
+	warmPackObjectsCacheOnPush bool
+	packObjectsCacheWarmer     packObjectsCacheWarmer
+}
+
+// Option is an option for the hook server.
+type Option func(*server)
+
+// WithPackObjectsCacheWarming sets whether the post-receive hook pre-generates
+// the pack for a clone of the default branch when a push updates the default
+// branch or creates a tag.
+func WithPackObjectsCacheWarming(enabled bool) Option {
+	return func(s *server) {
+		s.warmPackObjectsCacheOnPush = enabled
+	}
 }
 
 // NewServer creates a new instance of a gRPC namespace server
@@ -37,6 +54,7 @@ func NewServer(
 	packObjectsCache streamcache.Cache,
 	concurrencyTracker *gitalyhook.ConcurrencyTracker,
 	packObjectsLimiter limithandler.Limiter,
+	opts ...Option,
 ) gitalypb.HookServiceServer {
 	srv := &server{
 		manager:            manager,
@@ -47,5 +65,9 @@ func NewServer(
 		runPackObjectsFn:   runPackObjects,
 	}
 
+	for _, opt := range opts {
+		opt(srv)
+	}
+
 	return srv
 }
@@ -160,14 +160,19 @@ func (s *Server) userApplyPatch(ctx context.Context, header *gitalypb.UserApplyP
 		return fmt.Errorf("get patched commit: %w", gitError{ErrMsg: revParseStderr.String(), Err: err})
 	}
 
-	patchedCommit, err := git.ObjectHashSHA1.FromHex(text.ChompBytes(revParseStdout.Bytes()))
+	objectHash, err := repo.ObjectHash(ctx)
+	if err != nil {
+		return fmt.Errorf("detecting object hash: %w", err)
+	}
+
+	patchedCommit, err := objectHash.FromHex(text.ChompBytes(revParseStdout.Bytes()))
 	if err != nil {
 		return fmt.Errorf("parse patched commit oid: %w", err)
 	}
 
 	currentCommit := parentCommitID
 	if branchCreated {
-		currentCommit = git.ObjectHashSHA1.ZeroOID
+		currentCommit = objectHash.ZeroOID
 	}
 
 	if err := s.updateReferenceWithHooks(ctx, header.Repository, header.User, nil, targetBranch, patchedCommit, currentCommit); err != nil {
@@ -50,14 +50,19 @@ func (s *Server) UserCreateBranch(ctx context.Context, req *gitalypb.UserCreateB
 		return nil, helper.ErrFailedPreconditionf("revspec '%s' not found", req.StartPoint)
 	}
 
-	startPointOID, err := git.ObjectHashSHA1.FromHex(startPointCommit.Id)
+	objectHash, err := quarantineRepo.ObjectHash(ctx)
+	if err != nil {
+		return nil, helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	startPointOID, err := objectHash.FromHex(startPointCommit.Id)
 	if err != nil {
 		return nil, helper.ErrInvalidArgumentf("could not parse start point commit ID: %w", err)
 	}
 
 	referenceName := git.NewReferenceNameFromBranchName(string(req.BranchName))
 
-	if err := s.updateReferenceWithHooks(ctx, req.GetRepository(), req.User, quarantineDir, referenceName, startPointOID, git.ObjectHashSHA1.ZeroOID); err != nil {
+	if err := s.updateReferenceWithHooks(ctx, req.GetRepository(), req.User, quarantineDir, referenceName, startPointOID, objectHash.ZeroOID); err != nil {
 		var customHookErr updateref.CustomHookError
 
 		if errors.As(err, &customHookErr) {
@@ -128,12 +133,17 @@ func (s *Server) UserUpdateBranch(ctx context.Context, req *gitalypb.UserUpdateB
 		return nil, helper.ErrInvalidArgument(err)
 	}
 
-	newOID, err := git.ObjectHashSHA1.FromHex(string(req.Newrev))
+	objectHash, err := s.localrepo(req.GetRepository()).ObjectHash(ctx)
+	if err != nil {
+		return nil, helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	newOID, err := objectHash.FromHex(string(req.Newrev))
 	if err != nil {
 		return nil, helper.ErrInternalf("could not parse newrev: %w", err)
 	}
 
-	oldOID, err := git.ObjectHashSHA1.FromHex(string(req.Oldrev))
+	oldOID, err := objectHash.FromHex(string(req.Oldrev))
 	if err != nil {
 		return nil, helper.ErrInternalf("could not parse oldrev: %w", err)
 	}
@@ -186,12 +196,19 @@ func (s *Server) UserDeleteBranch(ctx context.Context, req *gitalypb.UserDeleteB
 	}
 	referenceName := git.NewReferenceNameFromBranchName(string(req.BranchName))
 
-	referenceValue, err := s.localrepo(req.GetRepository()).ResolveRevision(ctx, referenceName.Revision())
+	repo := s.localrepo(req.GetRepository())
+
+	objectHash, err := repo.ObjectHash(ctx)
+	if err != nil {
+		return nil, helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	referenceValue, err := repo.ResolveRevision(ctx, referenceName.Revision())
 	if err != nil {
 		return nil, helper.ErrFailedPreconditionf("branch not found: %q", req.BranchName)
 	}
 
-	if err := s.updateReferenceWithHooks(ctx, req.Repository, req.User, nil, referenceName, git.ObjectHashSHA1.ZeroOID, referenceValue); err != nil {
+	if err := s.updateReferenceWithHooks(ctx, req.Repository, req.User, nil, referenceName, objectHash.ZeroOID, referenceValue); err != nil {
 		var notAllowedError hook.NotAllowedError
 		var customHookErr updateref.CustomHookError
 		var updateRefError updateref.Error
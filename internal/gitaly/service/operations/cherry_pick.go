@@ -112,8 +112,13 @@ func (s *Server) UserCherryPick(ctx context.Context, req *gitalypb.UserCherryPic
 	branchCreated := false
 	oldrev, err := quarantineRepo.ResolveRevision(ctx, referenceName.Revision()+"^{commit}")
 	if errors.Is(err, git.ErrReferenceNotFound) {
+		objectHash, err := quarantineRepo.ObjectHash(ctx)
+		if err != nil {
+			return nil, helper.ErrInternalf("detecting object hash: %w", err)
+		}
+
 		branchCreated = true
-		oldrev = git.ObjectHashSHA1.ZeroOID
+		oldrev = objectHash.ZeroOID
 	} else if err != nil {
 		return nil, helper.ErrInvalidArgumentf("resolve ref: %w", err)
 	}
@@ -127,6 +127,11 @@ func (s *Server) userCommitFiles(ctx context.Context, header *gitalypb.UserCommi
 		return err
 	}
 
+	objectHash, err := quarantineRepo.ObjectHash(ctx)
+	if err != nil {
+		return fmt.Errorf("detecting object hash: %w", err)
+	}
+
 	remoteRepo := header.GetStartRepository()
 	if sameRepository(header.GetRepository(), remoteRepo) {
 		// Some requests set a StartRepository that refers to the same repository as the target repository.
@@ -160,7 +165,7 @@ func (s *Server) userCommitFiles(ctx context.Context, header *gitalypb.UserCommi
 			return fmt.Errorf("resolve parent commit: %w", err)
 		}
 	} else {
-		parentCommitOID, err = git.ObjectHashSHA1.FromHex(header.StartSha)
+		parentCommitOID, err = objectHash.FromHex(header.StartSha)
 		if err != nil {
 			return helper.ErrInvalidArgumentf("cannot resolve parent commit: %w", err)
 		}
@@ -309,7 +314,7 @@ func (s *Server) userCommitFiles(ctx context.Context, header *gitalypb.UserCommi
 
 	oldRevision := parentCommitOID
 	if targetBranchCommit == "" {
-		oldRevision = git.ObjectHashSHA1.ZeroOID
+		oldRevision = objectHash.ZeroOID
 	} else if header.Force {
 		oldRevision = targetBranchCommit
 	}
@@ -325,7 +330,7 @@ func (s *Server) userCommitFiles(ctx context.Context, header *gitalypb.UserCommi
 	return stream.SendAndClose(&gitalypb.UserCommitFilesResponse{BranchUpdate: &gitalypb.OperationBranchUpdate{
 		CommitId:      commitID.String(),
 		RepoCreated:   !hasBranches,
-		BranchCreated: git.ObjectHashSHA1.IsZeroOID(oldRevision),
+		BranchCreated: objectHash.IsZeroOID(oldRevision),
 	}})
 }
 
@@ -433,7 +438,7 @@ func validateUserCommitFilesHeader(header *gitalypb.UserCommitFilesRequestHeader
 
 	startSha := header.GetStartSha()
 	if len(startSha) > 0 {
-		err := git.ObjectHashSHA1.ValidateHex(startSha)
+		_, err := git.ObjectHashByHex(startSha)
 		if err != nil {
 			return err
 		}
@@ -131,7 +131,12 @@ func (s *Server) UserMergeBranch(stream gitalypb.OperationService_UserMergeBranc
 		return helper.ErrInternal(err)
 	}
 
-	mergeOID, err := git.ObjectHashSHA1.FromHex(merge.CommitID)
+	objectHash, err := quarantineRepo.ObjectHash(ctx)
+	if err != nil {
+		return helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	mergeOID, err := objectHash.FromHex(merge.CommitID)
 	if err != nil {
 		return helper.ErrInternalf("could not parse merge ID: %w", err)
 	}
@@ -271,7 +276,12 @@ func (s *Server) UserFFBranch(ctx context.Context, in *gitalypb.UserFFBranchRequ
 		return nil, helper.ErrInvalidArgument(err)
 	}
 
-	commitID, err := git.ObjectHashSHA1.FromHex(in.CommitId)
+	objectHash, err := quarantineRepo.ObjectHash(ctx)
+	if err != nil {
+		return nil, helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	commitID, err := objectHash.FromHex(in.CommitId)
 	if err != nil {
 		return nil, helper.ErrInvalidArgumentf("cannot parse commit ID: %w", err)
 	}
@@ -353,6 +363,11 @@ func (s *Server) UserMergeToRef(ctx context.Context, request *gitalypb.UserMerge
 
 	repo := s.localrepo(request.GetRepository())
 
+	objectHash, err := repo.ObjectHash(ctx)
+	if err != nil {
+		return nil, helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
 	revision := git.Revision(request.Branch)
 	if request.FirstParentRef != nil {
 		revision = git.Revision(request.FirstParentRef)
@@ -384,14 +399,14 @@ func (s *Server) UserMergeToRef(ctx context.Context, request *gitalypb.UserMerge
 			return nil, helper.ErrFailedPreconditionf("target reference is symbolic: %q", request.TargetRef)
 		}
 
-		oid, err := git.ObjectHashSHA1.FromHex(targetRef.Target)
+		oid, err := objectHash.FromHex(targetRef.Target)
 		if err != nil {
 			return nil, helper.ErrInternalf("invalid target revision: %w", err)
 		}
 
 		oldTargetOID = oid
 	} else if errors.Is(err, git.ErrReferenceNotFound) {
-		oldTargetOID = git.ObjectHashSHA1.ZeroOID
+		oldTargetOID = objectHash.ZeroOID
 	} else {
 		return nil, helper.ErrInternalf("could not read target reference: %w", err)
 	}
@@ -423,7 +438,7 @@ func (s *Server) UserMergeToRef(ctx context.Context, request *gitalypb.UserMerge
 			sourceOID, oid, string(request.TargetRef))
 	}
 
-	mergeOID, err := git.ObjectHashSHA1.FromHex(merge.CommitID)
+	mergeOID, err := objectHash.FromHex(merge.CommitID)
 	if err != nil {
 		return nil, helper.ErrInternalf("parsing merge commit SHA: %w", err)
 	}
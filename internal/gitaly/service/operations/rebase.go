@@ -41,8 +41,13 @@ func (s *Server) UserRebaseConfirmable(stream gitalypb.OperationService_UserReba
 		return err
 	}
 
+	objectHash, err := quarantineRepo.ObjectHash(ctx)
+	if err != nil {
+		return helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
 	branch := git.NewReferenceNameFromBranchName(string(header.Branch))
-	oldrev, err := git.ObjectHashSHA1.FromHex(header.BranchSha)
+	oldrev, err := objectHash.FromHex(header.BranchSha)
 	if err != nil {
 		return helper.ErrNotFound(err)
 	}
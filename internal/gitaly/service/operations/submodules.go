@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
@@ -38,7 +37,7 @@ func validateUserUpdateSubmoduleRequest(req *gitalypb.UserUpdateSubmoduleRequest
 		return errors.New("empty CommitSha")
 	}
 
-	if match, err := regexp.MatchString(`\A[0-9a-f]{40}\z`, req.GetCommitSha()); !match || err != nil {
+	if _, err := git.ObjectHashByHex(req.GetCommitSha()); err != nil {
 		return errors.New("invalid CommitSha")
 	}
 
@@ -135,7 +134,12 @@ func (s *Server) userUpdateSubmodule(ctx context.Context, req *gitalypb.UserUpda
 		return nil, helper.ErrInternalf("submodule subcommand: %w", err)
 	}
 
-	commitID, err := git.ObjectHashSHA1.FromHex(result.CommitID)
+	objectHash, err := quarantineRepo.ObjectHash(ctx)
+	if err != nil {
+		return nil, helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	commitID, err := objectHash.FromHex(result.CommitID)
 	if err != nil {
 		return nil, helper.ErrInvalidArgumentf("cannot parse commit ID: %w", err)
 	}
@@ -1,10 +1,10 @@
 package ref
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/chunk"
@@ -19,8 +19,8 @@ func (s *server) ListBranchNamesContainingCommit(in *gitalypb.ListBranchNamesCon
 	if err := service.ValidateRepository(in.GetRepository()); err != nil {
 		return helper.ErrInvalidArgument(err)
 	}
-	if err := git.ObjectHashSHA1.ValidateHex(in.GetCommitId()); err != nil {
-		return helper.ErrInvalidArgument(err)
+	if err := s.validateCommitID(stream.Context(), in.GetRepository(), in.GetCommitId()); err != nil {
+		return err
 	}
 
 	chunker := chunk.New(&branchNamesContainingCommitSender{stream: stream})
@@ -32,6 +32,20 @@ func (s *server) ListBranchNamesContainingCommit(in *gitalypb.ListBranchNamesCon
 	return nil
 }
 
+// validateCommitID verifies that the commit ID is a full object ID of the repository's object hash.
+func (s *server) validateCommitID(ctx context.Context, repo *gitalypb.Repository, commitID string) error {
+	objectHash, err := s.localrepo(repo).ObjectHash(ctx)
+	if err != nil {
+		return helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	if err := objectHash.ValidateHex(commitID); err != nil {
+		return helper.ErrInvalidArgument(err)
+	}
+
+	return nil
+}
+
 type containingRequest interface {
 	GetCommitId() string
 	GetLimit() uint32
@@ -65,8 +79,8 @@ func (s *server) ListTagNamesContainingCommit(in *gitalypb.ListTagNamesContainin
 	if err := service.ValidateRepository(in.GetRepository()); err != nil {
 		return helper.ErrInvalidArgument(err)
 	}
-	if err := git.ObjectHashSHA1.ValidateHex(in.GetCommitId()); err != nil {
-		return helper.ErrInvalidArgument(err)
+	if err := s.validateCommitID(stream.Context(), in.GetRepository(), in.GetCommitId()); err != nil {
+		return err
 	}
 
 	chunker := chunk.New(&tagNamesContainingCommitSender{stream: stream})
@@ -40,7 +40,14 @@ func (s *server) FindRemoteRepository(ctx context.Context, req *gitalypb.FindRem
 	// Regexp would've read better, but this is faster
 	// 58fbff2e0d3b620f591a748c158799ead87b51cd	HEAD
 	fields := bytes.Fields(output)
-	match := len(fields) == 2 && len(fields[0]) == 40 && string(fields[1]) == "HEAD"
+	match := len(fields) == 2 && isObjectID(string(fields[0])) && string(fields[1]) == "HEAD"
 
 	return &gitalypb.FindRemoteRepositoryResponse{Exists: match}, nil
 }
+
+// isObjectID determines whether the given string is a full object ID. As we don't know which object
+// hash the remote repository uses, object IDs of any supported hash are accepted.
+func isObjectID(oid string) bool {
+	_, err := git.ObjectHashByHex(oid)
+	return err == nil
+}
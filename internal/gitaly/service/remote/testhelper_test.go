@@ -45,6 +45,8 @@ func setupRemoteService(t *testing.T, ctx context.Context, opts ...testserver.Gi
 			deps.GetConnsPool(),
 			deps.GetGit2goExecutor(),
 			deps.GetHousekeepingManager(),
+			deps.GetBackupSink(),
+			deps.GetBackupLocator(),
 		))
 	}, opts...)
 	cfg.SocketPath = addr
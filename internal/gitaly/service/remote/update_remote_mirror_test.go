@@ -524,6 +524,8 @@ func TestUpdateRemoteMirror(t *testing.T) {
 					deps.GetConnsPool(),
 					deps.GetGit2goExecutor(),
 					deps.GetHousekeepingManager(),
+					deps.GetBackupSink(),
+					deps.GetBackupLocator(),
 				))
 			})
 			cfg.SocketPath = addr
@@ -63,9 +63,10 @@ func (s *server) applyGitattributes(ctx context.Context, repo *localrepo.Repo, o
 			return fmt.Errorf("locking gitattributes: %w", err)
 		}
 
-		// We use the zero OID as placeholder to vote on removal of the
-		// gitattributes file.
-		if err := s.vote(ctx, git.ObjectHashSHA1.ZeroOID, voting.Prepared); err != nil {
+		// We use the zero vote as placeholder to vote on removal of the
+		// gitattributes file. It is independent of the repository's object
+		// hash and matches the zero OID of SHA1 repositories.
+		if err := s.vote(ctx, voting.Vote{}, voting.Prepared); err != nil {
 			return fmt.Errorf("preimage vote: %w", err)
 		}
 
@@ -73,7 +74,7 @@ func (s *server) applyGitattributes(ctx context.Context, repo *localrepo.Repo, o
 			return err
 		}
 
-		if err := s.vote(ctx, git.ObjectHashSHA1.ZeroOID, voting.Committed); err != nil {
+		if err := s.vote(ctx, voting.Vote{}, voting.Committed); err != nil {
 			return fmt.Errorf("postimage vote: %w", err)
 		}
 
@@ -105,22 +106,12 @@ func (s *server) applyGitattributes(ctx context.Context, repo *localrepo.Repo, o
 	return nil
 }
 
-func (s *server) vote(ctx context.Context, oid git.ObjectID, phase voting.Phase) error {
+func (s *server) vote(ctx context.Context, vote voting.Vote, phase voting.Phase) error {
 	tx, err := txinfo.TransactionFromContext(ctx)
 	if errors.Is(err, txinfo.ErrTransactionNotFound) {
 		return nil
 	}
 
-	hash, err := oid.Bytes()
-	if err != nil {
-		return fmt.Errorf("vote with invalid object ID: %w", err)
-	}
-
-	vote, err := voting.VoteFromHash(hash)
-	if err != nil {
-		return fmt.Errorf("cannot convert OID to vote: %w", err)
-	}
-
 	if err := s.txManager.Vote(ctx, tx, vote, phase); err != nil {
 		return fmt.Errorf("vote failed: %w", err)
 	}
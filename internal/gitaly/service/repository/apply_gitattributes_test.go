@@ -8,11 +8,15 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/backchannel"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/text"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/metadata"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testcfg"
@@ -201,6 +205,87 @@ func TestApplyGitattributesWithTransaction(t *testing.T) {
 	}
 }
 
+func TestApplyGitattributesWithTransaction_objectHash(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg := testcfg.Build(t)
+
+	transactionServer := &testTransactionServer{}
+	runRepositoryService(t, cfg, nil)
+
+	logger := testhelper.NewDiscardingLogEntry(t)
+	client := newMuxedRepositoryClient(t, ctx, cfg, "unix://"+cfg.InternalSocketPath(),
+		backchannel.NewClientHandshaker(logger, func() backchannel.Server {
+			srv := grpc.NewServer()
+			gitalypb.RegisterRefTransactionServer(srv, transactionServer)
+			return srv
+		}),
+	)
+
+	for _, objectHash := range []git.ObjectHash{git.ObjectHashSHA1, git.ObjectHashSHA256} {
+		t.Run(objectHash.Format, func(t *testing.T) {
+			repo, repoPath := gittest.CreateRepository(t, ctx, cfg, gittest.CreateRepositoryConfig{
+				SkipCreationViaService: true,
+				ObjectFormat:           objectHash.Format,
+			})
+
+			// The gittest helpers only handle the default object hash, so the commits are
+			// written with plain Git commands.
+			writeCommit := func(path, content string) string {
+				blob := text.ChompBytes(gittest.ExecOpts(t, cfg, gittest.ExecConfig{Stdin: strings.NewReader(content)},
+					"-C", repoPath, "hash-object", "-w", "--stdin",
+				))
+				tree := text.ChompBytes(gittest.ExecOpts(t, cfg, gittest.ExecConfig{Stdin: strings.NewReader("100644 blob " + blob + "\t" + path + "\n")},
+					"-C", repoPath, "mktree",
+				))
+				return text.ChompBytes(gittest.Exec(t, cfg, "-C", repoPath, "commit-tree", "-m", "message", tree))
+			}
+
+			withGitattributes := writeCommit(".gitattributes", "*.go diff=golang\n")
+			withoutGitattributes := writeCommit("README", "readme\n")
+
+			ctx, err := txinfo.InjectTransaction(ctx, 1, "primary", true)
+			require.NoError(t, err)
+			ctx = metadata.IncomingToOutgoing(ctx)
+
+			var votes []voting.Vote
+			transactionServer.vote = func(request *gitalypb.VoteTransactionRequest) (*gitalypb.VoteTransactionResponse, error) {
+				vote, err := voting.VoteFromHash(request.ReferenceUpdatesHash)
+				require.NoError(t, err)
+				votes = append(votes, vote)
+
+				return &gitalypb.VoteTransactionResponse{
+					State: gitalypb.VoteTransactionResponse_COMMIT,
+				}, nil
+			}
+
+			attributesPath := filepath.Join(repoPath, "info", "attributes")
+
+			_, err = client.ApplyGitattributes(ctx, &gitalypb.ApplyGitattributesRequest{
+				Repository: repo,
+				Revision:   []byte(withGitattributes),
+			})
+			require.NoError(t, err)
+			require.Equal(t, []byte("*.go diff=golang\n"), testhelper.MustReadFile(t, attributesPath))
+
+			expectedVote := voting.VoteFromData([]byte("*.go diff=golang\n"))
+			require.Equal(t, []voting.Vote{expectedVote, expectedVote}, votes)
+
+			// Removing the gitattributes votes on the zero vote, whatever the object hash of
+			// the repository.
+			votes = nil
+			_, err = client.ApplyGitattributes(ctx, &gitalypb.ApplyGitattributesRequest{
+				Repository: repo,
+				Revision:   []byte(withoutGitattributes),
+			})
+			require.NoError(t, err)
+			require.NoFileExists(t, attributesPath)
+			require.Equal(t, []voting.Vote{{}, {}}, votes)
+		})
+	}
+}
+
 func TestApplyGitattributesFailure(t *testing.T) {
 	t.Parallel()
 	ctx := testhelper.Context(t)
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/storage"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+// errServerSideBackupsNotConfigured is returned when server-side backups are
+// requested but no backup sink has been configured.
+var errServerSideBackupsNotConfigured = helper.ErrFailedPreconditionf("server-side backups are not configured")
+
+// BackupRepository creates a backup of the repository and writes it to the
+// configured backup sink.
+func (s *server) BackupRepository(ctx context.Context, in *gitalypb.BackupRepositoryRequest) (*gitalypb.BackupRepositoryResponse, error) {
+	if s.backupSink == nil || s.backupLocator == nil {
+		return nil, errServerSideBackupsNotConfigured
+	}
+
+	if err := service.ValidateRepository(in.GetRepository()); err != nil {
+		return nil, helper.ErrInvalidArgument(err)
+	}
+
+	if in.GetBackupId() == "" {
+		return nil, helper.ErrInvalidArgumentf("empty BackupId")
+	}
+
+	manager := backup.NewManager(s.backupSink, s.backupLocator, s.conns, in.GetBackupId())
+	err := manager.Create(ctx, &backup.CreateRequest{
+		Server:           s.internalServerInfo(),
+		Repository:       in.GetRepository(),
+		VanityRepository: in.GetVanityRepository(),
+		Incremental:      in.GetIncremental(),
+	})
+	switch {
+	case errors.Is(err, backup.ErrSkipped):
+		detailedErr, err := helper.ErrWithDetails(
+			helper.ErrFailedPreconditionf("backup repository: %w", err),
+			&gitalypb.BackupRepositoryResponse_SkippedError{},
+		)
+		if err != nil {
+			return nil, helper.ErrInternalf("error details: %w", err)
+		}
+
+		return nil, detailedErr
+	case err != nil:
+		return nil, helper.ErrInternalf("backup repository: %w", err)
+	}
+
+	return &gitalypb.BackupRepositoryResponse{}, nil
+}
+
+// internalServerInfo returns the connection information used to access this
+// Gitaly server from within itself.
+func (s *server) internalServerInfo() storage.ServerInfo {
+	return storage.ServerInfo{
+		Address: "unix:" + s.cfg.InternalSocketPath(),
+		Token:   s.cfg.Auth.Token,
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !gitaly_test_sha256
+
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+func TestBackupRepository(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	backupRoot := testhelper.TempDir(t)
+	sink := backup.NewFilesystemSink(backupRoot)
+	locator, err := backup.ResolveLocator("pointer", sink)
+	require.NoError(t, err)
+
+	cfg, client := setupRepositoryServiceWithoutRepo(t,
+		testserver.WithBackupSink(sink),
+		testserver.WithBackupLocator(locator),
+	)
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+		gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+		vanityRepo := &gitalypb.Repository{
+			StorageName:  repo.GetStorageName(),
+			RelativePath: "some/path.git",
+		}
+
+		_, err := client.BackupRepository(ctx, &gitalypb.BackupRepositoryRequest{
+			Repository:       repo,
+			VanityRepository: vanityRepo,
+			BackupId:         "abc123",
+		})
+		require.NoError(t, err)
+
+		b, err := locator.Find(ctx, vanityRepo, "abc123")
+		require.NoError(t, err)
+		require.Len(t, b.Steps, 1)
+		require.FileExists(t, filepath.Join(backupRoot, b.Steps[0].BundlePath))
+		require.FileExists(t, filepath.Join(backupRoot, b.Steps[0].RefPath))
+	})
+
+	t.Run("empty repository", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+		_, err := client.BackupRepository(ctx, &gitalypb.BackupRepositoryRequest{
+			Repository: repo,
+			BackupId:   "abc123",
+		})
+
+		expectedErr, err2 := helper.ErrWithDetails(
+			helper.ErrFailedPreconditionf("backup repository: manager: repository empty: repository skipped"),
+			&gitalypb.BackupRepositoryResponse_SkippedError{},
+		)
+		require.NoError(t, err2)
+		testhelper.RequireGrpcError(t, expectedErr, err)
+	})
+
+	t.Run("missing backup ID", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+		_, err := client.BackupRepository(ctx, &gitalypb.BackupRepositoryRequest{
+			Repository: repo,
+		})
+		testhelper.RequireGrpcError(t, helper.ErrInvalidArgumentf("empty BackupId"), err)
+	})
+}
+
+func TestBackupRepository_notConfigured(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg, client := setupRepositoryServiceWithoutRepo(t)
+	repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+	_, err := client.BackupRepository(ctx, &gitalypb.BackupRepositoryRequest{
+		Repository: repo,
+		BackupId:   "abc123",
+	})
+	testhelper.RequireGrpcError(t, helper.ErrFailedPreconditionf("server-side backups are not configured"), err)
+}
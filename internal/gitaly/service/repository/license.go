@@ -185,6 +185,11 @@ func (f *gitFiler) ReadFile(path string) ([]byte, error) {
 }
 
 func (f *gitFiler) ReadDir(string) ([]filer.File, error) {
+	objectHash, err := f.repo.ObjectHash(f.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("detecting object hash: %w", err)
+	}
+
 	// We're doing a recursive listing returning all files at once such that we do not have to
 	// call git-ls-tree(1) multiple times.
 	var stderr bytes.Buffer
@@ -200,7 +205,7 @@ func (f *gitFiler) ReadDir(string) ([]filer.File, error) {
 		return nil, err
 	}
 
-	tree := lstree.NewParser(cmd, git.ObjectHashSHA1)
+	tree := lstree.NewParser(cmd, objectHash)
 
 	var files []filer.File
 	for {
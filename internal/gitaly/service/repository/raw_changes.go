@@ -31,25 +31,30 @@ func (s *server) GetRawChanges(req *gitalypb.GetRawChangesRequest, stream gitaly
 	}
 	defer cancel()
 
-	if err := validateRawChangesRequest(ctx, req, objectInfoReader); err != nil {
+	objectHash, err := repo.ObjectHash(ctx)
+	if err != nil {
+		return helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
+	if err := validateRawChangesRequest(ctx, objectHash, req, objectInfoReader); err != nil {
 		return helper.ErrInvalidArgument(err)
 	}
 
-	if err := s.getRawChanges(stream, repo, objectInfoReader, req.GetFromRevision(), req.GetToRevision()); err != nil {
+	if err := s.getRawChanges(stream, repo, objectHash, objectInfoReader, req.GetFromRevision(), req.GetToRevision()); err != nil {
 		return helper.ErrInternal(err)
 	}
 
 	return nil
 }
 
-func validateRawChangesRequest(ctx context.Context, req *gitalypb.GetRawChangesRequest, objectInfoReader catfile.ObjectInfoReader) error {
-	if from := req.FromRevision; !git.ObjectHashSHA1.IsZeroOID(git.ObjectID(from)) {
+func validateRawChangesRequest(ctx context.Context, objectHash git.ObjectHash, req *gitalypb.GetRawChangesRequest, objectInfoReader catfile.ObjectInfoReader) error {
+	if from := req.FromRevision; !objectHash.IsZeroOID(git.ObjectID(from)) {
 		if _, err := objectInfoReader.Info(ctx, git.Revision(from)); err != nil {
 			return fmt.Errorf("invalid 'from' revision: %q", from)
 		}
 	}
 
-	if to := req.ToRevision; !git.ObjectHashSHA1.IsZeroOID(git.ObjectID(to)) {
+	if to := req.ToRevision; !objectHash.IsZeroOID(git.ObjectID(to)) {
 		if _, err := objectInfoReader.Info(ctx, git.Revision(to)); err != nil {
 			return fmt.Errorf("invalid 'to' revision: %q", to)
 		}
@@ -58,13 +63,13 @@ func validateRawChangesRequest(ctx context.Context, req *gitalypb.GetRawChangesR
 	return nil
 }
 
-func (s *server) getRawChanges(stream gitalypb.RepositoryService_GetRawChangesServer, repo git.RepositoryExecutor, objectInfoReader catfile.ObjectInfoReader, from, to string) error {
-	if git.ObjectHashSHA1.IsZeroOID(git.ObjectID(to)) {
+func (s *server) getRawChanges(stream gitalypb.RepositoryService_GetRawChangesServer, repo git.RepositoryExecutor, objectHash git.ObjectHash, objectInfoReader catfile.ObjectInfoReader, from, to string) error {
+	if objectHash.IsZeroOID(git.ObjectID(to)) {
 		return nil
 	}
 
-	if git.ObjectHashSHA1.IsZeroOID(git.ObjectID(from)) {
-		from = git.ObjectHashSHA1.EmptyTreeOID.String()
+	if objectHash.IsZeroOID(git.ObjectID(from)) {
+		from = objectHash.EmptyTreeOID.String()
 	}
 
 	ctx := stream.Context()
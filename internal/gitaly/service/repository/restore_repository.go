@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/service"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+// RestoreRepository restores the repository from a backup read from the
+// configured backup sink.
+func (s *server) RestoreRepository(ctx context.Context, in *gitalypb.RestoreRepositoryRequest) (*gitalypb.RestoreRepositoryResponse, error) {
+	if s.backupSink == nil || s.backupLocator == nil {
+		return nil, errServerSideBackupsNotConfigured
+	}
+
+	if err := service.ValidateRepository(in.GetRepository()); err != nil {
+		return nil, helper.ErrInvalidArgument(err)
+	}
+
+	manager := backup.NewManager(s.backupSink, s.backupLocator, s.conns, "")
+	err := manager.Restore(ctx, &backup.RestoreRequest{
+		Server:           s.internalServerInfo(),
+		Repository:       in.GetRepository(),
+		VanityRepository: in.GetVanityRepository(),
+		BackupID:         in.GetBackupId(),
+		AlwaysCreate:     in.GetAlwaysCreate(),
+	})
+	switch {
+	case errors.Is(err, backup.ErrSkipped):
+		detailedErr, err := helper.ErrWithDetails(
+			helper.ErrFailedPreconditionf("restore repository: %w", err),
+			&gitalypb.RestoreRepositoryResponse_SkippedError{},
+		)
+		if err != nil {
+			return nil, helper.ErrInternalf("error details: %w", err)
+		}
+
+		return nil, detailedErr
+	case errors.Is(err, backup.ErrDoesntExist):
+		return nil, helper.ErrNotFoundf("restore repository: %w", err)
+	case errors.Is(err, backup.ErrCorrupted):
+		return nil, helper.ErrDataLossf("restore repository: %w", err)
+	case err != nil:
+		return nil, helper.ErrInternalf("restore repository: %w", err)
+	}
+
+	return &gitalypb.RestoreRepositoryResponse{}, nil
+}
@@ -0,0 +1,79 @@
+//go:build !gitaly_test_sha256
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/git/gittest"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testserver"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRestoreRepository(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	sink := backup.NewFilesystemSink(testhelper.TempDir(t))
+	locator, err := backup.ResolveLocator("pointer", sink)
+	require.NoError(t, err)
+
+	cfg, client := setupRepositoryServiceWithoutRepo(t,
+		testserver.WithBackupSink(sink),
+		testserver.WithBackupLocator(locator),
+	)
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		repo, repoPath := gittest.CreateRepository(t, ctx, cfg)
+		commitID := gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("master"))
+
+		_, err := client.BackupRepository(ctx, &gitalypb.BackupRepositoryRequest{
+			Repository: repo,
+			BackupId:   "abc123",
+		})
+		require.NoError(t, err)
+
+		gittest.WriteCommit(t, cfg, repoPath, gittest.WithBranch("feature"))
+
+		_, err = client.RestoreRepository(ctx, &gitalypb.RestoreRepositoryRequest{
+			Repository: repo,
+			BackupId:   "abc123",
+		})
+		require.NoError(t, err)
+
+		refs := gittest.Exec(t, cfg, "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)")
+		require.Equal(t, commitID.String()+" refs/heads/master\n", string(refs))
+	})
+
+	t.Run("missing backup bundle", func(t *testing.T) {
+		t.Parallel()
+
+		repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+		_, err := client.RestoreRepository(ctx, &gitalypb.RestoreRepositoryRequest{
+			Repository: repo,
+			BackupId:   "missing",
+		})
+		testhelper.RequireGrpcCode(t, err, codes.NotFound)
+	})
+}
+
+func TestRestoreRepository_notConfigured(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	cfg, client := setupRepositoryServiceWithoutRepo(t)
+	repo, _ := gittest.CreateRepository(t, ctx, cfg)
+
+	_, err := client.RestoreRepository(ctx, &gitalypb.RestoreRepositoryRequest{
+		Repository: repo,
+	})
+	testhelper.RequireGrpcError(t, helper.ErrFailedPreconditionf("server-side backups are not configured"), err)
+}
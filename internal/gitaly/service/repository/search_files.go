@@ -114,6 +114,12 @@ func (s *server) SearchFilesByName(req *gitalypb.SearchFilesByNameRequest, strea
 	}
 
 	ctx := stream.Context()
+
+	objectHash, err := s.localrepo(req.GetRepository()).ObjectHash(ctx)
+	if err != nil {
+		return helper.ErrInternalf("detecting object hash: %w", err)
+	}
+
 	cmd, err := s.gitCmdFactory.New(
 		ctx,
 		req.GetRepository(),
@@ -131,7 +137,7 @@ func (s *server) SearchFilesByName(req *gitalypb.SearchFilesByNameRequest, strea
 		return helper.ErrInternalf("SearchFilesByName: cmd start failed: %v", err)
 	}
 
-	files, err := parseLsTree(cmd, filter, int(req.GetOffset()), int(req.GetLimit()))
+	files, err := parseLsTree(cmd, objectHash, filter, int(req.GetOffset()), int(req.GetLimit()))
 	if err != nil {
 		return err
 	}
@@ -165,10 +171,10 @@ func validateSearchFilesRequest(req searchFilesRequest) error {
 	return nil
 }
 
-func parseLsTree(cmd *command.Command, filter *regexp.Regexp, offset int, limit int) ([][]byte, error) {
+func parseLsTree(cmd *command.Command, objectHash git.ObjectHash, filter *regexp.Regexp, offset int, limit int) ([][]byte, error) {
 	var files [][]byte
 	var index int
-	parser := lstree.NewParser(cmd, git.ObjectHashSHA1)
+	parser := lstree.NewParser(cmd, objectHash)
 
 	for {
 		path, err := parser.NextEntryPath()
@@ -227,6 +227,8 @@ func TestSearchFilesByContentFailure(t *testing.T) {
 		connsPool,
 		git2goExecutor,
 		housekeepingManager,
+		nil,
+		nil,
 	)
 
 	testCases := []struct {
@@ -577,6 +579,8 @@ func TestSearchFilesByNameFailure(t *testing.T) {
 		connsPool,
 		git2goExecutor,
 		housekeepingManager,
+		nil,
+		nil,
 	)
 
 	testCases := []struct {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"gitlab.com/gitlab-org/gitaly/v15/client"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/catfile"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/housekeeping"
@@ -27,6 +28,8 @@ type server struct {
 	catfileCache        catfile.Cache
 	git2goExecutor      *git2go.Executor
 	housekeepingManager housekeeping.Manager
+	backupSink          backup.Sink
+	backupLocator       backup.Locator
 }
 
 // NewServer creates a new instance of a gRPC repo server
@@ -40,6 +43,8 @@ func NewServer(
 	connsPool *client.Pool,
 	git2goExecutor *git2go.Executor,
 	housekeepingManager housekeeping.Manager,
+	backupSink backup.Sink,
+	backupLocator backup.Locator,
 ) gitalypb.RepositoryServiceServer {
 	return &server{
 		ruby:                rs,
@@ -52,6 +57,8 @@ func NewServer(
 		catfileCache:        catfileCache,
 		git2goExecutor:      git2goExecutor,
 		housekeepingManager: housekeepingManager,
+		backupSink:          backupSink,
+		backupLocator:       backupLocator,
 	}
 }
 
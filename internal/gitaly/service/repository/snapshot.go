@@ -18,7 +18,7 @@ import (
 
 var objectFiles = []*regexp.Regexp{
 	regexp.MustCompile(`/[[:xdigit:]]{2}/[[:xdigit:]]{38}\z`),
-	regexp.MustCompile(`/pack/pack\-[[:xdigit:]]{40}\.(pack|idx)\z`),
+	regexp.MustCompile(`/pack/pack\-([[:xdigit:]]{40}|[[:xdigit:]]{64})\.(pack|idx)\z`),
 }
 
 func (s *server) GetSnapshot(in *gitalypb.GetSnapshotRequest, stream gitalypb.RepositoryService_GetSnapshotServer) error {
@@ -100,6 +100,8 @@ func RegisterAll(srv *grpc.Server, deps *service.Dependencies) {
 		deps.GetConnsPool(),
 		deps.GetGit2goExecutor(),
 		deps.GetHousekeepingManager(),
+		deps.GetBackupSink(),
+		deps.GetBackupLocator(),
 	))
 	gitalypb.RegisterSSHServiceServer(srv, ssh.NewServer(
 		deps.GetLocator(),
@@ -144,6 +146,7 @@ func RegisterAll(srv *grpc.Server, deps *service.Dependencies) {
 		deps.GetPackObjectsCache(),
 		deps.GetPackObjectsConcurrencyTracker(),
 		deps.GetPackObjectsLimiter(),
+		hook.WithPackObjectsCacheWarming(deps.GetCfg().PackObjectsCache.WarmOnPush),
 	))
 	gitalypb.RegisterInternalGitalyServer(srv, internalgitaly.NewServer(deps.GetCfg().Storages))
 
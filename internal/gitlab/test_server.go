@@ -20,7 +20,7 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
 )
 
-var changeLineRegex = regexp.MustCompile("^[a-f0-9]{40} [a-f0-9]{40} refs/[^ ]+$")
+var changeLineRegex = regexp.MustCompile("^([a-f0-9]{40} [a-f0-9]{40}|[a-f0-9]{64} [a-f0-9]{64}) refs/[^ ]+$")
 
 // WriteShellSecretFile writes a .gitlab_shell_secret file in the specified directory
 func WriteShellSecretFile(tb testing.TB, dir, secretToken string) string {
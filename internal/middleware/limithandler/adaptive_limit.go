@@ -0,0 +1,191 @@
+package limithandler
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+)
+
+const (
+	defaultCalibrationInterval     = 5 * time.Second
+	defaultBackoffFactor           = 0.75
+	defaultLatencyTolerance        = 2.0
+	defaultCPUPressureThreshold    = 60
+	defaultMemoryPressureThreshold = 20
+)
+
+// AdaptiveLimit is a concurrency limit that adjusts itself based on the observed latency of
+// requests and on resource pressure. It follows an additive-increase/multiplicative-decrease
+// (AIMD) scheme: the limit is recalculated once per calibration interval. If Gitaly is overloaded
+// during the interval, the limit is multiplied by the backoff factor. Otherwise, if requests had
+// to be queued because the limit was reached, the limit is increased by one.
+//
+// Gitaly is considered to be overloaded when either the CPU or memory pressure exceeds their
+// thresholds, or when the average latency of requests exceeds the baseline latency by more than
+// the latency tolerance. The baseline latency is the lowest average latency observed, which
+// slowly drifts towards the current average latency so that it can follow long-term changes in
+// the workload.
+type AdaptiveLimit struct {
+	mu sync.Mutex
+
+	current, minLimit, maxLimit int
+
+	calibrationInterval     time.Duration
+	backoffFactor           float64
+	latencyTolerance        float64
+	cpuPressureThreshold    float64
+	memoryPressureThreshold float64
+	readPressure            PressureReader
+	now                     func() time.Time
+
+	lastCalibration time.Time
+	baselineLatency time.Duration
+	windowLatency   time.Duration
+	windowRequests  int
+	windowSaturated bool
+
+	onChange []func(limit int)
+}
+
+// NewAdaptiveLimit creates a new adaptive limit that starts at the initial limit and is adjusted
+// between the minimum and maximum limit. readPressure may be nil, in which case resource pressure
+// is not taken into account.
+func NewAdaptiveLimit(initial, minLimit, maxLimit int, cfg config.AdaptiveLimiting, readPressure PressureReader) *AdaptiveLimit {
+	if minLimit <= 0 {
+		minLimit = 1
+	}
+
+	limit := &AdaptiveLimit{
+		current:                 initial,
+		minLimit:                minLimit,
+		maxLimit:                maxLimit,
+		calibrationInterval:     cfg.CalibrationInterval.Duration(),
+		backoffFactor:           cfg.BackoffFactor,
+		latencyTolerance:        cfg.LatencyTolerance,
+		cpuPressureThreshold:    cfg.CPUPressureThreshold,
+		memoryPressureThreshold: cfg.MemoryPressureThreshold,
+		readPressure:            readPressure,
+		now:                     time.Now,
+	}
+
+	if limit.calibrationInterval == 0 {
+		limit.calibrationInterval = defaultCalibrationInterval
+	}
+	if limit.backoffFactor == 0 {
+		limit.backoffFactor = defaultBackoffFactor
+	}
+	if limit.latencyTolerance == 0 {
+		limit.latencyTolerance = defaultLatencyTolerance
+	}
+	if limit.cpuPressureThreshold == 0 {
+		limit.cpuPressureThreshold = defaultCPUPressureThreshold
+	}
+	if limit.memoryPressureThreshold == 0 {
+		limit.memoryPressureThreshold = defaultMemoryPressureThreshold
+	}
+
+	limit.lastCalibration = limit.now()
+
+	return limit
+}
+
+// Current returns the current limit.
+func (l *AdaptiveLimit) Current() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.current
+}
+
+// OnChange registers a callback that is invoked with the new limit whenever the limit changes.
+func (l *AdaptiveLimit) OnChange(callback func(limit int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.onChange = append(l.onChange, callback)
+}
+
+// Observe records a finished request that took the given latency. saturated indicates whether
+// the request had to be queued because the limit has been reached. The limit is recalibrated in
+// case the calibration interval has passed.
+func (l *AdaptiveLimit) Observe(latency time.Duration, saturated bool) {
+	l.mu.Lock()
+
+	l.windowLatency += latency
+	l.windowRequests++
+	l.windowSaturated = l.windowSaturated || saturated
+
+	if l.now().Sub(l.lastCalibration) < l.calibrationInterval {
+		l.mu.Unlock()
+		return
+	}
+
+	previous := l.current
+	l.calibrate()
+	current := l.current
+	callbacks := l.onChange
+
+	l.mu.Unlock()
+
+	if current != previous {
+		for _, callback := range callbacks {
+			callback(current)
+		}
+	}
+}
+
+// calibrate recalculates the limit based on the current window. It must be called with the mutex
+// held.
+func (l *AdaptiveLimit) calibrate() {
+	defer func() {
+		l.lastCalibration = l.now()
+		l.windowLatency = 0
+		l.windowRequests = 0
+		l.windowSaturated = false
+	}()
+
+	if l.overloaded() {
+		l.current = int(math.Floor(float64(l.current) * l.backoffFactor))
+		if l.current < l.minLimit {
+			l.current = l.minLimit
+		}
+		return
+	}
+
+	if l.windowSaturated && l.current < l.maxLimit {
+		l.current++
+	}
+}
+
+func (l *AdaptiveLimit) overloaded() bool {
+	if l.readPressure != nil {
+		// Errors are ignored on purpose: pressure stall information may not be available
+		// on all systems, in which case we rely on latency only.
+		if pressure, err := l.readPressure(); err == nil {
+			if pressure.CPU > l.cpuPressureThreshold || pressure.Memory > l.memoryPressureThreshold {
+				return true
+			}
+		}
+	}
+
+	if l.windowRequests == 0 {
+		return false
+	}
+
+	averageLatency := l.windowLatency / time.Duration(l.windowRequests)
+
+	if l.baselineLatency == 0 || averageLatency < l.baselineLatency {
+		l.baselineLatency = averageLatency
+		return false
+	}
+
+	overloaded := float64(averageLatency) > float64(l.baselineLatency)*l.latencyTolerance
+
+	// Let the baseline drift towards the current latency so that it follows workloads whose
+	// latency changes permanently.
+	l.baselineLatency += (averageLatency - l.baselineLatency) / 16
+
+	return overloaded
+}
@@ -0,0 +1,166 @@
+//go:build !gitaly_test_sha256
+
+package limithandler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/duration"
+)
+
+func TestAdaptiveLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.AdaptiveLimiting{
+		CalibrationInterval: duration.Duration(time.Second),
+		BackoffFactor:       0.5,
+		LatencyTolerance:    2,
+	}
+
+	type observation struct {
+		latency   time.Duration
+		saturated bool
+		pressure  Pressure
+	}
+
+	for _, tc := range []struct {
+		desc           string
+		initial        int
+		min, max       int
+		observations   []observation
+		expectedLimits []int
+	}{
+		{
+			desc:    "unsaturated limit stays constant",
+			initial: 10, max: 20,
+			observations: []observation{
+				{latency: time.Millisecond},
+				{latency: time.Millisecond},
+			},
+			expectedLimits: []int{10, 10},
+		},
+		{
+			desc:    "saturated limit increases additively",
+			initial: 10, max: 20,
+			observations: []observation{
+				{latency: time.Millisecond, saturated: true},
+				{latency: time.Millisecond, saturated: true},
+			},
+			expectedLimits: []int{11, 12},
+		},
+		{
+			desc:    "limit does not exceed maximum",
+			initial: 10, max: 11,
+			observations: []observation{
+				{latency: time.Millisecond, saturated: true},
+				{latency: time.Millisecond, saturated: true},
+			},
+			expectedLimits: []int{11, 11},
+		},
+		{
+			desc:    "latency increase backs off multiplicatively",
+			initial: 10, max: 20,
+			observations: []observation{
+				{latency: time.Millisecond, saturated: true},
+				{latency: 10 * time.Millisecond, saturated: true},
+			},
+			expectedLimits: []int{11, 5},
+		},
+		{
+			desc:    "cpu pressure backs off",
+			initial: 10, max: 20,
+			observations: []observation{
+				{latency: time.Millisecond, saturated: true, pressure: Pressure{CPU: 61}},
+			},
+			expectedLimits: []int{5},
+		},
+		{
+			desc:    "memory pressure backs off",
+			initial: 10, max: 20,
+			observations: []observation{
+				{latency: time.Millisecond, saturated: true, pressure: Pressure{Memory: 21}},
+			},
+			expectedLimits: []int{5},
+		},
+		{
+			desc:    "limit does not fall below minimum",
+			initial: 10, min: 4, max: 20,
+			observations: []observation{
+				{latency: time.Millisecond, pressure: Pressure{CPU: 100}},
+				{latency: time.Millisecond, pressure: Pressure{CPU: 100}},
+			},
+			expectedLimits: []int{5, 4},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var pressure Pressure
+			limit := NewAdaptiveLimit(tc.initial, tc.min, tc.max, cfg, func() (Pressure, error) {
+				return pressure, nil
+			})
+
+			now := time.Now()
+			limit.now = func() time.Time { return now }
+
+			var changes []int
+			limit.OnChange(func(limit int) {
+				changes = append(changes, limit)
+			})
+
+			var expectedChanges []int
+			previous := tc.initial
+			for i, observation := range tc.observations {
+				pressure = observation.pressure
+				now = now.Add(time.Second)
+
+				limit.Observe(observation.latency, observation.saturated)
+				require.Equal(t, tc.expectedLimits[i], limit.Current())
+
+				if tc.expectedLimits[i] != previous {
+					expectedChanges = append(expectedChanges, tc.expectedLimits[i])
+				}
+				previous = tc.expectedLimits[i]
+			}
+
+			require.Equal(t, expectedChanges, changes)
+		})
+	}
+}
+
+func TestAdaptiveLimit_calibrationInterval(t *testing.T) {
+	t.Parallel()
+
+	limit := NewAdaptiveLimit(10, 1, 20, config.AdaptiveLimiting{}, nil)
+
+	now := time.Now()
+	limit.now = func() time.Time { return now }
+
+	limit.Observe(time.Millisecond, true)
+	require.Equal(t, 10, limit.Current())
+
+	now = now.Add(defaultCalibrationInterval)
+
+	limit.Observe(time.Millisecond, false)
+	require.Equal(t, 11, limit.Current())
+}
+
+func TestAdaptiveLimit_pressureError(t *testing.T) {
+	t.Parallel()
+
+	limit := NewAdaptiveLimit(10, 1, 20, config.AdaptiveLimiting{}, func() (Pressure, error) {
+		return Pressure{}, errors.New("not available")
+	})
+
+	now := time.Now()
+	limit.now = func() time.Time { return now.Add(defaultCalibrationInterval) }
+
+	limit.Observe(time.Millisecond, true)
+	require.Equal(t, 11, limit.Current())
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
@@ -12,6 +15,7 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
 	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+	"gitlab.com/gitlab-org/labkit/log"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
@@ -27,27 +31,65 @@ type QueueTickerCreator func() helper.Ticker
 
 // ConcurrencyLimiter contains rate limiter state
 type ConcurrencyLimiter struct {
-	semaphores map[string]*semaphoreReference
+	// state holds the per-key semaphores and the number of queued operations. It is shared
+	// with the limiter replacing this one when the middleware is reloaded.
+	state *limiterState
 	// maxPerKey is the maximum number of concurrent operations
-	// per lockKey
+	// per lockKey. It must be accessed atomically as it can be changed
+	// via SetMaxPerKey.
 	maxPerKey int64
-	// queued tracks the current number of operations waiting to be picked up
-	queued int64
+	// adaptiveLimit, if set, dynamically adjusts the maximum number of concurrent operations
+	// per lockKey. maxPerKey is only used to determine whether limiting is enabled at all.
+	adaptiveLimit *AdaptiveLimit
 	// queuedLimit is the maximum number of operations allowed to wait in a queued state.
 	// subsequent incoming operations will fail with an error.
-	queuedLimit         int64
+	queuedLimit int64
+	// priorities assigns requests to priority classes. If unset, queued requests are
+	// admitted in FIFO order.
+	priorities          *priorityClassifier
 	monitor             ConcurrencyMonitor
-	mux                 sync.RWMutex
 	maxWaitTickerGetter QueueTickerCreator
 }
 
+// limiterState is the per-key state of a ConcurrencyLimiter. When the middleware is reloaded, the
+// limiter replacing the previous one takes over its state so that operations admitted or queued by
+// the previous limiter keep counting against the limits of the new one.
+type limiterState struct {
+	mux        sync.RWMutex
+	semaphores map[string]*semaphoreReference
+	// queued tracks the current number of operations waiting to be picked up
+	queued int64
+	// limit returns the per-key limit of the limiter currently owning the state.
+	limit func() int64
+}
+
 type semaphoreReference struct {
-	tokens    chan struct{}
+	// limit returns the maximum number of concurrent holders of the semaphore. It is evaluated
+	// every time the semaphore is acquired or released so that the limit may change over time.
+	limit func() int64
+
+	mu       sync.Mutex
+	inFlight int64
+	// waiters is the queue of callers waiting for the semaphore.
+	waiters *priorityQueue
+
 	count     int
 	newTicker QueueTickerCreator
 }
 
-func (sem *semaphoreReference) acquire(ctx context.Context) error {
+// acquire acquires the semaphore. If the caller has to wait, it is queued with the given priority.
+// It returns whether the caller had to wait for the semaphore because the limit has been reached.
+func (sem *semaphoreReference) acquire(ctx context.Context, priority requestPriority) (bool, error) {
+	sem.mu.Lock()
+	if sem.waiters.len() == 0 && sem.inFlight < sem.limit() {
+		sem.inFlight++
+		sem.mu.Unlock()
+		return false, nil
+	}
+
+	waiter := sem.waiters.push(priority)
+	sem.mu.Unlock()
+
 	var ticker helper.Ticker
 
 	if sem.newTicker != nil {
@@ -59,39 +101,96 @@ func (sem *semaphoreReference) acquire(ctx context.Context) error {
 	defer ticker.Stop()
 	ticker.Reset()
 
+	var err error
 	select {
-	case sem.tokens <- struct{}{}:
-		return nil
+	case <-waiter.acquired:
+		return true, nil
 	case <-ticker.C():
-		return ErrMaxQueueTime
+		err = ErrMaxQueueTime
 	case <-ctx.Done():
-		return ctx.Err()
+		err = ctx.Err()
+	}
+
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+
+	select {
+	case <-waiter.acquired:
+		// The semaphore has been handed to us concurrently. We're not going to use it
+		// though, so we need to pass it on to the next waiter.
+		sem.inFlight--
+		sem.admit()
+	default:
+		sem.waiters.remove(waiter)
 	}
+
+	return true, err
+}
+
+func (sem *semaphoreReference) release() {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+
+	sem.inFlight--
+	sem.admit()
+}
+
+// wake admits as many waiters as the current limit permits. It needs to be called when the limit
+// has been raised.
+func (sem *semaphoreReference) wake() {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+
+	sem.admit()
 }
 
-func (sem *semaphoreReference) release() { <-sem.tokens }
+// setLimit replaces the function returning the limit of the semaphore and admits waiters up to the
+// new limit.
+func (sem *semaphoreReference) setLimit(limit func() int64) {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+
+	sem.limit = limit
+	sem.admit()
+}
+
+// admit hands the semaphore to waiters in order of their priority until the limit has been
+// reached. It must be called with the mutex held.
+func (sem *semaphoreReference) admit() {
+	for sem.waiters.len() > 0 && sem.inFlight < sem.limit() {
+		waiter := sem.waiters.pop()
+		sem.inFlight++
+		close(waiter.acquired)
+	}
+}
 
 // Lazy create a semaphore for the given key
 func (c *ConcurrencyLimiter) getSemaphore(lockKey string) *semaphoreReference {
-	c.mux.Lock()
-	defer c.mux.Unlock()
+	c.state.mux.Lock()
+	defer c.state.mux.Unlock()
+
+	if c.state.semaphores[lockKey] == nil {
+		var maxStarvation time.Duration
+		if c.priorities != nil {
+			maxStarvation = c.priorities.maxStarvation
+		}
 
-	if c.semaphores[lockKey] == nil {
-		c.semaphores[lockKey] = &semaphoreReference{
-			tokens:    make(chan struct{}, c.maxPerKey),
+		c.state.semaphores[lockKey] = &semaphoreReference{
+			limit:     c.state.limit,
+			waiters:   newPriorityQueue(maxStarvation),
 			newTicker: c.maxWaitTickerGetter,
 		}
 	}
 
-	c.semaphores[lockKey].count++
-	return c.semaphores[lockKey]
+	c.state.semaphores[lockKey].count++
+	return c.state.semaphores[lockKey]
 }
 
 func (c *ConcurrencyLimiter) putSemaphore(lockKey string) {
-	c.mux.Lock()
-	defer c.mux.Unlock()
+	c.state.mux.Lock()
+	defer c.state.mux.Unlock()
 
-	ref := c.semaphores[lockKey]
+	ref := c.state.semaphores[lockKey]
 	if ref == nil {
 		panic("semaphore should be in the map")
 	}
@@ -102,28 +201,86 @@ func (c *ConcurrencyLimiter) putSemaphore(lockKey string) {
 
 	ref.count--
 	if ref.count == 0 {
-		delete(c.semaphores, lockKey)
+		delete(c.state.semaphores, lockKey)
+	}
+}
+
+func (c *ConcurrencyLimiter) currentLimit() int64 {
+	if c.adaptiveLimit != nil {
+		return int64(c.adaptiveLimit.Current())
+	}
+
+	if maxPerKey := atomic.LoadInt64(&c.maxPerKey); maxPerKey > 0 {
+		return maxPerKey
 	}
+
+	// Limiting has been disabled via SetMaxPerKey, so all waiters should be admitted.
+	return math.MaxInt64
+}
+
+// SetMaxPerKey changes the maximum number of concurrent operations per lockKey. Operations which
+// are queued are admitted immediately if the limit has been raised. A limit of zero disables
+// limiting. The limit of adaptive limiters cannot be changed.
+func (c *ConcurrencyLimiter) SetMaxPerKey(perKeyLimit int) {
+	if c.adaptiveLimit != nil {
+		return
+	}
+
+	atomic.StoreInt64(&c.maxPerKey, int64(perKeyLimit))
+	c.wakeSemaphores()
+}
+
+// wakeSemaphores admits waiters of all semaphores up to the current limit.
+func (c *ConcurrencyLimiter) wakeSemaphores() {
+	c.state.mux.RLock()
+	defer c.state.mux.RUnlock()
+
+	for _, sem := range c.state.semaphores {
+		sem.wake()
+	}
+}
+
+// takeOver takes over the per-key state of the given limiter, which is being replaced by this one.
+// Operations which have been admitted by the previous limiter keep counting against the per-key
+// limit and the queue size of this limiter until they are done, and operations queued by the
+// previous limiter are admitted according to the limit of this one. Limiters of other kinds don't
+// have any state to take over. takeOver must be called before the limiter is used.
+func (c *ConcurrencyLimiter) takeOver(previous Limiter) {
+	previousLimiter, ok := previous.(*ConcurrencyLimiter)
+	if !ok || previousLimiter == c {
+		return
+	}
+
+	state := previousLimiter.state
+	state.mux.Lock()
+	defer state.mux.Unlock()
+
+	state.limit = c.currentLimit
+	for _, sem := range state.semaphores {
+		sem.setLimit(c.currentLimit)
+	}
+
+	c.state = state
 }
 
 func (c *ConcurrencyLimiter) countSemaphores() int {
-	c.mux.RLock()
-	defer c.mux.RUnlock()
+	c.state.mux.RLock()
+	defer c.state.mux.RUnlock()
 
-	return len(c.semaphores)
+	return len(c.state.semaphores)
 }
 
 func (c *ConcurrencyLimiter) queueInc(ctx context.Context) error {
-	c.mux.Lock()
-	defer c.mux.Unlock()
+	c.state.mux.Lock()
+	defer c.state.mux.Unlock()
 
 	if c.queuedLimit > 0 &&
-		c.queued >= c.queuedLimit {
+		c.state.queued >= c.queuedLimit {
 		c.monitor.Dropped(ctx, "max_size")
 		return ErrMaxQueueSize
 	}
 
-	c.queued++
+	c.state.queued++
 	return nil
 }
 
@@ -132,20 +289,23 @@ func (c *ConcurrencyLimiter) queueDec(decremented *bool) {
 		return
 	}
 	*decremented = true
-	c.mux.Lock()
-	defer c.mux.Unlock()
+	c.state.mux.Lock()
+	defer c.state.mux.Unlock()
 
-	c.queued--
+	c.state.queued--
 }
 
 // Limit will limit the concurrency of f
 func (c *ConcurrencyLimiter) Limit(ctx context.Context, lockKey string, f LimitedFunc) (interface{}, error) {
-	if c.maxPerKey <= 0 {
+	if atomic.LoadInt64(&c.maxPerKey) <= 0 {
 		return f()
 	}
 
 	var decremented bool
 
+	priority := c.priorities.classify(ctx)
+	ctx = withPriority(ctx, priority)
+
 	log := ctxlogrus.Extract(ctx).WithField("limiting_key", lockKey)
 	if err := c.queueInc(ctx); err != nil {
 		if errors.Is(err, ErrMaxQueueSize) {
@@ -178,9 +338,13 @@ func (c *ConcurrencyLimiter) Limit(ctx context.Context, lockKey string, f Limite
 	sem := c.getSemaphore(lockKey)
 	defer c.putSemaphore(lockKey)
 
-	err := sem.acquire(ctx)
+	saturated, err := sem.acquire(ctx, priority)
 	c.queueDec(&decremented)
 
+	if observer, ok := c.monitor.(queueWaitObserver); ok {
+		observer.observeQueueWait(ctx, time.Since(start))
+	}
+
 	c.monitor.Dequeued(ctx)
 	if err != nil {
 		if errors.Is(err, ErrMaxQueueTime) {
@@ -212,6 +376,13 @@ func (c *ConcurrencyLimiter) Limit(ctx context.Context, lockKey string, f Limite
 	c.monitor.Enter(ctx, time.Since(start))
 	defer c.monitor.Exit(ctx)
 
+	if c.adaptiveLimit != nil {
+		enter := time.Now()
+		defer func() {
+			c.adaptiveLimit.Observe(time.Since(enter), saturated)
+		}()
+	}
+
 	return f()
 }
 
@@ -221,13 +392,30 @@ func NewConcurrencyLimiter(perKeyLimit, globalLimit int, maxWaitTickerGetter Que
 		monitor = NewNoopConcurrencyMonitor()
 	}
 
-	return &ConcurrencyLimiter{
-		semaphores:          make(map[string]*semaphoreReference),
+	limiter := &ConcurrencyLimiter{
 		maxPerKey:           int64(perKeyLimit),
 		queuedLimit:         int64(globalLimit),
 		monitor:             monitor,
 		maxWaitTickerGetter: maxWaitTickerGetter,
 	}
+	limiter.state = &limiterState{
+		semaphores: make(map[string]*semaphoreReference),
+		limit:      limiter.currentLimit,
+	}
+
+	return limiter
+}
+
+// NewAdaptiveConcurrencyLimiter creates a new concurrency limiter whose per-key limit is adjusted
+// dynamically by the given adaptive limit.
+func NewAdaptiveConcurrencyLimiter(adaptiveLimit *AdaptiveLimit, globalLimit int, maxWaitTickerGetter QueueTickerCreator, monitor ConcurrencyMonitor) *ConcurrencyLimiter {
+	limiter := NewConcurrencyLimiter(adaptiveLimit.Current(), globalLimit, maxWaitTickerGetter, monitor)
+	limiter.adaptiveLimit = adaptiveLimit
+	adaptiveLimit.OnChange(func(int) {
+		limiter.wakeSemaphores()
+	})
+
+	return limiter
 }
 
 // WithConcurrencyLimiters sets up middleware to limit the concurrency of
@@ -261,19 +449,57 @@ func WithConcurrencyLimiters(cfg config.Cfg, middleware *LimiterMiddleware) {
 		},
 		[]string{"system", "grpc_service", "grpc_method"},
 	)
+	queueWaitMetric := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "gitaly",
+			Subsystem: "concurrency_limiting",
+			Name:      "queue_wait_seconds",
+			Help:      "Histogram of time calls have been queued per priority class (in seconds)",
+			Buckets:   cfg.Prometheus.GRPCLatencyBuckets,
+		},
+		[]string{"system", "grpc_service", "grpc_method", "priority"},
+	)
+	limitMetric := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "gitaly",
+			Subsystem: "concurrency_limiting",
+			Name:      "current_limit",
+			Help:      "Gauge of the current per-key concurrency limit",
+		},
+		[]string{"system", "grpc_service", "grpc_method"},
+	)
 
 	middleware.collect = func(metrics chan<- prometheus.Metric) {
 		acquiringSecondsMetric.Collect(metrics)
 		inProgressMetric.Collect(metrics)
 		queuedMetric.Collect(metrics)
+		limitMetric.Collect(metrics)
+		queueWaitMetric.Collect(metrics)
 	}
 
+	priorities := newPriorityClassifier(cfg.RequestPriority)
+
 	result := make(map[string]Limiter)
 
 	newTickerFunc := func() helper.Ticker {
 		return helper.NewManualTicker()
 	}
 
+	var readPressure PressureReader
+	for _, limit := range cfg.Concurrency {
+		if !limit.Adaptive {
+			continue
+		}
+
+		readPressure = newPressureReader(cfg, os.Getpid())
+		if _, err := readPressure(); err != nil {
+			log.WithError(err).Info("pressure stall information not available, adaptive limits only consider latency")
+			readPressure = nil
+		}
+
+		break
+	}
+
 	for _, limit := range cfg.Concurrency {
 		if limit.MaxQueueWait > 0 {
 			limit := limit
@@ -282,18 +508,56 @@ func WithConcurrencyLimiters(cfg config.Cfg, middleware *LimiterMiddleware) {
 			}
 		}
 
-		result[limit.RPC] = NewConcurrencyLimiter(
-			limit.MaxPerRepo,
+		monitor := newPerRPCPromMonitor("gitaly", limit.RPC, queuedMetric, inProgressMetric,
+			acquiringSecondsMetric, middleware.requestsDroppedMetric)
+
+		serviceName, methodName := splitMethodName(limit.RPC)
+		if priorities != nil {
+			monitor.queueWaitMetric = queueWaitMetric.MustCurryWith(prometheus.Labels{
+				"system":       "gitaly",
+				"grpc_service": serviceName,
+				"grpc_method":  methodName,
+			})
+		}
+
+		currentLimitMetric := limitMetric.WithLabelValues("gitaly", serviceName, methodName)
+		currentLimitMetric.Set(float64(limit.MaxPerRepo))
+
+		if !limit.Adaptive {
+			limiter := NewConcurrencyLimiter(
+				limit.MaxPerRepo,
+				limit.MaxQueueSize,
+				newTickerFunc,
+				monitor,
+			)
+			limiter.priorities = priorities
+
+			result[limit.RPC] = limiter
+			continue
+		}
+
+		adaptiveLimit := NewAdaptiveLimit(limit.MaxPerRepo, limit.MinLimit, limit.MaxLimit, cfg.AdaptiveLimiting, readPressure)
+		adaptiveLimit.OnChange(func(limit int) {
+			currentLimitMetric.Set(float64(limit))
+		})
+
+		limiter := NewAdaptiveConcurrencyLimiter(
+			adaptiveLimit,
 			limit.MaxQueueSize,
 			newTickerFunc,
-			newPerRPCPromMonitor("gitaly", limit.RPC, queuedMetric, inProgressMetric,
-				acquiringSecondsMetric, middleware.requestsDroppedMetric),
+			monitor,
 		)
+		limiter.priorities = priorities
+
+		result[limit.RPC] = limiter
 	}
 
 	// Set default for ReplicateRepository.
 	replicateRepositoryFullMethod := "/gitaly.RepositoryService/ReplicateRepository"
 	if _, ok := result[replicateRepositoryFullMethod]; !ok {
+		serviceName, methodName := splitMethodName(replicateRepositoryFullMethod)
+		limitMetric.WithLabelValues("gitaly", serviceName, methodName).Set(1)
+
 		result[replicateRepositoryFullMethod] = NewConcurrencyLimiter(
 			1,
 			0,
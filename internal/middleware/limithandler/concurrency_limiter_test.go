@@ -28,6 +28,7 @@ type counter struct {
 	exit        int
 	droppedSize int
 	droppedTime int
+	queueWaits  int
 }
 
 func (c *counter) up() {
@@ -77,6 +78,12 @@ func (c *counter) Exit(ctx context.Context) {
 	c.exit++
 }
 
+func (c *counter) observeQueueWait(ctx context.Context, queueWait time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.queueWaits++
+}
+
 func (c *counter) Dropped(ctx context.Context, reason string) {
 	switch reason {
 	case "max_time":
@@ -380,6 +387,94 @@ func TestLimitConcurrency_queueWaitTime(t *testing.T) {
 	assert.Equal(t, durationpb.New(0), limitErr.RetryAfter)
 
 	assert.Equal(t, monitor.droppedTime, 1)
+	// The queue wait is observed both for the admitted and for the dropped request.
+	assert.Equal(t, 2, monitor.queueWaits)
 	close(ch)
 	wg.Wait()
 }
+
+func TestSemaphoreReference_changingLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+
+	var mu sync.Mutex
+	limit := int64(1)
+
+	sem := &semaphoreReference{
+		limit: func() int64 {
+			mu.Lock()
+			defer mu.Unlock()
+			return limit
+		},
+		waiters: newPriorityQueue(0),
+	}
+
+	saturated, err := sem.acquire(ctx, fifoPriority)
+	require.NoError(t, err)
+	require.False(t, saturated)
+
+	acquiredCh := make(chan bool)
+	go func() {
+		saturated, err := sem.acquire(ctx, fifoPriority)
+		assert.NoError(t, err)
+		acquiredCh <- saturated
+	}()
+
+	select {
+	case <-acquiredCh:
+		require.FailNow(t, "semaphore acquired beyond its limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	mu.Lock()
+	limit = 2
+	mu.Unlock()
+	sem.wake()
+
+	require.True(t, <-acquiredCh)
+
+	sem.release()
+	sem.release()
+	require.Zero(t, sem.inFlight)
+}
+
+func TestConcurrencyLimiter_setMaxPerKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+
+	limiter := NewConcurrencyLimiter(1, 0, nil, nil)
+
+	blockCh := make(chan struct{})
+	enteredCh := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := limiter.Limit(ctx, "key", func() (interface{}, error) {
+				enteredCh <- struct{}{}
+				<-blockCh
+				return nil, nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+
+	<-enteredCh
+	select {
+	case <-enteredCh:
+		require.FailNow(t, "limit exceeded")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	limiter.SetMaxPerKey(3)
+	<-enteredCh
+	<-enteredCh
+
+	close(blockCh)
+	wg.Wait()
+}
@@ -2,10 +2,12 @@ package limithandler
 
 import (
 	"context"
+	"sync"
 
 	grpcmwtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/middleware/metadatahandler"
 	"google.golang.org/grpc"
 )
 
@@ -14,8 +16,28 @@ type GetLockKey func(context.Context) string
 
 // LimitConcurrencyByRepo implements GetLockKey by using the repository path as lock.
 func LimitConcurrencyByRepo(ctx context.Context) string {
+	return tagValue(ctx, "grpc.request.repoPath")
+}
+
+// LimitByUser implements GetLockKey by using the GitLab user ID as lock.
+func LimitByUser(ctx context.Context) string {
+	return tagValue(ctx, metadatahandler.UserIDKey)
+}
+
+// LimitByRemoteIP implements GetLockKey by using the IP address of the client that
+// talks to GitLab as lock.
+func LimitByRemoteIP(ctx context.Context) string {
+	return tagValue(ctx, metadatahandler.RemoteIPKey)
+}
+
+// LimitByProject implements GetLockKey by using the GitLab project path as lock.
+func LimitByProject(ctx context.Context) string {
+	return tagValue(ctx, "grpc.request.glProjectPath")
+}
+
+func tagValue(ctx context.Context, key string) string {
 	tags := grpcmwtags.Extract(ctx)
-	ctxValue := tags.Values()["grpc.request.repoPath"]
+	ctxValue := tags.Values()[key]
 	if ctxValue == nil {
 		return ""
 	}
@@ -38,17 +60,25 @@ type LimitedFunc func() (resp interface{}, err error)
 
 // LimiterMiddleware contains rate limiter state
 type LimiterMiddleware struct {
-	methodLimiters        map[string]Limiter
-	getLockKey            GetLockKey
+	// mu protects the limiter state which is replaced when the middleware is reloaded.
+	mu             sync.RWMutex
+	methodLimiters map[string]Limiter
+	getLockKey     GetLockKey
+	// methodLockKeys overrides getLockKey for specific methods.
+	methodLockKeys        map[string]GetLockKey
 	requestsDroppedMetric *prometheus.CounterVec
 	collect               func(metrics chan<- prometheus.Metric)
+	// stop stops background goroutines started by the setup function, if any.
+	stop            func()
+	setupMiddleware SetupFunc
 }
 
 // New creates a new middleware that limits requests. SetupFunc sets up the
 // middlware with a specific kind of limiter.
 func New(cfg config.Cfg, getLockKey GetLockKey, setupMiddleware SetupFunc) *LimiterMiddleware {
 	middleware := &LimiterMiddleware{
-		getLockKey: getLockKey,
+		getLockKey:      getLockKey,
+		setupMiddleware: setupMiddleware,
 		requestsDroppedMetric: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "gitaly_requests_dropped_total",
@@ -76,20 +106,83 @@ func (c *LimiterMiddleware) Describe(descs chan<- *prometheus.Desc) {
 // Collect is used to collect Prometheus metrics.
 func (c *LimiterMiddleware) Collect(metrics chan<- prometheus.Metric) {
 	c.requestsDroppedMetric.Collect(metrics)
-	if c.collect != nil {
-		c.collect(metrics)
+
+	c.mu.RLock()
+	collect := c.collect
+	c.mu.RUnlock()
+
+	if collect != nil {
+		collect(metrics)
+	}
+}
+
+// reloadableLimiter is implemented by limiters which can take over the per-key state of the limiter
+// they replace when the middleware is reloaded.
+type reloadableLimiter interface {
+	takeOver(previous Limiter)
+}
+
+// Reload sets up the limiters anew from the given configuration. Requests which are already being
+// limited keep using the previous limiters, while new requests use the reloaded ones. If a method
+// is limited by the same kind of limiter before and after the reload, the reloaded limiter takes
+// over the per-key state of the previous one: requests which are in flight or queued keep counting
+// against the concurrency limits and queue sizes, and rate limiting buckets keep their tokens.
+// Metrics of requests which have been admitted before the reload are reported to the previous
+// metrics, which are not collected anymore.
+func (c *LimiterMiddleware) Reload(cfg config.Cfg) {
+	reloaded := &LimiterMiddleware{
+		getLockKey:            c.getLockKey,
+		requestsDroppedMetric: c.requestsDroppedMetric,
+	}
+	c.setupMiddleware(cfg, reloaded)
+
+	c.mu.Lock()
+	for fullMethod, limiter := range reloaded.methodLimiters {
+		previous, ok := c.methodLimiters[fullMethod]
+		if !ok {
+			continue
+		}
+
+		if limiter, ok := limiter.(reloadableLimiter); ok {
+			limiter.takeOver(previous)
+		}
+	}
+
+	c.methodLimiters = reloaded.methodLimiters
+	c.methodLockKeys = reloaded.methodLockKeys
+	c.collect = reloaded.collect
+	stop := c.stop
+	c.stop = reloaded.stop
+	c.mu.Unlock()
+
+	if stop != nil {
+		stop()
 	}
 }
 
+// limiter returns the limiter and the lock key for the given method. The limiter is nil if the
+// method is not limited.
+func (c *LimiterMiddleware) limiter(ctx context.Context, fullMethod string) (Limiter, string) {
+	c.mu.RLock()
+	limiter := c.methodLimiters[fullMethod]
+	getLockKey, ok := c.methodLockKeys[fullMethod]
+	c.mu.RUnlock()
+
+	if !ok {
+		getLockKey = c.getLockKey
+	}
+
+	return limiter, getLockKey(ctx)
+}
+
 // UnaryInterceptor returns a Unary Interceptor
 func (c *LimiterMiddleware) UnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		lockKey := c.getLockKey(ctx)
+		limiter, lockKey := c.limiter(ctx, info.FullMethod)
 		if lockKey == "" {
 			return handler(ctx, req)
 		}
 
-		limiter := c.methodLimiters[info.FullMethod]
 		if limiter == nil {
 			// No concurrency limiting
 			return handler(ctx, req)
@@ -133,12 +226,11 @@ func (w *wrappedStream) RecvMsg(m interface{}) error {
 
 	ctx := w.Context()
 
-	lockKey := w.limiterMiddleware.getLockKey(ctx)
+	limiter, lockKey := w.limiterMiddleware.limiter(ctx, w.info.FullMethod)
 	if lockKey == "" {
 		return nil
 	}
 
-	limiter := w.limiterMiddleware.methodLimiters[w.info.FullMethod]
 	if limiter == nil {
 		// No concurrency limiting
 		return nil
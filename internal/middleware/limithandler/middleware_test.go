@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	grpcmwtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	promtest "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,11 +19,13 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/duration"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/middleware/limithandler"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/middleware/metadatahandler"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
 	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/grpc_testing"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -78,6 +81,78 @@ func TestUnaryLimitHandler(t *testing.T) {
 	wg.Wait()
 }
 
+func TestUnaryLimitHandler_reload(t *testing.T) {
+	t.Parallel()
+
+	s := &server{blockCh: make(chan struct{})}
+
+	methodName := "/grpc.testing.TestService/UnaryCall"
+	cfg := config.Cfg{
+		Concurrency: []config.Concurrency{
+			{RPC: methodName, MaxPerRepo: 1},
+		},
+	}
+
+	lh := limithandler.New(cfg, fixedLockKey, limithandler.WithConcurrencyLimiters)
+	interceptor := lh.UnaryInterceptor()
+	srv, serverSocketPath := runServer(t, s, grpc.UnaryInterceptor(interceptor))
+	defer srv.Stop()
+
+	client, conn := newClient(t, serverSocketPath)
+	defer conn.Close()
+	ctx := testhelper.Context(t)
+
+	var wg sync.WaitGroup
+	call := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.UnaryCall(ctx, &grpc_testing.SimpleRequest{})
+			assert.NoError(t, err)
+		}()
+	}
+
+	call()
+	require.Eventually(t, func() bool {
+		return s.getRequestCount() == 1
+	}, 10*time.Second, time.Millisecond)
+
+	// The request admitted before the reload keeps counting against the raised limit, so only
+	// one more request can run concurrently while the other one is queued.
+	cfg.Concurrency[0].MaxPerRepo = 2
+	lh.Reload(cfg)
+
+	call()
+	call()
+	require.Eventually(t, func() bool {
+		return s.getRequestCount() == 2
+	}, 10*time.Second, time.Millisecond)
+	require.Never(t, func() bool {
+		return s.getRequestCount() > 2
+	}, 50*time.Millisecond, time.Millisecond)
+
+	// Raising the limit once more admits the request which has been queued before the reload.
+	cfg.Concurrency[0].MaxPerRepo = 3
+	lh.Reload(cfg)
+
+	require.Eventually(t, func() bool {
+		return s.getRequestCount() == 3
+	}, 10*time.Second, time.Millisecond)
+
+	// Without any limits, requests are not limited at all anymore.
+	lh.Reload(config.Cfg{})
+
+	for i := 0; i < 5; i++ {
+		call()
+	}
+	require.Eventually(t, func() bool {
+		return s.getRequestCount() == 8
+	}, 10*time.Second, time.Millisecond)
+
+	close(s.blockCh)
+	wg.Wait()
+}
+
 func TestStreamLimitHandler(t *testing.T) {
 	t.Parallel()
 
@@ -470,7 +545,11 @@ func TestConcurrencyLimitHandlerMetrics(t *testing.T) {
 		}
 	}
 
-	expectedMetrics := `# HELP gitaly_concurrency_limiting_in_progress Gauge of number of concurrent in-progress calls
+	expectedMetrics := `# HELP gitaly_concurrency_limiting_current_limit Gauge of the current per-key concurrency limit
+# TYPE gitaly_concurrency_limiting_current_limit gauge
+gitaly_concurrency_limiting_current_limit{grpc_method="ReplicateRepository",grpc_service="gitaly.RepositoryService",system="gitaly"} 1
+gitaly_concurrency_limiting_current_limit{grpc_method="UnaryCall",grpc_service="grpc.testing.TestService",system="gitaly"} 1
+# HELP gitaly_concurrency_limiting_in_progress Gauge of number of concurrent in-progress calls
 # TYPE gitaly_concurrency_limiting_in_progress gauge
 gitaly_concurrency_limiting_in_progress{grpc_method="ReplicateRepository",grpc_service="gitaly.RepositoryService",system="gitaly"} 0
 gitaly_concurrency_limiting_in_progress{grpc_method="UnaryCall",grpc_service="grpc.testing.TestService",system="gitaly"} 1
@@ -483,6 +562,7 @@ gitaly_concurrency_limiting_queued{grpc_method="UnaryCall",grpc_service="grpc.te
 gitaly_requests_dropped_total{grpc_method="UnaryCall",grpc_service="grpc.testing.TestService",reason="max_size",system="gitaly"} 9
 `
 	assert.NoError(t, promtest.CollectAndCompare(lh, bytes.NewBufferString(expectedMetrics),
+		"gitaly_concurrency_limiting_current_limit",
 		"gitaly_concurrency_limiting_queued",
 		"gitaly_requests_dropped_total",
 		"gitaly_concurrency_limiting_in_progress"))
@@ -577,6 +657,114 @@ gitaly_requests_dropped_total{grpc_method="UnaryCall",grpc_service="grpc.testing
 		assert.NoError(t, promtest.CollectAndCompare(lh, bytes.NewBufferString(expectedMetrics),
 			"gitaly_requests_dropped_total"))
 	})
+
+	t.Run("dry run", func(t *testing.T) {
+		s := &server{blockCh: make(chan struct{})}
+
+		cfg := config.Cfg{
+			RateLimiting: []config.RateLimiting{
+				{RPC: methodName, Interval: duration.Duration(1 * time.Hour), Burst: 1, DryRun: true},
+			},
+		}
+
+		lh := limithandler.New(cfg, fixedLockKey, limithandler.WithRateLimiters(ctx))
+		interceptor := lh.UnaryInterceptor()
+		srv, serverSocketPath := runServer(t, s, grpc.UnaryInterceptor(interceptor))
+		defer srv.Stop()
+
+		client, conn := newClient(t, serverSocketPath)
+		defer testhelper.MustClose(t, conn)
+
+		close(s.blockCh)
+		for i := 0; i < 10; i++ {
+			_, err := client.UnaryCall(ctx, &grpc_testing.SimpleRequest{})
+			require.NoError(t, err)
+		}
+
+		expectedMetrics := `# HELP gitaly_requests_rate_limit_dry_run_total Number of requests that would have been dropped by rate limiters in dry-run mode
+# TYPE gitaly_requests_rate_limit_dry_run_total counter
+gitaly_requests_rate_limit_dry_run_total{grpc_method="UnaryCall",grpc_service="grpc.testing.TestService",system="gitaly"} 9
+`
+		assert.NoError(t, promtest.CollectAndCompare(lh, bytes.NewBufferString(expectedMetrics),
+			"gitaly_requests_dropped_total",
+			"gitaly_requests_rate_limit_dry_run_total"))
+	})
+
+	for _, tc := range []struct {
+		desc     string
+		key      config.RateLimitingKey
+		metadata func(value string) metadata.MD
+	}{
+		{
+			desc: "user",
+			key:  config.RateLimitingKeyUser,
+			metadata: func(value string) metadata.MD {
+				return metadata.Pairs("user_id", value)
+			},
+		},
+		{
+			desc: "remote IP",
+			key:  config.RateLimitingKeyRemoteIP,
+			metadata: func(value string) metadata.MD {
+				return metadata.Pairs("remote_ip", value)
+			},
+		},
+		{
+			desc: "project",
+			key:  config.RateLimitingKeyProject,
+			metadata: func(value string) metadata.MD {
+				return metadata.Pairs("gl_project_path", value)
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run("limits by "+tc.desc, func(t *testing.T) {
+			s := &server{blockCh: make(chan struct{})}
+
+			cfg := config.Cfg{
+				RateLimiting: []config.RateLimiting{
+					{RPC: methodName, Interval: duration.Duration(1 * time.Hour), Burst: 1, Key: tc.key},
+				},
+			}
+
+			lh := limithandler.New(cfg, fixedLockKey, limithandler.WithRateLimiters(ctx))
+			srv, serverSocketPath := runServer(t, s, grpc.ChainUnaryInterceptor(
+				grpcmwtags.UnaryServerInterceptor(),
+				metadatahandler.UnaryInterceptor,
+				// The test service's requests don't carry a repository, so we inject the
+				// project path into the tags directly.
+				func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+					if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("gl_project_path")) == 1 {
+						grpcmwtags.Extract(ctx).Set("grpc.request.glProjectPath", md.Get("gl_project_path")[0])
+					}
+					return handler(ctx, req)
+				},
+				lh.UnaryInterceptor(),
+			))
+			defer srv.Stop()
+
+			client, conn := newClient(t, serverSocketPath)
+			defer testhelper.MustClose(t, conn)
+
+			close(s.blockCh)
+
+			_, err := client.UnaryCall(metadata.NewOutgoingContext(ctx, tc.metadata("a")), &grpc_testing.SimpleRequest{})
+			require.NoError(t, err)
+
+			_, err = client.UnaryCall(metadata.NewOutgoingContext(ctx, tc.metadata("a")), &grpc_testing.SimpleRequest{})
+			testhelper.RequireGrpcCode(t, err, codes.Unavailable)
+
+			_, err = client.UnaryCall(metadata.NewOutgoingContext(ctx, tc.metadata("b")), &grpc_testing.SimpleRequest{})
+			require.NoError(t, err)
+
+			// Requests without the key are not limited at all.
+			for i := 0; i < 3; i++ {
+				_, err = client.UnaryCall(ctx, &grpc_testing.SimpleRequest{})
+				require.NoError(t, err)
+			}
+		})
+	}
 }
 
 func runServer(t *testing.T, s grpc_testing.TestServiceServer, opt ...grpc.ServerOption) (*grpc.Server, string) {
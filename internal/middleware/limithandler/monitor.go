@@ -28,6 +28,12 @@ func (c *noopConcurrencyMonitor) Enter(ctx context.Context, acquireTime time.Dur
 func (c *noopConcurrencyMonitor) Exit(ctx context.Context)                             {}
 func (c *noopConcurrencyMonitor) Dropped(ctx context.Context, reason string)           {}
 
+// queueWaitObserver is implemented by monitors which track the time requests have been queued,
+// regardless of whether they have been admitted or dropped afterwards.
+type queueWaitObserver interface {
+	observeQueueWait(ctx context.Context, queueWait time.Duration)
+}
+
 // NewNoopConcurrencyMonitor returns a noopConcurrencyMonitor
 func NewNoopConcurrencyMonitor() ConcurrencyMonitor {
 	return &noopConcurrencyMonitor{}
@@ -41,6 +47,8 @@ type PromMonitor struct {
 	inProgressMetric       prometheus.Gauge
 	acquiringSecondsMetric prometheus.Observer
 	requestsDroppedMetric  *prometheus.CounterVec
+	// queueWaitMetric, if set, tracks the time requests have been queued per priority class.
+	queueWaitMetric prometheus.ObserverVec
 
 	acquiringSecondsHistogramVec *prometheus.HistogramVec
 }
@@ -90,6 +98,14 @@ func (p *PromMonitor) Enter(ctx context.Context, acquireTime time.Duration) {
 	p.acquiringSecondsMetric.Observe(acquireTime.Seconds())
 }
 
+// observeQueueWait is called when a request has left the queue, either because it has been
+// admitted or because it has been dropped while waiting.
+func (p *PromMonitor) observeQueueWait(ctx context.Context, queueWait time.Duration) {
+	if p.queueWaitMetric != nil {
+		p.queueWaitMetric.WithLabelValues(priorityFromContext(ctx)).Observe(queueWait.Seconds())
+	}
+}
+
 // Exit is called when a request has finished processing
 func (p *PromMonitor) Exit(ctx context.Context) {
 	p.inProgressMetric.Dec()
@@ -0,0 +1,103 @@
+package limithandler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+)
+
+// Pressure is the resource pressure as reported by the kernel's pressure stall information (PSI).
+// Values are the percentage of time in which at least one task was stalled waiting for the
+// resource, averaged over the last 10 seconds.
+type Pressure struct {
+	CPU    float64
+	Memory float64
+}
+
+// PressureReader reads the current resource pressure.
+type PressureReader func() (Pressure, error)
+
+// newPSIReader returns a PressureReader that reads pressure stall information from the given
+// files.
+func newPSIReader(cpuPath, memoryPath string) PressureReader {
+	return func() (Pressure, error) {
+		cpu, err := readPSIFile(cpuPath)
+		if err != nil {
+			return Pressure{}, fmt.Errorf("reading cpu pressure: %w", err)
+		}
+
+		memory, err := readPSIFile(memoryPath)
+		if err != nil {
+			return Pressure{}, fmt.Errorf("reading memory pressure: %w", err)
+		}
+
+		return Pressure{CPU: cpu, Memory: memory}, nil
+	}
+}
+
+// newPressureReader returns a PressureReader for Gitaly. If Gitaly runs in a cgroup v2 hierarchy,
+// the pressure of Gitaly's own cgroup is read. Otherwise, the system-wide pressure is read from
+// /proc/pressure.
+func newPressureReader(cfg config.Cfg, pid int) PressureReader {
+	cgroupPath := filepath.Join(
+		cfg.Cgroups.Mountpoint,
+		config.GetGitalyProcessTempDir(cfg.Cgroups.HierarchyRoot, pid),
+	)
+
+	if _, err := os.Stat(filepath.Join(cgroupPath, "cpu.pressure")); err == nil {
+		return newPSIReader(
+			filepath.Join(cgroupPath, "cpu.pressure"),
+			filepath.Join(cgroupPath, "memory.pressure"),
+		)
+	}
+
+	return newPSIReader("/proc/pressure/cpu", "/proc/pressure/memory")
+}
+
+func readPSIFile(path string) (float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return parsePSI(file)
+}
+
+// parsePSI parses the "some avg10" value of pressure stall information in the format
+// "some avg10=0.00 avg60=0.00 avg300=0.00 total=0".
+func parsePSI(r io.Reader) (float64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "avg10=") {
+				continue
+			}
+
+			pressure, err := strconv.ParseFloat(strings.TrimPrefix(field, "avg10="), 64)
+			if err != nil {
+				return 0, fmt.Errorf("parsing pressure: %w", err)
+			}
+
+			return pressure, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("scanning pressure: %w", err)
+	}
+
+	return 0, errors.New("no pressure information found")
+}
@@ -0,0 +1,81 @@
+//go:build !gitaly_test_sha256
+
+package limithandler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+)
+
+func TestParsePSI(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc             string
+		input            string
+		expectedPressure float64
+		expectedErr      string
+	}{
+		{
+			desc: "cpu",
+			input: `some avg10=12.34 avg60=5.00 avg300=1.00 total=123456
+full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+`,
+			expectedPressure: 12.34,
+		},
+		{
+			desc:             "full precedes some",
+			input:            "full avg10=50.00 avg60=0.00 avg300=0.00 total=0\nsome avg10=1.50 avg60=0.00 avg300=0.00 total=0\n",
+			expectedPressure: 1.5,
+		},
+		{
+			desc:        "empty",
+			expectedErr: "no pressure information found",
+		},
+		{
+			desc:        "invalid value",
+			input:       "some avg10=foo avg60=0.00 avg300=0.00 total=0\n",
+			expectedErr: `parsing pressure: strconv.ParseFloat: parsing "foo": invalid syntax`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pressure, err := parsePSI(strings.NewReader(tc.input))
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPressure, pressure)
+		})
+	}
+}
+
+func TestNewPSIReader(t *testing.T) {
+	t.Parallel()
+
+	dir := testhelper.TempDir(t)
+	cpuPath := filepath.Join(dir, "cpu.pressure")
+	memoryPath := filepath.Join(dir, "memory.pressure")
+
+	readPressure := newPSIReader(cpuPath, memoryPath)
+
+	_, err := readPressure()
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(cpuPath, []byte("some avg10=42.00 avg60=0.00 avg300=0.00 total=0\n"), 0o644))
+	require.NoError(t, os.WriteFile(memoryPath, []byte("some avg10=7.00 avg60=0.00 avg300=0.00 total=0\n"), 0o644))
+
+	pressure, err := readPressure()
+	require.NoError(t, err)
+	require.Equal(t, Pressure{CPU: 42, Memory: 7}, pressure)
+}
@@ -0,0 +1,223 @@
+package limithandler
+
+import (
+	"container/list"
+	"context"
+	"time"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/server/auth"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/middleware/metadatahandler"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// priorityMetadataKey is the gRPC metadata key clients can use to explicitly request a
+	// priority class. It is only honored for requests authenticated with Gitaly's token, as
+	// any other caller could otherwise promote its requests.
+	priorityMetadataKey = "request_priority"
+	// defaultPriorityClass is the class requests are assigned to if they don't match any other
+	// class.
+	defaultPriorityClass = "default"
+	// defaultMaxStarvation is the default time after which queued requests are admitted
+	// regardless of their priority.
+	defaultMaxStarvation = time.Second
+)
+
+// requestPriority is the priority class a request has been assigned to.
+type requestPriority struct {
+	class  string
+	weight int
+}
+
+// fifoPriority is the priority all requests are assigned to when no priority classes have been
+// configured. As all requests are in the same class, they are admitted in FIFO order.
+var fifoPriority = requestPriority{weight: 1}
+
+// priorityClassifier assigns requests to priority classes.
+type priorityClassifier struct {
+	weights       map[string]int
+	clientClasses map[string]string
+	maxStarvation time.Duration
+}
+
+// newPriorityClassifier creates a new classifier from the given configuration. It returns nil if
+// no priority classes have been configured.
+func newPriorityClassifier(cfg config.RequestPriority) *priorityClassifier {
+	if len(cfg.Classes) == 0 {
+		return nil
+	}
+
+	classifier := &priorityClassifier{
+		weights:       map[string]int{defaultPriorityClass: 1},
+		clientClasses: map[string]string{},
+		maxStarvation: cfg.MaxStarvation.Duration(),
+	}
+
+	if classifier.maxStarvation == 0 {
+		classifier.maxStarvation = defaultMaxStarvation
+	}
+
+	for _, class := range cfg.Classes {
+		classifier.weights[class.Name] = class.Weight
+
+		for _, clientName := range class.ClientNames {
+			// The first class that matches a client wins.
+			if _, ok := classifier.clientClasses[clientName]; !ok {
+				classifier.clientClasses[clientName] = class.Name
+			}
+		}
+	}
+
+	return classifier
+}
+
+// classify determines the priority of the request. The class requested via gRPC metadata takes
+// precedence over the class of the calling client if the request has been authenticated. Unknown
+// classes are treated as the default class.
+func (p *priorityClassifier) classify(ctx context.Context) requestPriority {
+	if p == nil {
+		return fifoPriority
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok && auth.IsAuthenticated(ctx) {
+		if values := md.Get(priorityMetadataKey); len(values) > 0 {
+			if weight, ok := p.weights[values[0]]; ok {
+				return requestPriority{class: values[0], weight: weight}
+			}
+		}
+	}
+
+	if class, ok := p.clientClasses[tagValue(ctx, metadatahandler.ClientNameKey)]; ok {
+		return requestPriority{class: class, weight: p.weights[class]}
+	}
+
+	return requestPriority{class: defaultPriorityClass, weight: p.weights[defaultPriorityClass]}
+}
+
+type priorityContextKey struct{}
+
+// withPriority stores the priority class of the request in its context so that monitors can
+// attribute metrics to it.
+func withPriority(ctx context.Context, priority requestPriority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority.class)
+}
+
+// priorityFromContext returns the priority class stored in the context, or the empty string if
+// there is none.
+func priorityFromContext(ctx context.Context) string {
+	class, _ := ctx.Value(priorityContextKey{}).(string)
+	return class
+}
+
+// waiter is a caller waiting in a priorityQueue.
+type waiter struct {
+	// acquired is closed when the waiter has been admitted.
+	acquired chan struct{}
+	enqueued time.Time
+	class    *priorityQueueClass
+	element  *list.Element
+}
+
+type priorityQueueClass struct {
+	waiters *list.List
+	weight  int
+	// pass is the virtual time at which the class is due to be admitted next. It advances by
+	// the inverse of the class's weight whenever one of its waiters is admitted.
+	pass float64
+}
+
+// priorityQueue is a queue of waiters which uses stride scheduling to pick the next waiter across
+// priority classes: every class gets waiters admitted proportionally to its weight while it has
+// waiters queued. Within a class, waiters are admitted in FIFO order. If the oldest waiter has
+// been queued for longer than maxStarvation, it is admitted first regardless of its class.
+type priorityQueue struct {
+	// classes are the classes in the order they have first been seen.
+	classes       []*priorityQueueClass
+	classesByName map[string]*priorityQueueClass
+	// virtualTime is the pass of the class that has most recently been admitted.
+	virtualTime   float64
+	length        int
+	maxStarvation time.Duration
+	now           func() time.Time
+}
+
+func newPriorityQueue(maxStarvation time.Duration) *priorityQueue {
+	return &priorityQueue{
+		classesByName: map[string]*priorityQueueClass{},
+		maxStarvation: maxStarvation,
+		now:           time.Now,
+	}
+}
+
+func (q *priorityQueue) len() int {
+	return q.length
+}
+
+// push enqueues a new waiter with the given priority.
+func (q *priorityQueue) push(priority requestPriority) *waiter {
+	class, ok := q.classesByName[priority.class]
+	if !ok {
+		class = &priorityQueueClass{waiters: list.New()}
+		q.classesByName[priority.class] = class
+		q.classes = append(q.classes, class)
+	}
+	class.weight = priority.weight
+
+	if class.waiters.Len() == 0 && class.pass < q.virtualTime {
+		// A class must not accumulate credit while it has no waiters, or otherwise it would
+		// monopolize the queue once it has waiters again.
+		class.pass = q.virtualTime
+	}
+
+	w := &waiter{
+		acquired: make(chan struct{}),
+		enqueued: q.now(),
+		class:    class,
+	}
+	w.element = class.waiters.PushBack(w)
+	q.length++
+
+	return w
+}
+
+// remove removes a waiter that has not been popped from the queue.
+func (q *priorityQueue) remove(w *waiter) {
+	w.class.waiters.Remove(w.element)
+	q.length--
+}
+
+// pop dequeues the waiter that is to be admitted next. It must not be called on an empty queue.
+func (q *priorityQueue) pop() *waiter {
+	var next, oldest *priorityQueueClass
+	for _, class := range q.classes {
+		if class.waiters.Len() == 0 {
+			continue
+		}
+
+		if next == nil || class.pass < next.pass {
+			next = class
+		}
+
+		if oldest == nil || front(class).enqueued.Before(front(oldest).enqueued) {
+			oldest = class
+		}
+	}
+
+	if q.maxStarvation > 0 && q.now().Sub(front(oldest).enqueued) >= q.maxStarvation {
+		next = oldest
+	}
+
+	w := front(next)
+	next.waiters.Remove(w.element)
+	q.length--
+
+	q.virtualTime = next.pass
+	next.pass += 1 / float64(next.weight)
+
+	return w
+}
+
+func front(class *priorityQueueClass) *waiter {
+	return class.waiters.Front().Value.(*waiter)
+}
@@ -0,0 +1,259 @@
+//go:build !gitaly_test_sha256
+
+package limithandler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	grpcmwtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/stretchr/testify/require"
+	gitalyauth "gitlab.com/gitlab-org/gitaly/v15/auth"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
+	gitalycfgauth "gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config/auth"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/server/auth"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper/duration"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/middleware/metadatahandler"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestPriorityClassifier(t *testing.T) {
+	t.Parallel()
+
+	classifier := newPriorityClassifier(config.RequestPriority{
+		Classes: []config.PriorityClass{
+			{Name: "interactive", Weight: 4, ClientNames: []string{"gitlab-web"}},
+			{Name: "ci", Weight: 2, ClientNames: []string{"gitlab-ci", "gitlab-web"}},
+		},
+	})
+	require.Equal(t, defaultMaxStarvation, classifier.maxStarvation)
+
+	withClientName := func(ctx context.Context, clientName string) context.Context {
+		tags := grpcmwtags.NewTags()
+		tags.Set(metadatahandler.ClientNameKey, clientName)
+		return grpcmwtags.SetInContext(ctx, tags)
+	}
+
+	// withPriority sets the priority metadata of the request. If secret is set, the request is
+	// passed through the authentication interceptor with a token signed by the secret.
+	withPriority := func(ctx context.Context, class, secret string) context.Context {
+		md := metadata.Pairs(priorityMetadataKey, class)
+		if secret == "" {
+			return metadata.NewIncomingContext(ctx, md)
+		}
+
+		authMetadata, err := gitalyauth.RPCCredentialsV2(secret).GetRequestMetadata(ctx)
+		require.NoError(t, err)
+		ctx = metadata.NewIncomingContext(ctx, metadata.Join(md, metadata.New(authMetadata)))
+
+		var authenticatedCtx context.Context
+		_, err = auth.UnaryServerInterceptor(gitalycfgauth.Config{Token: "secret"})(
+			ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				authenticatedCtx = ctx
+				return nil, nil
+			},
+		)
+		if err != nil {
+			return ctx
+		}
+
+		return authenticatedCtx
+	}
+
+	for _, tc := range []struct {
+		desc             string
+		ctx              func(context.Context) context.Context
+		expectedPriority requestPriority
+	}{
+		{
+			desc:             "no metadata",
+			ctx:              func(ctx context.Context) context.Context { return ctx },
+			expectedPriority: requestPriority{class: "default", weight: 1},
+		},
+		{
+			desc: "client name",
+			ctx: func(ctx context.Context) context.Context {
+				return withClientName(ctx, "gitlab-ci")
+			},
+			expectedPriority: requestPriority{class: "ci", weight: 2},
+		},
+		{
+			desc: "first matching client name wins",
+			ctx: func(ctx context.Context) context.Context {
+				return withClientName(ctx, "gitlab-web")
+			},
+			expectedPriority: requestPriority{class: "interactive", weight: 4},
+		},
+		{
+			desc: "unknown client name",
+			ctx: func(ctx context.Context) context.Context {
+				return withClientName(ctx, "gitlab-shell")
+			},
+			expectedPriority: requestPriority{class: "default", weight: 1},
+		},
+		{
+			desc: "metadata overrides client name",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = withPriority(ctx, "ci", "secret")
+				return withClientName(ctx, "gitlab-web")
+			},
+			expectedPriority: requestPriority{class: "ci", weight: 2},
+		},
+		{
+			desc: "metadata of unauthenticated request",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = withPriority(ctx, "interactive", "")
+				return withClientName(ctx, "gitlab-ci")
+			},
+			expectedPriority: requestPriority{class: "ci", weight: 2},
+		},
+		{
+			desc: "metadata of request with invalid token",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = withPriority(ctx, "interactive", "wrong-secret")
+				return withClientName(ctx, "gitlab-ci")
+			},
+			expectedPriority: requestPriority{class: "ci", weight: 2},
+		},
+		{
+			desc: "unknown class in metadata",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = withPriority(ctx, "urgent", "secret")
+				return withClientName(ctx, "gitlab-ci")
+			},
+			expectedPriority: requestPriority{class: "ci", weight: 2},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := tc.ctx(testhelper.Context(t))
+			require.Equal(t, tc.expectedPriority, classifier.classify(ctx))
+		})
+	}
+
+	t.Run("without classes", func(t *testing.T) {
+		t.Parallel()
+
+		classifier := newPriorityClassifier(config.RequestPriority{
+			MaxStarvation: duration.Duration(time.Minute),
+		})
+		require.Nil(t, classifier)
+		require.Equal(t, fifoPriority, classifier.classify(withClientName(testhelper.Context(t), "gitlab-web")))
+	})
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Parallel()
+
+	high := requestPriority{class: "high", weight: 3}
+	low := requestPriority{class: "low", weight: 1}
+
+	popAll := func(q *priorityQueue, waiters map[*waiter]string) []string {
+		var order []string
+		for q.len() > 0 {
+			order = append(order, waiters[q.pop()])
+		}
+		return order
+	}
+
+	t.Run("single class is FIFO", func(t *testing.T) {
+		t.Parallel()
+
+		q := newPriorityQueue(0)
+		waiters := map[*waiter]string{
+			q.push(fifoPriority): "a",
+			q.push(fifoPriority): "b",
+			q.push(fifoPriority): "c",
+		}
+
+		require.Equal(t, []string{"a", "b", "c"}, popAll(q, waiters))
+	})
+
+	t.Run("classes are admitted by weight", func(t *testing.T) {
+		t.Parallel()
+
+		q := newPriorityQueue(0)
+		waiters := map[*waiter]string{}
+		for _, name := range []string{"l1", "l2", "l3"} {
+			waiters[q.push(low)] = name
+		}
+		for _, name := range []string{"h1", "h2", "h3", "h4", "h5", "h6"} {
+			waiters[q.push(high)] = name
+		}
+
+		require.Equal(t, []string{
+			"l1", "h1", "h2", "h3", "l2", "h4", "h5", "h6", "l3",
+		}, popAll(q, waiters))
+	})
+
+	t.Run("idle classes don't accumulate credit", func(t *testing.T) {
+		t.Parallel()
+
+		q := newPriorityQueue(0)
+		waiters := map[*waiter]string{}
+		waiters[q.push(high)] = "h1"
+		for _, name := range []string{"l1", "l2", "l3", "l4"} {
+			waiters[q.push(low)] = name
+		}
+		require.Equal(t, "h1", waiters[q.pop()])
+		require.Equal(t, "l1", waiters[q.pop()])
+		require.Equal(t, "l2", waiters[q.pop()])
+
+		// The high priority class has been idle, so it mustn't be able to starve the low
+		// priority class by catching up on the admissions it has missed.
+		for _, name := range []string{"h2", "h3", "h4", "h5", "h6", "h7"} {
+			waiters[q.push(high)] = name
+		}
+
+		require.Equal(t, []string{
+			"h2", "h3", "h4", "h5", "l3", "h6", "h7", "l4",
+		}, popAll(q, waiters))
+	})
+
+	t.Run("starving waiters are admitted first", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+
+		q := newPriorityQueue(time.Second)
+		q.now = func() time.Time { return now }
+
+		waiters := map[*waiter]string{}
+		waiters[q.push(high)] = "h1"
+		waiters[q.push(low)] = "l1"
+		waiters[q.push(low)] = "l2"
+
+		now = now.Add(500 * time.Millisecond)
+		for _, name := range []string{"h2", "h3", "h4"} {
+			waiters[q.push(high)] = name
+		}
+
+		require.Equal(t, "h1", waiters[q.pop()])
+		require.Equal(t, "l1", waiters[q.pop()])
+
+		// l2 has now been waiting longer than the maximum starvation time, so it must be
+		// admitted before h2 and h3 even though the high priority class is due.
+		now = now.Add(600 * time.Millisecond)
+		require.Equal(t, []string{"l2", "h2", "h3", "h4"}, popAll(q, waiters))
+	})
+
+	t.Run("removed waiters are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		q := newPriorityQueue(0)
+		waiters := map[*waiter]string{}
+		waiters[q.push(low)] = "l1"
+		removed := q.push(high)
+		waiters[q.push(high)] = "h2"
+
+		q.remove(removed)
+		require.Equal(t, 2, q.len())
+		require.Equal(t, []string{"l1", "h2"}, popAll(q, waiters))
+	})
+}
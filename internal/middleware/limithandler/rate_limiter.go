@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
 	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/gitaly/config"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
@@ -23,6 +24,9 @@ type RateLimiter struct {
 	burst                            int
 	requestsDroppedMetric            prometheus.Counter
 	ticker                           helper.Ticker
+	// dryRun causes requests that exceed the rate limit to be logged and counted
+	// via requestsDroppedMetric, but not to be rejected.
+	dryRun bool
 }
 
 // ErrRateLimit is returned when RateLimiter determined a request has breached
@@ -43,6 +47,13 @@ func (r *RateLimiter) Limit(ctx context.Context, lockKey string, f LimitedFunc)
 		// of traffic.
 		r.requestsDroppedMetric.Inc()
 
+		if r.dryRun {
+			ctxlogrus.Extract(ctx).WithField("rate_limit_key", lockKey).
+				Info("request would have been rejected by rate limiter")
+
+			return f()
+		}
+
 		err := helper.ErrUnavailable(ErrRateLimit)
 
 		detailedErr, errGeneratingDetailedErr := helper.ErrWithDetails(
@@ -92,6 +103,30 @@ func (r *RateLimiter) pruneUnusedLimiters() {
 	})
 }
 
+// takeOver takes over the per-key token buckets of the given limiter, which is being replaced by
+// this one. The buckets keep the tokens which have been consumed already, but are refilled at the
+// rate and up to the burst of this limiter. Limiters of other kinds don't have any state to take
+// over. takeOver must be called before the limiter is used.
+func (r *RateLimiter) takeOver(previous Limiter) {
+	previousLimiter, ok := previous.(*RateLimiter)
+	if !ok || previousLimiter == r {
+		return
+	}
+
+	previousLimiter.limitersByKey.Range(func(key, value interface{}) bool {
+		limiter := value.(*rate.Limiter)
+		limiter.SetLimit(rate.Every(r.refillInterval))
+		limiter.SetBurst(r.burst)
+		r.limitersByKey.Store(key, limiter)
+
+		if lastAccessed, ok := previousLimiter.lastAccessedByKey.Load(key); ok {
+			r.lastAccessedByKey.Store(key, lastAccessed)
+		}
+
+		return true
+	})
+}
+
 // NewRateLimiter creates a new instance of RateLimiter
 func NewRateLimiter(
 	refillInterval time.Duration,
@@ -113,27 +148,75 @@ func NewRateLimiter(
 // based on its rate per second per RPC
 func WithRateLimiters(ctx context.Context) SetupFunc {
 	return func(cfg config.Cfg, middleware *LimiterMiddleware) {
+		// The middleware may be reloaded, in which case the limiters set up here are
+		// replaced. We thus need to be able to stop pruning them independently of ctx.
+		ctx, cancel := context.WithCancel(ctx)
+		middleware.stop = cancel
+
+		requestsDryRunMetric := prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gitaly_requests_rate_limit_dry_run_total",
+				Help: "Number of requests that would have been dropped by rate limiters in dry-run mode",
+			},
+			[]string{"system", "grpc_service", "grpc_method"},
+		)
+
+		middleware.collect = func(metrics chan<- prometheus.Metric) {
+			requestsDryRunMetric.Collect(metrics)
+		}
+
 		result := make(map[string]Limiter)
+		lockKeys := make(map[string]GetLockKey)
 
 		for _, limitCfg := range cfg.RateLimiting {
 			if limitCfg.Burst > 0 && limitCfg.Interval > 0 {
 				serviceName, methodName := splitMethodName(limitCfg.RPC)
-				rateLimiter := NewRateLimiter(
-					limitCfg.Interval.Duration(),
-					limitCfg.Burst,
-					helper.NewTimerTicker(5*time.Minute),
-					middleware.requestsDroppedMetric.With(prometheus.Labels{
+
+				var requestsDroppedMetric prometheus.Counter
+				if limitCfg.DryRun {
+					requestsDroppedMetric = requestsDryRunMetric.WithLabelValues("gitaly", serviceName, methodName)
+				} else {
+					requestsDroppedMetric = middleware.requestsDroppedMetric.With(prometheus.Labels{
 						"system":       "gitaly",
 						"grpc_service": serviceName,
 						"grpc_method":  methodName,
 						"reason":       "rate",
-					}),
+					})
+				}
+
+				rateLimiter := NewRateLimiter(
+					limitCfg.Interval.Duration(),
+					limitCfg.Burst,
+					helper.NewTimerTicker(5*time.Minute),
+					requestsDroppedMetric,
 				)
+				rateLimiter.dryRun = limitCfg.DryRun
+
 				result[limitCfg.RPC] = rateLimiter
 				go rateLimiter.PruneUnusedLimiters(ctx)
+
+				if getLockKey := rateLimitingLockKey(limitCfg.Key); getLockKey != nil {
+					lockKeys[limitCfg.RPC] = getLockKey
+				}
 			}
 		}
 
 		middleware.methodLimiters = result
+		middleware.methodLockKeys = lockKeys
+	}
+}
+
+// rateLimitingLockKey returns the GetLockKey function for the given key. It returns nil for the
+// repository key, which is the middleware's default.
+func rateLimitingLockKey(key config.RateLimitingKey) GetLockKey {
+	switch key {
+	case config.RateLimitingKeyUser:
+		return LimitByUser
+	case config.RateLimitingKeyRemoteIP:
+		return LimitByRemoteIP
+	case config.RateLimitingKeyProject:
+		return LimitByProject
+	default:
+		return nil
 	}
 }
@@ -3,10 +3,13 @@
 package limithandler
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
 )
@@ -94,3 +97,42 @@ func TestRateLimiter_pruneUnusedLimiters(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimiter_takeOver(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+
+	newLimiter := func(refillInterval time.Duration) *RateLimiter {
+		return NewRateLimiter(refillInterval, 1, helper.NewManualTicker(), prometheus.NewCounter(prometheus.CounterOpts{}))
+	}
+
+	limit := func(tb testing.TB, limiter *RateLimiter, lockKey string) error {
+		tb.Helper()
+
+		_, err := limiter.Limit(ctx, lockKey, func() (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	previous := newLimiter(time.Hour)
+	require.NoError(t, limit(t, previous, "key"))
+	require.Error(t, limit(t, previous, "key"))
+
+	// The bucket of the key is exhausted, and the reloaded limiter must not grant it a new
+	// burst of requests.
+	reloaded := newLimiter(time.Hour)
+	reloaded.takeOver(previous)
+	err := limit(t, reloaded, "key")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrRateLimit))
+	require.NoError(t, limit(t, reloaded, "other-key"))
+
+	// The taken over bucket is refilled at the rate of the reloaded limiter.
+	faster := newLimiter(time.Millisecond)
+	faster.takeOver(reloaded)
+	require.Eventually(t, func() bool {
+		return limit(t, faster, "key") == nil
+	}, 10*time.Second, time.Millisecond)
+}
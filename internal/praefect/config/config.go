@@ -87,6 +87,10 @@ type BackgroundVerification struct {
 	// DeleteInvalidRecords controls whether the background verifier will actually delete the metadata
 	// records that point to non-existent replicas.
 	DeleteInvalidRecords bool `toml:"delete_invalid_records"`
+	// VerifyChecksums controls whether the background verifier compares the checksums of the up to date
+	// replicas of a repository. Replicas with a checksum differing from the other replicas are marked
+	// outdated so the reconciler repairs them.
+	VerifyChecksums bool `toml:"verify_checksums"`
 }
 
 // DefaultBackgroundVerificationConfig returns the default background verification configuration.
@@ -111,6 +115,28 @@ func DefaultReconciliationConfig() Reconciliation {
 	}
 }
 
+// Rebalancing contains configuration options for rebalancing replicas across storages by disk usage.
+type Rebalancing struct {
+	// SchedulingInterval is the interval between each automatic rebalancing run. If set to 0,
+	// automatic rebalancing is disabled.
+	SchedulingInterval duration.Duration `toml:"scheduling_interval,omitempty"`
+	// MaxMoves is the maximum number of replicas moved in a virtual storage in a single run.
+	MaxMoves int `toml:"max_moves,omitempty"`
+	// UsageThreshold is the difference in percentage points between the disk usage of the
+	// fullest and the emptiest storage of a virtual storage above which replicas are moved.
+	UsageThreshold float64 `toml:"usage_threshold,omitempty"`
+	// DryRun logs the planned moves without changing the assignments.
+	DryRun bool `toml:"dry_run,omitempty"`
+}
+
+// DefaultRebalancingConfig returns the default values for rebalancing configuration.
+func DefaultRebalancingConfig() Rebalancing {
+	return Rebalancing{
+		MaxMoves:       10,
+		UsageThreshold: 10,
+	}
+}
+
 // Replication contains replication specific configuration options.
 type Replication struct {
 	// BatchSize controls how many replication jobs to dequeue and lock
@@ -131,6 +157,7 @@ type Config struct {
 	AllowLegacyElectors    bool                   `toml:"i_understand_my_election_strategy_is_unsupported_and_will_be_removed_without_warning,omitempty"`
 	BackgroundVerification BackgroundVerification `toml:"background_verification,omitempty"`
 	Reconciliation         Reconciliation         `toml:"reconciliation,omitempty"`
+	Rebalancing            Rebalancing            `toml:"rebalancing,omitempty"`
 	Replication            Replication            `toml:"replication,omitempty"`
 	ListenAddr             string                 `toml:"listen_addr,omitempty"`
 	TLSListenAddr          string                 `toml:"tls_listen_addr,omitempty"`
@@ -179,6 +206,7 @@ func FromFile(filePath string) (Config, error) {
 	conf := &Config{
 		BackgroundVerification: DefaultBackgroundVerificationConfig(),
 		Reconciliation:         DefaultReconciliationConfig(),
+		Rebalancing:            DefaultRebalancingConfig(),
 		Replication:            DefaultReplicationConfig(),
 		Prometheus:             prometheus.DefaultConfig(),
 		PrometheusExcludeDatabaseFromDefaultMetrics: true,
@@ -279,6 +307,15 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Rebalancing.SchedulingInterval.Duration() > 0 {
+		if c.Rebalancing.MaxMoves < 1 {
+			return fmt.Errorf("rebalancing.max_moves was %d but must be >=1", c.Rebalancing.MaxMoves)
+		}
+		if c.Rebalancing.UsageThreshold <= 0 || c.Rebalancing.UsageThreshold > 100 {
+			return fmt.Errorf("rebalancing.usage_threshold was %v but must be >0 and <=100", c.Rebalancing.UsageThreshold)
+		}
+	}
+
 	return nil
 }
 
@@ -327,6 +364,26 @@ func (c *Config) StorageNames() map[string][]string {
 	return storages
 }
 
+// FailureDomains returns the failure domains of the storages by their virtual storage. Storages
+// without a failure domain are omitted.
+func (c *Config) FailureDomains() map[string]map[string]string {
+	failureDomains := make(map[string]map[string]string, len(c.VirtualStorages))
+	for _, vs := range c.VirtualStorages {
+		domains := make(map[string]string, len(vs.Nodes))
+		for _, n := range vs.Nodes {
+			if n.FailureDomain == "" {
+				continue
+			}
+
+			domains[n.Storage] = n.FailureDomain
+		}
+
+		failureDomains[vs.Name] = domains
+	}
+
+	return failureDomains
+}
+
 // DefaultReplicationFactors returns a map with the default replication factors of
 // the virtual storages.
 func (c Config) DefaultReplicationFactors() map[string]int {
@@ -207,6 +207,20 @@ func TestConfigValidation(t *testing.T) {
 			},
 			errMsg: `repositories_cleanup.run_interval is less then 1m0s, which could lead to a database performance problem`,
 		},
+		{
+			desc: "rebalancing max moves too low",
+			changeConfig: func(cfg *Config) {
+				cfg.Rebalancing = Rebalancing{SchedulingInterval: duration.Duration(time.Minute), UsageThreshold: 10}
+			},
+			errMsg: `rebalancing.max_moves was 0 but must be >=1`,
+		},
+		{
+			desc: "rebalancing usage threshold out of range",
+			changeConfig: func(cfg *Config) {
+				cfg.Rebalancing = Rebalancing{SchedulingInterval: duration.Duration(time.Minute), MaxMoves: 1, UsageThreshold: 101}
+			},
+			errMsg: `rebalancing.usage_threshold was 101 but must be >0 and <=100`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -266,12 +280,14 @@ func TestConfigParsing(t *testing.T) {
 						DefaultReplicationFactor: 2,
 						Nodes: []*Node{
 							{
-								Address: "tcp://gitaly-internal-1.example.com",
-								Storage: "praefect-internal-1",
+								Address:       "tcp://gitaly-internal-1.example.com",
+								Storage:       "praefect-internal-1",
+								FailureDomain: "zone-a",
 							},
 							{
-								Address: "tcp://gitaly-internal-2.example.com",
-								Storage: "praefect-internal-2",
+								Address:       "tcp://gitaly-internal-2.example.com",
+								Storage:       "praefect-internal-2",
+								FailureDomain: "zone-b",
 							},
 							{
 								Address: "tcp://gitaly-internal-3.example.com",
@@ -313,6 +329,12 @@ func TestConfigParsing(t *testing.T) {
 					SchedulingInterval: duration.Duration(time.Minute),
 					HistogramBuckets:   []float64{1, 2, 3, 4, 5},
 				},
+				Rebalancing: Rebalancing{
+					SchedulingInterval: duration.Duration(time.Hour),
+					MaxMoves:           5,
+					UsageThreshold:     20,
+					DryRun:             true,
+				},
 				Replication: Replication{BatchSize: 1, ParallelStorageProcessingWorkers: 2},
 				Failover: Failover{
 					Enabled:                  true,
@@ -331,6 +353,7 @@ func TestConfigParsing(t *testing.T) {
 				BackgroundVerification: BackgroundVerification{
 					VerificationInterval: duration.Duration(24 * time.Hour),
 					DeleteInvalidRecords: true,
+					VerifyChecksums:      true,
 				},
 			},
 		},
@@ -343,7 +366,8 @@ func TestConfigParsing(t *testing.T) {
 					SchedulingInterval: 0,
 					HistogramBuckets:   []float64{1, 2, 3, 4, 5},
 				},
-				Prometheus: prometheus.DefaultConfig(),
+				Rebalancing: DefaultRebalancingConfig(),
+				Prometheus:  prometheus.DefaultConfig(),
 				PrometheusExcludeDatabaseFromDefaultMetrics: true,
 				Replication: Replication{BatchSize: 1, ParallelStorageProcessingWorkers: 2},
 				Failover: Failover{
@@ -368,6 +392,7 @@ func TestConfigParsing(t *testing.T) {
 				Prometheus:          prometheus.DefaultConfig(),
 				PrometheusExcludeDatabaseFromDefaultMetrics: true,
 				Reconciliation: DefaultReconciliationConfig(),
+				Rebalancing:    DefaultRebalancingConfig(),
 				Replication:    DefaultReplicationConfig(),
 				Failover: Failover{
 					Enabled:           true,
@@ -417,6 +442,23 @@ func TestStorageNames(t *testing.T) {
 	}, conf.StorageNames())
 }
 
+func TestFailureDomains(t *testing.T) {
+	conf := Config{
+		VirtualStorages: []*VirtualStorage{
+			{Name: "virtual-storage-1", Nodes: []*Node{
+				{Storage: "gitaly-1", FailureDomain: "zone-a"},
+				{Storage: "gitaly-2", FailureDomain: "zone-b"},
+				{Storage: "gitaly-3"},
+			}},
+			{Name: "virtual-storage-2", Nodes: []*Node{{Storage: "gitaly-4"}}},
+		},
+	}
+	require.Equal(t, map[string]map[string]string{
+		"virtual-storage-1": {"gitaly-1": "zone-a", "gitaly-2": "zone-b"},
+		"virtual-storage-2": {},
+	}, conf.FailureDomains())
+}
+
 func TestDefaultReplicationFactors(t *testing.T) {
 	for _, tc := range []struct {
 		desc                      string
@@ -10,14 +10,23 @@ type Node struct {
 	Storage string `toml:"storage,omitempty"`
 	Address string `toml:"address,omitempty"`
 	Token   string `toml:"token,omitempty"`
+	// FailureDomain is a label for the rack, availability zone or other domain the node shares
+	// failures with. Praefect spreads the replicas of a repository across failure domains.
+	FailureDomain string `toml:"failure_domain,omitempty"`
 }
 
 //nolint:revive // This is unintentionally missing documentation.
 func (n Node) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]interface{}{
+	fields := map[string]interface{}{
 		"storage": n.Storage,
 		"address": n.Address,
-	})
+	}
+
+	if n.FailureDomain != "" {
+		fields["failure_domain"] = n.FailureDomain
+	}
+
+	return json.Marshal(fields)
 }
 
 // String prints out the node attributes but hiding the token
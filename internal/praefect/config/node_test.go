@@ -21,3 +21,16 @@ func TestNode_MarshalJSON(t *testing.T) {
 	require.NoError(t, err)
 	require.JSONEq(t, `{"storage":"storage","address":"address"}`, string(b))
 }
+
+func TestNode_MarshalJSON_failureDomain(t *testing.T) {
+	node := &Node{
+		Storage:       "storage",
+		Address:       "address",
+		Token:         "secretToken",
+		FailureDomain: "zone-a",
+	}
+
+	b, err := json.Marshal(node)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"storage":"storage","address":"address","failure_domain":"zone-a"}`, string(b))
+}
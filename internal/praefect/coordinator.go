@@ -92,6 +92,10 @@ var transactionRPCs = map[string]transactionsCondition{
 	// This RPC call should be made transactional. Furthermore, we should consider whether we
 	// have to replicate custom hooks.
 	"/gitaly.RepositoryService/RestoreCustomHooks": transactionsDisabled,
+
+	// The repository is restored on the primary from the backup sink, and secondaries are
+	// brought up to date through replication afterwards.
+	"/gitaly.RepositoryService/RestoreRepository": transactionsDisabled,
 }
 
 // forcePrimaryRoutingRPCs tracks RPCs which need to always get routed to the primary. This should
@@ -231,13 +231,14 @@ func TestStreamDirectorMutator_Transaction(t *testing.T) {
 				rs,
 				NewPerRepositoryRouter(
 					nodeSet.Connections(),
-					nodes.NewPerRepositoryElector(tx),
+					nodes.NewPerRepositoryElector(tx, nil),
 					StaticHealthChecker(conf.StorageNames()),
 					NewLockedRandom(rand.New(rand.NewSource(0))),
 					rs,
-					datastore.NewAssignmentStore(tx, conf.StorageNames()),
+					datastore.NewAssignmentStore(tx, conf.StorageNames(), nil),
 					rs,
 					nil,
+					nil,
 				),
 				txMgr,
 				conf,
@@ -206,13 +206,14 @@ func TestStreamDirectorMutator(t *testing.T) {
 				rs,
 				NewPerRepositoryRouter(
 					nodeSet.Connections(),
-					nodes.NewPerRepositoryElector(tx),
+					nodes.NewPerRepositoryElector(tx, nil),
 					StaticHealthChecker(conf.StorageNames()),
 					NewLockedRandom(rand.New(rand.NewSource(0))),
 					rs,
-					datastore.NewAssignmentStore(tx, conf.StorageNames()),
+					datastore.NewAssignmentStore(tx, conf.StorageNames(), nil),
 					rs,
 					nil,
+					nil,
 				),
 				txMgr,
 				conf,
@@ -644,13 +645,14 @@ func TestStreamDirector_maintenance(t *testing.T) {
 		rs,
 		NewPerRepositoryRouter(
 			nodeSet.Connections(),
-			nodes.NewPerRepositoryElector(tx),
+			nodes.NewPerRepositoryElector(tx, nil),
 			StaticHealthChecker(cfg.StorageNames()),
 			NewLockedRandom(rand.New(rand.NewSource(0))),
 			rs,
-			datastore.NewAssignmentStore(tx, cfg.StorageNames()),
+			datastore.NewAssignmentStore(tx, cfg.StorageNames(), nil),
 			rs,
 			nil,
+			nil,
 		),
 		nil,
 		cfg,
@@ -1534,6 +1536,7 @@ func TestStreamDirector_repo_creation(t *testing.T) {
 				nil,
 				rs,
 				conf.DefaultReplicationFactors(),
+				nil,
 			)
 
 			txMgr := transactions.NewManager(conf)
@@ -30,11 +30,13 @@ func newRepositoryNotFoundError(virtualStorage, relativePath string) error {
 type AssignmentStore struct {
 	db                 glsql.Querier
 	configuredStorages map[string][]string
+	failureDomains     map[string]map[string]string
 }
 
-// NewAssignmentStore returns a new AssignmentStore using the passed in database.
-func NewAssignmentStore(db glsql.Querier, configuredStorages map[string][]string) AssignmentStore {
-	return AssignmentStore{db: db, configuredStorages: configuredStorages}
+// NewAssignmentStore returns a new AssignmentStore using the passed in database. failureDomains
+// contains the failure domains of the storages by their virtual storage.
+func NewAssignmentStore(db glsql.Querier, configuredStorages map[string][]string, failureDomains map[string]map[string]string) AssignmentStore {
+	return AssignmentStore{db: db, configuredStorages: configuredStorages, failureDomains: failureDomains}
 }
 
 //nolint:revive // This is unintentionally missing documentation.
@@ -92,6 +94,13 @@ func (s AssignmentStore) SetReplicationFactor(ctx context.Context, virtualStorag
 		return nil, newUnattainableReplicationFactorError(replicationFactor, max)
 	}
 
+	domainStorages := make([]string, 0, len(s.failureDomains[virtualStorage]))
+	failureDomains := make([]string, 0, len(s.failureDomains[virtualStorage]))
+	for storage, failureDomain := range s.failureDomains[virtualStorage] {
+		domainStorages = append(domainStorages, storage)
+		failureDomains = append(failureDomains, failureDomain)
+	}
+
 	// The query works as follows:
 	//
 	// 1. `repository` CTE locks the repository's record for the duration of the update.
@@ -109,11 +118,16 @@ func (s AssignmentStore) SetReplicationFactor(ctx context.Context, virtualStorag
 	// 3. `created_assignments` CTE assigns new hosts to the repository if the replication
 	//    factor has been increased. Random storages which are not yet assigned to the repository
 	//    are picked until the replication factor is met. The primary of a repository is always
-	//    assigned first.
+	//    assigned first. Storages which are being drained are only picked if there are no other
+	//    storages left. Storages in failure domains with the fewest assigned storages are picked
+	//    first so the replicas are spread across the failure domains. Storages without a failure
+	//    domain are considered to be in a domain of their own.
 	//
 	// 4. `removed_assignments` CTE removes host assignments if the replication factor has been
 	//    decreased. Primary is never removed as it needs a copy of the repository in order to
-	//    accept writes. Random hosts are removed until the replication factor is met.
+	//    accept writes. Random hosts are removed until the replication factor is met, starting
+	//    with the storages which are being drained and then with the storages in the failure
+	//    domains with the most assigned storages.
 	//
 	// 6. Finally we return the current set of assignments. CTE updates are not visible in the
 	//    tables during the transaction. To account for that, we filter out removed assignments
@@ -129,6 +143,10 @@ WITH repository AS (
 	FOR UPDATE
 ),
 
+failure_domains AS (
+	SELECT unnest($5::text[]) AS storage, unnest($6::text[]) AS failure_domain
+),
+
 existing_assignments AS (
 	SELECT storage
 	FROM repository
@@ -139,10 +157,32 @@ existing_assignments AS (
 created_assignments AS (
 	INSERT INTO repository_assignments
 	SELECT virtual_storage, relative_path, storage, repository_id
-	FROM repository
-	CROSS JOIN ( SELECT unnest($4::text[]) AS storage ) AS configured_storages
-	WHERE storage NOT IN ( SELECT storage FROM existing_assignments )
-	ORDER BY CASE WHEN storage = "primary" THEN 1 ELSE 0 END DESC, random()
+	FROM (
+		SELECT
+			virtual_storage,
+			relative_path,
+			storage,
+			repository_id,
+			storage = "primary" AS is_primary,
+			storage IN ( SELECT storage FROM storage_drains WHERE virtual_storage = $1 ) AS is_draining,
+			failure_domain
+		FROM repository
+		CROSS JOIN ( SELECT unnest($4::text[]) AS storage ) AS configured_storages
+		LEFT JOIN failure_domains USING (storage)
+		WHERE storage NOT IN ( SELECT storage FROM existing_assignments )
+	) AS candidates
+	ORDER BY
+		is_primary DESC,
+		is_draining,
+		CASE WHEN failure_domain IS NULL THEN 0 ELSE
+			ROW_NUMBER() OVER (PARTITION BY failure_domain ORDER BY is_primary DESC, is_draining, random()) + (
+				SELECT COUNT(*)
+				FROM existing_assignments
+				JOIN failure_domains USING (storage)
+				WHERE failure_domains.failure_domain = candidates.failure_domain
+			)
+		END,
+		random()
 	LIMIT ( SELECT GREATEST(COUNT(*), $3) - COUNT(*) FROM existing_assignments )
 	RETURNING storage
 ),
@@ -151,9 +191,22 @@ removed_assignments AS (
 	DELETE FROM repository_assignments
 	USING (
 		SELECT virtual_storage, relative_path, storage
-		FROM repository, existing_assignments
+		FROM (
+			SELECT
+				virtual_storage,
+				relative_path,
+				storage,
+				"primary",
+				storage IN ( SELECT storage FROM storage_drains WHERE virtual_storage = $1 ) AS is_draining,
+				CASE WHEN failure_domain IS NULL THEN 0 ELSE
+					ROW_NUMBER() OVER (PARTITION BY failure_domain ORDER BY storage = "primary" DESC, random())
+				END AS domain_rank
+			FROM repository
+			CROSS JOIN existing_assignments
+			LEFT JOIN failure_domains USING (storage)
+		) AS candidates
 		WHERE storage != "primary"
-		ORDER BY random()
+		ORDER BY is_draining DESC, domain_rank DESC, random()
 		LIMIT ( SELECT COUNT(*) - LEAST(COUNT(*), $3)  FROM existing_assignments )
 	) AS removals
 	WHERE repository_assignments.virtual_storage = removals.virtual_storage
@@ -169,7 +222,7 @@ UNION
 SELECT storage
 FROM created_assignments
 ORDER BY storage
-	`, virtualStorage, relativePath, replicationFactor, candidateStorages)
+	`, virtualStorage, relativePath, replicationFactor, candidateStorages, domainStorages, failureDomains)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -107,6 +107,7 @@ func TestAssignmentStore_GetHostAssignments(t *testing.T) {
 			actualAssignments, err := NewAssignmentStore(
 				db,
 				map[string][]string{"virtual-storage": configuredStorages},
+				nil,
 			).GetHostAssignments(ctx, tc.virtualStorage, repositoryID)
 			require.Equal(t, tc.error, err)
 			require.ElementsMatch(t, tc.expectedAssignments, actualAssignments)
@@ -137,6 +138,7 @@ func TestAssignmentStore_SetReplicationFactor(t *testing.T) {
 	for _, tc := range []struct {
 		desc                  string
 		existingAssignments   []string
+		failureDomains        map[string]string
 		nonExistentRepository bool
 		replicationFactor     int
 		requireStorages       matcher
@@ -201,6 +203,20 @@ func TestAssignmentStore_SetReplicationFactor(t *testing.T) {
 			replicationFactor:   1,
 			requireStorages:     equal([]string{"primary"}),
 		},
+		{
+			desc:                "increase replication factor spreads replicas across failure domains",
+			existingAssignments: []string{"primary"},
+			failureDomains:      map[string]string{"primary": "zone-a", "secondary-1": "zone-a", "secondary-2": "zone-b"},
+			replicationFactor:   2,
+			requireStorages:     equal([]string{"primary", "secondary-2"}),
+		},
+		{
+			desc:                "decrease replication factor removes replicas from the most used failure domain",
+			existingAssignments: []string{"primary", "secondary-1", "secondary-2"},
+			failureDomains:      map[string]string{"primary": "zone-a", "secondary-1": "zone-a", "secondary-2": "zone-b"},
+			replicationFactor:   2,
+			requireStorages:     equal([]string{"primary", "secondary-2"}),
+		},
 		{
 			desc:              "minimum replication factor is enforced",
 			replicationFactor: 0,
@@ -229,7 +245,7 @@ func TestAssignmentStore_SetReplicationFactor(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			store := NewAssignmentStore(db, configuredStorages)
+			store := NewAssignmentStore(db, configuredStorages, map[string]map[string]string{"virtual-storage": tc.failureDomains})
 
 			setStorages, err := store.SetReplicationFactor(ctx, "virtual-storage", "relative-path", tc.replicationFactor)
 			require.Equal(t, tc.error, err)
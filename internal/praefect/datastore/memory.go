@@ -193,6 +193,19 @@ func (s *memoryReplicationEventQueue) Acknowledge(_ context.Context, state JobSt
 	return result, nil
 }
 
+func (s *memoryReplicationEventQueue) RecordErrors(_ context.Context, errs map[uint64]string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for i := range s.queued {
+		if message, ok := errs[s.queued[i].ID]; ok && s.queued[i].State == JobStateInProgress {
+			s.queued[i].Error = message
+		}
+	}
+
+	return nil
+}
+
 // StartHealthUpdate does nothing as it has no sense in terms of in-memory implementation as
 // all information about events will be lost after restart.
 func (s *memoryReplicationEventQueue) StartHealthUpdate(context.Context, <-chan time.Time, []ReplicationEvent) error {
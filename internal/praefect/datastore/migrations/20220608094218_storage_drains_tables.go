@@ -0,0 +1,33 @@
+package migrations
+
+import migrate "github.com/rubenv/sql-migrate"
+
+func init() {
+	m := &migrate.Migration{
+		Id: "20220608094218_storage_drains_tables",
+		Up: []string{
+			`
+CREATE TABLE storage_drains (
+	virtual_storage TEXT NOT NULL,
+	storage TEXT NOT NULL,
+	started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (virtual_storage, storage)
+)`,
+			`
+CREATE TABLE storage_drain_moves (
+	repository_id BIGINT NOT NULL REFERENCES repositories ON DELETE CASCADE,
+	virtual_storage TEXT NOT NULL,
+	source_storage TEXT NOT NULL,
+	target_storage TEXT,
+	PRIMARY KEY (repository_id, source_storage),
+	FOREIGN KEY (virtual_storage, source_storage) REFERENCES storage_drains ON DELETE CASCADE
+)`,
+		},
+		Down: []string{
+			"DROP TABLE storage_drain_moves",
+			"DROP TABLE storage_drains",
+		},
+	}
+
+	allMigrations = append(allMigrations, m)
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import migrate "github.com/rubenv/sql-migrate"
+
+func init() {
+	m := &migrate.Migration{
+		Id: "20220615091236_replica_checksum_columns",
+		Up: []string{
+			"ALTER TABLE storage_repositories ADD COLUMN checksum TEXT",
+			"ALTER TABLE storage_repositories ADD COLUMN checksum_generation BIGINT",
+			"ALTER TABLE storage_repositories ADD COLUMN checksum_verified_at TIMESTAMPTZ",
+			"ALTER TABLE storage_repositories ADD COLUMN checksum_matches BOOLEAN",
+		},
+		Down: []string{
+			"ALTER TABLE storage_repositories DROP COLUMN checksum_matches",
+			"ALTER TABLE storage_repositories DROP COLUMN checksum_verified_at",
+			"ALTER TABLE storage_repositories DROP COLUMN checksum_generation",
+			"ALTER TABLE storage_repositories DROP COLUMN checksum",
+		},
+	}
+
+	allMigrations = append(allMigrations, m)
+}
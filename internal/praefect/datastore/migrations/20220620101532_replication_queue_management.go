@@ -0,0 +1,19 @@
+package migrations
+
+import migrate "github.com/rubenv/sql-migrate"
+
+func init() {
+	m := &migrate.Migration{
+		Id: "20220620101532_replication_queue_management",
+		Up: []string{
+			"ALTER TABLE replication_queue ADD COLUMN priority INTEGER NOT NULL DEFAULT 0",
+			"ALTER TABLE replication_queue ADD COLUMN error TEXT",
+		},
+		Down: []string{
+			"ALTER TABLE replication_queue DROP COLUMN error",
+			"ALTER TABLE replication_queue DROP COLUMN priority",
+		},
+	}
+
+	allMigrations = append(allMigrations, m)
+}
@@ -25,6 +25,9 @@ type ReplicationEventQueue interface {
 	// 'completed'. Otherwise it won't be changed.
 	// It returns sub-set of passed in ids that were updated.
 	Acknowledge(ctx context.Context, state JobState, ids []uint64) ([]uint64, error)
+	// RecordErrors stores the errors the events failed with on their latest processing attempt. The errors
+	// are keyed by the event ID. Only events in 'in_progress' state are updated.
+	RecordErrors(ctx context.Context, errs map[uint64]string) error
 	// StartHealthUpdate starts periodical update of the event's health identifier.
 	// The events with fresh health identifier won't be considered as stale.
 	// The health update will be executed on each new entry received from trigger channel passed in.
@@ -99,6 +102,11 @@ type ReplicationEvent struct {
 	UpdatedAt *time.Time
 	Job       ReplicationJob
 	Meta      Params
+	// Priority determines the order the events are processed in. Events with a higher priority are
+	// processed before events with a lower priority.
+	Priority int
+	// Error is the error the latest processing attempt of the event failed with.
+	Error string
 }
 
 // Mapping returns list of references to the struct fields that correspond to the SQL columns/column aliases.
@@ -122,6 +130,10 @@ func (event *ReplicationEvent) Mapping(columns []string) ([]interface{}, error)
 			mapping = append(mapping, &event.Job)
 		case "meta":
 			mapping = append(mapping, &event.Meta)
+		case "priority":
+			mapping = append(mapping, &event.Priority)
+		case "error":
+			mapping = append(mapping, &event.Error)
 		default:
 			return nil, fmt.Errorf("unknown column specified in SELECT statement: %q", column)
 		}
@@ -200,6 +212,7 @@ type PostgresReplicationEventQueue struct {
 	//  - state: `ready`
 	//  - created_at: UTC timestamp
 	//  - updated_at: NULL
+	//  - priority: 0
 	//
 	// `replication_queue_job_lock` holds event specific locks to prevent multiple queue workers from operating on the same
 	// event and track the events that are protected by the <lock>.
@@ -249,7 +262,8 @@ func (rq PostgresReplicationEventQueue) Dequeue(ctx context.Context, virtualStor
 	//     in the `replication_queue_job_lock` table.
 	//  2. Events for repositories that are already locked by another Praefect instance are filtered out.
 	//     Repository locks are stored in the `replication_queue_lock` table.
-	//  3. The events that still remain after filtering are dequeued. On dequeuing:
+	//  3. The events that still remain after filtering are dequeued starting with the events of the highest priority
+	//     and the oldest events within the same priority. On dequeuing:
 	//      - The event's attempts are decremented by 1.
 	//      - The event's state is set to `in_progress`
 	//      - The event's `updated_at` is set to current time in UTC.
@@ -275,13 +289,13 @@ func (rq PostgresReplicationEventQueue) Dequeue(ctx context.Context, virtualStor
 			SELECT id
 			FROM replication_queue
 			WHERE id IN (
-				SELECT DISTINCT FIRST_VALUE(queue.id) OVER (PARTITION BY lock_id, job->>'change'  ORDER BY queue.created_at)
+				SELECT DISTINCT FIRST_VALUE(queue.id) OVER (PARTITION BY lock_id, job->>'change'  ORDER BY queue.priority DESC, queue.created_at)
 				FROM replication_queue AS queue
 				JOIN lock ON queue.lock_id = lock.id
 				WHERE queue.state IN ('ready', 'failed' )
 					AND NOT EXISTS (SELECT 1 FROM replication_queue_job_lock WHERE lock_id = queue.lock_id)
 			)
-			ORDER BY created_at
+			ORDER BY priority DESC, created_at
 			LIMIT $3
 			FOR UPDATE
 		)
@@ -425,6 +439,35 @@ func (rq PostgresReplicationEventQueue) Acknowledge(ctx context.Context, state J
 	return acknowledged.Values(), rows.Err()
 }
 
+// RecordErrors stores the errors the events failed with on their latest processing attempt.
+func (rq PostgresReplicationEventQueue) RecordErrors(ctx context.Context, errs map[uint64]string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(errs))
+	messages := make([]string, 0, len(errs))
+	for id, message := range errs {
+		ids = append(ids, int64(id))
+		messages = append(messages, message)
+	}
+
+	if _, err := rq.qc.ExecContext(ctx, `
+		UPDATE replication_queue AS queue
+		SET error = errors.error
+		FROM (
+			SELECT unnest($1::bigint[]) AS id, unnest($2::text[]) AS error
+		) AS errors
+		WHERE queue.id = errors.id
+		AND queue.state = 'in_progress'`,
+		ids, messages,
+	); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
 // StartHealthUpdate starts periodical update of the event's health identifier.
 // The events with fresh health identifier won't be considered as stale.
 // The health update will be executed on each new entry received from trigger channel passed in.
@@ -0,0 +1,149 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore/glsql"
+)
+
+// ReplicationJobFilter filters the replication jobs returned by ListJobs. Zero values don't
+// filter the jobs.
+type ReplicationJobFilter struct {
+	// VirtualStorage is the virtual storage the jobs belong to.
+	VirtualStorage string
+	// TargetStorage is the storage the jobs replicate to.
+	TargetStorage string
+	// RelativePath is the relative path of the repository the jobs replicate.
+	RelativePath string
+	// States are the states the jobs must be in.
+	States []JobState
+	// CreatedBefore filters out jobs created at or after the given time.
+	CreatedBefore time.Time
+	// CreatedAfter filters out jobs created at or before the given time.
+	CreatedAfter time.Time
+	// Limit is the maximum number of jobs returned.
+	Limit int
+}
+
+// ReplicationJobManager lists and manages the jobs waiting in the replication queue. Completed and dead
+// jobs are removed from the queue, so only jobs which are ready, in progress or failed are managed.
+type ReplicationJobManager interface {
+	// ListJobs returns the jobs matching the filter ordered by their ID.
+	ListJobs(ctx context.Context, filter ReplicationJobFilter) ([]ReplicationEvent, error)
+	// CancelJobs removes the jobs which are waiting to be processed from the queue. It returns the IDs
+	// of the cancelled jobs. Jobs being processed are not cancelled.
+	CancelJobs(ctx context.Context, ids []uint64) ([]uint64, error)
+	// PrioritizeJobs sets the priority of the jobs which are waiting to be processed. It returns the
+	// IDs of the updated jobs.
+	PrioritizeJobs(ctx context.Context, ids []uint64, priority int) ([]uint64, error)
+}
+
+// interface implementation protection
+var _ ReplicationJobManager = PostgresReplicationEventQueue{}
+
+// ListJobs returns the jobs matching the filter ordered by their ID.
+func (rq PostgresReplicationEventQueue) ListJobs(ctx context.Context, filter ReplicationJobFilter) ([]ReplicationEvent, error) {
+	states := make([]string, len(filter.States))
+	for i, state := range filter.States {
+		states[i] = state.String()
+	}
+
+	var createdBefore, createdAfter *time.Time
+	if !filter.CreatedBefore.IsZero() {
+		utc := filter.CreatedBefore.UTC()
+		createdBefore = &utc
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		utc := filter.CreatedAfter.UTC()
+		createdAfter = &utc
+	}
+
+	rows, err := rq.qc.QueryContext(ctx, `
+		SELECT id, state, created_at, updated_at, lock_id, attempt, job, meta, priority, COALESCE(error, '') AS error
+		FROM replication_queue
+		WHERE ($1 = '' OR job->>'virtual_storage' = $1)
+		AND ($2 = '' OR job->>'target_node_storage' = $2)
+		AND ($3 = '' OR job->>'relative_path' = $3)
+		AND (COALESCE(cardinality($4::text[]), 0) = 0 OR state::text = ANY($4::text[]))
+		AND ($5::timestamp IS NULL OR created_at < $5::timestamp)
+		AND ($6::timestamp IS NULL OR created_at > $6::timestamp)
+		ORDER BY id
+		LIMIT NULLIF($7::int, 0)`,
+		filter.VirtualStorage,
+		filter.TargetStorage,
+		filter.RelativePath,
+		states,
+		createdBefore,
+		createdAfter,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	events, err := scanReplicationEvents(rows)
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return events, nil
+}
+
+// CancelJobs removes the jobs which are waiting to be processed from the queue. Like completed and dead jobs,
+// the cancelled jobs are deleted. If the target replica remains outdated, the reconciler schedules a new job
+// for it.
+func (rq PostgresReplicationEventQueue) CancelJobs(ctx context.Context, ids []uint64) ([]uint64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := rq.qc.QueryContext(ctx, `
+		DELETE FROM replication_queue
+		WHERE id = ANY($1)
+		AND state IN ('ready', 'failed')
+		RETURNING id`,
+		ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	var cancelled glsql.Uint64Provider
+	if err := glsql.ScanAll(rows, &cancelled); err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return cancelled.Values(), rows.Err()
+}
+
+// PrioritizeJobs sets the priority of the jobs which are waiting to be processed. Jobs being processed keep
+// their priority.
+func (rq PostgresReplicationEventQueue) PrioritizeJobs(ctx context.Context, ids []uint64, priority int) ([]uint64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := rq.qc.QueryContext(ctx, `
+		UPDATE replication_queue
+		SET priority = $2
+		WHERE id = ANY($1)
+		AND state IN ('ready', 'failed')
+		RETURNING id`,
+		ids, priority,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	var prioritized glsql.Uint64Provider
+	if err := glsql.ScanAll(rows, &prioritized); err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return prioritized.Values(), rows.Err()
+}
@@ -0,0 +1,149 @@
+//go:build !gitaly_test_sha256
+
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testdb"
+)
+
+func TestPostgresReplicationEventQueue_ListJobs(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+	ctx := testhelper.Context(t)
+
+	queue := PostgresReplicationEventQueue{db.DB}
+
+	var events []ReplicationEvent
+	for _, job := range []ReplicationJob{
+		{Change: UpdateRepo, VirtualStorage: "praefect", RelativePath: "repository-1", SourceNodeStorage: "gitaly-1", TargetNodeStorage: "gitaly-2"},
+		{Change: UpdateRepo, VirtualStorage: "praefect", RelativePath: "repository-2", SourceNodeStorage: "gitaly-1", TargetNodeStorage: "gitaly-3"},
+		{Change: DeleteReplica, VirtualStorage: "other-praefect", RelativePath: "repository-1", TargetNodeStorage: "gitaly-2"},
+	} {
+		event, err := queue.Enqueue(ctx, ReplicationEvent{Job: job})
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	// Fail the processing of the first event so it has an error recorded.
+	dequeued, err := queue.Dequeue(ctx, "praefect", "gitaly-2", 1)
+	require.NoError(t, err)
+	require.Len(t, dequeued, 1)
+	require.NoError(t, queue.RecordErrors(ctx, map[uint64]string{dequeued[0].ID: "replication failed"}))
+	_, err = queue.Acknowledge(ctx, JobStateFailed, []uint64{dequeued[0].ID})
+	require.NoError(t, err)
+
+	jobIDs := func(tb testing.TB, filter ReplicationJobFilter) []uint64 {
+		tb.Helper()
+
+		jobs, err := queue.ListJobs(ctx, filter)
+		require.NoError(tb, err)
+
+		ids := []uint64{}
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+
+		return ids
+	}
+
+	require.Equal(t, []uint64{events[0].ID, events[1].ID, events[2].ID}, jobIDs(t, ReplicationJobFilter{}))
+	require.Equal(t, []uint64{events[0].ID, events[1].ID}, jobIDs(t, ReplicationJobFilter{VirtualStorage: "praefect"}))
+	require.Equal(t, []uint64{events[0].ID, events[2].ID}, jobIDs(t, ReplicationJobFilter{TargetStorage: "gitaly-2"}))
+	require.Equal(t, []uint64{events[0].ID, events[2].ID}, jobIDs(t, ReplicationJobFilter{RelativePath: "repository-1"}))
+	require.Equal(t, []uint64{events[0].ID}, jobIDs(t, ReplicationJobFilter{States: []JobState{JobStateFailed}}))
+	require.Equal(t, []uint64{events[1].ID, events[2].ID}, jobIDs(t, ReplicationJobFilter{States: []JobState{JobStateReady, JobStateInProgress}}))
+	require.Equal(t, []uint64{events[0].ID, events[1].ID}, jobIDs(t, ReplicationJobFilter{Limit: 2}))
+	require.Equal(t, []uint64{events[0].ID, events[1].ID, events[2].ID}, jobIDs(t, ReplicationJobFilter{CreatedBefore: time.Now().Add(time.Hour)}))
+	require.Equal(t, []uint64{}, jobIDs(t, ReplicationJobFilter{CreatedBefore: time.Now().Add(-time.Hour)}))
+	require.Equal(t, []uint64{}, jobIDs(t, ReplicationJobFilter{CreatedAfter: time.Now().Add(time.Hour)}))
+
+	jobs, err := queue.ListJobs(ctx, ReplicationJobFilter{States: []JobState{JobStateFailed}})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "replication failed", jobs[0].Error)
+	require.Equal(t, 2, jobs[0].Attempt)
+	require.Equal(t, events[0].Job, jobs[0].Job)
+}
+
+func TestPostgresReplicationEventQueue_CancelJobs(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+	ctx := testhelper.Context(t)
+
+	queue := PostgresReplicationEventQueue{db.DB}
+
+	var ids []uint64
+	for _, relativePath := range []string{"repository-1", "repository-2"} {
+		event, err := queue.Enqueue(ctx, ReplicationEvent{Job: ReplicationJob{
+			Change:            UpdateRepo,
+			VirtualStorage:    "praefect",
+			RelativePath:      relativePath,
+			SourceNodeStorage: "gitaly-1",
+			TargetNodeStorage: "gitaly-2",
+		}})
+		require.NoError(t, err)
+		ids = append(ids, event.ID)
+	}
+
+	// The first job is being processed and can't be cancelled.
+	dequeued, err := queue.Dequeue(ctx, "praefect", "gitaly-2", 1)
+	require.NoError(t, err)
+	require.Len(t, dequeued, 1)
+	require.Equal(t, ids[0], dequeued[0].ID)
+
+	cancelled, err := queue.CancelJobs(ctx, append(ids, 1000))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{ids[1]}, cancelled)
+
+	jobs, err := queue.ListJobs(ctx, ReplicationJobFilter{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, ids[0], jobs[0].ID)
+}
+
+func TestPostgresReplicationEventQueue_PrioritizeJobs(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+	ctx := testhelper.Context(t)
+
+	queue := PostgresReplicationEventQueue{db.DB}
+
+	var ids []uint64
+	for _, relativePath := range []string{"repository-1", "repository-2", "repository-3"} {
+		event, err := queue.Enqueue(ctx, ReplicationEvent{Job: ReplicationJob{
+			Change:            UpdateRepo,
+			VirtualStorage:    "praefect",
+			RelativePath:      relativePath,
+			SourceNodeStorage: "gitaly-1",
+			TargetNodeStorage: "gitaly-2",
+		}})
+		require.NoError(t, err)
+		ids = append(ids, event.ID)
+	}
+
+	prioritized, err := queue.PrioritizeJobs(ctx, []uint64{ids[2], 1000}, 10)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{ids[2]}, prioritized)
+
+	// The prioritized job is dequeued first even though it's the newest one.
+	dequeued, err := queue.Dequeue(ctx, "praefect", "gitaly-2", 1)
+	require.NoError(t, err)
+	require.Len(t, dequeued, 1)
+	require.Equal(t, ids[2], dequeued[0].ID)
+
+	// Jobs being processed are not prioritized.
+	prioritized, err = queue.PrioritizeJobs(ctx, []uint64{ids[2]}, 20)
+	require.NoError(t, err)
+	require.Empty(t, prioritized)
+
+	dequeued, err = queue.Dequeue(ctx, "praefect", "gitaly-2", 2)
+	require.NoError(t, err)
+	require.Len(t, dequeued, 2)
+	require.Equal(t, ids[0], dequeued[0].ID)
+	require.Equal(t, ids[1], dequeued[1].ID)
+}
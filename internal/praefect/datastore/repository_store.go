@@ -149,6 +149,14 @@ type RepositoryStore interface {
 	MarkVirtualStorageUnverified(ctx context.Context, virtualStorage string) (int64, error)
 	// MarkStorageUnverified marsk all replicas on the storage as unverified.
 	MarkStorageUnverified(ctx context.Context, virtualStorage, storage string) (int64, error)
+	// DrainStorage marks the storage as draining and performs a pass of moving the repositories off of it.
+	// It returns the progress of draining the storage. failureDomains contains the failure domains of the
+	// virtual storage's storages.
+	DrainStorage(ctx context.Context, virtualStorage, storage string, failureDomains map[string]string) (StorageDrainProgress, error)
+	// StopDrainingStorage stops draining the storage.
+	StopDrainingStorage(ctx context.Context, virtualStorage, storage string) error
+	// GetDrainingStorages returns the storages of the virtual storage which are being drained.
+	GetDrainingStorages(ctx context.Context, virtualStorage string) (map[string]struct{}, error)
 }
 
 // PostgresRepositoryStore is a Postgres implementation of RepositoryStore.
@@ -26,6 +26,7 @@ type MockRepositoryStore struct {
 	GetReplicaPathFunc                      func(ctx context.Context, repositoryID int64) (string, error)
 	GetRepositoryMetadataFunc               func(ctx context.Context, repositoryID int64) (RepositoryMetadata, error)
 	GetRepositoryMetadataByPathFunc         func(ctx context.Context, virtualStorage, relativePath string) (RepositoryMetadata, error)
+	GetDrainingStoragesFunc                 func(ctx context.Context, virtualStorage string) (map[string]struct{}, error)
 }
 
 //nolint:revive // This is unintentionally missing documentation.
@@ -191,3 +192,12 @@ func (m MockRepositoryStore) GetRepositoryMetadata(ctx context.Context, reposito
 func (m MockRepositoryStore) GetRepositoryMetadataByPath(ctx context.Context, virtualStorage, relativePath string) (RepositoryMetadata, error) {
 	return m.GetRepositoryMetadataByPathFunc(ctx, virtualStorage, relativePath)
 }
+
+// GetDrainingStorages returns the result of GetDrainingStoragesFunc or no storages if it is unset.
+func (m MockRepositoryStore) GetDrainingStorages(ctx context.Context, virtualStorage string) (map[string]struct{}, error) {
+	if m.GetDrainingStoragesFunc == nil {
+		return nil, nil
+	}
+
+	return m.GetDrainingStoragesFunc(ctx, virtualStorage)
+}
@@ -0,0 +1,302 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore/glsql"
+)
+
+// StorageDrainProgress describes how far draining a storage has progressed.
+type StorageDrainProgress struct {
+	// Repositories is the number of repositories which still have a replica on the storage or
+	// are still assigned to it. The storage is drained once this drops to zero.
+	Repositories int64
+	// Primaries is the number of repositories whose primary is still on the storage.
+	Primaries int64
+	// Moves is the number of repositories whose replica is currently being moved from the storage
+	// to another storage.
+	Moves int64
+	// Unmovable contains the relative paths of the repositories which can't be moved off of the
+	// storage because no other storage of the virtual storage can take their replica.
+	Unmovable []string
+}
+
+// DrainStorage marks the storage as draining and moves the repositories off of it. Draining
+// storages are not elected as primaries nor picked for new assignments. Each call performs a
+// single pass which:
+//
+//  1. Stores the assignments of repositories which have a replica on the storage but no explicit
+//     assignments. Every configured storage is considered assigned for such repositories, so this
+//     doesn't change their replication.
+//  2. Assigns a replacement storage for each repository assigned to the draining storage. The
+//     replacement is a random configured storage which is neither draining nor assigned yet. Storages
+//     in the failure domains with the fewest other assigned storages are picked first, so the
+//     replicas stay spread across the failure domains. The reconciler then replicates the
+//     repository to the replacement. Repositories for which no replacement was found are
+//     considered again on the next pass.
+//  3. Moves the primaries away from the storage to a valid primary on a storage which is not
+//     draining.
+//  4. Unassigns the draining storage from the repositories whose replacement is up to date and
+//     whose primary has moved. The reconciler then deletes the replica from the drained storage.
+//
+// The draining storage stays assigned until its replacement is up to date so the repositories
+// don't lose a replica while the storage is being drained. DrainStorage should be called
+// repeatedly until the returned progress shows no repositories remaining on the storage.
+// failureDomains contains the failure domains of the virtual storage's storages. Storages without
+// a failure domain are considered to be in a domain of their own.
+func (rs *PostgresRepositoryStore) DrainStorage(ctx context.Context, virtualStorage, storage string, failureDomains map[string]string) (StorageDrainProgress, error) {
+	configuredStorages := rs.storages[virtualStorage]
+
+	domainStorages := make([]string, 0, len(failureDomains))
+	domains := make([]string, 0, len(failureDomains))
+	for domainStorage, failureDomain := range failureDomains {
+		domainStorages = append(domainStorages, domainStorage)
+		domains = append(domains, failureDomain)
+	}
+
+	for _, step := range []struct {
+		desc  string
+		query string
+		args  []interface{}
+	}{
+		{
+			desc: "mark draining",
+			query: `
+INSERT INTO storage_drains (virtual_storage, storage)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`,
+			args: []interface{}{virtualStorage, storage},
+		},
+		{
+			desc: "store implicit assignments",
+			query: `
+INSERT INTO repository_assignments (virtual_storage, relative_path, storage, repository_id)
+SELECT virtual_storage, relative_path, configured_storage, repository_id
+FROM repositories
+CROSS JOIN unnest($3::text[]) AS configured_storage
+WHERE virtual_storage = $1
+AND EXISTS (
+	SELECT FROM storage_repositories
+	WHERE repository_id = repositories.repository_id
+	AND storage = $2
+)
+AND NOT EXISTS (
+	SELECT FROM repository_assignments
+	WHERE repository_id = repositories.repository_id
+)
+ON CONFLICT DO NOTHING
+`,
+			args: []interface{}{virtualStorage, storage, configuredStorages},
+		},
+		{
+			desc: "assign replacements",
+			query: `
+WITH failure_domains AS (
+	SELECT unnest($4::text[]) AS storage, unnest($5::text[]) AS failure_domain
+),
+
+planned_moves AS (
+	INSERT INTO storage_drain_moves (repository_id, virtual_storage, source_storage, target_storage)
+	SELECT repository_id, virtual_storage, storage, (
+		SELECT candidate
+		FROM unnest($3::text[]) AS candidate
+		LEFT JOIN failure_domains ON failure_domains.storage = candidate
+		WHERE candidate NOT IN (
+			SELECT storage
+			FROM repository_assignments AS assigned
+			WHERE assigned.repository_id = repository_assignments.repository_id
+		)
+		AND candidate NOT IN (
+			SELECT storage
+			FROM storage_drains
+			WHERE virtual_storage = $1
+		)
+		ORDER BY (
+			SELECT COUNT(*)
+			FROM repository_assignments AS assigned
+			JOIN failure_domains AS assigned_domains ON assigned_domains.storage = assigned.storage
+			WHERE assigned.repository_id = repository_assignments.repository_id
+			AND assigned.storage != $2
+			AND assigned_domains.failure_domain = failure_domains.failure_domain
+		), random()
+		LIMIT 1
+	)
+	FROM repository_assignments
+	WHERE virtual_storage = $1
+	AND storage = $2
+	ON CONFLICT (repository_id, source_storage) DO UPDATE
+	SET target_storage = excluded.target_storage
+	WHERE storage_drain_moves.target_storage IS NULL
+	RETURNING repository_id, target_storage
+)
+
+INSERT INTO repository_assignments (virtual_storage, relative_path, storage, repository_id)
+SELECT virtual_storage, relative_path, target_storage, repository_id
+FROM planned_moves
+JOIN repositories USING (repository_id)
+WHERE target_storage IS NOT NULL
+ON CONFLICT DO NOTHING
+`,
+			args: []interface{}{virtualStorage, storage, configuredStorages, domainStorages, domains},
+		},
+		{
+			desc: "move primaries",
+			query: `
+UPDATE repositories
+SET "primary" = candidates.storage
+FROM (
+	SELECT DISTINCT ON (repository_id) repository_id, storage
+	FROM valid_primaries
+	WHERE repository_id IN (
+		SELECT repository_id
+		FROM repositories
+		WHERE virtual_storage = $1
+		AND "primary" = $2
+	)
+	AND storage NOT IN (
+		SELECT storage
+		FROM storage_drains
+		WHERE virtual_storage = $1
+	)
+	ORDER BY repository_id, random()
+) AS candidates
+WHERE repositories.repository_id = candidates.repository_id
+AND repositories."primary" = $2
+`,
+			args: []interface{}{virtualStorage, storage},
+		},
+		{
+			desc: "unassign drained storage",
+			query: `
+WITH completed_moves AS (
+	DELETE FROM storage_drain_moves
+	USING repositories
+	WHERE storage_drain_moves.repository_id = repositories.repository_id
+	AND storage_drain_moves.virtual_storage = $1
+	AND storage_drain_moves.source_storage = $2
+	AND repositories."primary" IS DISTINCT FROM $2
+	AND (
+		-- If there was no storage to replace the draining one with, the draining storage can be
+		-- unassigned as long as some other storage remains assigned.
+		target_storage IS NULL AND EXISTS (
+			SELECT FROM repository_assignments
+			WHERE repository_id = repositories.repository_id
+			AND storage != $2
+		)
+		OR EXISTS (
+			SELECT FROM storage_repositories
+			WHERE repository_id = repositories.repository_id
+			AND storage = target_storage
+			AND generation = repositories.generation
+		)
+	)
+	RETURNING storage_drain_moves.repository_id
+)
+
+DELETE FROM repository_assignments
+USING completed_moves
+WHERE repository_assignments.repository_id = completed_moves.repository_id
+AND repository_assignments.storage = $2
+`,
+			args: []interface{}{virtualStorage, storage},
+		},
+	} {
+		if _, err := rs.db.ExecContext(ctx, step.query, step.args...); err != nil {
+			return StorageDrainProgress{}, fmt.Errorf("%s: %w", step.desc, err)
+		}
+	}
+
+	var progress StorageDrainProgress
+	var unmovable glsql.StringArray
+	if err := rs.db.QueryRowContext(ctx, `
+SELECT
+	(
+		SELECT COUNT(*)
+		FROM repositories
+		WHERE virtual_storage = $1
+		AND (
+			EXISTS (
+				SELECT FROM storage_repositories
+				WHERE repository_id = repositories.repository_id
+				AND storage = $2
+			) OR EXISTS (
+				SELECT FROM repository_assignments
+				WHERE repository_id = repositories.repository_id
+				AND storage = $2
+			)
+		)
+	),
+	(
+		SELECT COUNT(*)
+		FROM repositories
+		WHERE virtual_storage = $1
+		AND "primary" = $2
+	),
+	(
+		SELECT COUNT(*)
+		FROM storage_drain_moves
+		WHERE virtual_storage = $1
+		AND source_storage = $2
+	),
+	ARRAY(
+		SELECT relative_path
+		FROM storage_drain_moves
+		JOIN repositories USING (repository_id)
+		WHERE storage_drain_moves.virtual_storage = $1
+		AND source_storage = $2
+		AND target_storage IS NULL
+		AND NOT EXISTS (
+			SELECT FROM repository_assignments
+			WHERE repository_id = repositories.repository_id
+			AND storage != $2
+		)
+		ORDER BY relative_path
+	)
+`, virtualStorage, storage).Scan(&progress.Repositories, &progress.Primaries, &progress.Moves, &unmovable); err != nil {
+		return StorageDrainProgress{}, fmt.Errorf("progress: %w", err)
+	}
+
+	if paths := unmovable.Slice(); len(paths) > 0 {
+		progress.Unmovable = paths
+	}
+
+	return progress, nil
+}
+
+// StopDrainingStorage stops draining the storage. Repositories which were already moved off of the
+// storage are not moved back. Replacements assigned for repositories which were still being moved
+// remain assigned along with the storage.
+func (rs *PostgresRepositoryStore) StopDrainingStorage(ctx context.Context, virtualStorage, storage string) error {
+	if _, err := rs.db.ExecContext(ctx, `
+DELETE FROM storage_drains
+WHERE virtual_storage = $1
+AND storage = $2
+`, virtualStorage, storage); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+// GetDrainingStorages returns the storages of the virtual storage which are being drained.
+func (rs *PostgresRepositoryStore) GetDrainingStorages(ctx context.Context, virtualStorage string) (map[string]struct{}, error) {
+	var storages glsql.StringArray
+	if err := rs.db.QueryRowContext(ctx, `
+SELECT ARRAY(
+	SELECT storage
+	FROM storage_drains
+	WHERE virtual_storage = $1
+)
+`, virtualStorage).Scan(&storages); err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	draining := make(map[string]struct{}, len(storages.Slice()))
+	for _, storage := range storages.Slice() {
+		draining[storage] = struct{}{}
+	}
+
+	return draining, nil
+}
@@ -0,0 +1,189 @@
+//go:build !gitaly_test_sha256
+
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore/glsql"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testdb"
+)
+
+func TestPostgresRepositoryStore_DrainStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+	db := testdb.New(t)
+
+	tx := db.Begin(t)
+	defer tx.Rollback(t)
+
+	testdb.SetHealthyNodes(t, ctx, tx, map[string]map[string][]string{
+		"praefect-0": {"virtual-storage": {"gitaly-1", "gitaly-2", "gitaly-3"}},
+	})
+
+	rs := NewPostgresRepositoryStore(tx, map[string][]string{
+		"virtual-storage": {"gitaly-1", "gitaly-2", "gitaly-3"},
+	})
+
+	// Repository 1 has explicit assignments and its primary on the drained storage.
+	require.NoError(t, rs.CreateRepository(ctx, 1, "virtual-storage", "repository-1", "replica-path-1", "gitaly-1", []string{"gitaly-2"}, nil, true, true))
+	// Repository 2 has no explicit assignments, so every storage is considered assigned.
+	require.NoError(t, rs.CreateRepository(ctx, 2, "virtual-storage", "repository-2", "replica-path-2", "gitaly-2", []string{"gitaly-1"}, nil, true, false))
+
+	draining, err := rs.GetDrainingStorages(ctx, "virtual-storage")
+	require.NoError(t, err)
+	require.Empty(t, draining)
+
+	progress, err := rs.DrainStorage(ctx, "virtual-storage", "gitaly-1", nil)
+	require.NoError(t, err)
+	// Repository 1 is being moved to gitaly-3 and its primary moved to the other up to date
+	// replica. Repository 2 has no storage to move to, so gitaly-1 is unassigned right away.
+	require.Equal(t, StorageDrainProgress{Repositories: 2, Primaries: 0, Moves: 1}, progress)
+	requireAssignments(t, ctx, tx, map[int64][]string{
+		1: {"gitaly-1", "gitaly-2", "gitaly-3"},
+		2: {"gitaly-2", "gitaly-3"},
+	})
+
+	primary, err := rs.GetRepositoryMetadata(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "gitaly-2", primary.Primary)
+
+	draining, err = rs.GetDrainingStorages(ctx, "virtual-storage")
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"gitaly-1": {}}, draining)
+
+	// Draining again doesn't pick further replacements while the move is in progress.
+	progress, err = rs.DrainStorage(ctx, "virtual-storage", "gitaly-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, StorageDrainProgress{Repositories: 2, Primaries: 0, Moves: 1}, progress)
+	requireAssignments(t, ctx, tx, map[int64][]string{
+		1: {"gitaly-1", "gitaly-2", "gitaly-3"},
+		2: {"gitaly-2", "gitaly-3"},
+	})
+
+	// Once the replacement has been replicated to, the drained storage is unassigned.
+	require.NoError(t, rs.SetGeneration(ctx, 1, "gitaly-3", "repository-1", 0))
+
+	progress, err = rs.DrainStorage(ctx, "virtual-storage", "gitaly-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, StorageDrainProgress{Repositories: 2, Primaries: 0, Moves: 0}, progress)
+	requireAssignments(t, ctx, tx, map[int64][]string{
+		1: {"gitaly-2", "gitaly-3"},
+		2: {"gitaly-2", "gitaly-3"},
+	})
+
+	// The storage is drained once the reconciler has deleted the unassigned replicas.
+	require.NoError(t, rs.DeleteReplica(ctx, 1, "gitaly-1"))
+	require.NoError(t, rs.DeleteReplica(ctx, 2, "gitaly-1"))
+
+	progress, err = rs.DrainStorage(ctx, "virtual-storage", "gitaly-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, StorageDrainProgress{}, progress)
+
+	require.NoError(t, rs.StopDrainingStorage(ctx, "virtual-storage", "gitaly-1"))
+
+	draining, err = rs.GetDrainingStorages(ctx, "virtual-storage")
+	require.NoError(t, err)
+	require.Empty(t, draining)
+}
+
+func TestPostgresRepositoryStore_DrainStorage_failureDomains(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+	db := testdb.New(t)
+
+	tx := db.Begin(t)
+	defer tx.Rollback(t)
+
+	storages := []string{"gitaly-1", "gitaly-2", "gitaly-3", "gitaly-4"}
+	testdb.SetHealthyNodes(t, ctx, tx, map[string]map[string][]string{
+		"praefect-0": {"virtual-storage": storages},
+	})
+
+	rs := NewPostgresRepositoryStore(tx, map[string][]string{"virtual-storage": storages})
+
+	require.NoError(t, rs.CreateRepository(ctx, 1, "virtual-storage", "repository-1", "replica-path-1", "gitaly-2", []string{"gitaly-1"}, nil, true, true))
+
+	// gitaly-3 is in the same failure domain as gitaly-2, so gitaly-4 replaces gitaly-1 to keep the
+	// replicas in two failure domains.
+	progress, err := rs.DrainStorage(ctx, "virtual-storage", "gitaly-1", map[string]string{
+		"gitaly-1": "zone-a",
+		"gitaly-2": "zone-b",
+		"gitaly-3": "zone-b",
+		"gitaly-4": "zone-a",
+	})
+	require.NoError(t, err)
+	require.Equal(t, StorageDrainProgress{Repositories: 1, Primaries: 0, Moves: 1}, progress)
+	requireAssignments(t, ctx, tx, map[int64][]string{
+		1: {"gitaly-1", "gitaly-2", "gitaly-4"},
+	})
+}
+
+func TestPostgresRepositoryStore_DrainStorage_unmovable(t *testing.T) {
+	t.Parallel()
+
+	ctx := testhelper.Context(t)
+	db := testdb.New(t)
+
+	tx := db.Begin(t)
+	defer tx.Rollback(t)
+
+	storages := []string{"gitaly-1", "gitaly-2"}
+	testdb.SetHealthyNodes(t, ctx, tx, map[string]map[string][]string{
+		"praefect-0": {"virtual-storage": storages},
+	})
+
+	rs := NewPostgresRepositoryStore(tx, map[string][]string{"virtual-storage": storages})
+
+	require.NoError(t, rs.CreateRepository(ctx, 1, "virtual-storage", "repository-1", "replica-path-1", "gitaly-1", nil, nil, true, true))
+
+	progress, err := rs.DrainStorage(ctx, "virtual-storage", "gitaly-2", nil)
+	require.NoError(t, err)
+	require.Equal(t, StorageDrainProgress{}, progress)
+
+	// The only other storage is draining as well, so the repository can't be moved anywhere.
+	progress, err = rs.DrainStorage(ctx, "virtual-storage", "gitaly-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, StorageDrainProgress{Repositories: 1, Primaries: 1, Moves: 1, Unmovable: []string{"repository-1"}}, progress)
+	requireAssignments(t, ctx, tx, map[int64][]string{
+		1: {"gitaly-1"},
+	})
+
+	// Once gitaly-2 can take replicas again, it's picked as the replacement on the next pass.
+	require.NoError(t, rs.StopDrainingStorage(ctx, "virtual-storage", "gitaly-2"))
+
+	progress, err = rs.DrainStorage(ctx, "virtual-storage", "gitaly-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, StorageDrainProgress{Repositories: 1, Primaries: 1, Moves: 1}, progress)
+	requireAssignments(t, ctx, tx, map[int64][]string{
+		1: {"gitaly-1", "gitaly-2"},
+	})
+}
+
+func requireAssignments(tb testing.TB, ctx context.Context, db glsql.Querier, expected map[int64][]string) {
+	tb.Helper()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT repository_id, storage
+		FROM repository_assignments
+		ORDER BY repository_id, storage
+	`)
+	require.NoError(tb, err)
+	defer rows.Close()
+
+	actual := map[int64][]string{}
+	for rows.Next() {
+		var repositoryID int64
+		var storage string
+		require.NoError(tb, rows.Scan(&repositoryID, &storage))
+		actual[repositoryID] = append(actual[repositoryID], storage)
+	}
+
+	require.NoError(tb, rows.Err())
+	require.Equal(tb, expected, actual)
+}
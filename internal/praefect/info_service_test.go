@@ -48,6 +48,8 @@ func TestInfoService_RepositoryReplicas(t *testing.T) {
 				deps.GetConnsPool(),
 				deps.GetGit2goExecutor(),
 				deps.GetHousekeepingManager(),
+				deps.GetBackupSink(),
+				deps.GetBackupLocator(),
 			))
 		}, testserver.WithDisablePraefect())
 		cfgNodes = append(cfgNodes, &config.Node{
@@ -95,7 +97,7 @@ func TestInfoService_RepositoryReplicas(t *testing.T) {
 	testdb.SetHealthyNodes(t, ctx, tx, map[string]map[string][]string{
 		"praefect-0": {virtualStorage: storages[0:1]},
 	})
-	elector := nodes.NewPerRepositoryElector(tx)
+	elector := nodes.NewPerRepositoryElector(tx, nil)
 
 	conns := nodeSet.Connections()
 	rs := datastore.NewPostgresRepositoryStore(db, conf.StorageNames())
@@ -109,9 +111,10 @@ func TestInfoService_RepositoryReplicas(t *testing.T) {
 			StaticHealthChecker{virtualStorage: storages},
 			NewLockedRandom(rand.New(rand.NewSource(0))),
 			rs,
-			datastore.NewAssignmentStore(db, conf.StorageNames()),
+			datastore.NewAssignmentStore(db, conf.StorageNames(), nil),
 			rs,
 			conf.DefaultReplicationFactors(),
+			nil,
 		),
 		WithPrimaryGetter: elector,
 		WithTxMgr:         txManager,
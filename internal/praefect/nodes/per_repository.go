@@ -18,11 +18,18 @@ var ErrNoPrimary = errors.New("no primary")
 // PerRepositoryElector implements an elector that selects a primary for each repository.
 // It elects a healthy node with most recent generation as the primary. If all nodes are
 // on the same generation, it picks one randomly to balance repositories in simple fashion.
-type PerRepositoryElector struct{ db glsql.Querier }
+// Storages which are being drained are only elected if there are no other valid candidates.
+// Candidates outside of the previous primary's failure domain are preferred, as the failure
+// that made the previous primary invalid may affect its whole domain.
+type PerRepositoryElector struct {
+	db             glsql.Querier
+	failureDomains map[string]map[string]string
+}
 
-// NewPerRepositoryElector returns a new per repository primary elector.
-func NewPerRepositoryElector(db glsql.Querier) *PerRepositoryElector {
-	return &PerRepositoryElector{db: db}
+// NewPerRepositoryElector returns a new per repository primary elector. failureDomains contains
+// the failure domains of the storages by their virtual storage.
+func NewPerRepositoryElector(db glsql.Querier, failureDomains map[string]map[string]string) *PerRepositoryElector {
+	return &PerRepositoryElector{db: db, failureDomains: failureDomains}
 }
 
 // GetPrimary returns the primary storage of a repository. If the current primary is invalid, a new primary
@@ -46,9 +53,20 @@ func (pr *PerRepositoryElector) GetPrimary(ctx context.Context, virtualStorage s
 	//      recent change
 	//   2. `reread`, as this indicates a concurrent transaction had potentially changed the primary.
 	//   3. `snapshot`, if the current primary was valid in the transcation's database snapshot.
+	storages := make([]string, 0, len(pr.failureDomains[virtualStorage]))
+	failureDomains := make([]string, 0, len(pr.failureDomains[virtualStorage]))
+	for storage, failureDomain := range pr.failureDomains[virtualStorage] {
+		storages = append(storages, storage)
+		failureDomains = append(failureDomains, failureDomain)
+	}
+
 	var current, previous sql.NullString
 	if err := pr.db.QueryRowContext(ctx, `
-WITH reread AS (
+WITH failure_domains AS (
+	SELECT unnest($2::text[]) AS storage, unnest($3::text[]) AS failure_domain
+),
+
+reread AS (
 	SELECT true AS valid, repository_id, "primary"
 	FROM repositories
 	WHERE repository_id = $1
@@ -69,7 +87,16 @@ election AS (
 		SELECT storage
 		FROM valid_primaries
 		WHERE valid_primaries.repository_id = repositories.repository_id
-		ORDER BY random()
+		ORDER BY EXISTS (
+			SELECT FROM storage_drains
+			WHERE storage_drains.virtual_storage = valid_primaries.virtual_storage
+			AND storage_drains.storage = valid_primaries.storage
+		), EXISTS (
+			SELECT FROM failure_domains AS candidate
+			JOIN failure_domains AS previous USING (failure_domain)
+			WHERE candidate.storage = valid_primaries.storage
+			AND previous.storage = reread.primary
+		), random()
 		LIMIT 1
 	)
 	FROM reread
@@ -100,7 +127,7 @@ LEFT JOIN reread ON reread.valid
 LEFT JOIN election ON election.valid
 WHERE snapshot.repository_id = $1
 `,
-		repositoryID,
+		repositoryID, storages, failureDomains,
 	).Scan(&current, &previous); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", commonerr.ErrRepositoryNotFound
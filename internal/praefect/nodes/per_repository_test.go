@@ -534,7 +534,7 @@ func TestPerRepositoryElector(t *testing.T) {
 			for _, step := range tc.steps {
 				runElection := func(tx *testdb.TxWrapper) (string, *logrus.Entry) {
 					logger, hook := test.NewNullLogger()
-					elector := NewPerRepositoryElector(tx)
+					elector := NewPerRepositoryElector(tx, nil)
 
 					primary, err := elector.GetPrimary(ctxlogrus.ToContext(ctx, logrus.NewEntry(logger)), "", repositoryID)
 					assert.Equal(t, step.error, err)
@@ -614,3 +614,27 @@ func TestPerRepositoryElector(t *testing.T) {
 		})
 	}
 }
+
+func TestPerRepositoryElector_failureDomains(t *testing.T) {
+	t.Parallel()
+	ctx := testhelper.Context(t)
+
+	db := testdb.New(t)
+
+	rs := datastore.NewPostgresRepositoryStore(db, nil)
+	require.NoError(t, rs.CreateRepository(ctx, 1, "virtual-storage", "relative-path", "relative-path", "gitaly-1", []string{"gitaly-2", "gitaly-3"}, nil, true, false))
+
+	// gitaly-1 failing leaves gitaly-2 and gitaly-3 as candidates. gitaly-3 is preferred as it is
+	// not in the same failure domain as the failed primary.
+	testdb.SetHealthyNodes(t, ctx, db, map[string]map[string][]string{
+		"praefect-0": {"virtual-storage": {"gitaly-2", "gitaly-3"}},
+	})
+
+	elector := NewPerRepositoryElector(db, map[string]map[string]string{
+		"virtual-storage": {"gitaly-1": "zone-a", "gitaly-2": "zone-a", "gitaly-3": "zone-b"},
+	})
+
+	primary, err := elector.GetPrimary(ctx, "virtual-storage", 1)
+	require.NoError(t, err)
+	require.Equal(t, "gitaly-3", primary)
+}
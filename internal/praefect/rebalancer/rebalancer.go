@@ -0,0 +1,524 @@
+package rebalancer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore/advisorylock"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore/glsql"
+)
+
+// candidateBatchSize is the number of repositories considered at once when picking a replica to
+// move from a storage.
+const candidateBatchSize = 100
+
+// Move describes a replica being moved from one storage to another.
+type Move struct {
+	VirtualStorage string `json:"virtual_storage"`
+	RepositoryID   int64  `json:"repository_id"`
+	RelativePath   string `json:"relative_path"`
+	SourceStorage  string `json:"source_storage"`
+	TargetStorage  string `json:"target_storage"`
+	// Size is the size of the replica in bytes.
+	Size int64 `json:"size"`
+}
+
+// Rebalancer moves replicas from the fullest storages of a virtual storage to the emptiest ones
+// based on their disk usage.
+//
+// A replica is moved by assigning the target storage in place of the source storage. The reconciler
+// then replicates the repository to the target storage and deletes the replica from the source storage
+// once all of the assigned replicas are up to date. Replicas which are pending deletion from unassigned
+// storages count towards the maximum number of moves, so the rebalancer only moves further replicas once
+// the reconciler has finished the earlier moves. Replicas are not moved if the move would reduce the
+// number of failure domains the repository's replicas are spread across.
+type Rebalancer struct {
+	log            logrus.FieldLogger
+	db             glsql.Querier
+	hc             praefect.HealthChecker
+	stats          Statistics
+	maxMoves       int
+	usageThreshold float64
+	dryRun         bool
+	failureDomains map[string]map[string]string
+	// handleError is called with a possible error from rebalance.
+	// If it returns an error, Run stops and returns with the error.
+	handleError func(error) error
+}
+
+// NewRebalancer returns a new Rebalancer configured with the given rebalancing configuration. failureDomains
+// contains the failure domains of the storages by their virtual storage.
+func NewRebalancer(
+	log logrus.FieldLogger,
+	db glsql.Querier,
+	hc praefect.HealthChecker,
+	stats Statistics,
+	cfg config.Rebalancing,
+	failureDomains map[string]map[string]string,
+) *Rebalancer {
+	log = log.WithField("component", "rebalancer")
+
+	return &Rebalancer{
+		log:            log,
+		db:             db,
+		hc:             hc,
+		stats:          stats,
+		maxMoves:       cfg.MaxMoves,
+		usageThreshold: cfg.UsageThreshold,
+		dryRun:         cfg.DryRun,
+		failureDomains: failureDomains,
+		handleError: func(err error) error {
+			log.WithError(err).Error("automatic rebalancing failed")
+			return nil
+		},
+	}
+}
+
+// Run rebalances on each tick the Ticker emits. Run returns
+// when the context is canceled, returning the error from the context.
+func (r *Rebalancer) Run(ctx context.Context, ticker helper.Ticker) error {
+	r.log.WithField("dry_run", r.dryRun).Info("automatic rebalancer started")
+	defer r.log.Info("automatic rebalancer stopped")
+
+	defer ticker.Stop()
+
+	for {
+		ticker.Reset()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+			if err := r.rebalance(ctx); err != nil {
+				if err := r.handleError(err); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (r *Rebalancer) rebalance(ctx context.Context) error {
+	moves, err := r.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("plan: %w", err)
+	}
+
+	if r.dryRun {
+		for _, move := range moves {
+			r.log.WithField("move", move).Info("planned replica move")
+		}
+
+		return nil
+	}
+
+	applied, err := r.Apply(ctx, moves)
+	if err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+
+	for _, move := range applied {
+		r.log.WithField("move", move).Info("moving replica")
+	}
+
+	return nil
+}
+
+// storageUsage is the disk usage of a storage.
+type storageUsage struct {
+	storage   string
+	used      int64
+	available int64
+}
+
+func (u storageUsage) ratio() float64 {
+	return float64(u.used) / float64(u.used+u.available)
+}
+
+// Plan plans the moves needed to even out the disk usage of the healthy storages. Storages which
+// are being drained are neither moved from nor moved to. The disk usage is simulated as the moves
+// are planned, so the moves stop once the fullest and the emptiest storages' usage differs by less
+// than the configured threshold.
+func (r *Rebalancer) Plan(ctx context.Context) ([]Move, error) {
+	healthyNodes := r.hc.HealthyNodes()
+
+	virtualStorages := make([]string, 0, len(healthyNodes))
+	for virtualStorage := range healthyNodes {
+		virtualStorages = append(virtualStorages, virtualStorage)
+	}
+	sort.Strings(virtualStorages)
+
+	var moves []Move
+	for _, virtualStorage := range virtualStorages {
+		virtualStorageMoves, err := r.planVirtualStorage(ctx, virtualStorage, healthyNodes[virtualStorage])
+		if err != nil {
+			return nil, fmt.Errorf("virtual storage %q: %w", virtualStorage, err)
+		}
+
+		moves = append(moves, virtualStorageMoves...)
+	}
+
+	return moves, nil
+}
+
+func (r *Rebalancer) planVirtualStorage(ctx context.Context, virtualStorage string, healthyStorages []string) ([]Move, error) {
+	log := r.log.WithField("virtual_storage", virtualStorage)
+
+	if len(healthyStorages) < 2 {
+		log.Info("rebalancing skipped for virtual storage due to not having enough healthy storages")
+		return nil, nil
+	}
+
+	inFlight, err := r.movesInFlight(ctx, virtualStorage)
+	if err != nil {
+		return nil, fmt.Errorf("moves in flight: %w", err)
+	}
+
+	budget := r.maxMoves - inFlight
+	if budget <= 0 {
+		log.WithField("moves_in_flight", inFlight).Info("rebalancing skipped for virtual storage until earlier moves are finished")
+		return nil, nil
+	}
+
+	draining, err := r.drainingStorages(ctx, virtualStorage)
+	if err != nil {
+		return nil, fmt.Errorf("draining storages: %w", err)
+	}
+
+	var usages []*storageUsage
+	for _, storage := range healthyStorages {
+		if _, ok := draining[storage]; ok {
+			continue
+		}
+
+		used, available, err := r.stats.DiskUsage(ctx, virtualStorage, storage)
+		if err != nil {
+			log.WithError(err).WithField("storage", storage).Warn("failed to get disk usage")
+			continue
+		}
+
+		usages = append(usages, &storageUsage{storage: storage, used: used, available: available})
+	}
+
+	if len(usages) < 2 {
+		return nil, nil
+	}
+
+	var moves []Move
+	planned := map[int64]struct{}{}
+	sizes := map[int64]int64{}
+	for len(moves) < budget {
+		sort.Slice(usages, func(i, j int) bool { return usages[i].ratio() > usages[j].ratio() })
+		source, target := usages[0], usages[len(usages)-1]
+
+		if (source.ratio()-target.ratio())*100 < r.usageThreshold {
+			break
+		}
+
+		move, ok, err := r.pickReplica(ctx, virtualStorage, *source, *target, planned, sizes)
+		if err != nil {
+			return nil, fmt.Errorf("pick replica: %w", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		source.used -= move.Size
+		source.available += move.Size
+		target.used += move.Size
+		target.available -= move.Size
+
+		planned[move.RepositoryID] = struct{}{}
+		moves = append(moves, move)
+	}
+
+	return moves, nil
+}
+
+// pickReplica picks the largest replica on the source storage which can be moved to the target storage
+// without the target becoming fuller than the source. Only replicas of repositories which have explicit
+// assignments, whose primary is not on the source storage and whose assigned replicas are all up to
+// date are considered. Replicas are only moved to a storage in another failure domain if the target's
+// failure domain has no other assigned replica of the repository, or if the source's failure domain
+// keeps one. Storages without a failure domain are considered to be in a domain of their own.
+func (r *Rebalancer) pickReplica(
+	ctx context.Context,
+	virtualStorage string,
+	source, target storageUsage,
+	planned map[int64]struct{},
+	sizes map[int64]int64,
+) (Move, bool, error) {
+	excluded := make([]int64, 0, len(planned))
+	for repositoryID := range planned {
+		excluded = append(excluded, repositoryID)
+	}
+
+	failureDomains := r.failureDomains[virtualStorage]
+	domainStorages := make([]string, 0, len(failureDomains))
+	domains := make([]string, 0, len(failureDomains))
+	for storage, failureDomain := range failureDomains {
+		domainStorages = append(domainStorages, storage)
+		domains = append(domains, failureDomain)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+WITH failure_domains AS (
+	SELECT unnest($6::text[]) AS storage, unnest($7::text[]) AS failure_domain
+)
+
+SELECT repositories.repository_id, repositories.relative_path, repositories.replica_path
+FROM repositories
+JOIN repository_assignments
+	ON repository_assignments.repository_id = repositories.repository_id
+	AND repository_assignments.storage = $2
+JOIN storage_repositories
+	ON storage_repositories.repository_id = repositories.repository_id
+	AND storage_repositories.storage = $2
+WHERE repositories.virtual_storage = $1
+AND repositories."primary" IS DISTINCT FROM $2
+AND NOT repositories.repository_id = ANY($4::bigint[])
+AND NOT EXISTS (
+	SELECT FROM repository_assignments AS target
+	WHERE target.repository_id = repositories.repository_id
+	AND target.storage = $3
+)
+AND NOT EXISTS (
+	SELECT FROM repository_assignments AS assigned
+	LEFT JOIN storage_repositories AS replica
+		ON replica.repository_id = assigned.repository_id
+		AND replica.storage = assigned.storage
+	WHERE assigned.repository_id = repositories.repository_id
+	AND (replica.generation IS NULL OR replica.generation < repositories.generation)
+)
+AND NOT EXISTS (
+	SELECT FROM storage_drain_moves
+	WHERE storage_drain_moves.repository_id = repositories.repository_id
+)
+AND (
+	$9 = ''
+	OR $8 = $9
+	OR NOT EXISTS (
+		SELECT FROM repository_assignments AS assigned
+		JOIN failure_domains ON failure_domains.storage = assigned.storage
+		WHERE assigned.repository_id = repositories.repository_id
+		AND assigned.storage != $2
+		AND failure_domains.failure_domain = $9
+	)
+	OR EXISTS (
+		SELECT FROM repository_assignments AS assigned
+		JOIN failure_domains ON failure_domains.storage = assigned.storage
+		WHERE assigned.repository_id = repositories.repository_id
+		AND assigned.storage != $2
+		AND failure_domains.failure_domain = $8
+	)
+)
+ORDER BY repositories.repository_id
+LIMIT $5
+`,
+		virtualStorage, source.storage, target.storage, excluded, candidateBatchSize,
+		domainStorages, domains, failureDomains[source.storage], failureDomains[target.storage],
+	)
+	if err != nil {
+		return Move{}, false, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	type candidate struct {
+		move        Move
+		replicaPath string
+	}
+
+	var candidates []candidate
+	for rows.Next() {
+		c := candidate{move: Move{
+			VirtualStorage: virtualStorage,
+			SourceStorage:  source.storage,
+			TargetStorage:  target.storage,
+		}}
+
+		if err := rows.Scan(&c.move.RepositoryID, &c.move.RelativePath, &c.replicaPath); err != nil {
+			return Move{}, false, fmt.Errorf("scan: %w", err)
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Move{}, false, fmt.Errorf("rows: %w", err)
+	}
+
+	var best Move
+	for _, c := range candidates {
+		size, ok := sizes[c.move.RepositoryID]
+		if !ok {
+			var err error
+			size, err = r.stats.ReplicaSize(ctx, virtualStorage, source.storage, c.replicaPath)
+			if err != nil {
+				r.log.WithError(err).WithFields(logrus.Fields{
+					"virtual_storage": virtualStorage,
+					"storage":         source.storage,
+					"repository_id":   c.move.RepositoryID,
+				}).Warn("failed to get replica size")
+				continue
+			}
+
+			sizes[c.move.RepositoryID] = size
+		}
+
+		if size <= best.Size {
+			continue
+		}
+
+		// Moving the replica must not make the target fuller than the source.
+		movedSource, movedTarget := source, target
+		movedSource.used, movedSource.available = source.used-size, source.available+size
+		movedTarget.used, movedTarget.available = target.used+size, target.available-size
+		if movedTarget.ratio() > movedSource.ratio() {
+			continue
+		}
+
+		c.move.Size = size
+		best = c.move
+	}
+
+	return best, best.Size > 0, nil
+}
+
+// movesInFlight returns the number of repositories in the virtual storage which still have a replica on
+// a storage that is not assigned to them.
+func (r *Rebalancer) movesInFlight(ctx context.Context, virtualStorage string) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(DISTINCT repositories.repository_id)
+FROM repositories
+JOIN storage_repositories ON storage_repositories.repository_id = repositories.repository_id
+WHERE repositories.virtual_storage = $1
+AND EXISTS (
+	SELECT FROM repository_assignments
+	WHERE repository_assignments.repository_id = repositories.repository_id
+)
+AND NOT EXISTS (
+	SELECT FROM repository_assignments
+	WHERE repository_assignments.repository_id = repositories.repository_id
+	AND repository_assignments.storage = storage_repositories.storage
+)
+`, virtualStorage).Scan(&count); err != nil {
+		return 0, fmt.Errorf("query: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *Rebalancer) drainingStorages(ctx context.Context, virtualStorage string) (map[string]struct{}, error) {
+	var storages glsql.StringArray
+	if err := r.db.QueryRowContext(ctx, `
+SELECT ARRAY(
+	SELECT storage
+	FROM storage_drains
+	WHERE virtual_storage = $1
+)
+`, virtualStorage).Scan(&storages); err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	draining := make(map[string]struct{}, len(storages.Slice()))
+	for _, storage := range storages.Slice() {
+		draining[storage] = struct{}{}
+	}
+
+	return draining, nil
+}
+
+// Apply applies the moves by assigning the target storages in place of the source storages. Moves whose
+// source storage is no longer assigned or whose target storage has been assigned in the meanwhile are
+// skipped. Apply returns the moves that were applied.
+func (r *Rebalancer) Apply(ctx context.Context, moves []Move) ([]Move, error) {
+	if len(moves) == 0 {
+		return nil, nil
+	}
+
+	repositoryIDs := make([]int64, len(moves))
+	sourceStorages := make([]string, len(moves))
+	targetStorages := make([]string, len(moves))
+	for i, move := range moves {
+		repositoryIDs[i] = move.RepositoryID
+		sourceStorages[i] = move.SourceStorage
+		targetStorages[i] = move.TargetStorage
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+WITH rebalancing_lock AS (
+	SELECT pg_try_advisory_xact_lock($1) AS acquired
+),
+
+moves AS (
+	SELECT
+		unnest($2::bigint[]) AS repository_id,
+		unnest($3::text[]) AS source_storage,
+		unnest($4::text[]) AS target_storage
+),
+
+unassigned AS (
+	DELETE FROM repository_assignments
+	USING moves
+	WHERE repository_assignments.repository_id = moves.repository_id
+	AND repository_assignments.storage = moves.source_storage
+	AND NOT EXISTS (
+		SELECT FROM repository_assignments AS target
+		WHERE target.repository_id = moves.repository_id
+		AND target.storage = moves.target_storage
+	)
+	-- only move the replicas if we managed to acquire the lock as otherwise
+	-- another Praefect is moving replicas concurrently
+	AND ( SELECT acquired FROM rebalancing_lock )
+	RETURNING
+		repository_assignments.virtual_storage,
+		repository_assignments.relative_path,
+		repository_assignments.repository_id,
+		moves.target_storage
+)
+
+INSERT INTO repository_assignments (virtual_storage, relative_path, storage, repository_id)
+SELECT virtual_storage, relative_path, target_storage, repository_id
+FROM unassigned
+RETURNING repository_id, storage
+`, advisorylock.Rebalance, repositoryIDs, sourceStorages, targetStorages)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	type assignment struct {
+		repositoryID int64
+		storage      string
+	}
+
+	assigned := map[assignment]struct{}{}
+	for rows.Next() {
+		var a assignment
+		if err := rows.Scan(&a.repositoryID, &a.storage); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		assigned[a] = struct{}{}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	applied := make([]Move, 0, len(assigned))
+	for _, move := range moves {
+		if _, ok := assigned[assignment{repositoryID: move.RepositoryID, storage: move.TargetStorage}]; ok {
+			applied = append(applied, move)
+		}
+	}
+
+	return applied, nil
+}
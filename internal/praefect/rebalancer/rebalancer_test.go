@@ -0,0 +1,191 @@
+//go:build !gitaly_test_sha256
+
+package rebalancer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/config"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore/glsql"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper/testdb"
+)
+
+type diskUsage struct {
+	used      int64
+	available int64
+}
+
+type mockStatistics struct {
+	usage map[string]diskUsage
+	sizes map[string]int64
+}
+
+func (m mockStatistics) DiskUsage(ctx context.Context, virtualStorage, storage string) (int64, int64, error) {
+	usage, ok := m.usage[storage]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown storage %q", storage)
+	}
+
+	return usage.used, usage.available, nil
+}
+
+func (m mockStatistics) ReplicaSize(ctx context.Context, virtualStorage, storage, replicaPath string) (int64, error) {
+	return m.sizes[replicaPath], nil
+}
+
+func TestRebalancer(t *testing.T) {
+	t.Parallel()
+
+	db := testdb.New(t)
+
+	storages := map[string][]string{"virtual-storage": {"gitaly-1", "gitaly-2", "gitaly-3"}}
+
+	stats := mockStatistics{
+		usage: map[string]diskUsage{
+			"gitaly-1": {used: 80, available: 20},
+			"gitaly-2": {used: 50, available: 50},
+			"gitaly-3": {used: 20, available: 80},
+		},
+		sizes: map[string]int64{
+			"replica-path-1": 20,
+			"replica-path-2": 20,
+			"replica-path-3": 50,
+			"replica-path-4": 10,
+		},
+	}
+
+	setup := func(t *testing.T, ctx context.Context) glsql.Querier {
+		tx := db.Begin(t)
+		t.Cleanup(func() { tx.Rollback(t) })
+
+		rs := datastore.NewPostgresRepositoryStore(tx, storages)
+		for _, repository := range []struct {
+			id      int64
+			primary string
+		}{
+			// Repository 1 can be moved from gitaly-1.
+			{id: 1, primary: "gitaly-2"},
+			// Repository 2 has its primary on gitaly-1 and is not moved.
+			{id: 2, primary: "gitaly-1"},
+			// Repository 3 is too large to move without overshooting gitaly-3's usage.
+			{id: 3, primary: "gitaly-2"},
+			// Repository 4 can be moved from gitaly-1.
+			{id: 4, primary: "gitaly-2"},
+		} {
+			secondary := "gitaly-1"
+			if repository.primary == "gitaly-1" {
+				secondary = "gitaly-2"
+			}
+
+			require.NoError(t, rs.CreateRepository(ctx,
+				repository.id,
+				"virtual-storage",
+				fmt.Sprintf("relative-path-%d", repository.id),
+				fmt.Sprintf("replica-path-%d", repository.id),
+				repository.primary,
+				[]string{secondary},
+				nil,
+				true,
+				true,
+			))
+		}
+
+		return tx
+	}
+
+	t.Run("moves replicas until usage is even", func(t *testing.T) {
+		ctx := testhelper.Context(t)
+		tx := setup(t, ctx)
+
+		r := NewRebalancer(testhelper.NewDiscardingLogEntry(t), tx, praefect.StaticHealthChecker(storages), stats, config.Rebalancing{
+			MaxMoves:       2,
+			UsageThreshold: 10,
+		}, nil)
+
+		expectedMoves := []Move{
+			{VirtualStorage: "virtual-storage", RepositoryID: 1, RelativePath: "relative-path-1", SourceStorage: "gitaly-1", TargetStorage: "gitaly-3", Size: 20},
+			{VirtualStorage: "virtual-storage", RepositoryID: 4, RelativePath: "relative-path-4", SourceStorage: "gitaly-1", TargetStorage: "gitaly-3", Size: 10},
+		}
+
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectedMoves, moves)
+
+		applied, err := r.Apply(ctx, moves)
+		require.NoError(t, err)
+		require.Equal(t, expectedMoves, applied)
+
+		assignmentStore := datastore.NewAssignmentStore(tx, storages, nil)
+		for _, repositoryID := range []int64{1, 4} {
+			assignments, err := assignmentStore.GetHostAssignments(ctx, "virtual-storage", repositoryID)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"gitaly-2", "gitaly-3"}, assignments)
+		}
+
+		// Applying the moves again is a no-op as the source storages are not assigned anymore.
+		applied, err = r.Apply(ctx, moves)
+		require.NoError(t, err)
+		require.Empty(t, applied)
+
+		// The replicas on gitaly-1 are still waiting to be deleted, so no further moves are planned.
+		moves, err = r.Plan(ctx)
+		require.NoError(t, err)
+		require.Empty(t, moves)
+	})
+
+	t.Run("draining storages are skipped", func(t *testing.T) {
+		ctx := testhelper.Context(t)
+		tx := setup(t, ctx)
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO storage_drains (virtual_storage, storage) VALUES ('virtual-storage', 'gitaly-3')`)
+		require.NoError(t, err)
+
+		r := NewRebalancer(testhelper.NewDiscardingLogEntry(t), tx, praefect.StaticHealthChecker(storages), stats, config.Rebalancing{
+			MaxMoves:       2,
+			UsageThreshold: 10,
+		}, nil)
+
+		// gitaly-2 is already assigned to every repository on gitaly-1, so there is nothing to move.
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		require.Empty(t, moves)
+	})
+
+	t.Run("failure domains are kept", func(t *testing.T) {
+		ctx := testhelper.Context(t)
+		tx := setup(t, ctx)
+
+		r := NewRebalancer(testhelper.NewDiscardingLogEntry(t), tx, praefect.StaticHealthChecker(storages), stats, config.Rebalancing{
+			MaxMoves:       2,
+			UsageThreshold: 10,
+		}, map[string]map[string]string{
+			"virtual-storage": {"gitaly-1": "zone-b", "gitaly-2": "zone-a", "gitaly-3": "zone-a"},
+		})
+
+		// Moving the replicas from gitaly-1 to gitaly-3 would leave the repositories with replicas
+		// only in zone-a.
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		require.Empty(t, moves)
+	})
+
+	t.Run("usage within the threshold", func(t *testing.T) {
+		ctx := testhelper.Context(t)
+		tx := setup(t, ctx)
+
+		r := NewRebalancer(testhelper.NewDiscardingLogEntry(t), tx, praefect.StaticHealthChecker(storages), stats, config.Rebalancing{
+			MaxMoves:       2,
+			UsageThreshold: 70,
+		}, nil)
+
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		require.Empty(t, moves)
+	})
+}
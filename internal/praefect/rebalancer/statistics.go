@@ -0,0 +1,76 @@
+package rebalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+// Statistics provides the disk usage of the storages and the sizes of the replicas stored on them.
+type Statistics interface {
+	// DiskUsage returns the used and available bytes of the storage.
+	DiskUsage(ctx context.Context, virtualStorage, storage string) (used int64, available int64, err error)
+	// ReplicaSize returns the size of the replica in bytes.
+	ReplicaSize(ctx context.Context, virtualStorage, storage, replicaPath string) (int64, error)
+}
+
+// NodeStatistics retrieves the statistics from the Gitaly nodes.
+type NodeStatistics struct {
+	conns praefect.Connections
+}
+
+// NewNodeStatistics returns a new NodeStatistics using the given connections to the Gitaly nodes.
+func NewNodeStatistics(conns praefect.Connections) *NodeStatistics {
+	return &NodeStatistics{conns: conns}
+}
+
+// DiskUsage returns the disk usage of the storage as reported by the Gitaly node's DiskStatistics RPC.
+func (s *NodeStatistics) DiskUsage(ctx context.Context, virtualStorage, storage string) (int64, int64, error) {
+	conn, ok := s.conns[virtualStorage][storage]
+	if !ok {
+		return 0, 0, fmt.Errorf("no connection to %q/%q", virtualStorage, storage)
+	}
+
+	resp, err := gitalypb.NewServerServiceClient(conn).DiskStatistics(ctx, &gitalypb.DiskStatisticsRequest{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("disk statistics: %w", err)
+	}
+
+	for _, status := range resp.GetStorageStatuses() {
+		if status.GetStorageName() != storage {
+			continue
+		}
+
+		if status.GetUsed() == 0 && status.GetAvailable() == 0 {
+			return 0, 0, errors.New("gitaly was unable to determine the disk usage")
+		}
+
+		return status.GetUsed(), status.GetAvailable(), nil
+	}
+
+	return 0, 0, fmt.Errorf("storage %q missing from disk statistics", storage)
+}
+
+// ReplicaSize returns the size of the replica as reported by the Gitaly node's RepositorySize RPC.
+func (s *NodeStatistics) ReplicaSize(ctx context.Context, virtualStorage, storage, replicaPath string) (int64, error) {
+	conn, ok := s.conns[virtualStorage][storage]
+	if !ok {
+		return 0, fmt.Errorf("no connection to %q/%q", virtualStorage, storage)
+	}
+
+	resp, err := gitalypb.NewRepositoryServiceClient(conn).RepositorySize(ctx, &gitalypb.RepositorySizeRequest{
+		Repository: &gitalypb.Repository{
+			StorageName:  storage,
+			RelativePath: replicaPath,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("repository size: %w", err)
+	}
+
+	// RepositorySize reports the size in kilobytes.
+	return resp.GetSize() * 1024, nil
+}
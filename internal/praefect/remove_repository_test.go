@@ -115,6 +115,7 @@ func TestRemoveRepositoryHandler(t *testing.T) {
 					return nil, errServedByGitaly
 				},
 				nil,
+				nil,
 				rs,
 				nil,
 				nodeSet.Connections(),
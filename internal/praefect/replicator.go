@@ -489,9 +489,18 @@ func (r ReplMgr) handleNode(ctx context.Context, virtualStorage string, target N
 	defer stopHealthUpdate()
 
 	eventIDsByState := map[datastore.JobState][]uint64{}
+	eventErrors := map[uint64]string{}
 	for _, event := range events {
-		state := r.handleNodeEvent(ctx, logger, target.Connection, event)
+		state, err := r.handleNodeEvent(ctx, logger, target.Connection, event)
 		eventIDsByState[state] = append(eventIDsByState[state], event.ID)
+		if err != nil {
+			eventErrors[event.ID] = err.Error()
+		}
+	}
+
+	// The errors are recorded before acknowledging the events as only events in progress are updated.
+	if err := r.queue.RecordErrors(ctx, eventErrors); err != nil {
+		logger.WithError(err).Error("failed to record replication event errors")
 	}
 
 	for state, eventIDs := range eventIDsByState {
@@ -533,7 +542,7 @@ func (r ReplMgr) startHealthUpdate(ctx context.Context, logger logrus.FieldLogge
 	return healthUpdateCancel
 }
 
-func (r ReplMgr) handleNodeEvent(ctx context.Context, logger logrus.FieldLogger, targetConnection *grpc.ClientConn, event datastore.ReplicationEvent) datastore.JobState {
+func (r ReplMgr) handleNodeEvent(ctx context.Context, logger logrus.FieldLogger, targetConnection *grpc.ClientConn, event datastore.ReplicationEvent) (datastore.JobState, error) {
 	cid := getCorrelationID(event.Meta)
 	ctx = correlation.ContextWithCorrelation(ctx, cid)
 
@@ -549,12 +558,12 @@ func (r ReplMgr) handleNodeEvent(ctx context.Context, logger logrus.FieldLogger,
 		}
 
 		logger.WithError(err).WithField("new_state", newState).Error("replication job processing finished")
-		return newState
+		return newState, err
 	}
 
 	newState := datastore.JobStateCompleted
 	logger.WithField("new_state", newState).Info("replication job processing finished")
-	return newState
+	return newState, nil
 }
 
 // backfillReplicaPath backfills the replica path in the replication job. As of 14.5, not all jobs are guaranteed
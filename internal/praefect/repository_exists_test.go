@@ -94,6 +94,7 @@ func TestRepositoryExistsHandler(t *testing.T) {
 					return nil, errServedByGitaly
 				},
 				nil,
+				nil,
 				rs,
 				nil,
 				nil,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/housekeeping"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore"
@@ -11,11 +12,15 @@ import (
 	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/praefectutil"
 	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
 	routeRepositoryAccessorPolicy            = "gitaly-route-repository-accessor-policy"
 	routeRepositoryAccessorPolicyPrimaryOnly = "primary-only"
+	// clientFailureDomain is the metadata key clients use to tell the failure domain they are in.
+	// Reads are routed to replicas in the client's failure domain if possible.
+	clientFailureDomain = "gitaly-client-failure-domain"
 )
 
 // errRepositoryNotFound is retuned when trying to operate on a non-existent repository.
@@ -56,6 +61,7 @@ type PerRepositoryRouter struct {
 	csg                       datastore.ConsistentStoragesGetter
 	rs                        datastore.RepositoryStore
 	defaultReplicationFactors map[string]int
+	failureDomains            map[string]map[string]string
 }
 
 // NewPerRepositoryRouter returns a new PerRepositoryRouter using the passed configuration.
@@ -68,6 +74,7 @@ func NewPerRepositoryRouter(
 	ag AssignmentGetter,
 	rs datastore.RepositoryStore,
 	defaultReplicationFactors map[string]int,
+	failureDomains map[string]map[string]string,
 ) *PerRepositoryRouter {
 	return &PerRepositoryRouter{
 		conns:                     conns,
@@ -78,6 +85,7 @@ func NewPerRepositoryRouter(
 		ag:                        ag,
 		rs:                        rs,
 		defaultReplicationFactors: defaultReplicationFactors,
+		failureDomains:            failureDomains,
 	}
 }
 
@@ -115,6 +123,52 @@ func (r *PerRepositoryRouter) pickRandom(nodes []RouterNode) (RouterNode, error)
 	return nodes[r.rand.Intn(len(nodes))], nil
 }
 
+// pickRandomInClientFailureDomain picks a random node from the failure domain the client has set in the
+// metadata. If the client has not set its failure domain or there are no nodes in it, a random node is
+// picked from all of the nodes.
+func (r *PerRepositoryRouter) pickRandomInClientFailureDomain(ctx context.Context, virtualStorage string, nodes []RouterNode) (RouterNode, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if header := md.Get(clientFailureDomain); len(header) > 0 && header[0] != "" {
+			var nodesInDomain []RouterNode
+			for _, node := range nodes {
+				if r.failureDomains[virtualStorage][node.Storage] == header[0] {
+					nodesInDomain = append(nodesInDomain, node)
+				}
+			}
+
+			if len(nodesInDomain) > 0 {
+				return r.pickRandom(nodesInDomain)
+			}
+		}
+	}
+
+	return r.pickRandom(nodes)
+}
+
+// spreadAcrossFailureDomains orders the nodes so that nodes in failure domains with fewer replicas
+// come first, taking the primary's failure domain into account. The relative order of the nodes is
+// otherwise kept. Nodes without a failure domain are considered to be in a domain of their own.
+func (r *PerRepositoryRouter) spreadAcrossFailureDomains(virtualStorage, primary string, nodes []RouterNode) {
+	failureDomains := r.failureDomains[virtualStorage]
+
+	replicas := map[string]int{}
+	if domain := failureDomains[primary]; domain != "" {
+		replicas[domain]++
+	}
+
+	ranks := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		if domain := failureDomains[node.Storage]; domain != "" {
+			ranks[node.Storage] = replicas[domain]
+			replicas[domain]++
+		}
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return ranks[nodes[i].Storage] < ranks[nodes[j].Storage]
+	})
+}
+
 // RouteStorageAccessor routes requests for storage-scoped accessor RPCs. The
 // only storage scoped accessor RPC is RemoteService/FindRemoteRepository,
 // which in turn executes a command without a repository. This can be done by
@@ -183,7 +237,7 @@ func (r *PerRepositoryRouter) RouteRepositoryAccessor(ctx context.Context, virtu
 		healthyConsistentNodes = append(healthyConsistentNodes, node)
 	}
 
-	node, err := r.pickRandom(healthyConsistentNodes)
+	node, err := r.pickRandomInClientFailureDomain(ctx, virtualStorage, healthyConsistentNodes)
 	if err != nil {
 		return RepositoryAccessorRoute{}, err
 	}
@@ -288,7 +342,8 @@ func (r *PerRepositoryRouter) RouteRepositoryMutator(ctx context.Context, virtua
 
 // RouteRepositoryCreation picks a random healthy node to act as the primary node and selects the secondary nodes
 // if assignments are enabled. Healthy secondaries take part in the transaction, unhealthy secondaries are set as
-// replication targets.
+// replication targets. Storages which are being drained are never picked. The secondaries are spread across
+// the failure domains of the storages.
 func (r *PerRepositoryRouter) RouteRepositoryCreation(ctx context.Context, virtualStorage, relativePath, additionalRelativePath string) (RepositoryMutatorRoute, error) {
 	additionalReplicaPath, err := r.resolveAdditionalReplicaPath(ctx, virtualStorage, additionalRelativePath)
 	if err != nil {
@@ -300,7 +355,21 @@ func (r *PerRepositoryRouter) RouteRepositoryCreation(ctx context.Context, virtu
 		return RepositoryMutatorRoute{}, err
 	}
 
-	primary, err := r.pickRandom(healthyNodes)
+	drainingStorages, err := r.rs.GetDrainingStorages(ctx, virtualStorage)
+	if err != nil {
+		return RepositoryMutatorRoute{}, fmt.Errorf("get draining storages: %w", err)
+	}
+
+	primaryCandidates := make([]RouterNode, 0, len(healthyNodes))
+	for _, node := range healthyNodes {
+		if _, draining := drainingStorages[node.Storage]; draining {
+			continue
+		}
+
+		primaryCandidates = append(primaryCandidates, node)
+	}
+
+	primary, err := r.pickRandom(primaryCandidates)
 	if err != nil {
 		return RepositoryMutatorRoute{}, err
 	}
@@ -333,6 +402,10 @@ func (r *PerRepositoryRouter) RouteRepositoryCreation(ctx context.Context, virtu
 			continue
 		}
 
+		if _, draining := drainingStorages[storage]; draining {
+			continue
+		}
+
 		secondaryNodes = append(secondaryNodes, RouterNode{
 			Storage:    storage,
 			Connection: conn,
@@ -342,12 +415,16 @@ func (r *PerRepositoryRouter) RouteRepositoryCreation(ctx context.Context, virtu
 	// replicationFactor being zero indicates it has not been configured. If so, we fallback to the behavior
 	// of no assignments, replicate everywhere and do not select assigned secondaries below.
 	if replicationFactor > 0 {
-		// Select random secondaries according to the default replication factor.
+		// Select random secondaries according to the default replication factor, spreading
+		// them across the failure domains.
 		r.rand.Shuffle(len(secondaryNodes), func(i, j int) {
 			secondaryNodes[i], secondaryNodes[j] = secondaryNodes[j], secondaryNodes[i]
 		})
+		r.spreadAcrossFailureDomains(virtualStorage, primary.Storage, secondaryNodes)
 
-		secondaryNodes = secondaryNodes[:replicationFactor-1]
+		if len(secondaryNodes) > replicationFactor-1 {
+			secondaryNodes = secondaryNodes[:replicationFactor-1]
+		}
 	}
 
 	var secondaries []RouterNode
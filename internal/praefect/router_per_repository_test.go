@@ -96,6 +96,7 @@ func TestPerRepositoryRouter_RouteStorageAccessor(t *testing.T) {
 				nil,
 				datastore.MockRepositoryStore{},
 				nil,
+				nil,
 			)
 
 			node, err := router.RouteStorageAccessor(ctx, tc.virtualStorage)
@@ -227,7 +228,7 @@ func TestPerRepositoryRouter_RouteRepositoryAccessor(t *testing.T) {
 
 			router := NewPerRepositoryRouter(
 				conns,
-				nodes.NewPerRepositoryElector(tx),
+				nodes.NewPerRepositoryElector(tx, nil),
 				tc.healthyNodes,
 				mockRandom{
 					intnFunc: func(n int) int {
@@ -239,6 +240,7 @@ func TestPerRepositoryRouter_RouteRepositoryAccessor(t *testing.T) {
 				nil,
 				rs,
 				nil,
+				nil,
 			)
 
 			route, err := router.RouteRepositoryAccessor(ctx, tc.virtualStorage, relativePath, tc.forcePrimary)
@@ -409,13 +411,14 @@ func TestPerRepositoryRouter_RouteRepositoryMutator(t *testing.T) {
 
 			router := NewPerRepositoryRouter(
 				conns,
-				nodes.NewPerRepositoryElector(tx),
+				nodes.NewPerRepositoryElector(tx, nil),
 				tc.healthyNodes,
 				nil,
 				rs,
-				datastore.NewAssignmentStore(tx, configuredNodes),
+				datastore.NewAssignmentStore(tx, configuredNodes, nil),
 				rs,
 				nil,
+				nil,
 			)
 
 			requestAdditionalRelativePath := additionalRelativePath
@@ -641,7 +644,7 @@ func TestPerRepositoryRouter_RouteRepositoryMaintenance(t *testing.T) {
 
 			router := NewPerRepositoryRouter(conns, nil, StaticHealthChecker{
 				virtualStorage: tc.healthyStorages,
-			}, nil, nil, nil, rs, nil)
+			}, nil, nil, nil, rs, nil, nil)
 
 			route, err := router.RouteRepositoryMaintenance(ctx, tc.virtualStorage, relativePath)
 			require.Equal(t, tc.expectedErr, err)
@@ -889,6 +892,7 @@ func TestPerRepositoryRouterRouteRepositoryCreation(t *testing.T) {
 				nil,
 				rs,
 				map[string]int{"virtual-storage-1": tc.replicationFactor},
+				nil,
 			).RouteRepositoryCreation(ctx, tc.virtualStorage, relativePath, tc.additionalRelativePath)
 			if tc.error != nil {
 				require.Equal(t, tc.error, err)
@@ -900,3 +904,93 @@ func TestPerRepositoryRouterRouteRepositoryCreation(t *testing.T) {
 		})
 	}
 }
+
+func TestPerRepositoryRouter_spreadAcrossFailureDomains(t *testing.T) {
+	t.Parallel()
+
+	router := NewPerRepositoryRouter(nil, nil, nil, nil, nil, nil, nil, nil, map[string]map[string]string{
+		"virtual-storage": {
+			"primary":     "zone-a",
+			"secondary-1": "zone-a",
+			"secondary-2": "zone-a",
+			"secondary-3": "zone-b",
+			"secondary-4": "zone-b",
+		},
+	})
+
+	nodes := []RouterNode{
+		{Storage: "secondary-1"},
+		{Storage: "secondary-2"},
+		{Storage: "secondary-3"},
+		{Storage: "secondary-4"},
+		{Storage: "secondary-5"},
+	}
+
+	router.spreadAcrossFailureDomains("virtual-storage", "primary", nodes)
+	require.Equal(t, []RouterNode{
+		{Storage: "secondary-3"},
+		{Storage: "secondary-5"},
+		{Storage: "secondary-1"},
+		{Storage: "secondary-4"},
+		{Storage: "secondary-2"},
+	}, nodes)
+}
+
+func TestPerRepositoryRouter_pickRandomInClientFailureDomain(t *testing.T) {
+	t.Parallel()
+
+	nodes := []RouterNode{
+		{Storage: "gitaly-1"},
+		{Storage: "gitaly-2"},
+		{Storage: "gitaly-3"},
+	}
+
+	for _, tc := range []struct {
+		desc          string
+		metadata      metadata.MD
+		numCandidates int
+		expectedNode  RouterNode
+	}{
+		{
+			desc:          "no failure domain set",
+			numCandidates: 3,
+			expectedNode:  RouterNode{Storage: "gitaly-1"},
+		},
+		{
+			desc:          "picks from the client's failure domain",
+			metadata:      metadata.Pairs(clientFailureDomain, "zone-b"),
+			numCandidates: 2,
+			expectedNode:  RouterNode{Storage: "gitaly-2"},
+		},
+		{
+			desc:          "falls back to all nodes if none are in the client's failure domain",
+			metadata:      metadata.Pairs(clientFailureDomain, "zone-c"),
+			numCandidates: 3,
+			expectedNode:  RouterNode{Storage: "gitaly-1"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := testhelper.Context(t)
+			if tc.metadata != nil {
+				ctx = metadata.NewIncomingContext(ctx, tc.metadata)
+			}
+
+			router := NewPerRepositoryRouter(nil, nil, nil, mockRandom{
+				intnFunc: func(n int) int {
+					require.Equal(t, tc.numCandidates, n)
+					return 0
+				},
+			}, nil, nil, nil, nil, map[string]map[string]string{
+				"virtual-storage": {
+					"gitaly-1": "zone-a",
+					"gitaly-2": "zone-b",
+					"gitaly-3": "zone-b",
+				},
+			})
+
+			node, err := router.pickRandomInClientFailureDomain(ctx, "virtual-storage", nodes)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedNode, node)
+		})
+	}
+}
@@ -89,6 +89,7 @@ func NewGRPCServer(
 	registry *protoregistry.Registry,
 	director proxy.StreamDirector,
 	txMgr *transactions.Manager,
+	queue datastore.ReplicationEventQueue,
 	rs datastore.RepositoryStore,
 	assignmentStore AssignmentStore,
 	conns Connections,
@@ -149,7 +150,7 @@ func NewGRPCServer(
 	warnDupeAddrs(logger, conf)
 
 	srv := grpc.NewServer(grpcOpts...)
-	registerServices(srv, txMgr, conf, rs, assignmentStore, service.Connections(conns), primaryGetter, checks)
+	registerServices(srv, txMgr, conf, queue, rs, assignmentStore, service.Connections(conns), primaryGetter, checks)
 
 	if conf.Failover.ElectionStrategy == config.ElectionStrategyPerRepository {
 		proxy.RegisterStreamHandlers(srv, "gitaly.RepositoryService", map[string]grpc.StreamHandler{
@@ -177,6 +178,7 @@ func registerServices(
 	srv *grpc.Server,
 	tm *transactions.Manager,
 	conf config.Config,
+	queue datastore.ReplicationEventQueue,
 	rs datastore.RepositoryStore,
 	assignmentStore AssignmentStore,
 	conns service.Connections,
@@ -185,7 +187,9 @@ func registerServices(
 ) {
 	// ServerServiceServer is necessary for the ServerInfo RPC
 	gitalypb.RegisterServerServiceServer(srv, server.NewServer(conf, conns, checks))
-	gitalypb.RegisterPraefectInfoServiceServer(srv, info.NewServer(conf, rs, assignmentStore, conns, primaryGetter))
+	// Only the Postgres replication queue supports managing the replication jobs.
+	jobManager, _ := queue.(datastore.ReplicationJobManager)
+	gitalypb.RegisterPraefectInfoServiceServer(srv, info.NewServer(conf, rs, assignmentStore, conns, primaryGetter, jobManager))
 	gitalypb.RegisterRefTransactionServer(srv, transaction.NewServer(tm))
 	healthpb.RegisterHealthServer(srv, health.NewServer())
 
@@ -130,6 +130,7 @@ func (s *ServerFactory) createGRPC(creds credentials.TransportCredentials) *grpc
 		s.registry,
 		s.director,
 		s.txMgr,
+		s.queue,
 		s.rs,
 		s.assignmentStore,
 		s.conns,
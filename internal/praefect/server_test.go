@@ -611,13 +611,14 @@ func TestRenameRepository(t *testing.T) {
 		WithRepoStore: rs,
 		WithRouter: NewPerRepositoryRouter(
 			nodeSet.Connections(),
-			nodes.NewPerRepositoryElector(db),
+			nodes.NewPerRepositoryElector(db, nil),
 			StaticHealthChecker(praefectCfg.StorageNames()),
 			NewLockedRandom(rand.New(rand.NewSource(0))),
 			rs,
-			datastore.NewAssignmentStore(db, praefectCfg.StorageNames()),
+			datastore.NewAssignmentStore(db, praefectCfg.StorageNames(), nil),
 			rs,
 			nil,
+			nil,
 		),
 		WithTxMgr: txManager,
 	})
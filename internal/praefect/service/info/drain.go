@@ -0,0 +1,48 @@
+package info
+
+import (
+	"context"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+)
+
+// DrainStorage drains a storage. See the protobuf declarations for details.
+func (s *Server) DrainStorage(ctx context.Context, req *gitalypb.DrainStorageRequest) (*gitalypb.DrainStorageResponse, error) {
+	storages := s.conf.StorageNames()[req.GetVirtualStorage()]
+	if storages == nil {
+		return nil, helper.ErrInvalidArgumentf("unknown virtual storage: %q", req.GetVirtualStorage())
+	}
+
+	foundStorage := false
+	for _, storage := range storages {
+		if storage == req.GetStorage() {
+			foundStorage = true
+			break
+		}
+	}
+
+	if !foundStorage {
+		return nil, helper.ErrInvalidArgumentf("unknown storage: %q", req.GetStorage())
+	}
+
+	if req.GetCancel() {
+		if err := s.rs.StopDrainingStorage(ctx, req.GetVirtualStorage(), req.GetStorage()); err != nil {
+			return nil, helper.ErrInternalf("stop draining storage: %w", err)
+		}
+
+		return &gitalypb.DrainStorageResponse{}, nil
+	}
+
+	progress, err := s.rs.DrainStorage(ctx, req.GetVirtualStorage(), req.GetStorage(), s.conf.FailureDomains()[req.GetVirtualStorage()])
+	if err != nil {
+		return nil, helper.ErrInternalf("drain storage: %w", err)
+	}
+
+	return &gitalypb.DrainStorageResponse{
+		Repositories:          progress.Repositories,
+		Primaries:             progress.Primaries,
+		Moves:                 progress.Moves,
+		UnmovableRepositories: progress.Unmovable,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package info
+
+import (
+	"context"
+
+	"gitlab.com/gitlab-org/gitaly/v15/internal/helper"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/praefect/datastore"
+	"gitlab.com/gitlab-org/gitaly/v15/proto/go/gitalypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// errReplicationJobsUnsupported is returned when the configured replication queue doesn't support managing the jobs.
+var errReplicationJobsUnsupported = helper.ErrFailedPreconditionf("managing replication jobs requires the Postgres replication queue")
+
+// ListReplicationJobs lists the jobs in the replication queue. See the protobuf declarations for details.
+func (s *Server) ListReplicationJobs(ctx context.Context, req *gitalypb.ListReplicationJobsRequest) (*gitalypb.ListReplicationJobsResponse, error) {
+	if s.jobManager == nil {
+		return nil, errReplicationJobsUnsupported
+	}
+
+	if req.GetVirtualStorage() != "" && s.conf.StorageNames()[req.GetVirtualStorage()] == nil {
+		return nil, helper.ErrInvalidArgumentf("unknown virtual storage: %q", req.GetVirtualStorage())
+	}
+
+	if req.GetLimit() < 0 {
+		return nil, helper.ErrInvalidArgumentf("limit must not be negative")
+	}
+
+	filter := datastore.ReplicationJobFilter{
+		VirtualStorage: req.GetVirtualStorage(),
+		TargetStorage:  req.GetTargetStorage(),
+		RelativePath:   req.GetRelativePath(),
+		Limit:          int(req.GetLimit()),
+	}
+
+	for _, state := range req.GetStates() {
+		switch jobState := datastore.JobState(state); jobState {
+		case datastore.JobStateReady, datastore.JobStateInProgress, datastore.JobStateFailed:
+			filter.States = append(filter.States, jobState)
+		default:
+			return nil, helper.ErrInvalidArgumentf("invalid state: %q", state)
+		}
+	}
+
+	if req.GetCreatedBefore() != nil {
+		filter.CreatedBefore = req.GetCreatedBefore().AsTime()
+	}
+
+	if req.GetCreatedAfter() != nil {
+		filter.CreatedAfter = req.GetCreatedAfter().AsTime()
+	}
+
+	events, err := s.jobManager.ListJobs(ctx, filter)
+	if err != nil {
+		return nil, helper.ErrInternalf("list jobs: %w", err)
+	}
+
+	jobs := make([]*gitalypb.ListReplicationJobsResponse_ReplicationJob, len(events))
+	for i, event := range events {
+		job := &gitalypb.ListReplicationJobsResponse_ReplicationJob{
+			Id:             event.ID,
+			State:          event.State.String(),
+			Change:         event.Job.Change.String(),
+			VirtualStorage: event.Job.VirtualStorage,
+			RelativePath:   event.Job.RelativePath,
+			RepositoryId:   event.Job.RepositoryID,
+			SourceStorage:  event.Job.SourceNodeStorage,
+			TargetStorage:  event.Job.TargetNodeStorage,
+			AttemptsLeft:   int32(event.Attempt),
+			Priority:       int32(event.Priority),
+			CreatedAt:      timestamppb.New(event.CreatedAt),
+			Error:          event.Error,
+		}
+
+		if event.UpdatedAt != nil {
+			job.UpdatedAt = timestamppb.New(*event.UpdatedAt)
+		}
+
+		jobs[i] = job
+	}
+
+	return &gitalypb.ListReplicationJobsResponse{Jobs: jobs}, nil
+}
+
+// CancelReplicationJobs cancels replication jobs. See the protobuf declarations for details.
+func (s *Server) CancelReplicationJobs(ctx context.Context, req *gitalypb.CancelReplicationJobsRequest) (*gitalypb.CancelReplicationJobsResponse, error) {
+	if s.jobManager == nil {
+		return nil, errReplicationJobsUnsupported
+	}
+
+	if len(req.GetIds()) == 0 {
+		return nil, helper.ErrInvalidArgumentf("no job IDs provided")
+	}
+
+	cancelled, err := s.jobManager.CancelJobs(ctx, req.GetIds())
+	if err != nil {
+		return nil, helper.ErrInternalf("cancel jobs: %w", err)
+	}
+
+	return &gitalypb.CancelReplicationJobsResponse{CancelledIds: cancelled}, nil
+}
+
+// PrioritizeReplicationJobs sets the priority of replication jobs. See the protobuf declarations for details.
+func (s *Server) PrioritizeReplicationJobs(ctx context.Context, req *gitalypb.PrioritizeReplicationJobsRequest) (*gitalypb.PrioritizeReplicationJobsResponse, error) {
+	if s.jobManager == nil {
+		return nil, errReplicationJobsUnsupported
+	}
+
+	if len(req.GetIds()) == 0 {
+		return nil, helper.ErrInvalidArgumentf("no job IDs provided")
+	}
+
+	prioritized, err := s.jobManager.PrioritizeJobs(ctx, req.GetIds(), int(req.GetPriority()))
+	if err != nil {
+		return nil, helper.ErrInternalf("prioritize jobs: %w", err)
+	}
+
+	return &gitalypb.PrioritizeReplicationJobsResponse{PrioritizedIds: prioritized}, nil
+}
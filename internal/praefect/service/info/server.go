@@ -41,6 +41,7 @@ type Server struct {
 	assignmentStore AssignmentStore
 	conns           service.Connections
 	primaryGetter   PrimaryGetter
+	jobManager      datastore.ReplicationJobManager
 }
 
 // NewServer creates a new instance of a grpc InfoServiceServer
@@ -50,6 +51,7 @@ func NewServer(
 	assignmentStore AssignmentStore,
 	conns service.Connections,
 	primaryGetter PrimaryGetter,
+	jobManager datastore.ReplicationJobManager,
 ) gitalypb.PraefectInfoServiceServer {
 	return &Server{
 		conf:            conf,
@@ -57,6 +59,7 @@ func NewServer(
 		assignmentStore: assignmentStore,
 		conns:           conns,
 		primaryGetter:   primaryGetter,
+		jobManager:      jobManager,
 	}
 }
 
@@ -245,6 +245,7 @@ func RunPraefectServer(
 		protoregistry.GitalyProtoPreregistered,
 		coordinator.StreamDirector,
 		opt.WithTxMgr,
+		opt.WithQueue,
 		opt.WithRepoStore,
 		opt.WithAssignmentStore,
 		opt.WithConnections,
@@ -2,6 +2,7 @@ package praefect
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"sort"
 	"sync"
@@ -20,6 +21,11 @@ import (
 // the replica's metadata record is removed and the removal logged. The repository's record
 // is still left in place even if all of the replicas are lost to ensure the data loss doesn't
 // go unnoticed.
+//
+// If checksum verification is enabled, the verifier additionally calculates the checksum of an up to
+// date replica and compares it against the checksums stored for the repository's other up to date
+// replicas when they were verified at the same generation. A replica whose checksum differs from the
+// majority is marked outdated so the reconciler repairs it.
 type MetadataVerifier struct {
 	log                  logrus.FieldLogger
 	db                   glsql.Querier
@@ -34,9 +40,12 @@ type MetadataVerifier struct {
 	// allows the worker to proceed. The invalid replicas will be found again after the configured
 	// verificationInterval has passed.
 	performDeletions bool
+	// verifyChecksums controls whether the checksums of the up to date replicas are compared.
+	verifyChecksums bool
 
 	dequeuedJobsTotal        *prometheus.CounterVec
 	completedJobsTotal       *prometheus.CounterVec
+	checksumMismatchesTotal  *prometheus.CounterVec
 	staleLeasesReleasedTotal prometheus.Counter
 }
 
@@ -54,6 +63,7 @@ func NewMetadataVerifier(
 	healthChecker HealthChecker,
 	verificationInterval time.Duration,
 	performDeletions bool,
+	verifyChecksums bool,
 ) *MetadataVerifier {
 	v := &MetadataVerifier{
 		log:                  log,
@@ -64,6 +74,7 @@ func NewMetadataVerifier(
 		healthChecker:        healthChecker,
 		verificationInterval: verificationInterval,
 		performDeletions:     performDeletions,
+		verifyChecksums:      verifyChecksums,
 		dequeuedJobsTotal: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "gitaly_praefect_verification_jobs_dequeued_total",
@@ -78,6 +89,13 @@ func NewMetadataVerifier(
 			},
 			[]string{"virtual_storage", "storage", "result"},
 		),
+		checksumMismatchesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gitaly_praefect_verification_checksum_mismatches_total",
+				Help: "Number of replicas found to have a checksum differing from the other up to date replicas.",
+			},
+			[]string{"virtual_storage", "storage"},
+		),
 		staleLeasesReleasedTotal: prometheus.NewCounter(
 			prometheus.CounterOpts{
 				Name: "gitaly_praefect_stale_verification_leases_released_total",
@@ -93,6 +111,10 @@ func NewMetadataVerifier(
 			for _, result := range []string{resultError, resultInvalid, resultValid} {
 				v.completedJobsTotal.WithLabelValues(virtualStorage, storage, result)
 			}
+
+			if verifyChecksums {
+				v.checksumMismatchesTotal.WithLabelValues(virtualStorage, storage)
+			}
 		}
 	}
 
@@ -105,12 +127,26 @@ type verificationJob struct {
 	relativePath   string
 	storage        string
 	replicaPath    string
+	primary        string
+	generation     int64
+	// verifyChecksum is set if checksums are verified and the replica being verified is up to date.
+	verifyChecksum bool
+	// replicaChecksums contains the checksums of the repository's other up to date replicas by their
+	// storage. Only checksums calculated at the repository's current generation are included.
+	replicaChecksums map[string]string
 }
 
 type verificationResult struct {
 	job    verificationJob
 	exists bool
 	error  error
+	// checksumVerified is set if the replica's checksum was compared against the checksums of the
+	// other up to date replicas.
+	checksumVerified bool
+	// checksum is the replica's checksum if it was verified.
+	checksum string
+	// checksumMatches is set if the replica's checksum matched the majority of the up to date replicas.
+	checksumMatches bool
 }
 
 // Run runs the metadata verifier. It keeps running until the context is canceled.
@@ -239,6 +275,27 @@ func (v *MetadataVerifier) run(ctx context.Context) error {
 				exists: exists,
 				error:  err,
 			}
+
+			if err != nil || !exists || !job.verifyChecksum {
+				return
+			}
+
+			checksum, matches, err := v.verifyChecksum(ctx, job)
+			if err != nil {
+				v.log.WithFields(logrus.Fields{
+					"repository_id":   job.repositoryID,
+					"replica_path":    job.replicaPath,
+					"virtual_storage": job.virtualStorage,
+					"storage":         job.storage,
+					"relative_path":   job.relativePath,
+					logrus.ErrorKey:   err,
+				}).Error("failed to verify replica's checksum")
+				return
+			}
+
+			results[i].checksumVerified = true
+			results[i].checksum = checksum
+			results[i].checksumMatches = matches
 		}()
 	}
 
@@ -258,6 +315,10 @@ func (v *MetadataVerifier) run(ctx context.Context) error {
 		}
 
 		v.completedJobsTotal.WithLabelValues(r.job.virtualStorage, r.job.storage, result).Inc()
+
+		if r.checksumVerified && !r.checksumMatches {
+			v.checksumMismatchesTotal.WithLabelValues(r.job.virtualStorage, r.job.storage).Inc()
+		}
 	}
 
 	return nil
@@ -283,13 +344,26 @@ func (v *MetadataVerifier) updateMetadata(ctx context.Context, results []verific
 	storages := make([]string, len(results))
 	successfullyVerifieds := make([]bool, len(results))
 	exists := make([]bool, len(results))
+	checksumVerifieds := make([]bool, len(results))
+	checksums := make([]string, len(results))
+	checksumMatches := make([]bool, len(results))
+	generations := make([]int64, len(results))
 
 	logRecords := logRecord{}
+	divergentReplicas := logRecord{}
 	for i, result := range results {
 		repositoryIDs[i] = result.job.repositoryID
 		storages[i] = result.job.storage
 		exists[i] = result.exists
 		successfullyVerifieds[i] = result.error == nil
+		checksumVerifieds[i] = result.checksumVerified
+		checksums[i] = result.checksum
+		checksumMatches[i] = result.checksumMatches
+		generations[i] = result.job.generation
+
+		if result.checksumVerified && !result.checksumMatches {
+			divergentReplicas.markRemoved(result.job.virtualStorage, result.job.relativePath, result.job.storage)
+		}
 
 		if result.error != nil {
 			v.log.WithFields(logrus.Fields{
@@ -312,14 +386,22 @@ func (v *MetadataVerifier) updateMetadata(ctx context.Context, results []verific
 		}).Info("removing metadata records of non-existent replicas")
 	}
 
+	if len(divergentReplicas) > 0 {
+		v.log.WithField("replicas", divergentReplicas).Info("marking replicas with diverging checksums outdated")
+	}
+
 	_, err := v.db.ExecContext(ctx, `
 WITH results AS (
-	SELECT repository_id, storage, successfully_verified, exists
+	SELECT repository_id, storage, successfully_verified, exists, checksum_verified, checksum, checksum_matches, generation
 	FROM (
 		SELECT unnest($1::bigint[]) AS repository_id,
 	           unnest($2::text[]) AS storage,
 	           unnest($3::bool[]) as successfully_verified,
-	           unnest($4::bool[]) AS exists
+	           unnest($4::bool[]) AS exists,
+	           unnest($6::bool[]) AS checksum_verified,
+	           unnest($7::text[]) AS checksum,
+	           unnest($8::bool[]) AS checksum_matches,
+	           unnest($9::bigint[]) AS generation
 	) AS results
 	JOIN (
 		SELECT repository_id
@@ -332,7 +414,19 @@ WITH results AS (
 release_leases AS (
 	UPDATE storage_repositories
 	SET verification_leased_until = NULL,
-	    verified_at = CASE WHEN successfully_verified THEN now() ELSE verified_at END
+	    verified_at = CASE WHEN successfully_verified THEN now() ELSE verified_at END,
+	    checksum = CASE WHEN checksum_verified THEN results.checksum ELSE storage_repositories.checksum END,
+	    checksum_generation = CASE WHEN checksum_verified THEN results.generation ELSE storage_repositories.checksum_generation END,
+	    checksum_verified_at = CASE WHEN checksum_verified THEN now() ELSE checksum_verified_at END,
+	    checksum_matches = CASE WHEN checksum_verified THEN results.checksum_matches ELSE storage_repositories.checksum_matches END,
+	    -- A replica diverging from the other up to date replicas is marked outdated so the reconciler
+	    -- repairs it. The generation is only decremented if no write has been applied to the replica
+	    -- since the checksums were calculated.
+	    generation = CASE
+	        WHEN checksum_verified AND NOT results.checksum_matches AND storage_repositories.generation = results.generation
+	        THEN storage_repositories.generation - 1
+	        ELSE storage_repositories.generation
+	    END
 	FROM results
 	WHERE storage_repositories.repository_id = results.repository_id
 	AND   storage_repositories.storage = results.storage
@@ -345,7 +439,17 @@ AND   storage_repositories.storage       = results.storage
 AND   successfully_verified
 AND   NOT exists
 AND   $5
-	`, repositoryIDs, storages, successfullyVerifieds, exists, v.performDeletions)
+	`,
+		repositoryIDs,
+		storages,
+		successfullyVerifieds,
+		exists,
+		v.performDeletions,
+		checksumVerifieds,
+		checksums,
+		checksumMatches,
+		generations,
+	)
 	if err != nil {
 		return fmt.Errorf("query: %w", err)
 	}
@@ -364,9 +468,10 @@ func (v *MetadataVerifier) pickJobs(ctx context.Context) ([]verificationJob, err
 
 	rows, err := v.db.QueryContext(ctx, `
 WITH to_verify AS (
-	SELECT repository_id, relative_path, replica_path, virtual_storage, storage
+	SELECT repository_id, relative_path, replica_path, virtual_storage, storage, "primary",
+		need_verification.generation, repositories.generation AS repository_generation
 	FROM (
-		SELECT repository_id, storage
+		SELECT repository_id, storage, generation
 		FROM storage_repositories
 		WHERE ( verified_at IS NULL OR verified_at < now() - $1 * '1 millisecond'::interval )
         AND verification_leased_until IS NULL
@@ -389,9 +494,28 @@ acquire_leases AS (
 	AND   storage_repositories.storage       = to_verify.storage
 )
 
-SELECT repository_id, replica_path, virtual_storage, relative_path, storage
+SELECT repository_id, replica_path, virtual_storage, relative_path, storage, "primary", generation,
+	$6 AND generation = repository_generation,
+	CASE WHEN $6 AND generation = repository_generation THEN ARRAY(
+		SELECT replicas.storage
+		FROM storage_repositories AS replicas
+		WHERE replicas.repository_id       = to_verify.repository_id
+		AND   replicas.storage            != to_verify.storage
+		AND   replicas.generation          = to_verify.repository_generation
+		AND   replicas.checksum_generation = to_verify.repository_generation
+		ORDER BY replicas.storage
+	) END,
+	CASE WHEN $6 AND generation = repository_generation THEN ARRAY(
+		SELECT replicas.checksum
+		FROM storage_repositories AS replicas
+		WHERE replicas.repository_id       = to_verify.repository_id
+		AND   replicas.storage            != to_verify.storage
+		AND   replicas.generation          = to_verify.repository_generation
+		AND   replicas.checksum_generation = to_verify.repository_generation
+		ORDER BY replicas.storage
+	) END
 FROM to_verify
-	`, v.verificationInterval.Milliseconds(), v.batchSize, v.leaseDuration.Milliseconds(), healthyVirtualStorages, healthyStorages)
+	`, v.verificationInterval.Milliseconds(), v.batchSize, v.leaseDuration.Milliseconds(), healthyVirtualStorages, healthyStorages, v.verifyChecksums)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -400,10 +524,32 @@ FROM to_verify
 	var jobs []verificationJob
 	for rows.Next() {
 		var job verificationJob
-		if err := rows.Scan(&job.repositoryID, &job.replicaPath, &job.virtualStorage, &job.relativePath, &job.storage); err != nil {
+		var primary sql.NullString
+		var replicaStorages, replicaChecksums glsql.StringArray
+		if err := rows.Scan(
+			&job.repositoryID,
+			&job.replicaPath,
+			&job.virtualStorage,
+			&job.relativePath,
+			&job.storage,
+			&primary,
+			&job.generation,
+			&job.verifyChecksum,
+			&replicaStorages,
+			&replicaChecksums,
+		); err != nil {
 			return nil, fmt.Errorf("scan: %w", err)
 		}
 
+		job.primary = primary.String
+		if job.verifyChecksum {
+			storages, checksums := replicaStorages.Slice(), replicaChecksums.Slice()
+			job.replicaChecksums = make(map[string]string, len(storages))
+			for i, storage := range storages {
+				job.replicaChecksums[storage] = checksums[i]
+			}
+		}
+
 		jobs = append(jobs, job)
 	}
 
@@ -433,6 +579,67 @@ func (v *MetadataVerifier) verify(ctx context.Context, job verificationJob) (boo
 	return resp.Exists, nil
 }
 
+// verifyChecksum calculates the checksum of the job's replica and compares it against the checksums of the
+// repository's other up to date replicas, which were stored when they were verified. It returns the
+// replica's checksum and whether it matches the checksum held by the majority of the replicas. On a tie,
+// the primary's checksum is considered the correct one.
+func (v *MetadataVerifier) verifyChecksum(ctx context.Context, job verificationJob) (string, bool, error) {
+	checksum, err := v.calculateChecksum(ctx, job.virtualStorage, job.storage, job.replicaPath)
+	if err != nil {
+		return "", false, fmt.Errorf("calculate checksum: %w", err)
+	}
+
+	checksums := make(map[string]string, len(job.replicaChecksums)+1)
+	for storage, replicaChecksum := range job.replicaChecksums {
+		checksums[storage] = replicaChecksum
+	}
+	checksums[job.storage] = checksum
+
+	return checksum, checksumMatchesMajority(checksums, job.storage, job.primary), nil
+}
+
+// checksumMatchesMajority returns whether the storage's checksum is held by at least as many
+// replicas as any other checksum. If another checksum is held by as many replicas, the storage's
+// checksum matches only if the primary holds it.
+func checksumMatchesMajority(checksums map[string]string, storage, primary string) bool {
+	counts := map[string]int{}
+	for _, checksum := range checksums {
+		counts[checksum]++
+	}
+
+	own := checksums[storage]
+	for checksum, count := range counts {
+		if checksum == own {
+			continue
+		}
+
+		if count > counts[own] || (count == counts[own] && checksums[primary] == checksum) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *MetadataVerifier) calculateChecksum(ctx context.Context, virtualStorage, storage, replicaPath string) (string, error) {
+	conn, ok := v.conns[virtualStorage][storage]
+	if !ok {
+		return "", fmt.Errorf("no connection to %q/%q", virtualStorage, storage)
+	}
+
+	resp, err := gitalypb.NewRepositoryServiceClient(conn).CalculateChecksum(ctx, &gitalypb.CalculateChecksumRequest{
+		Repository: &gitalypb.Repository{
+			StorageName:  storage,
+			RelativePath: replicaPath,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetChecksum(), nil
+}
+
 // Describe describes the collected metrics to Prometheus.
 func (v *MetadataVerifier) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(v, ch)
@@ -442,5 +649,6 @@ func (v *MetadataVerifier) Describe(ch chan<- *prometheus.Desc) {
 func (v *MetadataVerifier) Collect(ch chan<- prometheus.Metric) {
 	v.dequeuedJobsTotal.Collect(ch)
 	v.completedJobsTotal.Collect(ch)
+	v.checksumMismatchesTotal.Collect(ch)
 	v.staleLeasesReleasedTotal.Collect(ch)
 }
@@ -485,6 +485,8 @@ func TestVerifier(t *testing.T) {
 							deps.GetConnsPool(),
 							deps.GetGit2goExecutor(),
 							deps.GetHousekeepingManager(),
+							deps.GetBackupSink(),
+							deps.GetBackupLocator(),
 						)})
 					}
 				}
@@ -524,7 +526,7 @@ func TestVerifier(t *testing.T) {
 			testdb.SetHealthyNodes(t, ctx, tx, map[string]map[string][]string{
 				"praefect-0": conf.StorageNames(),
 			})
-			elector := nodes.NewPerRepositoryElector(tx)
+			elector := nodes.NewPerRepositoryElector(tx, nil)
 			conns := nodeSet.Connections()
 			rs := datastore.NewPostgresRepositoryStore(db, conf.StorageNames())
 
@@ -535,9 +537,10 @@ func TestVerifier(t *testing.T) {
 					StaticHealthChecker(conf.StorageNames()),
 					NewLockedRandom(rand.New(rand.NewSource(0))),
 					rs,
-					datastore.NewAssignmentStore(db, conf.StorageNames()),
+					datastore.NewAssignmentStore(db, conf.StorageNames(), nil),
 					rs,
 					conf.DefaultReplicationFactors(),
+					nil,
 				),
 				WithRepoStore: rs,
 				WithTxMgr:     txManager,
@@ -640,7 +643,7 @@ func TestVerifier(t *testing.T) {
 				healthyStorages = tc.healthyStorages
 			}
 
-			verifier := NewMetadataVerifier(logger, db, conns, healthyStorages, 24*7*time.Hour, !tc.dontPerformDeletions)
+			verifier := NewMetadataVerifier(logger, db, conns, healthyStorages, 24*7*time.Hour, !tc.dontPerformDeletions, false)
 			if tc.batchSize > 0 {
 				verifier.batchSize = tc.batchSize
 			}
@@ -803,7 +806,7 @@ func TestVerifier_runExpiredLeaseReleaser(t *testing.T) {
 	defer tx.Rollback(t)
 
 	logger, hook := test.NewNullLogger()
-	verifier := NewMetadataVerifier(logrus.NewEntry(logger), tx, nil, nil, 0, true)
+	verifier := NewMetadataVerifier(logrus.NewEntry(logger), tx, nil, nil, 0, true, false)
 	// set batch size lower than the number of locked leases to ensure the batching works
 	verifier.batchSize = 2
 
@@ -858,3 +861,72 @@ func TestVerifier_runExpiredLeaseReleaser(t *testing.T) {
 gitaly_praefect_stale_verification_leases_released_total 3
 	`)))
 }
+
+func TestChecksumMatchesMajority(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc      string
+		checksums map[string]string
+		storage   string
+		primary   string
+		matches   bool
+	}{
+		{
+			desc:      "only replica",
+			checksums: map[string]string{"gitaly-1": "a"},
+			storage:   "gitaly-1",
+			primary:   "gitaly-1",
+			matches:   true,
+		},
+		{
+			desc:      "all replicas match",
+			checksums: map[string]string{"gitaly-1": "a", "gitaly-2": "a", "gitaly-3": "a"},
+			storage:   "gitaly-2",
+			primary:   "gitaly-1",
+			matches:   true,
+		},
+		{
+			desc:      "replica in minority",
+			checksums: map[string]string{"gitaly-1": "a", "gitaly-2": "b", "gitaly-3": "a"},
+			storage:   "gitaly-2",
+			primary:   "gitaly-2",
+			matches:   false,
+		},
+		{
+			desc:      "replica in majority",
+			checksums: map[string]string{"gitaly-1": "a", "gitaly-2": "b", "gitaly-3": "b"},
+			storage:   "gitaly-2",
+			primary:   "gitaly-1",
+			matches:   true,
+		},
+		{
+			desc:      "tie resolved towards the primary",
+			checksums: map[string]string{"gitaly-1": "a", "gitaly-2": "b"},
+			storage:   "gitaly-1",
+			primary:   "gitaly-1",
+			matches:   true,
+		},
+		{
+			desc:      "tie resolved against the secondary",
+			checksums: map[string]string{"gitaly-1": "a", "gitaly-2": "b"},
+			storage:   "gitaly-2",
+			primary:   "gitaly-1",
+			matches:   false,
+		},
+		{
+			desc:      "tie without the primary's checksum",
+			checksums: map[string]string{"gitaly-1": "a", "gitaly-2": "b"},
+			storage:   "gitaly-2",
+			primary:   "gitaly-3",
+			matches:   true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.matches, checksumMatchesMajority(tc.checksums, tc.storage, tc.primary))
+		})
+	}
+}
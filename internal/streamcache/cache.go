@@ -22,13 +22,34 @@
 // entries, we also have a goroutine at the filestore level which
 // performs a directory walk. This will clean up cache files left behind
 // by other processes.
+//
+// On top of that, the Cache can be bounded in size: in total, per
+// repository, and by the free space left on the disk holding dir. When
+// any of these limits are exceeded, the least recently used entries are
+// evicted. Only entries that are complete can be evicted this way. The
+// limits are enforced by the Cache eviction goroutine, each time an entry
+// is completed and before an entry is created. If the entries that are
+// still being written keep the cache over one of its limits, no new entry
+// is created and the data is streamed to the caller without being cached.
+//
+// # Tiers
+//
+// Optionally, the cache has a second tier behind the local filestore. On
+// a local miss, the cache first looks for the entry in the second tier
+// and only runs the create callback if the second tier misses too. Entries
+// that had to be created are copied into the second tier once they are
+// complete. The only second tier so far is sharedStore, a directory that
+// can be shared between Gitaly nodes, which evicts least-recently-used
+// entries to stay within a size limit.
 package streamcache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -56,14 +77,62 @@ var (
 		},
 		[]string{"dir", "max_age"},
 	)
+
+	cacheIndexBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gitaly_streamcache_index_bytes",
+			Help: "Number of bytes used by index entries in streamcache",
+		},
+		[]string{"dir"},
+	)
+
+	cacheEvictions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gitaly_streamcache_evicted_total",
+			Help: "Number of index entries evicted from streamcache per reason",
+		},
+		[]string{"dir", "reason"},
+	)
+
+	cacheBypasses = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gitaly_streamcache_bypassed_total",
+			Help: "Number of streamcache misses that were not cached because the cache was full, per reason",
+		},
+		[]string{"dir", "reason"},
+	)
+
+	cacheLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gitaly_streamcache_lookups_total",
+			Help: "Number of streamcache lookups per tier and result",
+		},
+		[]string{"dir", "tier", "result"},
+	)
 )
 
+// secondTier is a store for complete cache entries that is consulted when
+// an entry is missing from the local index.
+type secondTier interface {
+	// Name identifies the tier in metrics.
+	Name() string
+	// Open opens the entry for key. It returns an error satisfying
+	// errors.Is(err, os.ErrNotExist) if the tier has no such entry.
+	Open(key string) (io.ReadCloser, error)
+	// Store copies r into the entry for key.
+	Store(key string, r io.Reader) error
+	// Stop stops the cleanup goroutines of the tier.
+	Stop()
+}
+
 // Cache is a cache for large byte streams.
 type Cache interface {
 	// FindOrCreate finds or creates a cache entry. If the create callback
 	// runs, it will be asynchronous and created is set to true. Callers must
-	// Close() the returned stream to free underlying resources.
-	FindOrCreate(key string, create func(io.Writer) error) (s *Stream, created bool, err error)
+	// Close() the returned stream to free underlying resources. The repo
+	// identifies the repository the entry belongs to, which is used to apply
+	// per-repository size limits.
+	FindOrCreate(key, repo string, create func(io.Writer) error) (s *Stream, created bool, err error)
 	// Stop stops the cleanup goroutines of the cache.
 	Stop()
 }
@@ -88,8 +157,8 @@ type TestLoggingCache struct {
 
 // FindOrCreate calls the underlying FindOrCreate method and logs the
 // result.
-func (tlc *TestLoggingCache) FindOrCreate(key string, create func(io.Writer) error) (s *Stream, created bool, err error) {
-	s, created, err = tlc.Cache.FindOrCreate(key, create)
+func (tlc *TestLoggingCache) FindOrCreate(key, repo string, create func(io.Writer) error) (s *Stream, created bool, err error) {
+	s, created, err = tlc.Cache.FindOrCreate(key, repo, create)
 
 	tlc.m.Lock()
 	defer tlc.m.Unlock()
@@ -114,7 +183,7 @@ type NullCache struct{}
 
 // FindOrCreate runs create in a goroutine and lets the caller consume
 // the result via the returned stream. The created flag is always true.
-func (NullCache) FindOrCreate(key string, create func(io.Writer) error) (s *Stream, created bool, err error) {
+func (NullCache) FindOrCreate(key, repo string, create func(io.Writer) error) (s *Stream, created bool, err error) {
 	pr, pw := io.Pipe()
 	w := newWaiter()
 	go func() { w.SetError(runCreate(pw, create)) }()
@@ -124,16 +193,26 @@ func (NullCache) FindOrCreate(key string, create func(io.Writer) error) (s *Stre
 // Stop is a no-op.
 func (NullCache) Stop() {}
 
+// cacheLimits bounds the size of a cache. Zero values mean no limit.
+type cacheLimits struct {
+	maxBytes     int64
+	maxRepoBytes int64
+	minFreeBytes int64
+}
+
 type cache struct {
 	m          sync.Mutex
 	maxAge     time.Duration
+	limits     cacheLimits
 	index      map[string]*entry
 	createFile func() (namedWriteCloser, error)
+	tier       secondTier
 	stop       chan struct{}
 	stopOnce   sync.Once
 	logger     logrus.FieldLogger
 	dir        string
 	sleepLoop  *dontpanic.Forever
+	freeBytes  func(dir string) (int64, error)
 
 	// removalCond is a condition that gets signalled after files have been removed from disk.
 	// This field is optional and should only be used for tests.
@@ -148,7 +227,18 @@ func New(cfg config.StreamCacheConfig, logger logrus.FieldLogger) Cache {
 			strconv.Itoa(int(cfg.MaxAge.Duration().Seconds())),
 		).Set(1)
 
-		return newCacheWithSleep(cfg.Dir, cfg.MaxAge.Duration(), time.After, time.After, logger)
+		var tier secondTier
+		if cfg.SharedDir != "" {
+			tier = newSharedStore(cfg.SharedDir, cfg.MaxAge.Duration(), cfg.SharedMaxBytes, time.After, logger)
+		}
+
+		limits := cacheLimits{
+			maxBytes:     cfg.MaxBytes,
+			maxRepoBytes: cfg.MaxBytesPerRepository,
+			minFreeBytes: cfg.MinFreeBytes,
+		}
+
+		return newCacheWithSleep(cfg.Dir, cfg.MaxAge.Duration(), limits, tier, time.After, time.After, logger)
 	}
 
 	return NullCache{}
@@ -157,6 +247,8 @@ func New(cfg config.StreamCacheConfig, logger logrus.FieldLogger) Cache {
 func newCacheWithSleep(
 	dir string,
 	maxAge time.Duration,
+	limits cacheLimits,
+	tier secondTier,
 	filestoreSleep func(time.Duration) <-chan time.Time,
 	cleanSleep func(time.Duration) <-chan time.Time,
 	logger logrus.FieldLogger,
@@ -165,12 +257,15 @@ func newCacheWithSleep(
 
 	c := &cache{
 		maxAge:     maxAge,
+		limits:     limits,
 		index:      make(map[string]*entry),
 		createFile: fs.Create,
+		tier:       tier,
 		stop:       make(chan struct{}),
 		logger:     logger,
 		dir:        dir,
 		sleepLoop:  dontpanic.NewForever(time.Minute),
+		freeBytes:  diskFree,
 	}
 
 	c.sleepLoop.Go(func() {
@@ -180,6 +275,9 @@ func newCacheWithSleep(
 		<-c.stop
 		c.sleepLoop.Cancel()
 		fs.Stop()
+		if c.tier != nil {
+			c.tier.Stop()
+		}
 	}()
 
 	return c
@@ -201,7 +299,111 @@ func (c *cache) clean() {
 			removed = append(removed, e)
 		}
 	}
+	cacheEvictions.WithLabelValues(c.dir, "max_age").Add(float64(len(removed)))
+
+	evicted, _ := c.enforceLimits()
+	c.removeFiles(append(removed, evicted...))
+}
+
+// cacheUsage is the usage of a cache after its limits have been enforced.
+type cacheUsage struct {
+	total     int64
+	repoBytes map[string]int64
+	// free is the estimated free space of the disk, or -1 if unknown.
+	free int64
+}
+
+// enforceLimits evicts the least recently used complete entries until the
+// cache is within its limits, and returns the evicted entries and the
+// remaining usage of the cache. The caller must hold c.m and remove the
+// files of the returned entries.
+func (c *cache) enforceLimits() ([]*entry, cacheUsage) {
+	var total int64
+	repoBytes := make(map[string]int64)
+	var candidates []*entry
+	for _, e := range c.index {
+		size := e.size()
+		total += size
+		repoBytes[e.repo] += size
+
+		if e.waiter.isDone() {
+			candidates = append(candidates, e)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].lastUsed.Before(candidates[j].lastUsed) })
+
+	var removed []*entry
+	evict := func(e *entry, reason string) {
+		size := e.size()
+		total -= size
+		repoBytes[e.repo] -= size
+
+		c.delete(e.key)
+		removed = append(removed, e)
+		cacheEvictions.WithLabelValues(c.dir, reason).Inc()
+	}
+
+	if c.limits.maxRepoBytes > 0 {
+		remaining := candidates[:0]
+		for _, e := range candidates {
+			if repoBytes[e.repo] > c.limits.maxRepoBytes {
+				evict(e, "max_bytes_per_repository")
+				continue
+			}
+			remaining = append(remaining, e)
+		}
+		candidates = remaining
+	}
+
+	if c.limits.maxBytes > 0 {
+		for len(candidates) > 0 && total > c.limits.maxBytes {
+			evict(candidates[0], "max_bytes")
+			candidates = candidates[1:]
+		}
+	}
+
+	free := int64(-1)
+	if c.limits.minFreeBytes > 0 {
+		var err error
+		free, err = c.freeBytes(c.dir)
+		if err != nil {
+			c.logger.WithError(err).Error("streamcache: get free disk space")
+			free = -1
+		} else {
+			for len(candidates) > 0 && free < c.limits.minFreeBytes {
+				// The space is only freed once the file is removed and all of its
+				// readers are gone, so this is an estimate.
+				free += candidates[0].size()
+				evict(candidates[0], "min_free_bytes")
+				candidates = candidates[1:]
+			}
+		}
+	}
+
+	cacheIndexBytes.WithLabelValues(c.dir).Set(float64(total))
+
+	return removed, cacheUsage{total: total, repoBytes: repoBytes, free: free}
+}
+
+// exceededLimit returns the limit a new entry of repo would exceed, or an
+// empty string if the entry fits in the cache.
+func (c *cache) exceededLimit(usage cacheUsage, repo string) string {
+	switch {
+	case c.limits.maxRepoBytes > 0 && usage.repoBytes[repo] >= c.limits.maxRepoBytes:
+		return "max_bytes_per_repository"
+	case c.limits.maxBytes > 0 && usage.total >= c.limits.maxBytes:
+		return "max_bytes"
+	case c.limits.minFreeBytes > 0 && usage.free >= 0 && usage.free < c.limits.minFreeBytes:
+		return "min_free_bytes"
+	default:
+		return ""
+	}
+}
 
+// removeFiles removes the files of entries that have been evicted from the
+// index.
+func (c *cache) removeFiles(removed []*entry) {
 	// Batch together file removals in a goroutine, without holding the mutex
 	go func() {
 		for _, e := range removed {
@@ -227,12 +429,18 @@ func (c *cache) setIndexSize() {
 	cacheIndexSize.WithLabelValues(c.dir).Set(float64(len(c.index)))
 }
 
-func (c *cache) FindOrCreate(key string, create func(io.Writer) error) (s *Stream, created bool, err error) {
+func (c *cache) countLookup(tier, result string) {
+	cacheLookups.WithLabelValues(c.dir, tier, result).Inc()
+}
+
+func (c *cache) FindOrCreate(key, repo string, create func(io.Writer) error) (s *Stream, created bool, err error) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
 	if e := c.index[key]; e != nil {
 		if s, err := e.Open(); err == nil {
+			e.lastUsed = time.Now()
+			c.countLookup("local", "hit")
 			return s, false, nil
 		}
 
@@ -242,8 +450,21 @@ func (c *cache) FindOrCreate(key string, create func(io.Writer) error) (s *Strea
 		// trying to open this entry.
 		c.delete(key)
 	}
+	c.countLookup("local", "miss")
+
+	// Entries that are still being written can't be evicted. If they keep
+	// the cache full, stream the data without caching it rather than growing
+	// the cache any further.
+	removed, usage := c.enforceLimits()
+	if len(removed) > 0 {
+		c.removeFiles(removed)
+	}
+	if reason := c.exceededLimit(usage, repo); reason != "" {
+		cacheBypasses.WithLabelValues(c.dir, reason).Inc()
+		return NullCache{}.FindOrCreate(key, repo, create)
+	}
 
-	s, e, err := c.newEntry(key, create)
+	s, e, err := c.newEntry(key, repo, create)
 	if err != nil {
 		return nil, false, err
 	}
@@ -256,12 +477,20 @@ func (c *cache) FindOrCreate(key string, create func(io.Writer) error) (s *Strea
 
 type entry struct {
 	key     string
+	repo    string
 	cache   *cache
 	pipe    *pipe
 	created time.Time
 	waiter  *waiter
+
+	// lastUsed is the last time the entry was found in the index. It is
+	// protected by the mutex of the cache.
+	lastUsed time.Time
 }
 
+// size returns the number of bytes written to the entry so far.
+func (e *entry) size() int64 { return e.pipe.wcursor.Position() }
+
 // Stream abstracts a stream of bytes (via Read()) plus an error (via
 // Wait()). Callers must always call Close() to prevent resource leaks.
 type Stream struct {
@@ -283,12 +512,15 @@ func (s *Stream) WriteTo(w io.Writer) (int64, error) {
 	return io.Copy(w, s.ReadCloser)
 }
 
-func (c *cache) newEntry(key string, create func(io.Writer) error) (_ *Stream, _ *entry, err error) {
+func (c *cache) newEntry(key, repo string, create func(io.Writer) error) (_ *Stream, _ *entry, err error) {
+	now := time.Now()
 	e := &entry{
-		key:     key,
-		cache:   c,
-		created: time.Now(),
-		waiter:  newWaiter(),
+		key:      key,
+		repo:     repo,
+		cache:    c,
+		created:  now,
+		waiter:   newWaiter(),
+		lastUsed: now,
 	}
 
 	// Every entry gets a unique underlying file. We do not want to reuse
@@ -319,7 +551,7 @@ func (c *cache) newEntry(key string, create func(io.Writer) error) (_ *Stream, _
 	}
 
 	go func() {
-		err := runCreate(e.pipe, create)
+		err := c.fill(key, e.pipe, create)
 
 		// We defer this until after we have removed the cache entry so that the waiter is
 		// only unblocked when the cache key has already been pruned from the cache.
@@ -330,12 +562,75 @@ func (c *cache) newEntry(key string, create func(io.Writer) error) (_ *Stream, _
 			c.m.Lock()
 			defer c.m.Unlock()
 			c.delete(key)
+			return
+		}
+
+		// The entry is complete, so it counts towards the limits of the cache
+		// and may be evicted now.
+		e.waiter.SetError(nil)
+		c.m.Lock()
+		removed, _ := c.enforceLimits()
+		c.m.Unlock()
+
+		if len(removed) > 0 {
+			c.removeFiles(removed)
 		}
 	}()
 
 	return e.wrapReadCloser(pr), e, nil
 }
 
+// fill writes the data of the entry for key into w. If the cache has a
+// second tier, the data is copied from there if possible. Otherwise it is
+// produced by create, and copied into the second tier in the background
+// once complete.
+func (c *cache) fill(key string, w *pipe, create func(io.Writer) error) error {
+	if c.tier == nil {
+		return runCreate(w, create)
+	}
+
+	r, err := c.tier.Open(key)
+	if err == nil {
+		defer r.Close()
+		c.countLookup(c.tier.Name(), "hit")
+
+		return runCreate(w, func(w io.Writer) error {
+			_, err := io.Copy(w, r)
+			return err
+		})
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		c.logger.WithError(err).Error("streamcache: open second tier entry")
+	}
+	c.countLookup(c.tier.Name(), "miss")
+
+	if err := runCreate(w, create); err != nil {
+		return err
+	}
+
+	go c.storeInTier(key, w.name)
+
+	return nil
+}
+
+func (c *cache) storeInTier(key string, name string) {
+	f, err := os.Open(name)
+	if err != nil {
+		// The local file may have been evicted already, which is not worth
+		// reporting.
+		if !os.IsNotExist(err) {
+			c.logger.WithError(err).Error("streamcache: open file for second tier")
+		}
+		return
+	}
+	defer f.Close()
+
+	if err := c.tier.Store(key, f); err != nil {
+		c.logger.WithError(err).Error("streamcache: store entry in second tier")
+	}
+}
+
 func (e *entry) wrapReadCloser(r io.ReadCloser) *Stream {
 	return &Stream{ReadCloser: r, waiter: e.waiter}
 }
@@ -383,6 +678,15 @@ func (w *waiter) SetError(err error) {
 	})
 }
 
+func (w *waiter) isDone() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
 func (w *waiter) Wait(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
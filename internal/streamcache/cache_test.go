@@ -47,7 +47,7 @@ func TestCache_writeOneReadMultiple(t *testing.T) {
 
 	for i := 0; i < N; i++ {
 		t.Run(fmt.Sprintf("read %d", i), func(t *testing.T) {
-			r, created, err := c.FindOrCreate(key, writeString(content(i)))
+			r, created, err := c.FindOrCreate(key, "repo", writeString(content(i)))
 			require.NoError(t, err)
 			defer r.Close()
 
@@ -89,7 +89,7 @@ func TestCache_manyConcurrentWrites(t *testing.T) {
 			errors <- func() error {
 				<-start
 
-				r, _, err := c.FindOrCreate(key, writeString(content[i]))
+				r, _, err := c.FindOrCreate(key, "repo", writeString(content[i]))
 				if err != nil {
 					return err
 				}
@@ -155,7 +155,7 @@ func TestCache_deletedFile(t *testing.T) {
 	)
 	content := func(i int) string { return fmt.Sprintf("content %d", i) }
 
-	r1, created, err := c.FindOrCreate(key, writeString(content(1)))
+	r1, created, err := c.FindOrCreate(key, "repo", writeString(content(1)))
 	require.NoError(t, err)
 	defer r1.Close()
 	require.True(t, created)
@@ -167,7 +167,7 @@ func TestCache_deletedFile(t *testing.T) {
 	requireCacheFiles(t, tmp, 0)
 	requireCacheEntries(t, c, 1)
 
-	r2, created, err := c.FindOrCreate(key, writeString(content(2)))
+	r2, created, err := c.FindOrCreate(key, "repo", writeString(content(2)))
 	require.NoError(t, err)
 	defer r2.Close()
 	require.True(t, created, "because the first file is gone, cache is forced to create a new entry")
@@ -204,7 +204,7 @@ func TestCache_scope(t *testing.T) {
 		defer func(i int) { cache[i].Stop() }(i)
 
 		var created bool
-		reader[i], created, err = cache[i].FindOrCreate(key, writeString(input[i]))
+		reader[i], created, err = cache[i].FindOrCreate(key, "repo", writeString(input[i]))
 		require.NoError(t, err)
 		defer func(i int) { require.NoError(t, reader[i].Close()) }(i)
 		require.True(t, created)
@@ -230,6 +230,58 @@ func TestCache_scope(t *testing.T) {
 	}
 }
 
+func TestCache_sharedTier(t *testing.T) {
+	ctx := testhelper.Context(t)
+
+	shared := testhelper.TempDir(t)
+
+	newTieredCache := func() Cache {
+		return New(config.StreamCacheConfig{
+			Enabled:        true,
+			Dir:            testhelper.TempDir(t),
+			MaxAge:         duration.Duration(time.Hour),
+			SharedDir:      shared,
+			SharedMaxBytes: 1024,
+		}, log.Default())
+	}
+
+	const key = "test key"
+
+	c1 := newTieredCache()
+	defer c1.Stop()
+
+	r1, created, err := c1.FindOrCreate(key, "repo", writeString("content"))
+	require.NoError(t, err)
+	defer r1.Close()
+	require.True(t, created)
+
+	out, err := io.ReadAll(r1)
+	require.NoError(t, err)
+	require.NoError(t, r1.Wait(ctx))
+	require.Equal(t, "content", string(out))
+
+	// Complete entries are copied into the shared tier in the background.
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(c1.(*cache).tier.(*sharedStore).path(key))
+		return err == nil
+	}, time.Minute, time.Millisecond)
+
+	c2 := newTieredCache()
+	defer c2.Stop()
+
+	r2, created, err := c2.FindOrCreate(key, "repo", func(io.Writer) error {
+		return errors.New("create should not run on a shared tier hit")
+	})
+	require.NoError(t, err)
+	defer r2.Close()
+	require.True(t, created, "the entry is new to the local tier")
+
+	out, err = io.ReadAll(r2)
+	require.NoError(t, err)
+	require.NoError(t, r2.Wait(ctx))
+	require.Equal(t, "content", string(out))
+}
+
 func TestCache_diskCleanup(t *testing.T) {
 	ctx := testhelper.Context(t)
 
@@ -249,7 +301,7 @@ func TestCache_diskCleanup(t *testing.T) {
 		return cleanSleepTimerCh
 	}
 
-	c := newCacheWithSleep(tmp, 0, filestoreClean, cleanSleep, log.Default())
+	c := newCacheWithSleep(tmp, 0, cacheLimits{}, nil, filestoreClean, cleanSleep, log.Default())
 	defer c.Stop()
 
 	var removalLock sync.Mutex
@@ -257,7 +309,7 @@ func TestCache_diskCleanup(t *testing.T) {
 
 	content := func(i int) string { return fmt.Sprintf("content %d", i) }
 
-	r1, created, err := c.FindOrCreate(key, writeString(content(1)))
+	r1, created, err := c.FindOrCreate(key, "repo", writeString(content(1)))
 	require.NoError(t, err)
 	defer r1.Close()
 	require.True(t, created)
@@ -296,7 +348,7 @@ func TestCache_diskCleanup(t *testing.T) {
 	requireCacheFiles(t, tmp, 0)
 	requireCacheEntries(t, c, 0)
 
-	r2, created, err := c.FindOrCreate(key, writeString(content(2)))
+	r2, created, err := c.FindOrCreate(key, "repo", writeString(content(2)))
 	require.NoError(t, err)
 	defer r2.Close()
 	require.True(t, created)
@@ -309,6 +361,167 @@ func TestCache_diskCleanup(t *testing.T) {
 	require.Equal(t, content(2), string(out2))
 }
 
+func TestCache_limits(t *testing.T) {
+	ctx := testhelper.Context(t)
+
+	neverSleep := func(time.Duration) <-chan time.Time { return nil }
+
+	type lookup struct {
+		key     string
+		repo    string
+		content string
+	}
+
+	for _, tc := range []struct {
+		desc         string
+		limits       cacheLimits
+		freeBytes    int64
+		lookups      []lookup
+		expectedKeys []string
+	}{
+		{
+			desc:   "max bytes",
+			limits: cacheLimits{maxBytes: 15},
+			lookups: []lookup{
+				{key: "key 1", repo: "repo", content: "aaaaa"},
+				{key: "key 2", repo: "repo", content: "bbbbb"},
+				{key: "key 1", repo: "repo"},
+				{key: "key 3", repo: "repo", content: "cccccc"},
+			},
+			expectedKeys: []string{"key 1", "key 3"},
+		},
+		{
+			desc:   "max bytes per repository",
+			limits: cacheLimits{maxRepoBytes: 10},
+			lookups: []lookup{
+				{key: "key 1", repo: "repo a", content: "aaaaaa"},
+				{key: "key 2", repo: "repo b", content: "bbbbbb"},
+				{key: "key 3", repo: "repo a", content: "cccccc"},
+			},
+			expectedKeys: []string{"key 2", "key 3"},
+		},
+		{
+			desc:      "min free bytes",
+			limits:    cacheLimits{minFreeBytes: 8},
+			freeBytes: 2,
+			lookups: []lookup{
+				{key: "key 1", repo: "repo", content: "aaaa"},
+				{key: "key 2", repo: "repo", content: "bbbb"},
+			},
+			expectedKeys: nil,
+		},
+		{
+			desc:      "within limits",
+			limits:    cacheLimits{maxBytes: 100, maxRepoBytes: 100, minFreeBytes: 8},
+			freeBytes: 1024,
+			lookups: []lookup{
+				{key: "key 1", repo: "repo a", content: "aaaa"},
+				{key: "key 2", repo: "repo b", content: "bbbb"},
+			},
+			expectedKeys: []string{"key 1", "key 2"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tmp := testhelper.TempDir(t)
+
+			c := newCacheWithSleep(tmp, time.Hour, tc.limits, nil, neverSleep, neverSleep, log.Default())
+			defer c.Stop()
+			c.freeBytes = func(string) (int64, error) { return tc.freeBytes, nil }
+
+			for _, l := range tc.lookups {
+				r, _, err := c.FindOrCreate(l.key, l.repo, writeString(l.content))
+				require.NoError(t, err)
+				_, err = io.ReadAll(r)
+				require.NoError(t, err)
+				require.NoError(t, r.Wait(ctx))
+				require.NoError(t, r.Close())
+			}
+
+			// Limits are enforced in the background when entries complete. Run
+			// the eviction once more so that we don't race with it.
+			c.clean()
+
+			c.m.Lock()
+			var keys []string
+			for key := range c.index {
+				keys = append(keys, key)
+			}
+			c.m.Unlock()
+			require.ElementsMatch(t, tc.expectedKeys, keys)
+
+			require.Eventually(t, func() bool {
+				find := string(testhelper.MustRunCommand(t, nil, "find", tmp, "-type", "f"))
+				return strings.Count(find, "\n") == len(tc.expectedKeys)
+			}, time.Minute, time.Millisecond, "evicted files are removed")
+		})
+	}
+}
+
+func TestCache_limitsInProgress(t *testing.T) {
+	ctx := testhelper.Context(t)
+
+	neverSleep := func(time.Duration) <-chan time.Time { return nil }
+
+	for _, tc := range []struct {
+		desc      string
+		limits    cacheLimits
+		freeBytes int64
+		repo      string
+	}{
+		{desc: "max bytes", limits: cacheLimits{maxBytes: 4}, repo: "repo b"},
+		{desc: "max bytes per repository", limits: cacheLimits{maxRepoBytes: 4}, repo: "repo a"},
+		{desc: "min free bytes", limits: cacheLimits{minFreeBytes: 8}, freeBytes: 2, repo: "repo b"},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tmp := testhelper.TempDir(t)
+
+			c := newCacheWithSleep(tmp, time.Hour, tc.limits, nil, neverSleep, neverSleep, log.Default())
+			defer c.Stop()
+			c.freeBytes = func(string) (int64, error) { return tc.freeBytes, nil }
+
+			// Fill the cache with an entry that is still being written, so that it
+			// can't be evicted.
+			written := make(chan struct{})
+			release := make(chan struct{})
+			c.limits = cacheLimits{}
+			r1, created, err := c.FindOrCreate("key 1", "repo a", func(w io.Writer) error {
+				if _, err := io.WriteString(w, "aaaaa"); err != nil {
+					return err
+				}
+				close(written)
+				<-release
+				return nil
+			})
+			require.NoError(t, err)
+			defer r1.Close()
+			require.True(t, created)
+			<-written
+			c.limits = tc.limits
+
+			r2, created, err := c.FindOrCreate("key 2", tc.repo, writeString("bbbbb"))
+			require.NoError(t, err)
+			defer r2.Close()
+			require.True(t, created)
+
+			out, err := io.ReadAll(r2)
+			require.NoError(t, err)
+			require.NoError(t, r2.Wait(ctx))
+			require.Equal(t, "bbbbb", string(out), "the data is streamed without being cached")
+
+			c.m.Lock()
+			require.Contains(t, c.index, "key 1")
+			require.NotContains(t, c.index, "key 2")
+			c.m.Unlock()
+
+			close(release)
+			out, err = io.ReadAll(r1)
+			require.NoError(t, err)
+			require.NoError(t, r1.Wait(ctx))
+			require.Equal(t, "aaaaa", string(out))
+		})
+	}
+}
+
 func TestCache_failedWrite(t *testing.T) {
 	ctx := testhelper.Context(t)
 
@@ -333,7 +546,7 @@ func TestCache_failedWrite(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			r1, created, err := c.FindOrCreate(tc.desc, tc.create)
+			r1, created, err := c.FindOrCreate(tc.desc, "repo", tc.create)
 			require.NoError(t, err)
 			require.True(t, created)
 
@@ -343,7 +556,7 @@ func TestCache_failedWrite(t *testing.T) {
 			require.Error(t, r1.Wait(ctx), "error propagation happens via Wait()")
 
 			const happy = "all is good"
-			r2, created, err := c.FindOrCreate(tc.desc, writeString(happy))
+			r2, created, err := c.FindOrCreate(tc.desc, "repo", writeString(happy))
 			require.NoError(t, err)
 			defer r2.Close()
 			require.True(t, created, "because the previous entry failed, a new one should have been created")
@@ -365,7 +578,7 @@ func TestCache_failCreateFile(t *testing.T) {
 	createError := errors.New("cannot create file")
 	c.(*cache).createFile = func() (namedWriteCloser, error) { return nil, createError }
 
-	_, _, err := c.FindOrCreate("key", func(io.Writer) error { return nil })
+	_, _, err := c.FindOrCreate("key", "repo", func(io.Writer) error { return nil })
 	require.Equal(t, createError, err)
 }
 
@@ -381,7 +594,7 @@ func TestCache_unWriteableFile(t *testing.T) {
 		return os.OpenFile(filepath.Join(tmp, "unwriteable"), os.O_RDONLY|os.O_CREATE|os.O_EXCL, 0o644)
 	}
 
-	r, created, err := c.FindOrCreate("key", func(w io.Writer) error {
+	r, created, err := c.FindOrCreate("key", "repo", func(w io.Writer) error {
 		_, err := io.WriteString(w, "hello")
 		return err
 	})
@@ -412,7 +625,7 @@ func TestCache_unCloseableFile(t *testing.T) {
 		return f, f.Close() // Already closed so cannot be closed again
 	}
 
-	r, created, err := c.FindOrCreate("key", func(w io.Writer) error { return nil })
+	r, created, err := c.FindOrCreate("key", "repo", func(w io.Writer) error { return nil })
 	require.NoError(t, err)
 	require.True(t, created)
 
@@ -438,7 +651,7 @@ func TestCache_cannotOpenFileForReading(t *testing.T) {
 		return f, os.Remove(f.Name()) // Removed so cannot be opened
 	}
 
-	_, _, err := c.FindOrCreate("key", func(w io.Writer) error { return nil })
+	_, _, err := c.FindOrCreate("key", "repo", func(w io.Writer) error { return nil })
 	err = errors.Unwrap(err)
 	require.IsType(t, &os.PathError{}, err)
 	require.Equal(t, "open", err.(*os.PathError).Op)
@@ -491,7 +704,7 @@ func TestNullCache(t *testing.T) {
 
 				<-start
 
-				s, created, err := c.FindOrCreate(key, func(w io.Writer) error {
+				s, created, err := c.FindOrCreate(key, "repo", func(w io.Writer) error {
 					for j := 0; j < len(input); j++ {
 						n, err := w.Write(input[j : j+1])
 						if err != nil {
@@ -0,0 +1,13 @@
+package streamcache
+
+import "golang.org/x/sys/unix"
+
+func diskFree(dir string) (int64, error) {
+	var stats unix.Statfs_t
+	if err := unix.Statfs(dir, &stats); err != nil {
+		return 0, err
+	}
+
+	// Redundant conversions to handle differences between unix families
+	return int64(stats.F_bavail) * int64(stats.F_bsize), nil
+}
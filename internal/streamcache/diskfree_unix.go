@@ -0,0 +1,15 @@
+//go:build !openbsd
+
+package streamcache
+
+import "golang.org/x/sys/unix"
+
+func diskFree(dir string) (int64, error) {
+	var stats unix.Statfs_t
+	if err := unix.Statfs(dir, &stats); err != nil {
+		return 0, err
+	}
+
+	// Redundant conversions to handle differences between unix families
+	return int64(stats.Bavail) * int64(stats.Bsize), nil //nolint:unconvert,nolintlint
+}
@@ -0,0 +1,186 @@
+package streamcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/dontpanic"
+)
+
+// sharedStore is a second tier that keeps complete cache entries in a
+// directory which may be shared between several Gitaly nodes, for
+// instance on a network filesystem. Unlike filestore, whose files are
+// anonymous, the files of sharedStore are named after the cache key so
+// that any node can find an entry written by another one.
+//
+// Entries are written to a temporary file first and then renamed into
+// place, so readers never see partially written entries. Each successful
+// Open bumps the mtime of the entry, which makes the mtime a
+// least-recently-used timestamp. A goroutine periodically removes entries
+// that have not been used for maxAge, and then removes the least recently
+// used entries until the total size of the directory is at most maxBytes.
+type sharedStore struct {
+	dir      string
+	maxAge   time.Duration
+	maxBytes int64
+	logger   logrus.FieldLogger
+
+	m         sync.Mutex
+	stop      chan struct{}
+	sleepLoop *dontpanic.Forever
+}
+
+func newSharedStore(dir string, maxAge time.Duration, maxBytes int64, sleep func(time.Duration) <-chan time.Time, logger logrus.FieldLogger) *sharedStore {
+	ss := &sharedStore{
+		dir:       dir,
+		maxAge:    maxAge,
+		maxBytes:  maxBytes,
+		logger:    logger,
+		stop:      make(chan struct{}),
+		sleepLoop: dontpanic.NewForever(time.Minute),
+	}
+
+	ss.sleepLoop.Go(func() {
+		sleepLoop(ss.stop, ss.maxAge, sleep, func() {
+			if err := ss.evict(time.Now().Add(-ss.maxAge)); err != nil {
+				logger.WithError(err).Error("streamcache shared store eviction")
+			}
+		})
+	})
+
+	return ss
+}
+
+func (ss *sharedStore) Name() string { return "shared" }
+
+func (ss *sharedStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(ss.dir, name[:2], name)
+}
+
+// Open opens the entry for key. It returns an error satisfying
+// errors.Is(err, os.ErrNotExist) if there is no such entry.
+func (ss *sharedStore) Open(key string) (io.ReadCloser, error) {
+	path := ss.path(key)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors are ignored on purpose: another node may have evicted the entry
+	// in the meantime, but we can still read it through the open file.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return f, nil
+}
+
+// Store copies r into the entry for key, replacing any existing entry.
+func (ss *sharedStore) Store(key string, r io.Reader) (returnedErr error) {
+	path := ss.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("Store: mkdir: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "tmp-*")
+	if err != nil {
+		return fmt.Errorf("Store: %w", err)
+	}
+	defer func() {
+		if returnedErr != nil {
+			f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("Store: copy: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Store: close: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("Store: rename: %w", err)
+	}
+
+	return nil
+}
+
+func (ss *sharedStore) Stop() {
+	ss.m.Lock()
+	defer ss.m.Unlock()
+
+	select {
+	case <-ss.stop:
+	default:
+		close(ss.stop)
+	}
+
+	ss.sleepLoop.Cancel()
+}
+
+type sharedStoreFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict removes entries last used before cutoff, and then removes the
+// least recently used entries until the store fits in maxBytes. Like
+// filestore.cleanWalk, it only removes files and leaves the directories
+// in place.
+func (ss *sharedStore) evict(cutoff time.Time) error {
+	var files []sharedStoreFile
+	if err := filepath.Walk(ss.dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			files = append(files, sharedStoreFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+
+	for _, f := range files {
+		if !f.modTime.Before(cutoff) && total <= ss.maxBytes {
+			break
+		}
+
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		total -= f.size
+		fileRemoveCounter.WithLabelValues(ss.dir).Inc()
+	}
+
+	diskUsageGauge.WithLabelValues(ss.dir).Set(float64(total))
+
+	return nil
+}
@@ -0,0 +1,77 @@
+//go:build !gitaly_test_sha256
+
+package streamcache
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/log"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/testhelper"
+)
+
+func TestSharedStore(t *testing.T) {
+	tmp := testhelper.TempDir(t)
+
+	ss := newSharedStore(tmp, time.Hour, 1024, time.After, log.Default())
+	defer ss.Stop()
+
+	_, err := ss.Open("test key")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, ss.Store("test key", strings.NewReader("content")))
+
+	relpath, err := filepath.Rel(tmp, ss.path("test key"))
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^[0-9a-f]{2}/[0-9a-f]{64}$`), relpath)
+
+	r, err := ss.Open("test key")
+	require.NoError(t, err)
+	defer r.Close()
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "content", string(out))
+
+	requireCacheFiles(t, tmp, 1)
+}
+
+func TestSharedStore_evict(t *testing.T) {
+	tmp := testhelper.TempDir(t)
+
+	ss := newSharedStore(tmp, time.Hour, 10, time.After, log.Default())
+	defer ss.Stop()
+
+	now := time.Now()
+	for _, entry := range []struct {
+		key     string
+		content string
+		used    time.Time
+	}{
+		{key: "stale", content: "a", used: now.Add(-2 * time.Hour)},
+		{key: "old", content: "bbbb", used: now.Add(-3 * time.Minute)},
+		{key: "recent", content: "cccc", used: now.Add(-2 * time.Minute)},
+		{key: "newest", content: "dddd", used: now.Add(-time.Minute)},
+	} {
+		require.NoError(t, ss.Store(entry.key, strings.NewReader(entry.content)))
+		require.NoError(t, os.Chtimes(ss.path(entry.key), entry.used, entry.used))
+	}
+
+	// Opening an entry marks it as recently used.
+	r, err := ss.Open("old")
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	require.NoError(t, ss.evict(now.Add(-time.Hour)))
+
+	require.NoFileExists(t, ss.path("stale"), "entries older than the cutoff are removed")
+	require.NoFileExists(t, ss.path("recent"), "least recently used entries are removed to fit max bytes")
+	require.FileExists(t, ss.path("old"))
+	require.FileExists(t, ss.path("newest"))
+}
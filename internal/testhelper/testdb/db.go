@@ -105,6 +105,8 @@ func (db DB) TruncateAll(tb testing.TB) {
 		"virtual_storages",
 		"repository_assignments",
 		"storage_cleanups",
+		"storage_drain_moves",
+		"storage_drains",
 	)
 }
 
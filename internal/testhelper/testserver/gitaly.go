@@ -12,6 +12,7 @@ import (
 	gitalyauth "gitlab.com/gitlab-org/gitaly/v15/auth"
 	"gitlab.com/gitlab-org/gitaly/v15/client"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/backchannel"
+	"gitlab.com/gitlab-org/gitaly/v15/internal/backup"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/cache"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git"
 	"gitlab.com/gitlab-org/gitaly/v15/internal/git/catfile"
@@ -249,6 +250,8 @@ type gitalyServerDeps struct {
 	git2goExecutor                *git2go.Executor
 	updaterWithHooks              *updateref.UpdaterWithHooks
 	housekeepingManager           housekeeping.Manager
+	backupSink                    backup.Sink
+	backupLocator                 backup.Locator
 }
 
 func (gsd *gitalyServerDeps) createDependencies(tb testing.TB, cfg config.Cfg, rubyServer *rubyserver.Server) *service.Dependencies {
@@ -349,6 +352,8 @@ func (gsd *gitalyServerDeps) createDependencies(tb testing.TB, cfg config.Cfg, r
 		Git2goExecutor:                gsd.git2goExecutor,
 		UpdaterWithHooks:              gsd.updaterWithHooks,
 		HousekeepingManager:           gsd.housekeepingManager,
+		BackupSink:                    gsd.backupSink,
+		BackupLocator:                 gsd.backupLocator,
 	}
 }
 
@@ -454,3 +459,21 @@ func WithHousekeepingManager(manager housekeeping.Manager) GitalyServerOpt {
 		return deps
 	}
 }
+
+// WithBackupSink sets the backup.Sink that will be used for Gitaly services
+// initialization.
+func WithBackupSink(backupSink backup.Sink) GitalyServerOpt {
+	return func(deps gitalyServerDeps) gitalyServerDeps {
+		deps.backupSink = backupSink
+		return deps
+	}
+}
+
+// WithBackupLocator sets the backup.Locator that will be used for Gitaly services
+// initialization.
+func WithBackupLocator(backupLocator backup.Locator) GitalyServerOpt {
+	return func(deps gitalyServerDeps) gitalyServerDeps {
+		deps.backupLocator = backupLocator
+		return deps
+	}
+}
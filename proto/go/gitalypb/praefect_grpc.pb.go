@@ -44,6 +44,21 @@ type PraefectInfoServiceClient interface {
 	SetReplicationFactor(ctx context.Context, in *SetReplicationFactorRequest, opts ...grpc.CallOption) (*SetReplicationFactorResponse, error)
 	// GetRepositoryMetadata returns the cluster metadata for a repository. Returns NotFound if the repository does not exist.
 	GetRepositoryMetadata(ctx context.Context, in *GetRepositoryMetadataRequest, opts ...grpc.CallOption) (*GetRepositoryMetadataResponse, error)
+	// DrainStorage marks a storage as draining, for example to take it out of the virtual storage for maintenance.
+	// Praefect doesn't elect primaries on a draining storage nor assign new replicas to it, and moves the repositories
+	// on it to the other storages in the virtual storage. Each call performs a single pass of moving the repositories
+	// and returns the progress of the drain. DrainStorage should be called until no repositories remain on the storage.
+	DrainStorage(ctx context.Context, in *DrainStorageRequest, opts ...grpc.CallOption) (*DrainStorageResponse, error)
+	// ListReplicationJobs lists the jobs in the replication queue matching the filters. Completed and dead jobs are
+	// removed from the queue, so only jobs which are ready, in progress or failed are returned. The jobs are ordered
+	// by their ID.
+	ListReplicationJobs(ctx context.Context, in *ListReplicationJobsRequest, opts ...grpc.CallOption) (*ListReplicationJobsResponse, error)
+	// CancelReplicationJobs removes jobs which are waiting to be processed from the replication queue. Jobs being
+	// processed are not cancelled. If a replica remains outdated, the reconciler schedules a new job for it.
+	CancelReplicationJobs(ctx context.Context, in *CancelReplicationJobsRequest, opts ...grpc.CallOption) (*CancelReplicationJobsResponse, error)
+	// PrioritizeReplicationJobs sets the priority of jobs which are waiting to be processed. Jobs with a higher priority
+	// are processed before jobs with a lower priority, and jobs with the same priority are processed oldest first.
+	PrioritizeReplicationJobs(ctx context.Context, in *PrioritizeReplicationJobsRequest, opts ...grpc.CallOption) (*PrioritizeReplicationJobsResponse, error)
 }
 
 type praefectInfoServiceClient struct {
@@ -108,6 +123,42 @@ func (c *praefectInfoServiceClient) GetRepositoryMetadata(ctx context.Context, i
 	return out, nil
 }
 
+func (c *praefectInfoServiceClient) DrainStorage(ctx context.Context, in *DrainStorageRequest, opts ...grpc.CallOption) (*DrainStorageResponse, error) {
+	out := new(DrainStorageResponse)
+	err := c.cc.Invoke(ctx, "/gitaly.PraefectInfoService/DrainStorage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *praefectInfoServiceClient) ListReplicationJobs(ctx context.Context, in *ListReplicationJobsRequest, opts ...grpc.CallOption) (*ListReplicationJobsResponse, error) {
+	out := new(ListReplicationJobsResponse)
+	err := c.cc.Invoke(ctx, "/gitaly.PraefectInfoService/ListReplicationJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *praefectInfoServiceClient) CancelReplicationJobs(ctx context.Context, in *CancelReplicationJobsRequest, opts ...grpc.CallOption) (*CancelReplicationJobsResponse, error) {
+	out := new(CancelReplicationJobsResponse)
+	err := c.cc.Invoke(ctx, "/gitaly.PraefectInfoService/CancelReplicationJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *praefectInfoServiceClient) PrioritizeReplicationJobs(ctx context.Context, in *PrioritizeReplicationJobsRequest, opts ...grpc.CallOption) (*PrioritizeReplicationJobsResponse, error) {
+	out := new(PrioritizeReplicationJobsResponse)
+	err := c.cc.Invoke(ctx, "/gitaly.PraefectInfoService/PrioritizeReplicationJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PraefectInfoServiceServer is the server API for PraefectInfoService service.
 // All implementations must embed UnimplementedPraefectInfoServiceServer
 // for forward compatibility
@@ -134,6 +185,21 @@ type PraefectInfoServiceServer interface {
 	SetReplicationFactor(context.Context, *SetReplicationFactorRequest) (*SetReplicationFactorResponse, error)
 	// GetRepositoryMetadata returns the cluster metadata for a repository. Returns NotFound if the repository does not exist.
 	GetRepositoryMetadata(context.Context, *GetRepositoryMetadataRequest) (*GetRepositoryMetadataResponse, error)
+	// DrainStorage marks a storage as draining, for example to take it out of the virtual storage for maintenance.
+	// Praefect doesn't elect primaries on a draining storage nor assign new replicas to it, and moves the repositories
+	// on it to the other storages in the virtual storage. Each call performs a single pass of moving the repositories
+	// and returns the progress of the drain. DrainStorage should be called until no repositories remain on the storage.
+	DrainStorage(context.Context, *DrainStorageRequest) (*DrainStorageResponse, error)
+	// ListReplicationJobs lists the jobs in the replication queue matching the filters. Completed and dead jobs are
+	// removed from the queue, so only jobs which are ready, in progress or failed are returned. The jobs are ordered
+	// by their ID.
+	ListReplicationJobs(context.Context, *ListReplicationJobsRequest) (*ListReplicationJobsResponse, error)
+	// CancelReplicationJobs removes jobs which are waiting to be processed from the replication queue. Jobs being
+	// processed are not cancelled. If a replica remains outdated, the reconciler schedules a new job for it.
+	CancelReplicationJobs(context.Context, *CancelReplicationJobsRequest) (*CancelReplicationJobsResponse, error)
+	// PrioritizeReplicationJobs sets the priority of jobs which are waiting to be processed. Jobs with a higher priority
+	// are processed before jobs with a lower priority, and jobs with the same priority are processed oldest first.
+	PrioritizeReplicationJobs(context.Context, *PrioritizeReplicationJobsRequest) (*PrioritizeReplicationJobsResponse, error)
 	mustEmbedUnimplementedPraefectInfoServiceServer()
 }
 
@@ -159,6 +225,18 @@ func (UnimplementedPraefectInfoServiceServer) SetReplicationFactor(context.Conte
 func (UnimplementedPraefectInfoServiceServer) GetRepositoryMetadata(context.Context, *GetRepositoryMetadataRequest) (*GetRepositoryMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRepositoryMetadata not implemented")
 }
+func (UnimplementedPraefectInfoServiceServer) DrainStorage(context.Context, *DrainStorageRequest) (*DrainStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainStorage not implemented")
+}
+func (UnimplementedPraefectInfoServiceServer) ListReplicationJobs(context.Context, *ListReplicationJobsRequest) (*ListReplicationJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReplicationJobs not implemented")
+}
+func (UnimplementedPraefectInfoServiceServer) CancelReplicationJobs(context.Context, *CancelReplicationJobsRequest) (*CancelReplicationJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelReplicationJobs not implemented")
+}
+func (UnimplementedPraefectInfoServiceServer) PrioritizeReplicationJobs(context.Context, *PrioritizeReplicationJobsRequest) (*PrioritizeReplicationJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrioritizeReplicationJobs not implemented")
+}
 func (UnimplementedPraefectInfoServiceServer) mustEmbedUnimplementedPraefectInfoServiceServer() {}
 
 // UnsafePraefectInfoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -280,6 +358,78 @@ func _PraefectInfoService_GetRepositoryMetadata_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PraefectInfoService_DrainStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PraefectInfoServiceServer).DrainStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitaly.PraefectInfoService/DrainStorage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PraefectInfoServiceServer).DrainStorage(ctx, req.(*DrainStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PraefectInfoService_ListReplicationJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReplicationJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PraefectInfoServiceServer).ListReplicationJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitaly.PraefectInfoService/ListReplicationJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PraefectInfoServiceServer).ListReplicationJobs(ctx, req.(*ListReplicationJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PraefectInfoService_CancelReplicationJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelReplicationJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PraefectInfoServiceServer).CancelReplicationJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitaly.PraefectInfoService/CancelReplicationJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PraefectInfoServiceServer).CancelReplicationJobs(ctx, req.(*CancelReplicationJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PraefectInfoService_PrioritizeReplicationJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrioritizeReplicationJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PraefectInfoServiceServer).PrioritizeReplicationJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitaly.PraefectInfoService/PrioritizeReplicationJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PraefectInfoServiceServer).PrioritizeReplicationJobs(ctx, req.(*PrioritizeReplicationJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PraefectInfoService_ServiceDesc is the grpc.ServiceDesc for PraefectInfoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -311,6 +461,22 @@ var PraefectInfoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRepositoryMetadata",
 			Handler:    _PraefectInfoService_GetRepositoryMetadata_Handler,
 		},
+		{
+			MethodName: "DrainStorage",
+			Handler:    _PraefectInfoService_DrainStorage_Handler,
+		},
+		{
+			MethodName: "ListReplicationJobs",
+			Handler:    _PraefectInfoService_ListReplicationJobs_Handler,
+		},
+		{
+			MethodName: "CancelReplicationJobs",
+			Handler:    _PraefectInfoService_CancelReplicationJobs_Handler,
+		},
+		{
+			MethodName: "PrioritizeReplicationJobs",
+			Handler:    _PraefectInfoService_PrioritizeReplicationJobs_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "praefect.proto",
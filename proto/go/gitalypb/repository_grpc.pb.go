@@ -139,6 +139,14 @@ type RepositoryServiceClient interface {
 	// FullPath reads the "gitlab.fullpath" configuration from the repository's
 	// gitconfig. Returns an error in case the full path has not been configured.
 	FullPath(ctx context.Context, in *FullPathRequest, opts ...grpc.CallOption) (*FullPathResponse, error)
+	// BackupRepository creates a full or incremental backup of a repository and
+	// writes it directly to the backup sink configured on the server. Requires
+	// server-side backups to be configured.
+	BackupRepository(ctx context.Context, in *BackupRepositoryRequest, opts ...grpc.CallOption) (*BackupRepositoryResponse, error)
+	// RestoreRepository restores a repository from a backup read directly from
+	// the backup sink configured on the server. Requires server-side backups to
+	// be configured.
+	RestoreRepository(ctx context.Context, in *RestoreRepositoryRequest, opts ...grpc.CallOption) (*RestoreRepositoryResponse, error)
 }
 
 type repositoryServiceClient struct {
@@ -837,6 +845,24 @@ func (c *repositoryServiceClient) FullPath(ctx context.Context, in *FullPathRequ
 	return out, nil
 }
 
+func (c *repositoryServiceClient) BackupRepository(ctx context.Context, in *BackupRepositoryRequest, opts ...grpc.CallOption) (*BackupRepositoryResponse, error) {
+	out := new(BackupRepositoryResponse)
+	err := c.cc.Invoke(ctx, "/gitaly.RepositoryService/BackupRepository", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) RestoreRepository(ctx context.Context, in *RestoreRepositoryRequest, opts ...grpc.CallOption) (*RestoreRepositoryResponse, error) {
+	out := new(RestoreRepositoryResponse)
+	err := c.cc.Invoke(ctx, "/gitaly.RepositoryService/RestoreRepository", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RepositoryServiceServer is the server API for RepositoryService service.
 // All implementations must embed UnimplementedRepositoryServiceServer
 // for forward compatibility
@@ -958,6 +984,14 @@ type RepositoryServiceServer interface {
 	// FullPath reads the "gitlab.fullpath" configuration from the repository's
 	// gitconfig. Returns an error in case the full path has not been configured.
 	FullPath(context.Context, *FullPathRequest) (*FullPathResponse, error)
+	// BackupRepository creates a full or incremental backup of a repository and
+	// writes it directly to the backup sink configured on the server. Requires
+	// server-side backups to be configured.
+	BackupRepository(context.Context, *BackupRepositoryRequest) (*BackupRepositoryResponse, error)
+	// RestoreRepository restores a repository from a backup read directly from
+	// the backup sink configured on the server. Requires server-side backups to
+	// be configured.
+	RestoreRepository(context.Context, *RestoreRepositoryRequest) (*RestoreRepositoryResponse, error)
 	mustEmbedUnimplementedRepositoryServiceServer()
 }
 
@@ -1091,6 +1125,12 @@ func (UnimplementedRepositoryServiceServer) SetFullPath(context.Context, *SetFul
 func (UnimplementedRepositoryServiceServer) FullPath(context.Context, *FullPathRequest) (*FullPathResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FullPath not implemented")
 }
+func (UnimplementedRepositoryServiceServer) BackupRepository(context.Context, *BackupRepositoryRequest) (*BackupRepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupRepository not implemented")
+}
+func (UnimplementedRepositoryServiceServer) RestoreRepository(context.Context, *RestoreRepositoryRequest) (*RestoreRepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreRepository not implemented")
+}
 func (UnimplementedRepositoryServiceServer) mustEmbedUnimplementedRepositoryServiceServer() {}
 
 // UnsafeRepositoryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -1919,6 +1959,42 @@ func _RepositoryService_FullPath_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepositoryService_BackupRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).BackupRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitaly.RepositoryService/BackupRepository",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).BackupRepository(ctx, req.(*BackupRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_RestoreRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).RestoreRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitaly.RepositoryService/RestoreRepository",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).RestoreRepository(ctx, req.(*RestoreRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RepositoryService_ServiceDesc is the grpc.ServiceDesc for RepositoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -2042,6 +2118,14 @@ var RepositoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FullPath",
 			Handler:    _RepositoryService_FullPath_Handler,
 		},
+		{
+			MethodName: "BackupRepository",
+			Handler:    _RepositoryService_BackupRepository_Handler,
+		},
+		{
+			MethodName: "RestoreRepository",
+			Handler:    _RepositoryService_RestoreRepository_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{